@@ -12,7 +12,12 @@ import https from 'https';
 import inquirer from 'inquirer';
 import ora from 'ora'; // Import ora
 
-import { log, readJSON } from './utils.js';
+import {
+	log,
+	readJSON,
+	setCurrentTag,
+	enableSilentMode
+} from './utils.js';
 import {
 	parsePRD,
 	updateTasks,
@@ -29,6 +34,8 @@ import {
 	updateTaskById,
 	updateSubtaskById,
 	removeTask,
+	moveTask,
+	addTag,
 	findTaskById,
 	taskExists
 } from './task-manager.js';
@@ -1019,14 +1026,35 @@ function registerCommands(programInstance) {
 		.description('Generate task files from tasks.json')
 		.option('-f, --file <file>', 'Path to the tasks file', path.join(getTasksPath(), 'tasks.json'))
 		.option('-o, --output <dir>', 'Output directory', getTasksPath())
+		.option(
+			'-i, --id <ids>',
+			'Comma-separated IDs of the tasks to generate files for (default: all)'
+		)
 		.action(async (options) => {
 			const tasksPath = options.file;
 			const outputDir = options.output;
+			const rawIds = options.id
+				? options.id.split(',').map((id) => id.trim())
+				: [];
+			const invalidIds = rawIds.filter((id) => !/^\d+$/.test(id));
+			if (invalidIds.length > 0) {
+				console.error(
+					chalk.red(
+						`Error: Invalid task ID(s) for --id: ${invalidIds.map((id) => `"${id}"`).join(', ')}`
+					)
+				);
+				console.log(chalk.yellow('Example: task-master generate --id=1,3'));
+				process.exit(1);
+			}
+			const ids = rawIds.map((id) => parseInt(id, 10));
 
 			console.log(chalk.blue(`Generating task files from: ${tasksPath}`));
 			console.log(chalk.blue(`Output directory: ${outputDir}`));
+			if (ids.length > 0) {
+				console.log(chalk.blue(`Tasks: ${ids.join(', ')}`));
+			}
 
-			await generateTaskFiles(tasksPath, outputDir);
+			await generateTaskFiles(tasksPath, outputDir, { ids });
 		});
 
 	// set-status command
@@ -1341,21 +1369,44 @@ function registerCommands(programInstance) {
 			'Type of task (standard or checkpoint)',
 			'standard'
 		)
+		.option(
+			'--acceptance-criteria <acceptanceCriteria>',
+			'Acceptance criteria for a checkpoint task (for manual task creation)'
+		)
 		.option(
 			'-r, --research',
 			'Whether to use research capabilities for task creation'
 		)
+		.option('--json', 'Print the result as JSON instead of text')
 		.action(async (options) => {
 			const isManualCreation = options.title && options.description;
 			const taskType = options.type;
 
+			// In JSON mode stdout carries only the result, for tools like the TUI
+			const printJSONError = (message) => {
+				console.log(JSON.stringify({ success: false, error: message }));
+			};
+			if (options.json) {
+				enableSilentMode();
+			}
+
 			if (taskType && !['standard', 'checkpoint'].includes(taskType)) {
+				if (options.json) {
+					printJSONError('Invalid task type. Must be "standard" or "checkpoint".');
+					process.exit(1);
+				}
 				console.error(chalk.red('Error: Invalid task type. Must be "standard" or "checkpoint".'));
 				process.exit(1);
 			}
 
 			// Validate that either prompt or title+description are provided
 			if (!options.prompt && !isManualCreation) {
+				if (options.json) {
+					printJSONError(
+						'Either --prompt or both --title and --description must be provided'
+					);
+					process.exit(1);
+				}
 				console.error(
 					chalk.red(
 						'Error: Either --prompt or both --title and --description must be provided'
@@ -1387,7 +1438,9 @@ function registerCommands(programInstance) {
 						// For now, assuming addTask handles prompting or errors if missing for checkpoint.
 						...(taskType === 'checkpoint' && options.acceptanceCriteria && { acceptanceCriteria: options.acceptanceCriteria })
 					};
+				}
 
+				if (!options.json && isManualCreation) {
 					console.log(
 						chalk.blue(`Creating ${taskType} task manually with title: "${options.title}"`)
 					);
@@ -1398,7 +1451,7 @@ function registerCommands(programInstance) {
 						console.log(chalk.blue(`Acceptance Criteria: "${manualTaskData.acceptanceCriteria}"`));
 					}
 
-				} else { // AI Creation Path
+				} else if (!options.json) { // AI Creation Path
 					console.log(
 						chalk.blue(
 							`Creating ${taskType} task with AI using prompt: "${options.prompt}"`
@@ -1421,15 +1474,29 @@ function registerCommands(programInstance) {
 						// as dotenv loads .env, and utils.resolveEnvVariable checks process.env
 						mcpLog: options.mcpLog // Pass mcpLog if available from higher up
 					},
-					'text', // outputFormat
+					options.json ? 'json' : 'text', // outputFormat
 					manualTaskData, // Pass the potentially created manualTaskData object
 					options.research || false, // Pass the research flag value
 					taskType // Pass the taskType
 				);
 
+				if (options.json) {
+					console.log(
+						JSON.stringify({
+							success: true,
+							data: { id: newTaskId },
+							message: `Added new ${taskType} task #${newTaskId}`
+						})
+					);
+					return;
+				}
 				console.log(chalk.green(`✓ Added new ${taskType} task #${newTaskId}`));
 				console.log(chalk.gray('Next: Complete this task or add more tasks'));
 			} catch (error) {
+				if (options.json) {
+					printJSONError(`Error adding task: ${error.message}`);
+					process.exit(1);
+				}
 				console.error(chalk.red(`Error adding task: ${error.message}`));
 				if (error.stack && getDebugFlag()) {
 					console.error(error.stack);
@@ -2138,6 +2205,61 @@ function registerCommands(programInstance) {
 			}
 		});
 
+	// move command
+	programInstance
+		.command('move')
+		.description('Move a task or subtask to a new ID or position')
+		.option('-f, --file <file>', 'Path to the tasks file', path.join(getTasksPath(), 'tasks.json'))
+		.option(
+			'--from <id>',
+			'ID of the task or subtask to move (e.g., "5" or "5.2")'
+		)
+		.option(
+			'--to <id>',
+			'ID to move it to (e.g., "7" or "7.3"); a task ID must be unused'
+		)
+		.action(async (options) => {
+			const tasksPath = options.file;
+			const sourceId = options.from;
+			const destinationId = options.to;
+
+			if (!sourceId || !destinationId) {
+				console.error(chalk.red('Error: Both --from and --to are required'));
+				console.log(
+					chalk.yellow('Usage: task-master move --from=<id> --to=<id>')
+				);
+				process.exit(1);
+			}
+
+			try {
+				const movedTask = await moveTask(tasksPath, sourceId, destinationId);
+				console.log(
+					chalk.green(
+						`Moved ${sourceId} to ${destinationId} (now ID ${movedTask.id})`
+					)
+				);
+			} catch (error) {
+				console.error(chalk.red(`Error: ${error.message}`));
+				process.exit(1);
+			}
+		});
+
+	// add-tag command
+	programInstance
+		.command('add-tag <name>')
+		.description('Add an empty tag (task context) to the tasks file')
+		.option('-f, --file <file>', 'Path to the tasks file', path.join(getTasksPath(), 'tasks.json'))
+		.action(async (name, options) => {
+			try {
+				const { tags } = addTag(options.file, name);
+				console.log(chalk.green(`Added tag ${name}`));
+				console.log(chalk.blue(`Tags: ${tags.join(', ')}`));
+			} catch (error) {
+				console.error(chalk.red(`Error: ${error.message}`));
+				process.exit(1);
+			}
+		});
+
 	// init command (Directly calls the implementation from init.js)
 	programInstance
 		.command('init')
@@ -2423,6 +2545,14 @@ function setupCLI() {
 			return 'unknown'; // Default fallback if package.json fails
 		})
 		.helpOption('-h, --help', 'Display help')
+		.option(
+			'--tag <tag>',
+			'Tag (task context) to work in within the tasks file (default: master)'
+		)
+		.hook('preAction', (thisCommand) => {
+			// Every command reads and writes its tasks in the chosen tag
+			setCurrentTag(thisCommand.opts().tag);
+		})
 		.addHelpCommand(false) // Disable default help command
 		.on('--help', () => {
 			displayHelp(); // Use your custom help display instead
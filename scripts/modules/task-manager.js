@@ -21,6 +21,8 @@ import addSubtask from './task-manager/add-subtask.js';
 import removeSubtask from './task-manager/remove-subtask.js';
 import updateSubtaskById from './task-manager/update-subtask-by-id.js';
 import removeTask from './task-manager/remove-task.js';
+import moveTask from './task-manager/move-task.js';
+import addTag from './task-manager/add-tag.js';
 import taskExists from './task-manager/task-exists.js';
 import isTaskDependentOn from './task-manager/is-task-dependent.js';
 
@@ -43,6 +45,8 @@ export {
 	findNextTask,
 	analyzeTaskComplexity,
 	removeTask,
+	moveTask,
+	addTag,
 	findTaskById,
 	taskExists,
 	isTaskDependentOn
@@ -0,0 +1,42 @@
+import fs from 'fs';
+
+import { log, writeJSON, isTaggedLayout } from '../utils.js';
+
+/**
+ * Adds an empty tag (task context) to a tasks file. An untagged file becomes
+ * tagged, with its tasks kept in the master tag.
+ * @param {string} tasksPath - Path to the tasks file
+ * @param {string} tagName - Name of the tag to add
+ * @returns {Object} Result object with the tags now in the file
+ */
+function addTag(tasksPath, tagName) {
+	if (!/^[A-Za-z0-9_-]+$/.test(tagName || '')) {
+		throw new Error(
+			`Invalid tag name "${tagName}": use letters, digits, hyphens and underscores`
+		);
+	}
+
+	let data = {};
+	if (fs.existsSync(tasksPath)) {
+		data = JSON.parse(fs.readFileSync(tasksPath, 'utf8'));
+		if (!isTaggedLayout(data)) {
+			if (!Array.isArray(data.tasks)) {
+				throw new Error(`Invalid tasks file at ${tasksPath}`);
+			}
+			data = { master: data };
+		}
+	}
+	if (data[tagName]) {
+		throw new Error(`Tag "${tagName}" already exists in ${tasksPath}`);
+	}
+
+	data[tagName] = { tasks: [] };
+	writeJSON(tasksPath, data);
+	log('info', `Added tag ${tagName} to ${tasksPath}`);
+
+	return {
+		tags: Object.keys(data).filter((key) => Array.isArray(data[key]?.tasks))
+	};
+}
+
+export default addTag;
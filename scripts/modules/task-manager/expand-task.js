@@ -364,6 +364,11 @@ async function expandTask(
 		const data = readJSON(tasksPath);
 		if (!data || !data.tasks)
 			throw new Error(`Invalid tasks data in ${tasksPath}`);
+		// parseInt would read subtask "2.3" as task 2 and expand the parent
+		if (String(taskId).includes('.'))
+			throw new Error(
+				`${taskId} is a subtask, which can't have subtasks of its own. Convert it to a task with remove-subtask --convert, then expand that.`
+			);
 		const taskIndex = data.tasks.findIndex(
 			(t) => t.id === parseInt(taskId, 10)
 		);
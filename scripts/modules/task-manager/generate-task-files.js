@@ -11,7 +11,7 @@ import { getDebugFlag } from '../config-manager.js';
  * Generate individual task files from tasks.json
  * @param {string} tasksPath - Path to the tasks.json file
  * @param {string} outputDir - Output directory for task files
- * @param {Object} options - Additional options (mcpLog for MCP mode)
+ * @param {Object} options - Additional options (mcpLog for MCP mode, ids to generate only those tasks' files)
  * @returns {Object|undefined} Result object in MCP mode, undefined in CLI mode
  */
 function generateTaskFiles(tasksPath, outputDir, options = {}) {
@@ -26,6 +26,22 @@ function generateTaskFiles(tasksPath, outputDir, options = {}) {
 			throw new Error(`No valid tasks found in ${tasksPath}`);
 		}
 
+		// Requested IDs must be tasks in the file, so a typo isn't silently skipped
+		if (options?.ids && options.ids.length > 0) {
+			const invalidIds = options.ids.filter((id) => !Number.isInteger(id));
+			if (invalidIds.length > 0) {
+				throw new Error(`Invalid task ID(s): ${invalidIds.join(', ')}`);
+			}
+			const missingIds = options.ids.filter(
+				(id) => !data.tasks.some((task) => task.id === id)
+			);
+			if (missingIds.length > 0) {
+				throw new Error(
+					`Task(s) ${missingIds.join(', ')} not found in ${tasksPath}`
+				);
+			}
+		}
+
 		// Create the output directory if it doesn't exist
 		if (!fs.existsSync(outputDir)) {
 			fs.mkdirSync(outputDir, { recursive: true });
@@ -37,9 +53,15 @@ function generateTaskFiles(tasksPath, outputDir, options = {}) {
 		log('info', `Validating and fixing dependencies`);
 		validateAndFixDependencies(data, tasksPath);
 
+		// Only the requested tasks' files are regenerated, if any were given
+		const tasksToGenerate =
+			options?.ids && options.ids.length > 0
+				? data.tasks.filter((task) => options.ids.includes(task.id))
+				: data.tasks;
+
 		// Generate task files
 		log('info', 'Generating individual task files...');
-		data.tasks.forEach((task) => {
+		tasksToGenerate.forEach((task) => {
 			const taskPath = path.join(
 				outputDir,
 				`task_${task.id.toString().padStart(3, '0')}.txt`
@@ -122,14 +144,16 @@ function generateTaskFiles(tasksPath, outputDir, options = {}) {
 
 		log(
 			'success',
-			`All ${data.tasks.length} tasks have been generated into '${outputDir}'.`
+			tasksToGenerate.length === data.tasks.length
+				? `All ${data.tasks.length} tasks have been generated into '${outputDir}'.`
+				: `${tasksToGenerate.length} of ${data.tasks.length} tasks have been generated into '${outputDir}'.`
 		);
 
 		// Return success data in MCP mode
 		if (isMcpMode) {
 			return {
 				success: true,
-				count: data.tasks.length,
+				count: tasksToGenerate.length,
 				directory: outputDir
 			};
 		}
@@ -1,6 +1,6 @@
 import path from 'path';
 import { log, readJSON, writeJSON } from '../utils.js';
-import { isTaskDependentOn } from '../task-manager.js';
+import isTaskDependentOn from './is-task-dependent.js';
 import generateTaskFiles from './generate-task-files.js';
 
 /**
@@ -131,8 +131,12 @@ async function moveTask(
 					insertIndex++;
 				}
 
-				// Insert the new task at the appropriate position
+				// Insert the new task at the appropriate position, shifting the
+				// source task along if it comes after it
 				data.tasks.splice(insertIndex, 0, newTask);
+				if (!isSourceSubtask && insertIndex <= sourceTaskIndex) {
+					sourceTaskIndex++;
+				}
 				destTaskIndex = insertIndex;
 				destTask = data.tasks[destTaskIndex];
 			} else {
@@ -484,6 +484,16 @@ function displayHelp() {
 					name: 'remove-task',
 					args: '--id=<id> [-y]',
 					desc: 'Permanently remove a task or subtask'
+				},
+				{
+					name: 'move',
+					args: '--from=<id> --to=<id>',
+					desc: 'Move a task or subtask to a new ID or position'
+				},
+				{
+					name: 'add-tag',
+					args: '<name>',
+					desc: 'Add an empty tag (task context) for use with --tag'
 				}
 			]
 		},
@@ -13,6 +13,12 @@ import { getLogLevel, getDebugFlag } from './config-manager.js';
 // Global silent mode flag
 let silentMode = false;
 
+// Tag (task context) that tasks files are read and written in, see setCurrentTag
+let currentTag = null;
+
+// Tag used when none is given, and the one an untagged tasks file holds
+const DEFAULT_TAG = 'master';
+
 // --- Environment Variable Resolution Utility ---
 /**
  * Resolves an environment variable's value.
@@ -173,7 +179,39 @@ function log(level, ...args) {
 }
 
 /**
- * Reads and parses a JSON file
+ * Sets the tag (task context) tasks files are read and written in
+ * @param {string|null} tag - Tag name, or null for the default tag
+ */
+function setCurrentTag(tag) {
+	currentTag = tag || null;
+}
+
+/**
+ * Gets the tag (task context) tasks files are read and written in
+ * @returns {string} The current tag, or the default tag if none is set
+ */
+function getCurrentTag() {
+	return currentTag || DEFAULT_TAG;
+}
+
+/**
+ * Checks whether parsed tasks data is in the tagged layout, where each tag
+ * holds its own { tasks: [...] } instead of a top-level tasks array
+ * @param {Object} data - Parsed JSON data
+ * @returns {boolean} True if the data is in the tagged layout
+ */
+function isTaggedLayout(data) {
+	return (
+		!!data &&
+		typeof data === 'object' &&
+		!Array.isArray(data.tasks) &&
+		Object.values(data).some((value) => Array.isArray(value?.tasks))
+	);
+}
+
+/**
+ * Reads and parses a JSON file. A tasks file in the tagged layout is read
+ * in the current tag, so callers always see { tasks: [...] }.
  * @param {string} filepath - Path to the JSON file
  * @returns {Object|null} Parsed JSON data or null if error occurs
  */
@@ -182,7 +220,18 @@ function readJSON(filepath) {
 	const isDebug = getDebugFlag();
 	try {
 		const rawData = fs.readFileSync(filepath, 'utf8');
-		return JSON.parse(rawData);
+		const data = JSON.parse(rawData);
+		const tag = getCurrentTag();
+		if (isTaggedLayout(data)) {
+			if (!data[tag] || !Array.isArray(data[tag].tasks)) {
+				throw new Error(`Tag "${tag}" not found`);
+			}
+			return data[tag];
+		}
+		if (tag !== DEFAULT_TAG && Array.isArray(data?.tasks)) {
+			throw new Error(`Tag "${tag}" not found`);
+		}
+		return data;
 	} catch (error) {
 		log('error', `Error reading JSON file ${filepath}:`, error.message);
 		if (isDebug) {
@@ -195,7 +244,9 @@ function readJSON(filepath) {
 }
 
 /**
- * Writes data to a JSON file
+ * Writes data to a JSON file. Tasks data is written to the current tag,
+ * keeping the file's other tags; an untagged file becomes tagged, its tasks
+ * kept in the default tag, only when written in another tag.
  * @param {string} filepath - Path to the JSON file
  * @param {Object} data - Data to write
  */
@@ -207,7 +258,24 @@ function writeJSON(filepath, data) {
 		if (!fs.existsSync(dir)) {
 			fs.mkdirSync(dir, { recursive: true });
 		}
-		fs.writeFileSync(filepath, JSON.stringify(data, null, 2), 'utf8');
+		let output = data;
+		if (Array.isArray(data?.tasks)) {
+			const tag = getCurrentTag();
+			let existing = null;
+			try {
+				existing = JSON.parse(fs.readFileSync(filepath, 'utf8'));
+			} catch (error) {
+				// A missing or unreadable file has no other tags to keep
+			}
+			if (isTaggedLayout(existing)) {
+				output = { ...existing, [tag]: data };
+			} else if (tag !== DEFAULT_TAG) {
+				output = Array.isArray(existing?.tasks)
+					? { [DEFAULT_TAG]: existing, [tag]: data }
+					: { [tag]: data };
+			}
+		}
+		fs.writeFileSync(filepath, JSON.stringify(output, null, 2), 'utf8');
 	} catch (error) {
 		log('error', `Error writing JSON file ${filepath}:`, error.message);
 		if (isDebug) {
@@ -535,6 +603,9 @@ export {
 	log,
 	readJSON,
 	writeJSON,
+	setCurrentTag,
+	getCurrentTag,
+	isTaggedLayout,
 	sanitizePrompt,
 	readComplexityReport,
 	findTaskInComplexityReport,
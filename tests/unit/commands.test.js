@@ -138,6 +138,13 @@ describe('Commands Module', () => {
 			expect(program.name()).toBe('dev');
 		});
 
+		test('should take the tag to work in as a program option', () => {
+			const program = setupCLI();
+			expect(program.options.map((option) => option.long)).toContain(
+				'--tag'
+			);
+		});
+
 		test('should read version from package.json when available', () => {
 			mockExistsSync.mockReturnValue(true);
 			mockReadFileSync.mockReturnValue('{"version": "1.0.0"}');
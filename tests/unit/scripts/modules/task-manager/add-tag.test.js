@@ -0,0 +1,73 @@
+/**
+ * Tests for the add-tag.js module
+ */
+import { jest } from '@jest/globals';
+import fs from 'fs';
+import os from 'os';
+import path from 'path';
+
+jest.unstable_mockModule(
+	'../../../../../scripts/modules/config-manager.js',
+	() => ({
+		getLogLevel: jest.fn(() => 'silent'),
+		getDebugFlag: jest.fn(() => false)
+	})
+);
+
+// Import the module under test
+const { default: addTag } = await import(
+	'../../../../../scripts/modules/task-manager/add-tag.js'
+);
+
+describe('addTag', () => {
+	let tasksPath;
+
+	const read = () => JSON.parse(fs.readFileSync(tasksPath, 'utf8'));
+
+	beforeEach(() => {
+		tasksPath = path.join(
+			fs.mkdtempSync(path.join(os.tmpdir(), 'tasks-')),
+			'tasks.json'
+		);
+	});
+
+	test('should keep the tasks of an untagged file in master', () => {
+		fs.writeFileSync(tasksPath, JSON.stringify({ tasks: [{ id: 1 }] }));
+
+		const result = addTag(tasksPath, 'feature-x');
+
+		expect(result.tags).toEqual(['master', 'feature-x']);
+		expect(read()).toEqual({
+			master: { tasks: [{ id: 1 }] },
+			'feature-x': { tasks: [] }
+		});
+	});
+
+	test('should add a tag to a tagged file', () => {
+		fs.writeFileSync(
+			tasksPath,
+			JSON.stringify({ master: { tasks: [{ id: 1 }] } })
+		);
+
+		addTag(tasksPath, 'hotfix');
+
+		expect(read()).toEqual({
+			master: { tasks: [{ id: 1 }] },
+			hotfix: { tasks: [] }
+		});
+	});
+
+	test('should refuse a tag that already exists', () => {
+		fs.writeFileSync(tasksPath, JSON.stringify({ tasks: [{ id: 1 }] }));
+
+		expect(() => addTag(tasksPath, 'master')).toThrow(
+			'Tag "master" already exists'
+		);
+		expect(read()).toEqual({ tasks: [{ id: 1 }] });
+	});
+
+	test('should refuse an invalid tag name', () => {
+		expect(() => addTag(tasksPath, 'my tag')).toThrow('Invalid tag name');
+		expect(fs.existsSync(tasksPath)).toBe(false);
+	});
+});
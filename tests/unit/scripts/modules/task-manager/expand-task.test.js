@@ -0,0 +1,127 @@
+/**
+ * Tests for the expand-task.js module
+ */
+import { jest } from '@jest/globals';
+
+// Mock the dependencies before importing the module under test
+jest.unstable_mockModule('fs', () => ({
+	default: {
+		existsSync: jest.fn(() => false),
+		readFileSync: jest.fn(),
+		writeFileSync: jest.fn()
+	},
+	existsSync: jest.fn(() => false),
+	readFileSync: jest.fn(),
+	writeFileSync: jest.fn()
+}));
+
+jest.unstable_mockModule('../../../../../scripts/modules/utils.js', () => ({
+	log: jest.fn(),
+	readJSON: jest.fn(),
+	writeJSON: jest.fn(),
+	isSilentMode: jest.fn(() => true),
+	findProjectRoot: jest.fn(() => '/mock/project/root')
+}));
+
+jest.unstable_mockModule('../../../../../scripts/modules/ui.js', () => ({
+	startLoadingIndicator: jest.fn(() => ({ stop: jest.fn() })),
+	stopLoadingIndicator: jest.fn()
+}));
+
+jest.unstable_mockModule(
+	'../../../../../scripts/modules/ai-services-unified.js',
+	() => ({
+		generateTextService: jest.fn()
+	})
+);
+
+jest.unstable_mockModule(
+	'../../../../../scripts/modules/config-manager.js',
+	() => ({
+		getDefaultSubtasks: jest.fn(() => 3),
+		getDebugFlag: jest.fn(() => false)
+	})
+);
+
+jest.unstable_mockModule(
+	'../../../../../scripts/modules/task-manager/generate-task-files.js',
+	() => ({
+		default: jest.fn()
+	})
+);
+
+// Import the mocked modules
+const { readJSON, writeJSON } = await import(
+	'../../../../../scripts/modules/utils.js'
+);
+const { generateTextService } = await import(
+	'../../../../../scripts/modules/ai-services-unified.js'
+);
+const { default: generateTaskFiles } = await import(
+	'../../../../../scripts/modules/task-manager/generate-task-files.js'
+);
+
+// Import the module under test
+const { default: expandTask } = await import(
+	'../../../../../scripts/modules/task-manager/expand-task.js'
+);
+
+describe('expandTask', () => {
+	const sampleTasks = {
+		tasks: [
+			{
+				id: 2,
+				title: 'Task 2',
+				description: 'Second task description',
+				status: 'pending',
+				dependencies: [],
+				subtasks: [
+					{
+						id: 3,
+						title: 'Subtask 3',
+						description: 'A subtask',
+						status: 'pending',
+						dependencies: []
+					}
+				]
+			}
+		]
+	};
+
+	const mcpLog = {
+		info: jest.fn(),
+		warn: jest.fn(),
+		error: jest.fn(),
+		debug: jest.fn()
+	};
+
+	beforeEach(() => {
+		jest.clearAllMocks();
+	});
+
+	test('should refuse a subtask ID instead of expanding its parent', async () => {
+		const data = JSON.parse(JSON.stringify(sampleTasks));
+		readJSON.mockReturnValueOnce(data);
+
+		await expect(
+			expandTask('tasks/tasks.json', '2.3', 2, false, '', { mcpLog })
+		).rejects.toThrow(
+			"2.3 is a subtask, which can't have subtasks of its own"
+		);
+
+		// Nothing was asked of the AI and tasks.json is unchanged
+		expect(generateTextService).not.toHaveBeenCalled();
+		expect(writeJSON).not.toHaveBeenCalled();
+		expect(generateTaskFiles).not.toHaveBeenCalled();
+		expect(data).toEqual(sampleTasks);
+	});
+
+	test('should report a task that is not in the file', async () => {
+		readJSON.mockReturnValueOnce(JSON.parse(JSON.stringify(sampleTasks)));
+
+		await expect(
+			expandTask('tasks/tasks.json', '7', 2, false, '', { mcpLog })
+		).rejects.toThrow('Task 7 not found');
+		expect(writeJSON).not.toHaveBeenCalled();
+	});
+});
@@ -319,6 +319,55 @@ describe('generateTaskFiles', () => {
 		}).toThrow('File read failed');
 	});
 
+	test('should generate only the requested tasks when ids are given', async () => {
+		// Set up mocks
+		readJSON.mockImplementationOnce(() => sampleTasks);
+		fs.existsSync.mockImplementationOnce(() => true);
+
+		// Call the function
+		const result = await generateTaskFiles('tasks/tasks.json', 'tasks', {
+			mcpLog: { info: jest.fn() },
+			ids: [1, 3]
+		});
+
+		// Verify only the requested files were written
+		expect(fs.writeFileSync).toHaveBeenCalledTimes(2);
+		expect(fs.writeFileSync).toHaveBeenCalledWith(
+			'tasks/task_001.txt',
+			expect.any(String)
+		);
+		expect(fs.writeFileSync).toHaveBeenCalledWith(
+			'tasks/task_003.txt',
+			expect.any(String)
+		);
+		expect(result.count).toBe(2);
+	});
+
+	test('should reject non-numeric task ids without writing files', () => {
+		// A typo such as --id=1,x reaches the function as NaN
+		readJSON.mockImplementationOnce(() => sampleTasks);
+
+		expect(() => {
+			generateTaskFiles('tasks/tasks.json', 'tasks', {
+				mcpLog: { info: jest.fn() },
+				ids: [1, NaN]
+			});
+		}).toThrow('Invalid task ID(s): NaN');
+		expect(fs.writeFileSync).not.toHaveBeenCalled();
+	});
+
+	test('should reject task ids that are not in the file without writing files', () => {
+		readJSON.mockImplementationOnce(() => sampleTasks);
+
+		expect(() => {
+			generateTaskFiles('tasks/tasks.json', 'tasks', {
+				mcpLog: { info: jest.fn() },
+				ids: [2, 7]
+			});
+		}).toThrow('Task(s) 7 not found in tasks/tasks.json');
+		expect(fs.writeFileSync).not.toHaveBeenCalled();
+	});
+
 	test('should validate dependencies before generating files', async () => {
 		// Set up mocks
 		readJSON.mockImplementationOnce(() => sampleTasks);
@@ -0,0 +1,94 @@
+/**
+ * Tests for the move-task.js module
+ */
+import { jest } from '@jest/globals';
+
+// Mock the dependencies before importing the module under test
+jest.unstable_mockModule('../../../../../scripts/modules/utils.js', () => ({
+	log: jest.fn(),
+	readJSON: jest.fn(),
+	writeJSON: jest.fn()
+}));
+
+jest.unstable_mockModule(
+	'../../../../../scripts/modules/task-manager/generate-task-files.js',
+	() => ({
+		default: jest.fn()
+	})
+);
+
+// Import the mocked modules
+const { readJSON, writeJSON } = await import(
+	'../../../../../scripts/modules/utils.js'
+);
+const { default: generateTaskFiles } = await import(
+	'../../../../../scripts/modules/task-manager/generate-task-files.js'
+);
+
+// Import the module under test
+const { default: moveTask } = await import(
+	'../../../../../scripts/modules/task-manager/move-task.js'
+);
+
+const task = (id, extra = {}) => ({
+	id,
+	title: `Write part ${id}`,
+	description: `Part ${id}`,
+	details: '',
+	status: 'pending',
+	dependencies: [],
+	...extra
+});
+
+describe('moveTask', () => {
+	beforeEach(() => {
+		jest.clearAllMocks();
+	});
+
+	test('should move a task to an unused ID after it', async () => {
+		readJSON.mockReturnValueOnce({
+			tasks: [task(1), task(2), task(3, { dependencies: [2] })]
+		});
+
+		const moved = await moveTask('tasks/tasks.json', '2', '5');
+
+		expect(moved.id).toBe(5);
+		const written = writeJSON.mock.calls[0][1];
+		expect(written.tasks.map((t) => t.id)).toEqual([1, 3, 5]);
+		expect(written.tasks[2].title).toBe('Write part 2');
+		expect(written.tasks[1].dependencies).toEqual([5]);
+		expect(generateTaskFiles).toHaveBeenCalledWith('tasks/tasks.json', 'tasks');
+	});
+
+	test('should move a task to an unused ID before it', async () => {
+		readJSON.mockReturnValueOnce({ tasks: [task(1), task(3), task(4)] });
+
+		await moveTask('tasks/tasks.json', '4', '2', false);
+
+		const written = writeJSON.mock.calls[0][1];
+		expect(written.tasks.map((t) => [t.id, t.title])).toEqual([
+			[1, 'Write part 1'],
+			[2, 'Write part 4'],
+			[3, 'Write part 3']
+		]);
+		expect(generateTaskFiles).not.toHaveBeenCalled();
+	});
+
+	test('should refuse to move a task onto one with content', async () => {
+		readJSON.mockReturnValueOnce({ tasks: [task(1), task(2)] });
+
+		await expect(moveTask('tasks/tasks.json', '1', '2')).rejects.toThrow(
+			'Cannot move to task ID 2 as it already contains content'
+		);
+		expect(writeJSON).not.toHaveBeenCalled();
+	});
+
+	test('should report a source task that is not in the file', async () => {
+		readJSON.mockReturnValueOnce({ tasks: [task(1)] });
+
+		await expect(moveTask('tasks/tasks.json', '7', '9')).rejects.toThrow(
+			'Source task with ID 7 not found'
+		);
+		expect(writeJSON).not.toHaveBeenCalled();
+	});
+});
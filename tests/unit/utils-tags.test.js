@@ -0,0 +1,91 @@
+/**
+ * Tests for reading and writing tasks files in a tag (task context)
+ */
+import { jest } from '@jest/globals';
+import fs from 'fs';
+import os from 'os';
+import path from 'path';
+
+jest.unstable_mockModule('../../scripts/modules/config-manager.js', () => ({
+	getLogLevel: jest.fn(() => 'silent'),
+	getDebugFlag: jest.fn(() => false)
+}));
+
+const { readJSON, writeJSON, setCurrentTag, getCurrentTag } = await import(
+	'../../scripts/modules/utils.js'
+);
+
+describe('tagged tasks files', () => {
+	let tasksPath;
+
+	const write = (data) =>
+		fs.writeFileSync(tasksPath, JSON.stringify(data), 'utf8');
+	const read = () => JSON.parse(fs.readFileSync(tasksPath, 'utf8'));
+
+	beforeEach(() => {
+		tasksPath = path.join(
+			fs.mkdtempSync(path.join(os.tmpdir(), 'tasks-')),
+			'tasks.json'
+		);
+		setCurrentTag(null);
+	});
+
+	test('should default to the master tag', () => {
+		expect(getCurrentTag()).toBe('master');
+	});
+
+	test('should read and write an untagged file as before', () => {
+		write({ tasks: [{ id: 1 }] });
+
+		const data = readJSON(tasksPath);
+		expect(data).toEqual({ tasks: [{ id: 1 }] });
+
+		data.tasks.push({ id: 2 });
+		writeJSON(tasksPath, data);
+		expect(read()).toEqual({ tasks: [{ id: 1 }, { id: 2 }] });
+	});
+
+	test('should read and write the current tag of a tagged file', () => {
+		write({
+			master: { tasks: [{ id: 1 }] },
+			'feature-x': { tasks: [{ id: 7 }] }
+		});
+		setCurrentTag('feature-x');
+
+		const data = readJSON(tasksPath);
+		expect(data).toEqual({ tasks: [{ id: 7 }] });
+
+		data.tasks.push({ id: 8 });
+		writeJSON(tasksPath, data);
+		expect(read()).toEqual({
+			master: { tasks: [{ id: 1 }] },
+			'feature-x': { tasks: [{ id: 7 }, { id: 8 }] }
+		});
+	});
+
+	test('should not read a tag the file does not have', () => {
+		write({ master: { tasks: [{ id: 1 }] } });
+		setCurrentTag('feature-x');
+		expect(readJSON(tasksPath)).toBeNull();
+
+		write({ tasks: [{ id: 1 }] });
+		expect(readJSON(tasksPath)).toBeNull();
+	});
+
+	test('should keep an untagged file in master when writing another tag', () => {
+		write({ tasks: [{ id: 1 }] });
+		setCurrentTag('feature-x');
+
+		writeJSON(tasksPath, { tasks: [{ id: 1 }] });
+		expect(read()).toEqual({
+			master: { tasks: [{ id: 1 }] },
+			'feature-x': { tasks: [{ id: 1 }] }
+		});
+	});
+
+	test('should leave data without tasks alone', () => {
+		setCurrentTag('feature-x');
+		writeJSON(tasksPath, { complexityAnalysis: [] });
+		expect(read()).toEqual({ complexityAnalysis: [] });
+	});
+});
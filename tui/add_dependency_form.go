@@ -2,9 +2,9 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
@@ -13,7 +13,7 @@ import (
 const (
 	addDepFormKeyFile      = "file"
 	addDepFormKeyTaskID    = "id"       // Task ID to add dependency to
-	addDepFormKeyDependsOn = "depends-on" // Task ID that is the dependency
+	addDepFormKeyDependsOn = "depends-on" // Task IDs that become dependencies, comma-separated
 )
 
 // AddDependencyModel holds the state for the add-dependency form.
@@ -23,32 +23,33 @@ type AddDependencyModel struct {
 	isProcessing bool
 	statusMsg    string
 	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
 
 	// Form values
 	FilePath  string
 	TaskID    string
-	DependsOn string
+	DependsOn string // Comma-separated
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
 }
 
 // NewAddDependencyForm creates a new form for the add-dependency command.
 func NewAddDependencyForm() *AddDependencyModel {
-	m := &AddDependencyModel{}
+	m := &AddDependencyModel{
+		FilePath: lastFilePath(), // Pre-populate with the last used tasks file
+	}
 
 	m.form = huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().
-				Key(addDepFormKeyFile).
-				Title("Tasks File Path").
-				Description("Path to the tasks file (e.g., tasks.md).").
-				Prompt("📄 ").
-				Validate(func(s string) error {
-					if s == "" {
-						return fmt.Errorf("tasks file path cannot be empty")
-					}
-					return nil
-				}).
-				Value(&m.FilePath),
-
+			newFilePathField(addDepFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json).", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		huh.NewGroup(
 			huh.NewInput().
 				Key(addDepFormKeyTaskID).
 				Title("Task ID").
@@ -58,29 +59,50 @@ func NewAddDependencyForm() *AddDependencyModel {
 					if s == "" {
 						return fmt.Errorf("task ID cannot be empty")
 					}
-					return nil
+					if err := validateTaskID(s); err != nil {
+						return err
+					}
+					return validateTaskExists(m.FilePath, s)
 				}).
+				SuggestionsFunc(taskIDSuggestions(&m.FilePath, true), &m.FilePath).
 				Value(&m.TaskID),
 
 			huh.NewInput().
 				Key(addDepFormKeyDependsOn).
-				Title("Depends On ID").
-				Description("ID of the task that the above task will depend on (e.g., \"1\").").
+				Title("Depends On IDs").
+				Description("IDs of the tasks that the above task will depend on, comma-separated (e.g., \"1\" or \"1, 2.1\").").
 				Prompt("🔗 ").
 				Validate(func(s string) error {
-					if s == "" {
+					if strings.TrimSpace(s) == "" {
 						return fmt.Errorf("'depends on' ID cannot be empty")
 					}
-					// Could add validation to ensure TaskID and DependsOn are different
-					return nil
+					return validateDependsOn(m.FilePath, m.TaskID, s)
 				}).
+				SuggestionsFunc(taskIDListSuggestions(&m.FilePath, &m.DependsOn, true), []any{&m.FilePath, &m.DependsOn}).
 				Value(&m.DependsOn),
 		),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
 	).WithTheme(huh.ThemeDracula())
 
 	return m
 }
 
+// NewAddDependencyFormFor creates an add-dependency form for the given task,
+// e.g. straight after the task has been added.
+func NewAddDependencyFormFor(filePath, taskID string) *AddDependencyModel {
+	m := NewAddDependencyForm()
+	m.FilePath = filePath
+	m.TaskID = taskID
+	return m
+}
+
+// addDependencyMsg asks the main model to open the add-dependency form for a
+// task.
+type addDependencyMsg struct {
+	filePath string
+	taskID   string
+}
+
 func (m *AddDependencyModel) Init() tea.Cmd {
 	m.isProcessing = false
 	m.statusMsg = ""
@@ -89,39 +111,104 @@ func (m *AddDependencyModel) Init() tea.Cmd {
 }
 
 func (m *AddDependencyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case addDependencyCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			m.statusMsg = "✅ Success!"
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
 	if m.isProcessing {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
-			if keyMsg.String() == "ctrl+c" || keyMsg.String() == "q" {
+			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
+			case "ctrl+c", "q":
 				return m, tea.Quit
 			}
 		}
 		return m, nil
 	}
 
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.statusMsg = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
 	var cmds []tea.Cmd
 	formModel, cmd := m.form.Update(msg)
 	if updatedForm, ok := formModel.(*huh.Form); ok {
 		m.form = updatedForm
 	} else {
 		m.statusMsg = "Error: Form update returned unexpected type."
-		fmt.Fprintf(os.Stderr, "Critical Error: add_dependency_form.go - form update did not return *huh.Form. Got: %T\n", formModel)
+		debugLog.Error("form update did not return *huh.Form", "file", "add_dependency_form.go", "type", fmt.Sprintf("%T", formModel))
 		return m, tea.Quit
 	}
 	cmds = append(cmds, cmd)
 
 	if m.form.State == huh.StateCompleted {
-		if m.TaskID == m.DependsOn && m.TaskID != "" { // Check bound struct fields
-			m.statusMsg = "Error: Task ID and 'Depends On' ID cannot be the same."
+		// The file may have changed since the fields were validated
+		if err := validateTaskExists(m.FilePath, m.TaskID); err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %s", err)
+			m.form.State = huh.StateNormal // Revert to allow correction
+			return m, nil
+		}
+		if err := validateDependsOn(m.FilePath, m.TaskID, m.DependsOn); err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %s", err)
 			m.form.State = huh.StateNormal // Revert to allow correction
-			// Note: Direct field access for error setting is not available in huh v0.7.0
-			// Error handling is managed through form validation state
 			return m, nil
 		}
 
+		if cycle := m.detectCycle(); cycle != nil {
+			m.statusMsg = fmt.Sprintf("Error: Adding this dependency would create a cycle: %s", strings.Join(cycle, " → "))
+			m.form.State = huh.StateNormal // Revert to allow correction
+			return m, nil
+		}
 
 		m.statusMsg = "Executing add-dependency command..."
 		m.isProcessing = true
-		return m, m.executeAddDependencyCommand()
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeAddDependencyCommand))
 	}
 
 	if m.form.State == huh.StateAborted {
@@ -130,14 +217,6 @@ func (m *AddDependencyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
-	case addDependencyCompleteMsg:
-		m.isProcessing = false
-		if msg.result.Success {
-			m.statusMsg = fmt.Sprintf("✅ Success!\n\n%s", msg.result.Output)
-		} else {
-			m.statusMsg = fmt.Sprintf("❌ Error: %s\n\n%s", msg.result.Error, msg.result.Output)
-		}
-		return m, nil
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
@@ -146,8 +225,6 @@ func (m *AddDependencyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, func() tea.Msg { return backToMenuMsg{} }
 			}
 		}
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
 	}
 
 	return m, tea.Batch(cmds...)
@@ -163,18 +240,22 @@ func (m *AddDependencyModel) View() string {
 
 	if m.statusMsg != "" {
 		viewBuilder.WriteString("\n\n")
-		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-		if strings.HasPrefix(m.statusMsg, "Error:") {
-			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
-		}
-		viewBuilder.WriteString(statusStyle.Render(m.statusMsg))
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
 	}
 
 	helpStyle := lipgloss.NewStyle().Faint(true)
 	if m.isProcessing {
-		viewBuilder.WriteString(helpStyle.Render("\n\nProcessing... Press Ctrl+C to force quit."))
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
 	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
-		viewBuilder.WriteString(helpStyle.Render("\n\nCommand completed! Press Esc to return to main menu."))
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
 	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
 		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
 	}
@@ -185,32 +266,104 @@ func (m *AddDependencyModel) View() string {
 		Render(viewBuilder.String())
 }
 
+func (m *AddDependencyModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
 // GetFormValues retrieves the structured data after completion.
 func (m *AddDependencyModel) GetFormValues() (map[string]interface{}, error) {
 	if m.form.State != huh.StateCompleted {
 		return nil, fmt.Errorf("form is not yet completed")
 	}
-	if m.TaskID == m.DependsOn && m.TaskID != "" {
-        return nil, fmt.Errorf("task ID and 'Depends On' ID cannot be the same")
-    }
+	if err := validateDependsOn(m.FilePath, m.TaskID, m.DependsOn); err != nil {
+		return nil, err
+	}
 	return map[string]interface{}{
 		addDepFormKeyFile:      m.FilePath,
 		addDepFormKeyTaskID:    m.TaskID,
 		addDepFormKeyDependsOn: m.DependsOn,
+		advancedFlagsFormKey:   m.AdvancedFlags,
 	}, nil
 }
 
+// dependsOnIDs returns the IDs of the Depends On field, each once.
+func (m *AddDependencyModel) dependsOnIDs() []string {
+	ids, _ := normalizeDependencies(m.DependsOn) // Validated by the field
+	return splitTaskIDs(ids)
+}
+
+// validateDependsOn checks each ID in the comma-separated dependsOn: its form,
+// that it isn't taskID itself and that it's in the tasks file.
+func validateDependsOn(filePath, taskID, dependsOn string) error {
+	ids, err := normalizeDependencies(dependsOn)
+	if err != nil {
+		return err
+	}
+	for _, id := range splitTaskIDs(ids) {
+		if err := validateDependencyPair(taskID, id); err != nil {
+			return err
+		}
+		if err := validateTaskExists(filePath, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// detectCycle checks whether any of the new dependencies would close a cycle
+// in the tasks file. If the file can't be read the check is skipped and the CLI
+// has the final say.
+func (m *AddDependencyModel) detectCycle() []string {
+	tasksFile, err := LoadTasksFile(m.FilePath)
+	if err != nil {
+		return nil
+	}
+	edges := tasksFile.DependencyEdges()
+	for _, dep := range m.dependsOnIDs() {
+		if cycle := findDependencyCycle(edges, m.TaskID, dep); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
 // addDependencyCompleteMsg is sent when the command execution is complete
 type addDependencyCompleteMsg struct {
 	result CLIResult
 }
 
 // executeAddDependencyCommand executes the actual add-dependency CLI command
+// Handles multiple dependencies by running the CLI for each one, in turn
 func (m *AddDependencyModel) executeAddDependencyCommand() tea.Cmd {
-	return func() tea.Msg {
-		result := cliExecutor.AddDependency(m.FilePath, m.TaskID, m.DependsOn)
-		return addDependencyCompleteMsg{result: result}
-	}
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+
+		depIDs := m.dependsOnIDs()
+		if len(depIDs) == 1 {
+			return addDependencyCompleteMsg{result: executor.AddDependency(m.FilePath, m.TaskID, depIDs[0])}
+		}
+
+		var results []string
+		var hasError bool
+		var lastError string
+
+		for i, result := range executor.AddDependencies(m.FilePath, m.TaskID, depIDs) {
+			if result.Success {
+				results = append(results, fmt.Sprintf("✅ Depends on %s: %s", depIDs[i], result.Output))
+			} else {
+				hasError = true
+				lastError = result.Error
+				results = append(results, fmt.Sprintf("❌ Depends on %s: %s", depIDs[i], result.Error))
+			}
+		}
+
+		return addDependencyCompleteMsg{result: CLIResult{
+			Success: !hasError,
+			Error:   lastError,
+			Output:  strings.Join(results, "\n"),
+		}}
+	})
 }
 
 var _ tea.Model = &AddDependencyModel{}
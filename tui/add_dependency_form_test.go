@@ -0,0 +1,73 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+func TestValidateDependsOn(t *testing.T) {
+	path := writeSampleTasks(t)
+
+	tests := []struct {
+		dependsOn string
+		wantErr   string
+	}{
+		{dependsOn: "1"},
+		{dependsOn: "1, 2.1 ,1"},
+		{dependsOn: "1,,2", wantErr: "empty dependency"},
+		{dependsOn: "1, x", wantErr: "invalid task ID"},
+		{dependsOn: "1, 3", wantErr: "cannot be the same"},
+		{dependsOn: "1, 9", wantErr: "task 9 not found"},
+	}
+	for _, tt := range tests {
+		err := validateDependsOn(path, "3", tt.dependsOn)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("validateDependsOn(%q) = %v, want no error", tt.dependsOn, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("validateDependsOn(%q) = %v, want %q", tt.dependsOn, err, tt.wantErr)
+		}
+	}
+}
+
+func TestAddDependencyRunsEachDependency(t *testing.T) {
+	fake := &fakeRunner{stdout: "Added dependency\n"}
+	useFakeRunner(t, fake)
+
+	m := NewAddDependencyFormFor(writeSampleTasks(t), "3")
+	m.DependsOn = "1, 2.1, 1"
+	m.form.State = huh.StateCompleted
+	batch := m.executeAddDependencyCommand()().(tea.BatchMsg)
+	m.Update(batch[len(batch)-1]())
+
+	var ran [][]string
+	for _, call := range fake.calls {
+		ran = append(ran, call[2:])
+	}
+	want := [][]string{
+		{"add-dependency", m.FilePath, "3", "1"},
+		{"add-dependency", m.FilePath, "3", "2.1"},
+	}
+	if !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran %q, want %q", ran, want)
+	}
+	if m.statusMsg != "✅ Success!" || m.result.content != "✅ Depends on 1: Added dependency\n\n✅ Depends on 2.1: Added dependency\n" {
+		t.Errorf("status %q, content %q, want a line per dependency", m.statusMsg, m.result.content)
+	}
+}
+
+func TestAddDependencyRefusesCycle(t *testing.T) {
+	m := NewAddDependencyFormFor(writeSampleTasks(t), "1")
+	m.DependsOn = "3"
+	if cycle := m.detectCycle(); !reflect.DeepEqual(cycle, []string{"1", "3", "2", "1"}) {
+		t.Errorf("detectCycle() = %v, want 1 → 3 → 2 → 1", cycle)
+	}
+	m.DependsOn = "2.1"
+	if cycle := m.detectCycle(); cycle != nil {
+		t.Errorf("detectCycle() = %v for a dependency without a cycle", cycle)
+	}
+}
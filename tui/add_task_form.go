@@ -2,28 +2,43 @@ package main
 
 import (
 	"fmt"
-	"os"
+	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 const (
 	addTaskFormKeyFile          = "file"
-	addTaskFormKeyPrompt        = "prompt" // For AI generation
-	addTaskFormKeyTitle         = "title"  // Manual
-	addTaskFormKeyDescription   = "description" // Manual
-	addTaskFormKeyDetails       = "details" // Manual
+	addTaskFormKeyDestination   = "destination"
+	addTaskFormKeyPrompt        = "prompt"        // For AI generation
+	addTaskFormKeyTitle         = "title"         // Manual
+	addTaskFormKeyDescription   = "description"   // Manual
+	addTaskFormKeyDetails       = "details"       // Manual
 	addTaskFormKeyTestStrategy  = "test-strategy" // Manual
 	addTaskFormKeyDependencies  = "dependencies"
 	addTaskFormKeyPriority      = "priority"
 	addTaskFormKeyType          = "type"
+	addTaskFormKeyCriteria      = "acceptance-criteria"
 	addTaskFormKeyResearch      = "research"
+	addTaskFormKeyResearchModel = "research-model"
+	addTaskFormKeyTag           = "tag"
+	addTaskFormKeyConfirm       = "confirm"
 	// addTaskFormKeyManual        = "manual-creation" // Could be a toggle
 )
 
+// addDependencyKey opens the add-dependency form for the new task once it has
+// been added.
+const addDependencyKey = "a"
+
+// createdTaskPattern finds the new task's ID in the CLI's add-task output.
+var createdTaskPattern = regexp.MustCompile(`Task (\d+) Created Successfully`)
+
 // TaskPriority represents task priority levels.
 type TaskPriority string
 
@@ -48,9 +63,17 @@ type AddTaskModel struct {
 	isProcessing bool
 	statusMsg    string
 	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
+	createdID    string              // ID of the task the command added, if the output says
 
 	// Form values
 	FilePath      string
+	Destination   string // Optional; must be FilePath itself, see CLIExecutor.AddTask
 	Prompt        string // AI prompt
 	Title         string // Manual title
 	Description   string // Manual description
@@ -59,54 +82,68 @@ type AddTaskModel struct {
 	Dependencies  string // Comma-separated IDs
 	Priority      TaskPriority
 	Type          TaskType
+	Criteria      string // Acceptance criteria; required for a manual checkpoint
 	UseResearch   bool
-	// IsManual      bool // If true, show manual fields, else show AI prompt
+	ResearchModel string // Research model for this command only; empty for the configured one
+	Tag           string // Tag to add the task to; empty for the active tag
+	Confirmed     bool   // The summary was reviewed and accepted
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
 }
 
 // NewAddTaskForm creates a new form for the add-task command.
 func NewAddTaskForm() *AddTaskModel {
 	m := &AddTaskModel{
-		Priority:    PriorityMedium, // Default priority
-		Type:        TypeStandard,   // Default type
-		UseResearch: false,
-		// IsManual:    false, // Default to AI prompt
+		FilePath:    lastFilePath(),             // Pre-populate with the last used tasks file
+		Prompt:      LoadLastPrompt("add-task"), // Offer the last prompt again, e.g. after a failure
+		Priority:    PriorityMedium,             // Default priority
+		Type:        TypeStandard,               // Default type
+		UseResearch: defaultResearch(),
 	}
 
-	// Note: The form doesn't dynamically show/hide fields based on IsManual.
-	// All fields are defined. Users should fill relevant ones.
-	// A more complex setup could use form groups that are conditionally shown,
-	// or multiple forms/steps. For this iteration, all fields are available.
+	// A task comes from either an AI prompt or the manual fields, so each group
+	// is hidden while the other is in use
+	tagPick, tagTyped := newTagGroups(addTaskFormKeyTag, &m.FilePath, &m.Tag)
+	review := reviewAddTask()
 
 	m.form = huh.NewForm(
 		huh.NewGroup( // Group 1: File and Core Task Info
+			newFilePathField(addTaskFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json).", &m.FilePath, tasksFileTypes),
 			huh.NewInput().
-				Key(addTaskFormKeyFile).
-				Title("Tasks File Path").
-				Description("Path to the tasks file (e.g., tasks.md).").
+				Key(addTaskFormKeyDestination).
+				Title("Destination File (Optional)").
+				Description("The CLI adds the task to the tasks file above. To add it to another file, choose that file as the tasks file instead.").
 				Prompt("📄 ").
-				Validate(func(s string) error {
-					if s == "" { return fmt.Errorf("file path cannot be empty") }
-					return nil
-				}).
-				Value(&m.FilePath),
+				Validate(func(s string) error { return checkAddTaskDestination(m.FilePath, s) }).
+				Value(&m.Destination),
 		),
+		newTasksFileCheckGroup(&m.FilePath),
+		tagPick,
+		tagTyped,
 		// Group for AI-assisted generation (prompt)
 		huh.NewGroup(
 			huh.NewText(). // Use Text for potentially longer prompts
 				Key(addTaskFormKeyPrompt).
 				Title("AI Prompt for Task (Optional)").
-				Description("Describe the task for AI generation. Leave blank for manual entry of title/description etc.").
+				Description("Describe the task for AI generation. Leave blank to enter the title, description and details yourself.").
 				CharLimit(1000).
 				Value(&m.Prompt),
-		).WithHideFunc(func() bool { return false }), // Always show for now
+		).WithHideFunc(m.usingManualFields),
 
-		// Group for Manual Creation Fields - shown if AI prompt is empty, or always available
+		// Group for Manual Creation Fields - only used without an AI prompt
 		huh.NewGroup(
 			huh.NewInput().
 				Key(addTaskFormKeyTitle).
 				Title("Task Title (Manual)").
 				Description("Enter the task title if not using AI prompt.").
 				Prompt("🏷️ ").
+				// Only shown without a prompt, which is when a title is needed
+				Validate(func(s string) error {
+					if m.Prompt == "" && s == "" {
+						return fmt.Errorf("enter a title, or go back and enter an AI prompt")
+					}
+					return nil
+				}).
 				Value(&m.Title),
 			huh.NewText().
 				Key(addTaskFormKeyDescription).
@@ -124,7 +161,7 @@ func NewAddTaskForm() *AddTaskModel {
 				Title("Test Strategy (Manual, Optional)").
 				Description("How to test this task.").
 				Value(&m.TestStrategy),
-		).Title("Manual Task Details (if AI Prompt is empty or for refinement)"),
+		).Title("Manual Task Details").WithHideFunc(func() bool { return m.Prompt != "" }),
 
 		// Group for Common Task Attributes
 		huh.NewGroup(
@@ -133,16 +170,16 @@ func NewAddTaskForm() *AddTaskModel {
 				Title("Dependencies (Optional)").
 				Description("Comma-separated task IDs (e.g., \"1,2.1,3\").").
 				Prompt("🔗 ").
+				Validate(func(s string) error {
+					_, err := normalizeDependencies(s)
+					return err
+				}).
 				Value(&m.Dependencies),
 
 			huh.NewSelect[TaskPriority]().
 				Key(addTaskFormKeyPriority).
 				Title("Priority").
-				Options(
-					huh.NewOption("High", PriorityHigh),
-					huh.NewOption("Medium", PriorityMedium),
-					huh.NewOption("Low", PriorityLow),
-				).
+				Options(taskPriorityOptions()...).
 				Value(&m.Priority),
 
 			huh.NewSelect[TaskType]().
@@ -162,11 +199,102 @@ func NewAddTaskForm() *AddTaskModel {
 				Negative("No").
 				Value(&m.UseResearch),
 		).Title("Task Attributes"),
+		// The CLI requires acceptance criteria for a checkpoint entered by hand,
+		// and has the AI write them for one from a prompt
+		huh.NewGroup(
+			huh.NewText().
+				Key(addTaskFormKeyCriteria).
+				Title("Acceptance Criteria").
+				Description("Specific, demonstrable criteria that must be met before the checkpoint is done.").
+				CharLimit(2000).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("a checkpoint task needs acceptance criteria")
+					}
+					return nil
+				}).
+				Value(&m.Criteria),
+		).WithHideFunc(func() bool { return !m.needsCriteria() }),
+		newResearchModelGroup(addTaskFormKeyResearchModel, &m.UseResearch, &m.ResearchModel),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
+		// A stray Enter shouldn't start an AI call, so the values are reviewed first
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key(addTaskFormKeyConfirm).
+				Title("Add This Task?").
+				DescriptionFunc(m.summary, []any{&m.FilePath, &m.Tag, &m.Prompt, &m.Title, &m.Description, &m.Details, &m.TestStrategy,
+					&m.Dependencies, &m.Priority, &m.Type, &m.Criteria, &m.UseResearch, &m.ResearchModel, &m.AdvancedFlags}).
+				Affirmative("Yes").
+				Negative("No").
+				Validate(func(confirmed bool) error {
+					if !confirmed {
+						return fmt.Errorf("choose Yes to add the task, or press Esc to cancel")
+					}
+					return nil
+				}).
+				Value(&m.Confirmed),
+		).WithHideFunc(func() bool { return !review }),
 	).WithTheme(huh.ThemeDracula())
 
 	return m
 }
 
+// usingManualFields reports whether the task is being entered by hand rather
+// than from a prompt. A prompt, once typed, takes precedence.
+func (m *AddTaskModel) usingManualFields() bool {
+	return m.Prompt == "" && (m.Title != "" || m.Description != "" || m.Details != "" || m.TestStrategy != "")
+}
+
+// needsCriteria reports whether the task is a checkpoint entered by hand,
+// which needs acceptance criteria.
+func (m *AddTaskModel) needsCriteria() bool {
+	return m.Type == TypeCheckpoint && m.Prompt == ""
+}
+
+// summary lists the values add-task will run with, for review.
+func (m *AddTaskModel) summary() string {
+	var lines []string
+	add := func(label, value string) {
+		if value != "" {
+			lines = append(lines, label+": "+value)
+		}
+	}
+	tag := m.Tag
+	if tag == "" {
+		tag = activeTag()
+	}
+	add("Tasks file", m.FilePath)
+	add("Tag", tag)
+	if m.Prompt != "" {
+		add("AI prompt", m.Prompt)
+	} else {
+		add("Title", m.Title)
+		add("Description", m.Description)
+		add("Details", m.Details)
+		add("Test strategy", m.TestStrategy)
+	}
+	dependencies, _ := normalizeDependencies(m.Dependencies) // Validated by the field
+	if dependencies == "" {
+		dependencies = "none"
+	}
+	add("Dependencies", dependencies)
+	add("Priority", string(m.Priority))
+	add("Type", string(m.Type))
+	if m.needsCriteria() {
+		add("Acceptance criteria", m.Criteria)
+	}
+	research := "no"
+	if m.UseResearch {
+		research = "yes"
+		if m.ResearchModel != "" {
+			research += ", with " + m.ResearchModel
+		}
+	}
+	add("Research", research)
+	add("Advanced flags", m.AdvancedFlags)
+	return strings.Join(lines, "\n")
+}
+
 func (m *AddTaskModel) Init() tea.Cmd {
 	m.isProcessing = false
 	m.statusMsg = ""
@@ -175,47 +303,97 @@ func (m *AddTaskModel) Init() tea.Cmd {
 }
 
 func (m *AddTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case addTaskCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			promptSucceeded("add-task")
+			m.statusMsg = "✅ Success!" + researchBadge(m.UseResearch)
+			content := msg.result.Output
+			if len(msg.result.Data) > 0 {
+				content = msg.result.Message // In JSON mode the output is just the payload
+			}
+			m.result.setContent(content)
+			m.createdID = createdTaskID(msg.result)
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
 	if m.isProcessing {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
-			if keyMsg.String() == "ctrl+c" || keyMsg.String() == "q" { return m, tea.Quit }
+			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
 		}
 		return m, nil
 	}
 
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.statusMsg = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			case addDependencyKey:
+				if m.createdID != "" && strings.HasPrefix(m.statusMsg, "✅") {
+					return m, func() tea.Msg { return addDependencyMsg{filePath: m.FilePath, taskID: m.createdID} }
+				}
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
 	var cmds []tea.Cmd
 	formModel, cmd := m.form.Update(msg)
 	if updatedForm, ok := formModel.(*huh.Form); ok {
 		m.form = updatedForm
 	} else {
 		m.statusMsg = "Error: Form update returned unexpected type."
-		fmt.Fprintf(os.Stderr, "Critical Error: add_task_form.go - form update did not return *huh.Form. Got: %T\n", formModel)
+		debugLog.Error("form update did not return *huh.Form", "file", "add_task_form.go", "type", fmt.Sprintf("%T", formModel))
 		return m, tea.Quit
 	}
 	cmds = append(cmds, cmd)
 
-	// Validation: if prompt is empty, title must be provided.
-	promptIsEmpty := m.form.GetString(addTaskFormKeyPrompt) == ""
-	titleIsEmpty := m.form.GetString(addTaskFormKeyTitle) == ""
-
-	// Note: Direct field access for validation is not available in huh v0.7.0
-	// We'll handle validation through the form's overall validation state
-	if promptIsEmpty && titleIsEmpty {
-		// Set form state to prevent completion until condition is met
-		if m.form.State == huh.StateCompleted { m.form.State = huh.StateNormal }
-	}
-
-
 	if m.form.State == huh.StateCompleted {
-		// Re-check for final submission
-		if m.Prompt == "" && m.Title == "" { // Check bound struct fields
-			m.statusMsg = "Error: Either an AI Prompt or a manual Task Title is required."
-			m.form.State = huh.StateNormal // Revert to allow correction
-			return m, nil
-		}
-
+		SaveLastPrompt("add-task", m.Prompt)
 		m.statusMsg = "Executing add-task command..."
 		m.isProcessing = true
-		return m, m.executeAddTaskCommand()
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeAddTaskCommand))
 	}
 
 	if m.form.State == huh.StateAborted {
@@ -224,14 +402,6 @@ func (m *AddTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
-	case addTaskCompleteMsg:
-		m.isProcessing = false
-		if msg.result.Success {
-			m.statusMsg = fmt.Sprintf("✅ Success!\n\n%s", msg.result.Output)
-		} else {
-			m.statusMsg = fmt.Sprintf("❌ Error: %s\n\n%s", msg.result.Error, msg.result.Output)
-		}
-		return m, nil
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
@@ -239,8 +409,6 @@ func (m *AddTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.aborted = true; return m, func() tea.Msg { return backToMenuMsg{} }
 			}
 		}
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
 	}
 
 	return m, tea.Batch(cmds...)
@@ -254,22 +422,52 @@ func (m *AddTaskModel) View() string {
 
 	if m.statusMsg != "" {
 		viewBuilder.WriteString("\n\n")
-		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-		if strings.HasPrefix(m.statusMsg, "Error:") {
-			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
-		}
-		viewBuilder.WriteString(statusStyle.Render(m.statusMsg))
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
 	}
 
 	helpStyle := lipgloss.NewStyle().Faint(true)
 	if m.isProcessing {
-		viewBuilder.WriteString(helpStyle.Render("\n\nProcessing... Press Ctrl+C to force quit."))
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
+	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		help := m.result.help()
+		if m.createdID != "" {
+			help += fmt.Sprintf(" Press %s to add a dependency to task %s.", addDependencyKey, m.createdID)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + help))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
 	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
 		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
 	}
 	return lipgloss.NewStyle().Width(m.width).Padding(1, 2).Render(viewBuilder.String())
 }
 
+func (m *AddTaskModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
+// addTaskData is the structured result of add-task in JSON mode.
+type addTaskData struct {
+	ID int `json:"id"`
+}
+
+// createdTaskID returns the ID of the task add-task created: from the
+// structured result in JSON mode, otherwise from the text output, or "" if it
+// doesn't say.
+func createdTaskID(result CLIResult) string {
+	if created, err := decodeResultData[addTaskData](result); err == nil && created.ID > 0 {
+		return strconv.Itoa(created.ID)
+	}
+	if match := createdTaskPattern.FindStringSubmatch(ansi.Strip(result.Output)); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
 // addTaskCompleteMsg is sent when the command execution is complete
 type addTaskCompleteMsg struct {
 	result CLIResult
@@ -277,9 +475,15 @@ type addTaskCompleteMsg struct {
 
 // executeAddTaskCommand executes the actual add-task CLI command
 func (m *AddTaskModel) executeAddTaskCommand() tea.Cmd {
-	return func() tea.Msg {
-		result := cliExecutor.AddTask(
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags).WithResearchModel(m.ResearchModel).WithTag(m.Tag).WithJSON()
+
+		result := executor.AddTask(
 			m.FilePath,
+			m.Destination,
 			m.Prompt,
 			m.Title,
 			m.Description,
@@ -288,10 +492,11 @@ func (m *AddTaskModel) executeAddTaskCommand() tea.Cmd {
 			m.Dependencies,
 			string(m.Priority),
 			string(m.Type),
+			m.Criteria,
 			m.UseResearch,
 		)
 		return addTaskCompleteMsg{result: result}
-	}
+	})
 }
 
 var _ tea.Model = &AddTaskModel{}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+func TestAddTaskSummary(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	m := NewAddTaskForm()
+	m.FilePath = "tasks.json"
+	m.Prompt = "Add a login page"
+	m.Title = "Ignored while there is a prompt"
+	m.UseResearch, m.ResearchModel = true, "sonar-pro"
+
+	want := "Tasks file: tasks.json\n" +
+		"AI prompt: Add a login page\n" +
+		"Dependencies: none\n" +
+		"Priority: medium\n" +
+		"Type: standard\n" +
+		"Research: yes, with sonar-pro"
+	if got := m.summary(); got != want {
+		t.Errorf("summary() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestReviewAddTaskSetting(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if !reviewAddTask() {
+		t.Error("reviewAddTask() = false by default, want true")
+	}
+	if err := SaveSettings(Settings{SkipAddTaskReview: true}); err != nil {
+		t.Fatal(err)
+	}
+	if reviewAddTask() {
+		t.Error("reviewAddTask() = true when skipped in the settings")
+	}
+}
+
+func TestCreatedTaskID(t *testing.T) {
+	tests := []struct {
+		result CLIResult
+		want   string
+	}{
+		{result: CLIResult{Data: []byte(`{"id": 12}`)}, want: "12"},
+		{result: CLIResult{Output: "\x1b[1mCreating New Task #12\x1b[0m\n\x1b[37mTask 12 Created Successfully\x1b[0m"}, want: "12"},
+		{result: CLIResult{Output: "Creating New Task"}, want: ""},
+	}
+	for _, tt := range tests {
+		if got := createdTaskID(tt.result); got != tt.want {
+			t.Errorf("createdTaskID(%+v) = %q, want %q", tt.result, got, tt.want)
+		}
+	}
+}
+
+func TestAddDependencyAfterAddTask(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	m := NewAddTaskForm()
+	m.FilePath = "tasks.json"
+	m.form.State = huh.StateCompleted
+	m.Update(addTaskCompleteMsg{result: CLIResult{Success: true, Output: "Task 7 Created Successfully"}})
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(addDependencyKey)})
+	if cmd == nil {
+		t.Fatal("no command for the add-dependency key")
+	}
+	if got, want := cmd(), (addDependencyMsg{filePath: "tasks.json", taskID: "7"}); got != want {
+		t.Errorf("add-dependency key sent %#v, want %#v", got, want)
+	}
+}
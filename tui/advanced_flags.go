@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+)
+
+// advancedFlagsFormKey is the key of the Advanced Flags field, the same on every form.
+const advancedFlagsFormKey = "advanced-flags"
+
+// splitFlags splits s into arguments the way a shell splits words, without
+// any of a shell's expansions: whitespace separates arguments, single and
+// double quotes group them, and a backslash escapes the next character outside
+// single quotes. Commands are run directly rather than through a shell, so
+// the arguments reach the CLI exactly as split; nothing in them is run.
+func splitFlags(s string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg := false // Set once an argument has started, so "" is an empty argument
+	var quote rune // The open quote, or 0
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			arg.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			arg.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unclosed %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("nothing after the final backslash to escape")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// validateAdvancedFlags checks that s splits into arguments.
+func validateAdvancedFlags(s string) error {
+	_, err := splitFlags(s)
+	return err
+}
+
+// newAdvancedFlagsGroup builds the group, placed after a command form's own
+// fields and before any confirmation, that takes extra flags for the CLI: an
+// escape hatch for flags the form doesn't offer. It is hidden unless the
+// settings show advanced flags.
+//
+// The flags are passed on as they are, after the form's own, so they can
+// override its values, change more than the form shows, or make the command
+// fail. Only their quoting is checked.
+func newAdvancedFlagsGroup(value *string) *huh.Group {
+	return huh.NewGroup(
+		huh.NewInput().
+			Key(advancedFlagsFormKey).
+			Title("Advanced Flags (Optional)").
+			Description("Extra CLI flags, quoted as in a shell, e.g. --num=3 --prompt \"two words\". They're passed on unchecked and can override the form's values.").
+			Prompt("⚙️ ").
+			Validate(validateAdvancedFlags).
+			Value(value),
+	).WithHideFunc(func() bool { return !showAdvancedFlags() })
+}
+
+// WithAdvancedFlags returns a copy of the executor that appends the arguments
+// in flags, split by splitFlags, to each CLI command. Flags that don't split
+// are left off; the form field has already refused them.
+func (e *CLIExecutor) WithAdvancedFlags(flags string) *CLIExecutor {
+	extended := *e
+	extended.extraArgs, _ = splitFlags(flags)
+	return &extended
+}
+
+// extraArgsAppended appends the executor's extra arguments, see WithAdvancedFlags.
+func (e *CLIExecutor) extraArgsAppended(args []string) []string {
+	if len(e.extraArgs) == 0 {
+		return args
+	}
+	return append(args[:len(args):len(args)], e.extraArgs...)
+}
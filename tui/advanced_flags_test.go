@@ -0,0 +1,66 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSplitFlags(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{in: ""},
+		{in: "   "},
+		{in: "--num=3  --force", want: []string{"--num=3", "--force"}},
+		{in: `--prompt "two words" --tag 'it''s'`, want: []string{"--prompt", "two words", "--tag", "its"}},
+		{in: `--prompt="say \"hi\""`, want: []string{`--prompt=say "hi"`}},
+		{in: `'a\b' two\ words ""`, want: []string{`a\b`, "two words", ""}},
+		{in: "; rm -rf / $(id)", want: []string{";", "rm", "-rf", "/", "$(id)"}},
+		{in: `--prompt "unclosed`, wantErr: true},
+		{in: `--force \`, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := splitFlags(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("splitFlags(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitFlags(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWithAdvancedFlags(t *testing.T) {
+	fake := &fakeRunner{}
+	useFakeRunner(t, fake)
+
+	cliExecutor.WithTag("feature-x").WithAdvancedFlags(`--prompt "two words"`).ShowTask("tasks.json", "3")
+	cliExecutor.WithAdvancedFlags("").ShowTask("tasks.json", "3")
+	want := [][]string{
+		{"node", "../scripts/dev.js", "show-task", "tasks.json", "3", "--tag", "feature-x", "--prompt", "two words"},
+		{"node", "../scripts/dev.js", "show-task", "tasks.json", "3"},
+	}
+	if !reflect.DeepEqual(fake.calls, want) {
+		t.Errorf("ran %q, want %q", fake.calls, want)
+	}
+}
+
+func TestFormPassesAdvancedFlags(t *testing.T) {
+	fake := &fakeRunner{}
+	useFakeRunner(t, fake)
+
+	m := NewReopenTaskForm()
+	m.FilePath, m.TaskID, m.AdvancedFlags = "tasks.json", "4", "--debug"
+	batch := m.executeReopenTaskCommand()().(tea.BatchMsg)
+	m.Update(batch[len(batch)-1]())
+
+	want := [][]string{{"node", "../scripts/dev.js", "set-task-status", "tasks.json", "4", "in-progress", "--debug"}}
+	if !reflect.DeepEqual(fake.calls, want) {
+		t.Errorf("ran %q, want %q", fake.calls, want)
+	}
+}
@@ -2,10 +2,10 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
@@ -26,21 +26,30 @@ type AnalyzeComplexityModel struct {
 	isProcessing bool
 	statusMsg    string
 	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
 
 	// Form values
-	FilePath         string
-	OutputPath       string
-	LLMModel         string // LLM model name
-	MinComplexity    int    // Minimum complexity score threshold
-	UseResearch      bool
+	FilePath      string
+	OutputPath    string
+	LLMModel      string // LLM model name
+	MinComplexity int    // Minimum complexity score threshold
+	UseResearch   bool
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
 }
 
 // NewAnalyzeComplexityForm creates a new form for the analyze-complexity command.
 func NewAnalyzeComplexityForm() *AnalyzeComplexityModel {
 	m := &AnalyzeComplexityModel{
-		LLMModel:      "gpt-4o", // Default LLM model
-		MinComplexity: 5,        // Default minimum complexity
-		UseResearch:   false,
+		FilePath:      lastFilePath(), // Pre-populate with the last used tasks file
+		LLMModel:      "gpt-4o",       // Default LLM model
+		MinComplexity: 5,              // Default minimum complexity
+		UseResearch:   defaultResearch(),
 	}
 
 	// Temporary string for MinComplexity input
@@ -48,17 +57,10 @@ func NewAnalyzeComplexityForm() *AnalyzeComplexityModel {
 
 	m.form = huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().
-				Key(analyzeComplexityFormKeyFile).
-				Title("Tasks File Path").
-				Description("Path to the input tasks file (e.g., tasks.md).").
-				Prompt("📄 ").
-				Validate(func(s string) error {
-					if s == "" { return fmt.Errorf("tasks file path cannot be empty") }
-					return nil
-				}).
-				Value(&m.FilePath),
-
+			newFilePathField(analyzeComplexityFormKeyFile, "Tasks File Path", "Path to the input tasks file (e.g., tasks.json).", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		huh.NewGroup(
 			huh.NewInput().
 				Key(analyzeComplexityFormKeyOutput).
 				Title("Output Report File Path").
@@ -105,6 +107,7 @@ func NewAnalyzeComplexityForm() *AnalyzeComplexityModel {
 				Negative("No").
 				Value(&m.UseResearch),
 		),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
 	).WithTheme(huh.ThemeDracula())
 
 	return m
@@ -118,20 +121,78 @@ func (m *AnalyzeComplexityModel) Init() tea.Cmd {
 }
 
 func (m *AnalyzeComplexityModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case analyzeComplexityCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			m.statusMsg = "✅ Success!" + researchBadge(m.UseResearch)
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
 	if m.isProcessing {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
-			if keyMsg.String() == "ctrl+c" || keyMsg.String() == "q" { return m, tea.Quit }
+			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
 		}
 		return m, nil
 	}
 
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.statusMsg = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
 	var cmds []tea.Cmd
 	formModel, cmd := m.form.Update(msg)
 	if updatedForm, ok := formModel.(*huh.Form); ok {
 		m.form = updatedForm
 	} else {
 		m.statusMsg = "Error: Form update returned unexpected type."
-		fmt.Fprintf(os.Stderr, "Critical Error: analyze_complexity_form.go - form update did not return *huh.Form. Got: %T\n", formModel)
+		debugLog.Error("form update did not return *huh.Form", "file", "analyze_complexity_form.go", "type", fmt.Sprintf("%T", formModel))
 		return m, tea.Quit
 	}
 	cmds = append(cmds, cmd)
@@ -150,7 +211,7 @@ func (m *AnalyzeComplexityModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		m.statusMsg = "Executing analyze-complexity command..."
 		m.isProcessing = true
-		return m, m.executeAnalyzeComplexityCommand()
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeAnalyzeComplexityCommand))
 	}
 
 	if m.form.State == huh.StateAborted {
@@ -159,14 +220,6 @@ func (m *AnalyzeComplexityModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
-	case analyzeComplexityCompleteMsg:
-		m.isProcessing = false
-		if msg.result.Success {
-			m.statusMsg = fmt.Sprintf("✅ Success!\n\n%s", msg.result.Output)
-		} else {
-			m.statusMsg = fmt.Sprintf("❌ Error: %s\n\n%s", msg.result.Error, msg.result.Output)
-		}
-		return m, nil
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
@@ -174,8 +227,6 @@ func (m *AnalyzeComplexityModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.aborted = true; return m, func() tea.Msg { return backToMenuMsg{} }
 			}
 		}
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
 	}
 
 	return m, tea.Batch(cmds...)
@@ -189,24 +240,30 @@ func (m *AnalyzeComplexityModel) View() string {
 
 	if m.statusMsg != "" {
 		viewBuilder.WriteString("\n\n")
-		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-		if strings.HasPrefix(m.statusMsg, "Error:") {
-			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
-		}
-		viewBuilder.WriteString(statusStyle.Render(m.statusMsg))
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
 	}
 
 	helpStyle := lipgloss.NewStyle().Faint(true)
 	if m.isProcessing {
-		viewBuilder.WriteString(helpStyle.Render("\n\nProcessing... Press Ctrl+C to force quit."))
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
 	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
-		viewBuilder.WriteString(helpStyle.Render("\n\nCommand completed! Press Esc to return to main menu."))
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
 	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
 		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
 	}
 	return lipgloss.NewStyle().Width(m.width).Padding(1, 2).Render(viewBuilder.String())
 }
 
+func (m *AnalyzeComplexityModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
 // GetFormValues retrieves the structured data after completion.
 func (m *AnalyzeComplexityModel) GetFormValues() (map[string]interface{}, error) {
 	if m.form.State != huh.StateCompleted {
@@ -218,6 +275,7 @@ func (m *AnalyzeComplexityModel) GetFormValues() (map[string]interface{}, error)
 		analyzeComplexityFormKeyModel:     m.LLMModel,
 		analyzeComplexityFormKeyThreshold: m.MinComplexity,
 		analyzeComplexityFormKeyResearch:  m.UseResearch,
+		advancedFlagsFormKey:              m.AdvancedFlags,
 	}, nil
 }
 
@@ -228,10 +286,15 @@ type analyzeComplexityCompleteMsg struct {
 
 // executeAnalyzeComplexityCommand executes the actual analyze-complexity CLI command
 func (m *AnalyzeComplexityModel) executeAnalyzeComplexityCommand() tea.Cmd {
-	return func() tea.Msg {
-		result := cliExecutor.AnalyzeComplexity(m.FilePath, m.MinComplexity, m.OutputPath)
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+
+		result := executor.AnalyzeComplexity(m.FilePath, m.MinComplexity, m.OutputPath)
 		return analyzeComplexityCompleteMsg{result: result}
-	}
+	})
 }
 
 var _ tea.Model = &AnalyzeComplexityModel{}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// backupDirName is the directory, next to a tasks file, that holds its backups.
+const backupDirName = ".taskmaster-backups"
+
+// backupRetention is the number of backups kept for each tasks file; older ones are removed.
+const backupRetention = 10
+
+// backupTimeFormat stamps backup names. It sorts in time order and never repeats within a process.
+const backupTimeFormat = "20060102-150405.000000000"
+
+// errNoBackup is returned when there is no backup to restore.
+var errNoBackup = errors.New("no backup found")
+
+// backupDir returns the backup directory for a tasks file.
+func backupDir(tasksPath string) string {
+	return filepath.Join(filepath.Dir(resolveTasksPath(tasksPath)), backupDirName)
+}
+
+// backupTasksFile copies a tasks file into its backup directory before a
+// command changes it, then prunes old backups. A file that doesn't exist yet
+// has nothing to back up.
+func backupTasksFile(tasksPath string) error {
+	src, err := os.Open(resolveTasksPath(tasksPath))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dir := backupDir(tasksPath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	name := filepath.Base(tasksPath) + "." + time.Now().Format(backupTimeFormat)
+	if err := copyToFile(filepath.Join(dir, name), src); err != nil {
+		return err
+	}
+	return pruneBackups(tasksPath, backupRetention)
+}
+
+// listBackups returns the backups of a tasks file, newest first.
+func listBackups(tasksPath string) ([]string, error) {
+	dir := backupDir(tasksPath)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := filepath.Base(tasksPath) + "."
+	var backups []string
+	for _, entry := range entries {
+		stamp, ok := strings.CutPrefix(entry.Name(), prefix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if _, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local); err != nil {
+			continue // Another file's backup whose name shares the prefix
+		}
+		backups = append(backups, filepath.Join(dir, entry.Name()))
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+	return backups, nil
+}
+
+// backupTime returns when a backup was taken, from its name.
+func backupTime(backupPath string) time.Time {
+	name := filepath.Base(backupPath)
+	if len(name) < len(backupTimeFormat) {
+		return time.Time{}
+	}
+	t, _ := time.ParseInLocation(backupTimeFormat, name[len(name)-len(backupTimeFormat):], time.Local)
+	return t
+}
+
+// pruneBackups removes all but the newest keep backups of a tasks file.
+func pruneBackups(tasksPath string, keep int) error {
+	backups, err := listBackups(tasksPath)
+	if err != nil {
+		return err
+	}
+	for len(backups) > keep {
+		if err := os.Remove(backups[len(backups)-1]); err != nil {
+			return err
+		}
+		backups = backups[:len(backups)-1]
+	}
+	return nil
+}
+
+// restoreLatestBackup replaces a tasks file with its newest backup and removes
+// that backup, so restoring again steps back one more change. It returns the
+// restored backup's path.
+func restoreLatestBackup(tasksPath string) (string, error) {
+	backups, err := listBackups(tasksPath)
+	if err != nil {
+		return "", err
+	}
+	if len(backups) == 0 {
+		return "", errNoBackup
+	}
+
+	latest := backups[0]
+	src, err := os.Open(latest)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	if err := copyToFile(resolveTasksPath(tasksPath), src); err != nil {
+		return "", err
+	}
+	return latest, os.Remove(latest)
+}
+
+// copyToFile writes the contents of src to path, replacing it atomically and
+// keeping its permissions if it already exists.
+func copyToFile(path string, src io.Reader) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if _, err := io.Copy(tmp, src); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// UndoLastChange restores the tasks file to its state before the most recent
+// change made through the TUI, while holding the file's lock.
+func (e *CLIExecutor) UndoLastChange(filePath string) CLIResult {
+	lock, err := acquireFileLock(filePath)
+	if errors.Is(err, errLockHeld) {
+		return lockHeldResult(err)
+	}
+	if lock != nil {
+		defer lock.Release()
+	}
+
+	restored, err := restoreLatestBackup(filePath)
+	if err != nil {
+		return CLIResult{
+			Success: false,
+			Error:   err.Error(),
+			Message: fmt.Sprintf("Undo failed: %s", err.Error()),
+		}
+	}
+	return CLIResult{
+		Success: true,
+		Message: "Undo successful",
+		Output:  fmt.Sprintf("Restored %s to its state at %s.", filePath, backupTime(restored).Format("2006-01-02 15:04:05")),
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTasks(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTasks(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	tasksPath := filepath.Join(t.TempDir(), "tasks.json")
+
+	for _, contents := range []string{"v1", "v2", "v3"} {
+		writeTasks(t, tasksPath, contents)
+		if err := backupTasksFile(tasksPath); err != nil {
+			t.Fatalf("backupTasksFile() unexpected error: %v", err)
+		}
+	}
+	writeTasks(t, tasksPath, "v4")
+
+	// Each restore steps back one change
+	for _, want := range []string{"v3", "v2", "v1"} {
+		if _, err := restoreLatestBackup(tasksPath); err != nil {
+			t.Fatalf("restoreLatestBackup() unexpected error: %v", err)
+		}
+		if got := readTasks(t, tasksPath); got != want {
+			t.Errorf("restored contents = %q, want %q", got, want)
+		}
+	}
+	if _, err := restoreLatestBackup(tasksPath); !errors.Is(err, errNoBackup) {
+		t.Errorf("restoreLatestBackup() with no backups error = %v, want errNoBackup", err)
+	}
+}
+
+func TestBackupTasksFileMissing(t *testing.T) {
+	tasksPath := filepath.Join(t.TempDir(), "tasks.json")
+	if err := backupTasksFile(tasksPath); err != nil {
+		t.Fatalf("backupTasksFile() of a missing file unexpected error: %v", err)
+	}
+	if backups, _ := listBackups(tasksPath); len(backups) != 0 {
+		t.Errorf("listBackups() = %q, want none", backups)
+	}
+}
+
+func TestBackupRetention(t *testing.T) {
+	dir := t.TempDir()
+	tasksPath := filepath.Join(dir, "tasks.json")
+	otherPath := filepath.Join(dir, "tasks.json.old")
+	writeTasks(t, tasksPath, "tasks")
+	writeTasks(t, otherPath, "other")
+
+	if err := backupTasksFile(otherPath); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < backupRetention+3; i++ {
+		if err := backupTasksFile(tasksPath); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backups, err := listBackups(tasksPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != backupRetention {
+		t.Errorf("kept %d backups, want %d", len(backups), backupRetention)
+	}
+	if others, _ := listBackups(otherPath); len(others) != 1 {
+		t.Errorf("backups of another file = %q, want 1 left untouched", others)
+	}
+}
+
+func TestLockedCommandBacksUp(t *testing.T) {
+	useFakeRunner(t, &fakeRunner{})
+	tasksPath := filepath.Join(t.TempDir(), "tasks.json")
+	writeTasks(t, tasksPath, "before")
+
+	if result := cliExecutor.SetTaskStatus(tasksPath, "1", "done", false); !result.Success {
+		t.Fatalf("SetTaskStatus() failed: %s", result.Error)
+	}
+	writeTasks(t, tasksPath, "after") // What the CLI would have done
+
+	if result := cliExecutor.UndoLastChange(tasksPath); !result.Success {
+		t.Fatalf("UndoLastChange() failed: %s", result.Error)
+	}
+	if got := readTasks(t, tasksPath); got != "before" {
+		t.Errorf("after undo contents = %q, want %q", got, "before")
+	}
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Command is one step of a batch: a CLI command name and its arguments.
+type Command struct {
+	Name string   `json:"command"`
+	Args []string `json:"args,omitempty"`
+	Line int      `json:"-"` // Where the step is in its batch file, for messages; 0 if unknown
+}
+
+// String returns the step as it would be written in a line-based batch file.
+func (c Command) String() string {
+	fields := []string{c.Name}
+	for _, arg := range c.Args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'#") {
+			arg = strconv.Quote(arg)
+		}
+		fields = append(fields, arg)
+	}
+	return strings.Join(fields, " ")
+}
+
+// batchCommand is a command a batch can run and how it maps onto the executor.
+type batchCommand struct {
+	usage   string // Arguments, for messages
+	minArgs int
+	maxArgs int // -1 for no limit
+	run     func(e *CLIExecutor, args []string) (CLIResult, error)
+}
+
+// batchDefaultNumTasks is how many tasks a batch's parse-prd generates when
+// the step doesn't say, as on the parse-prd form.
+const batchDefaultNumTasks = 5
+
+// batchCommands are the commands a batch can run, by CLI command name. Each
+// runs the executor method a form would, without research.
+var batchCommands = map[string]batchCommand{
+	"parse-prd": {"<prd-file> <tasks-file> [num-tasks]", 2, 3, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		numTasks, err := batchIntArg(a, 2, batchDefaultNumTasks)
+		if err != nil {
+			return CLIResult{}, err
+		}
+		return e.ParsePRD(a[0], a[1], numTasks, false, false), nil
+	}},
+	"add-task": {"<tasks-file> <prompt>", 2, 2, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		return e.AddTask(a[0], "", a[1], "", "", "", "", "", "", "", "", false), nil
+	}},
+	"next-task": {"<tasks-file> [count]", 1, 2, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		count, err := batchIntArg(a, 1, 1)
+		if err != nil {
+			return CLIResult{}, err
+		}
+		return e.NextTasks(a[0], count), nil
+	}},
+	"show-task": {"<tasks-file> <id>", 2, 2, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		return e.ShowTask(a[0], a[1]), nil
+	}},
+	"add-dependency": {"<tasks-file> <id> <depends-on-id>", 3, 3, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		return e.AddDependency(a[0], a[1], a[2]), nil
+	}},
+	"update-tasks": {"<tasks-file> <prompt> [id...]", 2, -1, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		return e.UpdateTasks(a[0], a[1], a[2:], false), nil
+	}},
+	"update-task": {"<tasks-file> <id> <prompt>", 3, 3, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		return e.UpdateOneTask(a[0], a[1], a[2], false), nil
+	}},
+	"update-subtask": {"<tasks-file> <task-id> <subtask-id> <prompt>", 4, 4, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		return e.UpdateSubtask(a[0], a[1], a[2], a[3], false), nil
+	}},
+	"generate-task-files": {"<tasks-file> <output-dir>", 2, 2, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		return e.GenerateTaskFiles(a[0], a[1], nil), nil
+	}},
+	"set-task-status": {"<tasks-file> <id> <status>", 3, 3, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		return e.SetTaskStatus(a[0], a[1], a[2], false), nil
+	}},
+	"set-task-statuses": {"<tasks-file> <id=status,...>", 2, 2, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		assignments, err := parseStatusMapping(a[1])
+		if err != nil {
+			return CLIResult{}, err
+		}
+		return statusMappingResult(assignments, e.SetTaskStatusMapping(a[0], assignments, false)), nil
+	}},
+	"list-tasks": {"<tasks-file> [status]", 1, 2, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		return e.ListTasks(a[0], a[1:], nil, false), nil
+	}},
+	"expand-task": {"<tasks-file> <id> [prompt] [num-subtasks]", 2, 4, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		numSubtasks, err := batchIntArg(a, 3, 0)
+		if err != nil {
+			return CLIResult{}, err
+		}
+		prompt := ""
+		if len(a) > 2 {
+			prompt = a[2]
+		}
+		return e.ExpandTask(a[0], a[1], prompt, numSubtasks, false), nil
+	}},
+	"analyze-complexity": {"<tasks-file>", 1, 1, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		return e.AnalyzeComplexity(a[0], 0, ""), nil
+	}},
+	"clear-subtasks": {"<tasks-file> <id>", 2, 2, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		return e.ClearSubtasks(a[0], a[1]), nil
+	}},
+	"remove-subtask": {"<tasks-file> <subtask-id> [convert]", 2, 3, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		convert := len(a) > 2 && a[2] == "convert"
+		if len(a) > 2 && !convert {
+			return CLIResult{}, fmt.Errorf("%q is not \"convert\"", a[2])
+		}
+		return e.RemoveSubtask(a[0], a[1], convert), nil
+	}},
+	"move-task": {"<tasks-file> <from-id> <to-id>", 3, 3, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		return e.MoveTask(a[0], a[1], a[2]), nil
+	}},
+	"duplicate-task": {"<tasks-file> <id> [title]", 2, 3, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		title := ""
+		if len(a) > 2 {
+			title = a[2]
+		}
+		return e.DuplicateTask(a[0], a[1], title), nil
+	}},
+	"set-priority": {"<tasks-file> <id> <priority>", 3, 3, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		return e.SetPriority(a[0], a[1], a[2]), nil
+	}},
+	"validate-dependencies": {"<tasks-file>", 1, 1, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		return e.ValidateDependencies(a[0]), nil
+	}},
+	"fix-dependencies": {"<tasks-file>", 1, 1, func(e *CLIExecutor, a []string) (CLIResult, error) {
+		return e.FixDependencies(a[0]), nil
+	}},
+}
+
+// batchIntArg returns args[i] as a positive number, or def if the step leaves it out.
+func batchIntArg(args []string, i, def int) (int, error) {
+	if i >= len(args) || args[i] == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(args[i])
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%q is not a positive number", args[i])
+	}
+	return n, nil
+}
+
+// checkCommand checks that a batch can run c, so a mistake is found before
+// any step of the batch has run.
+func checkCommand(c Command) error {
+	spec, ok := batchCommands[c.Name]
+	if !ok {
+		return fmt.Errorf("unknown command %q", c.Name)
+	}
+	if len(c.Args) < spec.minArgs || (spec.maxArgs >= 0 && len(c.Args) > spec.maxArgs) {
+		return fmt.Errorf("usage: %s %s", c.Name, spec.usage)
+	}
+	return nil
+}
+
+// LoadBatchFile reads the steps of a batch file. A .json file holds an array
+// of {"command": ..., "args": [...]} objects. Any other file has one step per
+// line, written as the command name and its arguments separated by spaces;
+// arguments with spaces are double-quoted, and blank lines and lines starting
+// with # are skipped. Every step is checked before any is returned.
+func LoadBatchFile(path string) ([]Command, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var commands []Command
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.Unmarshal(data, &commands); err != nil {
+			return nil, fmt.Errorf("failed to parse batch file: %w", err)
+		}
+		for i := range commands {
+			commands[i].Line = i + 1
+		}
+	} else if commands, err = parseBatchLines(string(data)); err != nil {
+		return nil, err
+	}
+
+	if len(commands) == 0 {
+		return nil, fmt.Errorf("batch file has no commands")
+	}
+	for _, c := range commands {
+		if err := checkCommand(c); err != nil {
+			return nil, fmt.Errorf("step %d: %w", c.Line, err)
+		}
+	}
+	return commands, nil
+}
+
+// parseBatchLines parses the steps of a line-based batch file.
+func parseBatchLines(text string) ([]Command, error) {
+	var commands []Command
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields, err := splitBatchLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		commands = append(commands, Command{Name: fields[0], Args: fields[1:], Line: lineNum})
+	}
+	return commands, scanner.Err()
+}
+
+// splitBatchLine splits a line into fields at spaces, keeping double-quoted
+// fields together. Quoted fields use Go string escapes.
+func splitBatchLine(line string) ([]string, error) {
+	var fields []string
+	for line = strings.TrimLeft(line, " \t"); line != ""; line = strings.TrimLeft(line, " \t") {
+		if line[0] != '"' {
+			end := strings.IndexAny(line, " \t")
+			if end < 0 {
+				end = len(line)
+			}
+			fields = append(fields, line[:end])
+			line = line[end:]
+			continue
+		}
+
+		quoted, err := strconv.QuotedPrefix(line)
+		if err != nil {
+			return nil, fmt.Errorf("unterminated quote")
+		}
+		field, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+		line = line[len(quoted):]
+	}
+	return fields, nil
+}
+
+// BatchRun runs the steps of a batch in order and combines their results,
+// each step's output under the step. It stops at the first step that fails
+// unless continueOnError is set, and the combined result fails if any step
+// did. Each step takes the lock on its own tasks file, as from its form, and
+// the batch stops if the executor's context is cancelled.
+func (e *CLIExecutor) BatchRun(commands []Command, continueOnError bool) CLIResult {
+	merged := CLIResult{Success: true, Message: "Batch completed successfully"}
+	var sections []string
+	failed := 0
+	for i, c := range commands {
+		if e.ctx != nil && e.ctx.Err() != nil {
+			sections = append(sections, fmt.Sprintf("Cancelled before step %d.", i+1))
+			merged.Success = false
+			merged.Error = e.ctx.Err().Error()
+			break
+		}
+
+		result := e.runBatchStep(c)
+		status := "✅"
+		if !result.Success {
+			status = "❌"
+			failed++
+			merged.Success = false
+			merged.Error = fmt.Sprintf("step %d (%s) failed: %s", i+1, c.Name, result.Error)
+		}
+		sections = append(sections, fmt.Sprintf("%s [%d/%d] %s\n%s", status, i+1, len(commands), c, strings.TrimRight(result.Output, "\n")))
+
+		if !result.Success && !continueOnError {
+			if i+1 < len(commands) {
+				sections = append(sections, fmt.Sprintf("Stopped after step %d; %d step(s) not run.", i+1, len(commands)-i-1))
+			}
+			break
+		}
+	}
+
+	if failed > 0 {
+		merged.Message = fmt.Sprintf("Batch failed: %d step(s) failed", failed)
+	}
+	merged.Output = strings.Join(sections, "\n\n")
+	return merged
+}
+
+// runBatchStep runs one step of a batch.
+func (e *CLIExecutor) runBatchStep(c Command) CLIResult {
+	err := checkCommand(c)
+	var result CLIResult
+	if err == nil {
+		result, err = batchCommands[c.Name].run(e, c.Args)
+	}
+	if err != nil {
+		return CLIResult{Success: false, Error: err.Error(), Message: err.Error(), Output: "Error: " + err.Error()}
+	}
+	return result
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	batchFormKeyFile     = "file"
+	batchFormKeyContinue = "continue"
+	batchFormKeyConfirm  = "confirm"
+)
+
+// batchPreviewSteps is how many steps the confirm lists before summarizing the rest.
+const batchPreviewSteps = 8
+
+// BatchModel holds the state for the run batch form.
+type BatchModel struct {
+	form         *huh.Form
+	aborted      bool
+	isProcessing bool
+	statusMsg    string
+	width        int
+	height       int
+	output       liveOutput          // Output streamed while the batch runs
+	spinner      processingIndicator // Animated while the batch runs
+	result       resultView          // Scrollable output of a successful batch
+	cancel       commandCancel       // Cancels the running batch on Esc
+	last         lastRun             // Starts a failed command again on r
+
+	// Form values
+	FilePath        string
+	ContinueOnError bool // Run the remaining steps after one fails
+	Confirmed       bool // Must be set before the batch runs
+}
+
+// NewBatchForm creates a new form for running a batch file of commands.
+func NewBatchForm() *BatchModel {
+	m := &BatchModel{}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			newFilePathField(batchFormKeyFile, "Batch File Path", "File of commands to run, one per line or as a JSON array.", &m.FilePath, batchFileTypes),
+		),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key(batchFormKeyContinue).
+				Title("Continue After Failures").
+				Description("Run the remaining commands when one fails? Otherwise the batch stops at the first failure.").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&m.ContinueOnError),
+		),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key(batchFormKeyConfirm).
+				Title("Run Batch").
+				DescriptionFunc(m.confirmDescription, &m.FilePath).
+				Affirmative("Run").
+				Negative("No").
+				Validate(func(run bool) error {
+					if !run {
+						return nil
+					}
+					_, err := LoadBatchFile(m.FilePath)
+					return err
+				}).
+				Value(&m.Confirmed), // Defaults to No
+		),
+	).WithTheme(huh.ThemeDracula())
+
+	return m
+}
+
+// confirmDescription lists the steps the batch will run, or why it can't.
+func (m *BatchModel) confirmDescription() string {
+	commands, err := LoadBatchFile(m.FilePath)
+	if err != nil {
+		return fmt.Sprintf("The batch file can't be run: %v", err)
+	}
+
+	lines := []string{fmt.Sprintf("Run these %d command(s) in order?", len(commands))}
+	for i, c := range commands {
+		if i == batchPreviewSteps {
+			lines = append(lines, fmt.Sprintf("  ...and %d more", len(commands)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("  %d. %s", i+1, c))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *BatchModel) Init() tea.Cmd {
+	m.isProcessing = false
+	m.statusMsg = ""
+	m.aborted = false
+	return m.form.Init()
+}
+
+func (m *BatchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case batchCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		m.cancel.finish()
+		m.last.finish(msg.result.Success)
+		// A cancelled batch still reports the steps that ran
+		if msg.result.Success {
+			m.statusMsg = "✅ Success!"
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
+	if m.isProcessing {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "esc":
+				// Kill the running step and skip the rest, but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, nil
+	}
+
+	// Once the batch has run, keep showing its result rather than letting the
+	// completed form run it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.statusMsg = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
+	var cmds []tea.Cmd
+	formModel, cmd := m.form.Update(msg)
+	if updatedForm, ok := formModel.(*huh.Form); ok {
+		m.form = updatedForm
+	} else {
+		m.statusMsg = "Error: Form update returned unexpected type."
+		debugLog.Error("form update did not return *huh.Form", "file", "batch_form.go", "type", fmt.Sprintf("%T", formModel))
+		return m, tea.Quit
+	}
+	cmds = append(cmds, cmd)
+
+	if m.form.State == huh.StateCompleted {
+		if !m.Confirmed {
+			// Declining the confirm runs nothing
+			m.aborted = true
+			return m, func() tea.Msg { return backToMenuMsg{} }
+		}
+
+		// Read again in case the file changed since the confirm was shown
+		commands, err := LoadBatchFile(m.FilePath)
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %v", err)
+			return m, nil
+		}
+
+		m.statusMsg = fmt.Sprintf("Running %d command(s)...", len(commands))
+		m.isProcessing = true
+		return m, tea.Batch(m.spinner.start(), m.last.run(func() tea.Cmd { return m.executeBatchCommand(commands) }))
+	}
+
+	if m.form.State == huh.StateAborted {
+		m.aborted = true
+		return m, func() tea.Msg { return backToMenuMsg{} }
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if !m.isProcessing {
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			}
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *BatchModel) View() string {
+	if m.aborted {
+		return "Form aborted. Returning to main menu..."
+	}
+
+	var viewBuilder strings.Builder
+	viewBuilder.WriteString(m.form.View())
+
+	if m.statusMsg != "" {
+		viewBuilder.WriteString("\n\n")
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
+	}
+
+	helpStyle := lipgloss.NewStyle().Faint(true)
+	if m.isProcessing {
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
+	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
+	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Padding(1, 2).
+		Render(viewBuilder.String())
+}
+
+func (m *BatchModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
+// GetFormValues retrieves the structured data after completion.
+func (m *BatchModel) GetFormValues() (map[string]interface{}, error) {
+	if m.form.State != huh.StateCompleted {
+		return nil, fmt.Errorf("form is not yet completed")
+	}
+	return map[string]interface{}{
+		batchFormKeyFile:     m.FilePath,
+		batchFormKeyContinue: m.ContinueOnError,
+		batchFormKeyConfirm:  m.Confirmed,
+	}, nil
+}
+
+// batchCompleteMsg is sent when the batch has finished
+type batchCompleteMsg struct {
+	result CLIResult
+}
+
+// executeBatchCommand runs the steps of the batch
+func (m *BatchModel) executeBatchCommand(commands []Command) tea.Cmd {
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx)
+		return batchCompleteMsg{result: executor.BatchRun(commands, m.ContinueOnError)}
+	})
+}
+
+var _ tea.Model = &BatchModel{}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitBatchLine(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{line: "set-task-status tasks.json 3 done", want: []string{"set-task-status", "tasks.json", "3", "done"}},
+		{line: `add-task  tasks.json "Add a \"login\" page"`, want: []string{"add-task", "tasks.json", `Add a "login" page`}},
+		{line: `expand-task tasks.json 3 "" 5`, want: []string{"expand-task", "tasks.json", "3", "", "5"}},
+		{line: `add-task tasks.json "unterminated`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := splitBatchLine(tt.line)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("splitBatchLine(%q) error = %v, wantErr %v", tt.line, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitBatchLine(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestLoadBatchFile(t *testing.T) {
+	dir := t.TempDir()
+	lines := filepath.Join(dir, "steps.txt")
+	writeTasks(t, lines, "# Plan the release\nparse-prd prd.txt tasks.json 8\n\nset-task-status tasks.json 1 done\n")
+	jsonFile := filepath.Join(dir, "steps.json")
+	writeTasks(t, jsonFile, `[{"command": "parse-prd", "args": ["prd.txt", "tasks.json", "8"]}, {"command": "set-task-status", "args": ["tasks.json", "1", "done"]}]`)
+
+	want := []Command{
+		{Name: "parse-prd", Args: []string{"prd.txt", "tasks.json", "8"}},
+		{Name: "set-task-status", Args: []string{"tasks.json", "1", "done"}},
+	}
+	for _, path := range []string{lines, jsonFile} {
+		got, err := LoadBatchFile(path)
+		if err != nil {
+			t.Fatalf("LoadBatchFile(%s) unexpected error: %v", filepath.Base(path), err)
+		}
+		for i := range got {
+			got[i].Line = 0
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("LoadBatchFile(%s) = %+v, want %+v", filepath.Base(path), got, want)
+		}
+	}
+
+	bad := filepath.Join(dir, "bad.txt")
+	writeTasks(t, bad, "next-task tasks.json\nshow-task tasks.json\n")
+	if _, err := LoadBatchFile(bad); err == nil || !strings.Contains(err.Error(), "step 2") {
+		t.Errorf("LoadBatchFile() with a missing argument error = %v, want one naming step 2", err)
+	}
+	writeTasks(t, bad, "deploy tasks.json\n")
+	if _, err := LoadBatchFile(bad); err == nil {
+		t.Error("LoadBatchFile() with an unknown command succeeded, want an error")
+	}
+	if _, err := LoadBatchFile(filepath.Join(dir, "missing.txt")); !os.IsNotExist(err) {
+		t.Errorf("LoadBatchFile() of a missing file error = %v, want not exist", err)
+	}
+}
+
+func TestBatchRun(t *testing.T) {
+	commands := []Command{
+		{Name: "show-task", Args: []string{"tasks.json", "1"}},
+		{Name: "show-task", Args: []string{"tasks.json", "2"}},
+		{Name: "next-task", Args: []string{"tasks.json"}},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		fake := &fakeRunner{stdout: "ok\n"}
+		useFakeRunner(t, fake)
+		result := cliExecutor.BatchRun(commands, false)
+		if !result.Success || len(fake.calls) != 3 {
+			t.Fatalf("BatchRun() = %+v after %d commands, want success after 3", result, len(fake.calls))
+		}
+		if !strings.Contains(result.Output, "✅ [3/3] next-task tasks.json\nok") {
+			t.Errorf("Output = %q, want each step with its output", result.Output)
+		}
+	})
+
+	t.Run("stops at first failure", func(t *testing.T) {
+		fake := &fakeRunner{err: errors.New("exit status 1"), exitCode: 1}
+		useFakeRunner(t, fake)
+		result := cliExecutor.BatchRun(commands, false)
+		if result.Success || len(fake.calls) != 1 {
+			t.Fatalf("BatchRun() ran %d commands, success %v, want failure after 1", len(fake.calls), result.Success)
+		}
+		if !strings.Contains(result.Output, "2 step(s) not run") {
+			t.Errorf("Output = %q, want the skipped steps reported", result.Output)
+		}
+	})
+
+	t.Run("continues after failure", func(t *testing.T) {
+		fake := &fakeRunner{err: errors.New("exit status 1"), exitCode: 1}
+		useFakeRunner(t, fake)
+		result := cliExecutor.BatchRun(commands, true)
+		if result.Success || len(fake.calls) != 3 {
+			t.Fatalf("BatchRun() ran %d commands, success %v, want failure after 3", len(fake.calls), result.Success)
+		}
+	})
+}
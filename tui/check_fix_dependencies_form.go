@@ -0,0 +1,334 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	checkFixDepsFormKeyFile = "file"
+)
+
+// fixIssuesKey confirms running fix-dependencies on the issues the check found.
+const fixIssuesKey = "y"
+
+// dependencyIssuePattern matches an issue in validate-dependencies output,
+// e.g. "[MISSING] Task 4: Dependency 9 does not exist".
+var dependencyIssuePattern = regexp.MustCompile(`\[[A-Z_]+\] Task \S+: .*`)
+
+// CheckFixDependenciesModel holds the state for the check and fix
+// dependencies form, which validates the dependencies and then, once the user
+// confirms, fixes the issues found and shows them before and after.
+type CheckFixDependenciesModel struct {
+	form         *huh.Form
+	aborted      bool
+	isProcessing bool
+	statusMsg    string
+	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
+	issues       []string            // Found by the check, waiting for confirmation to fix
+	fixed        bool                // The fix has run
+
+	// Form value
+	FilePath string
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
+}
+
+// NewCheckFixDependenciesForm creates a new form that runs validate-dependencies and then fix-dependencies.
+func NewCheckFixDependenciesForm() *CheckFixDependenciesModel {
+	m := &CheckFixDependenciesModel{
+		FilePath: lastFilePath(), // Pre-populate with the last used tasks file
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			newFilePathField(checkFixDepsFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json) to check. It is only modified if you confirm fixing the issues found.", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
+	).WithTheme(huh.ThemeDracula())
+
+	return m
+}
+
+func (m *CheckFixDependenciesModel) Init() tea.Cmd {
+	m.isProcessing = false
+	m.statusMsg = ""
+	m.aborted = false
+	return m.form.Init()
+}
+
+func (m *CheckFixDependenciesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case checkDependenciesCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			m.issues = dependencyIssues(msg.result.Output)
+			m.fixed = false
+			if len(m.issues) == 0 {
+				m.statusMsg = "✅ Success! No dependency issues found."
+			} else {
+				m.statusMsg = fmt.Sprintf("✅ Found %d dependency issue(s).", len(m.issues))
+			}
+			m.result.setContent(highlightDependencyIssues(msg.result.Output))
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	case fixCheckedDependenciesCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled."
+			return m, nil // The issues are still there to fix
+		}
+		if !msg.fix.Success {
+			m.statusMsg = renderResult(msg.fix)
+			return m, nil
+		}
+		m.fixed = true
+		m.statusMsg = "✅ Success! Dependencies fixed."
+		if !msg.after.Success {
+			m.statusMsg += " Checking them again failed, so only the changes are shown."
+			m.result.setContent(summarizeDependencyFixes(msg.fix.Output))
+			return m, nil
+		}
+		m.result.setContent(renderDependencyRepair(m.issues, dependencyIssues(msg.after.Output), msg.fix.Output))
+		return m, nil
+	}
+
+	if m.isProcessing {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, nil
+	}
+
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.statusMsg = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			case fixIssuesKey:
+				if m.canFix() {
+					m.statusMsg = "Executing fix-dependencies command..."
+					m.isProcessing = true
+					return m, tea.Batch(m.spinner.start(), m.executeFixDependenciesCommand())
+				}
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
+	var cmds []tea.Cmd
+	formModel, cmd := m.form.Update(msg)
+	if updatedForm, ok := formModel.(*huh.Form); ok {
+		m.form = updatedForm
+	} else {
+		m.statusMsg = "Error: Form update returned unexpected type."
+		debugLog.Error("form update did not return *huh.Form", "file", "check_fix_dependencies_form.go", "type", fmt.Sprintf("%T", formModel))
+		return m, tea.Quit
+	}
+	cmds = append(cmds, cmd)
+
+	if m.form.State == huh.StateCompleted {
+		m.statusMsg = "Executing validate-dependencies command..."
+		m.isProcessing = true
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeCheckDependenciesCommand))
+	}
+
+	if m.form.State == huh.StateAborted {
+		m.aborted = true
+		return m, func() tea.Msg { return backToMenuMsg{} }
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if !m.isProcessing {
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			}
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+// canFix reports whether the check found issues that haven't been fixed yet.
+func (m *CheckFixDependenciesModel) canFix() bool {
+	return len(m.issues) > 0 && !m.fixed
+}
+
+func (m *CheckFixDependenciesModel) View() string {
+	if m.aborted {
+		return "Form aborted. Returning to main menu..."
+	}
+
+	var viewBuilder strings.Builder
+	viewBuilder.WriteString(m.form.View())
+
+	if m.statusMsg != "" {
+		viewBuilder.WriteString("\n\n")
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
+	}
+
+	helpStyle := lipgloss.NewStyle().Faint(true)
+	if m.isProcessing {
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
+	} else if m.form.State == huh.StateCompleted && m.result.View() != "" {
+		viewBuilder.WriteString("\n\n" + m.result.View())
+		help := m.result.help()
+		if m.canFix() {
+			help = fmt.Sprintf("Press %s to fix these issues with fix-dependencies, which modifies the tasks file. ", fixIssuesKey) + help
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + help))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
+	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Padding(1, 2).
+		Render(viewBuilder.String())
+}
+
+func (m *CheckFixDependenciesModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
+// GetFormValues retrieves the structured data after completion.
+func (m *CheckFixDependenciesModel) GetFormValues() (map[string]interface{}, error) {
+	if m.form.State != huh.StateCompleted {
+		return nil, fmt.Errorf("form is not yet completed")
+	}
+	return map[string]interface{}{
+		checkFixDepsFormKeyFile: m.FilePath,
+		advancedFlagsFormKey:    m.AdvancedFlags,
+	}, nil
+}
+
+// dependencyIssues returns the issues reported in validate-dependencies
+// output, one per line, without the log prefix.
+func dependencyIssues(output string) []string {
+	var issues []string
+	for _, line := range strings.Split(ansi.Strip(output), "\n") {
+		if issue := dependencyIssuePattern.FindString(line); issue != "" {
+			issues = append(issues, strings.TrimSpace(issue))
+		}
+	}
+	return issues
+}
+
+// renderDependencyRepair shows the dependency issues before and after a fix,
+// followed by the changes fix-dependencies made and its output.
+func renderDependencyRepair(before, after []string, fixOutput string) string {
+	section := func(title string, issues []string) string {
+		if len(issues) == 0 {
+			return fixSummaryHeaderStyle.Render(title + ": no dependency issues")
+		}
+		lines := []string{fixSummaryHeaderStyle.Render(fmt.Sprintf("%s: %d dependency issue(s)", title, len(issues)))}
+		for _, issue := range issues {
+			lines = append(lines, dependencyIssueStyle.Render("  "+issue))
+		}
+		return strings.Join(lines, "\n")
+	}
+	return section("Before", before) + "\n\n" + section("After", after) + "\n\n" + summarizeDependencyFixes(fixOutput)
+}
+
+// checkDependenciesCompleteMsg is sent when the validate-dependencies command is complete
+type checkDependenciesCompleteMsg struct {
+	result CLIResult
+}
+
+// fixCheckedDependenciesCompleteMsg is sent when the fix-dependencies command,
+// and the check that follows it, are complete
+type fixCheckedDependenciesCompleteMsg struct {
+	fix   CLIResult
+	after CLIResult // Of validate-dependencies once fixed; unset if the fix failed
+}
+
+// executeCheckDependenciesCommand executes the validate-dependencies CLI command
+func (m *CheckFixDependenciesModel) executeCheckDependenciesCommand() tea.Cmd {
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+
+		result := executor.ValidateDependencies(m.FilePath)
+		return checkDependenciesCompleteMsg{result: result}
+	})
+}
+
+// executeFixDependenciesCommand executes the fix-dependencies CLI command and
+// then checks the dependencies again
+func (m *CheckFixDependenciesModel) executeFixDependenciesCommand() tea.Cmd {
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+
+		fix := executor.FixDependencies(m.FilePath)
+		if !fix.Success {
+			return fixCheckedDependenciesCompleteMsg{fix: fix}
+		}
+		return fixCheckedDependenciesCompleteMsg{fix: fix, after: executor.ValidateDependencies(m.FilePath)}
+	})
+}
+
+var _ tea.Model = &CheckFixDependenciesModel{}
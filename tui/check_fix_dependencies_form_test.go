@@ -0,0 +1,69 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const failedValidation = "[INFO] Checking for invalid dependencies in task files...\n" +
+	"\x1b[31m[ERROR]   [CIRCULAR] Task 3: Circular dependency detected\x1b[39m\n" +
+	"[ERROR]   [MISSING] Task 4: Dependency 9 does not exist (Dependency: 9)\n" +
+	"Dependency Validation FAILED"
+
+func TestDependencyIssues(t *testing.T) {
+	want := []string{
+		"[CIRCULAR] Task 3: Circular dependency detected",
+		"[MISSING] Task 4: Dependency 9 does not exist (Dependency: 9)",
+	}
+	if got := dependencyIssues(failedValidation); !reflect.DeepEqual(got, want) {
+		t.Errorf("dependencyIssues() = %q, want %q", got, want)
+	}
+	if got := dependencyIssues("[SUCCESS] No invalid dependencies found - all dependencies are valid"); got != nil {
+		t.Errorf("dependencyIssues(valid) = %q, want none", got)
+	}
+}
+
+func TestRenderDependencyRepair(t *testing.T) {
+	got := ansi.Strip(renderDependencyRepair(dependencyIssues(failedValidation), nil, "[WARN] Removing invalid task dependency from task 4: 9"))
+	want := "Before: 2 dependency issue(s)\n" +
+		"  [CIRCULAR] Task 3: Circular dependency detected\n" +
+		"  [MISSING] Task 4: Dependency 9 does not exist (Dependency: 9)\n\n" +
+		"After: no dependency issues\n\n" +
+		"1 dependency change(s):\n- Removing invalid task dependency from task 4: 9\n\n" +
+		"[WARN] Removing invalid task dependency from task 4: 9"
+	if got != want {
+		t.Errorf("renderDependencyRepair() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCheckFixDependenciesFixesOnConfirmation(t *testing.T) {
+	fake := &fakeRunner{stdout: failedValidation}
+	useFakeRunner(t, fake)
+	path := writeSampleTasks(t)
+
+	m := NewCheckFixDependenciesForm()
+	m.FilePath = path
+	batch := m.executeCheckDependenciesCommand()().(tea.BatchMsg)
+	m.Update(batch[len(batch)-1]())
+	if !m.canFix() {
+		t.Fatalf("canFix() = false after the check found issues, status %q", m.statusMsg)
+	}
+
+	batch = m.executeFixDependenciesCommand()().(tea.BatchMsg)
+	m.Update(batch[len(batch)-1]())
+
+	var commands []string
+	for _, call := range fake.calls {
+		commands = append(commands, call[2])
+	}
+	if want := []string{"validate-dependencies", "fix-dependencies", "validate-dependencies"}; !reflect.DeepEqual(commands, want) {
+		t.Errorf("ran %q, want %q", commands, want)
+	}
+	if m.canFix() || !strings.HasPrefix(m.statusMsg, "✅") {
+		t.Errorf("after fixing: canFix() = %v, status %q", m.canFix(), m.statusMsg)
+	}
+}
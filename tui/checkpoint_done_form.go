@@ -0,0 +1,289 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	checkpointDoneFormKeyFile = "file"
+	checkpointDoneFormKeyID   = "id"
+)
+
+// CheckpointDoneModel holds the state for the quick action that marks a
+// checkpoint done with its acceptance criteria met.
+type CheckpointDoneModel struct {
+	form         *huh.Form
+	aborted      bool
+	isProcessing bool
+	statusMsg    string
+	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
+
+	// Form values
+	FilePath string
+	TaskID   string
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
+}
+
+// NewCheckpointDoneForm creates a new form that marks a task done and
+// confirms its acceptance criteria in one step, without the status choice of
+// the set-status form.
+func NewCheckpointDoneForm() *CheckpointDoneModel {
+	m := &CheckpointDoneModel{
+		FilePath: lastFilePath(), // Pre-populate with the last used tasks file
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			newFilePathField(checkpointDoneFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json).", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		huh.NewGroup(
+			huh.NewInput().
+				Key(checkpointDoneFormKeyID).
+				Title("Checkpoint Task ID").
+				DescriptionFunc(func() string { return checkpointDescription(m.FilePath, m.TaskID) }, []any{&m.FilePath, &m.TaskID}).
+				Prompt("🏁 ").
+				Validate(func(s string) error {
+					if err := validateTaskID(s); err != nil {
+						return err
+					}
+					return validateTaskExists(m.FilePath, s)
+				}).
+				SuggestionsFunc(taskIDSuggestions(&m.FilePath, true), &m.FilePath).
+				Value(&m.TaskID),
+		),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
+	).WithTheme(huh.ThemeDracula())
+
+	return m
+}
+
+func (m *CheckpointDoneModel) Init() tea.Cmd {
+	m.isProcessing = false
+	m.statusMsg = ""
+	m.aborted = false
+	return m.form.Init()
+}
+
+func (m *CheckpointDoneModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case checkpointDoneCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			m.statusMsg = fmt.Sprintf("✅ Task %s marked done with its acceptance criteria met.", m.TaskID)
+			if warning := checkpointWarning(m.FilePath, m.TaskID); warning != "" {
+				m.statusMsg = fmt.Sprintf("✅ Task %s marked done. %s", m.TaskID, warning)
+			}
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
+	if m.isProcessing {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, nil
+	}
+
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.statusMsg = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
+	var cmds []tea.Cmd
+	formModel, cmd := m.form.Update(msg)
+	if updatedForm, ok := formModel.(*huh.Form); ok {
+		m.form = updatedForm
+	} else {
+		m.statusMsg = "Error: Form update returned unexpected type."
+		debugLog.Error("form update did not return *huh.Form", "file", "checkpoint_done_form.go", "type", fmt.Sprintf("%T", formModel))
+		return m, tea.Quit
+	}
+	cmds = append(cmds, cmd)
+
+	if m.form.State == huh.StateCompleted {
+		m.statusMsg = "Executing set-task-status command..."
+		m.isProcessing = true
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeCheckpointDoneCommand))
+	}
+
+	if m.form.State == huh.StateAborted {
+		m.aborted = true
+		return m, func() tea.Msg { return backToMenuMsg{} }
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if !m.isProcessing {
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			}
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *CheckpointDoneModel) View() string {
+	if m.aborted {
+		return "Form aborted. Returning to main menu..."
+	}
+
+	var viewBuilder strings.Builder
+	viewBuilder.WriteString(m.form.View())
+
+	if m.statusMsg != "" {
+		viewBuilder.WriteString("\n\n")
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
+	}
+
+	helpStyle := lipgloss.NewStyle().Faint(true)
+	if m.isProcessing {
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
+	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
+	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Padding(1, 2).
+		Render(viewBuilder.String())
+}
+
+func (m *CheckpointDoneModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
+// GetFormValues retrieves the structured data after completion.
+func (m *CheckpointDoneModel) GetFormValues() (map[string]interface{}, error) {
+	if m.form.State != huh.StateCompleted {
+		return nil, fmt.Errorf("form is not yet completed")
+	}
+	return map[string]interface{}{
+		checkpointDoneFormKeyFile: m.FilePath,
+		checkpointDoneFormKeyID:   m.TaskID,
+		advancedFlagsFormKey:      m.AdvancedFlags,
+	}, nil
+}
+
+// checkpointDoneCompleteMsg is sent when the command execution is complete
+type checkpointDoneCompleteMsg struct {
+	result CLIResult
+}
+
+// executeCheckpointDoneCommand marks the task done with criteriaMet set
+func (m *CheckpointDoneModel) executeCheckpointDoneCommand() tea.Cmd {
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+		return checkpointDoneCompleteMsg{result: executor.SetTaskStatus(m.FilePath, m.TaskID, string(StatusDone), true)}
+	})
+}
+
+// checkpointDescription describes the task id for the ID field: a
+// checkpoint's acceptance criteria to confirm, or checkpointWarning's
+// warning for any other task.
+func checkpointDescription(filePath, id string) string {
+	const prompt = "ID of the checkpoint to mark done, confirming its acceptance criteria are met."
+	tf, err := LoadTasksFile(filePath)
+	if err != nil {
+		return prompt
+	}
+	task, ok := tf.Find(id)
+	if !ok {
+		return prompt
+	}
+	if task.Type != string(TypeCheckpoint) {
+		return "⚠️  " + checkpointWarning(filePath, id)
+	}
+	if task.AcceptanceCriteria == "" {
+		return fmt.Sprintf("%s: %s", id, task.Title)
+	}
+	return fmt.Sprintf("%s: %s\nAcceptance criteria: %s", id, task.Title, task.AcceptanceCriteria)
+}
+
+// checkpointWarning returns a warning if id isn't a checkpoint task, for
+// which confirming acceptance criteria means nothing. It returns "" for a
+// checkpoint, or if the task can't be looked up.
+func checkpointWarning(filePath, id string) string {
+	tf, err := LoadTasksFile(filePath)
+	if err != nil {
+		return ""
+	}
+	task, ok := tf.Find(id)
+	if !ok || task.Type == string(TypeCheckpoint) {
+		return ""
+	}
+	return fmt.Sprintf("Task %s isn't a checkpoint, so it has no acceptance criteria to confirm.", id)
+}
+
+var _ tea.Model = &CheckpointDoneModel{}
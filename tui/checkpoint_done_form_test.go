@@ -0,0 +1,54 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCheckpointWarning(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	writeTasks(t, path, `{"tasks": [
+		{"id": 1, "title": "Release", "type": "checkpoint", "acceptanceCriteria": "Tagged and published"},
+		{"id": 2, "title": "Docs", "subtasks": [{"id": 1, "title": "Outline"}]}
+	]}`)
+
+	tests := []struct {
+		id, wantWarning, wantDescription string
+	}{
+		{id: "1", wantDescription: "Acceptance criteria: Tagged and published"},
+		{id: "2", wantWarning: "isn't a checkpoint", wantDescription: "⚠️  Task 2 isn't a checkpoint"},
+		{id: "2.1", wantWarning: "isn't a checkpoint", wantDescription: "⚠️  Task 2.1 isn't a checkpoint"},
+		{id: "9", wantDescription: "ID of the checkpoint to mark done"},
+	}
+	for _, tt := range tests {
+		warning := checkpointWarning(path, tt.id)
+		if (warning == "") != (tt.wantWarning == "") || !strings.Contains(warning, tt.wantWarning) {
+			t.Errorf("checkpointWarning(%s) = %q, want %q", tt.id, warning, tt.wantWarning)
+		}
+		if got := checkpointDescription(path, tt.id); !strings.Contains(got, tt.wantDescription) {
+			t.Errorf("checkpointDescription(%s) = %q, want it to contain %q", tt.id, got, tt.wantDescription)
+		}
+	}
+}
+
+func TestCheckpointDoneConfirmsCriteria(t *testing.T) {
+	fake := &fakeRunner{}
+	useFakeRunner(t, fake)
+
+	m := NewCheckpointDoneForm()
+	m.FilePath, m.TaskID = "tasks.json", "4"
+	// The batch streams output then runs the command, which sends the result
+	batch := m.executeCheckpointDoneCommand()().(tea.BatchMsg)
+	m.Update(batch[len(batch)-1]())
+
+	want := []string{"node", "../scripts/dev.js", "set-task-status", "tasks.json", "4", "done", "--criteria-met"}
+	if len(fake.calls) != 1 || strings.Join(fake.calls[0], " ") != strings.Join(want, " ") {
+		t.Errorf("ran %q, want %q", fake.calls, want)
+	}
+	if !strings.HasPrefix(m.statusMsg, "✅ Task 4 marked done") {
+		t.Errorf("status = %q", m.statusMsg)
+	}
+}
@@ -2,18 +2,20 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 )
 
 const (
-	clearSubtasksFormKeyFile = "file"
-	clearSubtasksFormKeyIDs  = "ids" // Comma-separated task IDs
-	clearSubtasksFormKeyAll  = "all"
+	clearSubtasksFormKeyFile        = "file"
+	clearSubtasksFormKeyIDs         = "ids" // Comma-separated task IDs
+	clearSubtasksFormKeySelectedIDs = "selected-ids"
+	clearSubtasksFormKeyAll         = "all"
+	clearSubtasksFormKeyConfirm     = "confirm"
 )
 
 // ClearSubtasksModel holds the state for the clear subtasks form.
@@ -23,42 +25,55 @@ type ClearSubtasksModel struct {
 	isProcessing bool
 	statusMsg    string
 	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
 
 	// Form values
-	FilePath string
-	TaskIDs  string // Can be empty if 'AllTasks' is true
-	AllTasks bool   // Clear subtasks from all tasks
+	FilePath    string
+	TaskIDs     string   // Used when the file can't be parsed; can be empty if 'AllTasks' is true
+	SelectedIDs []string // Task IDs picked from the tasks file
+	AllTasks    bool     // Clear subtasks from all tasks
+	Confirmed   bool     // Must be set before any subtasks are cleared
+
+	tasks taskChoices // Tasks offered by the multi-select
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
 }
 
 // NewClearSubtasksForm creates a new form for the clear-subtasks command.
 func NewClearSubtasksForm() *ClearSubtasksModel {
 	m := &ClearSubtasksModel{
-		AllTasks: false, // Default to not clearing all tasks
+		FilePath: lastFilePath(), // Pre-populate with the last used tasks file
+		AllTasks: false,          // Default to not clearing all tasks
 	}
 
 	m.form = huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().
-				Key(clearSubtasksFormKeyFile).
-				Title("Tasks File Path").
-				Description("Path to the tasks file (e.g., tasks.md).").
-				Prompt("📄 ").
-				Validate(func(s string) error {
-					if s == "" {
-						return fmt.Errorf("tasks file path cannot be empty")
-					}
-					return nil
-				}).
-				Value(&m.FilePath),
-
+			newFilePathField(clearSubtasksFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json).", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Key(clearSubtasksFormKeySelectedIDs).
+				Title("Task(s) (Optional)").
+				Description("Select the tasks to clear subtasks from (space to toggle). Leave empty if 'Clear All' is Yes.").
+				OptionsFunc(func() []huh.Option[string] { return m.tasks.load(m.FilePath) }, &m.FilePath).
+				Height(12).
+				Value(&m.SelectedIDs),
+		).WithHideFunc(func() bool { return !m.tasks.available(m.FilePath) }),
+		// Free-text fallback for files that can't be parsed
+		huh.NewGroup(
 			huh.NewInput().
 				Key(clearSubtasksFormKeyIDs).
 				Title("Task ID(s) (Optional)").
 				Description("IDs of tasks to clear subtasks from. Leave empty if 'Clear All' is Yes.").
 				Prompt("🆔 ").
-				// Validation will be handled in the Update method based on 'AllTasks'
 				Value(&m.TaskIDs),
-		),
+		).WithHideFunc(func() bool { return m.tasks.available(m.FilePath) }),
 		huh.NewGroup(
 			huh.NewConfirm().
 				Key(clearSubtasksFormKeyAll).
@@ -66,8 +81,25 @@ func NewClearSubtasksForm() *ClearSubtasksModel {
 				Description("Clear subtasks from all tasks in the file?").
 				Affirmative("Yes").
 				Negative("No").
+				// Asked after the tasks, so this is where a missing choice shows
+				Validate(func(all bool) error {
+					if !all && len(m.taskIDs()) == 0 {
+						return fmt.Errorf("choose tasks above, or choose Yes to clear subtasks from all tasks")
+					}
+					return nil
+				}).
 				Value(&m.AllTasks),
 		),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key(clearSubtasksFormKeyConfirm).
+				TitleFunc(m.confirmTitle, &m.AllTasks).
+				DescriptionFunc(m.confirmDescription, []any{&m.FilePath, &m.AllTasks, &m.SelectedIDs, &m.TaskIDs}).
+				Affirmative("Yes, clear them").
+				Negative("No").
+				Value(&m.Confirmed), // Defaults to No
+		),
 	).WithTheme(huh.ThemeDracula())
 
 	return m
@@ -81,46 +113,97 @@ func (m *ClearSubtasksModel) Init() tea.Cmd {
 }
 
 func (m *ClearSubtasksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case clearSubtasksCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		if len(msg.problems) > 0 {
+			m.statusMsg = batchProblemsStatus(msg.problems)
+			m.form.State = huh.StateNormal // Back to the form to fix them all before anything runs
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			m.statusMsg = "✅ Success!"
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
 	if m.isProcessing {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
-			if keyMsg.String() == "ctrl+c" || keyMsg.String() == "q" { return m, tea.Quit }
+			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
 		}
 		return m, nil
 	}
 
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.statusMsg = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
 	var cmds []tea.Cmd
 	formModel, cmd := m.form.Update(msg)
 	if updatedForm, ok := formModel.(*huh.Form); ok {
 		m.form = updatedForm
 	} else {
 		m.statusMsg = "Error: Form update returned unexpected type."
-		fmt.Fprintf(os.Stderr, "Critical Error: clear_subtasks_form.go - form update did not return *huh.Form. Got: %T\n", formModel)
+		debugLog.Error("form update did not return *huh.Form", "file", "clear_subtasks_form.go", "type", fmt.Sprintf("%T", formModel))
 		return m, tea.Quit
 	}
 	cmds = append(cmds, cmd)
 
-	// Custom validation logic based on 'AllTasks'
-	allTasksSelected := m.form.GetBool(clearSubtasksFormKeyAll)
-	taskIDsProvided := m.form.GetString(clearSubtasksFormKeyIDs) != ""
-
-	// Note: Direct field access for validation is not available in huh v0.7.0
-	// We'll handle validation through the form's overall validation state
-	if !allTasksSelected && !taskIDsProvided {
-		if m.form.State == huh.StateCompleted { m.form.State = huh.StateNormal } // Prevent completion
-	}
-
-
 	if m.form.State == huh.StateCompleted {
-		// Re-check validation before final processing because direct struct binding might occur before this point
-		if !m.AllTasks && m.TaskIDs == "" { // m.AllTasks and m.TaskIDs are bound from form
-			m.statusMsg = "Error: Task ID(s) are required if 'Clear All' is No."
-			m.form.State = huh.StateNormal // Revert to allow correction
-			return m, nil
+		if !m.Confirmed {
+			// Declining the confirm leaves the file untouched
+			m.aborted = true
+			return m, func() tea.Msg { return backToMenuMsg{} }
 		}
 
 		m.statusMsg = "Executing clear-subtasks command..."
 		m.isProcessing = true
-		return m, m.executeClearSubtasksCommand()
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeClearSubtasksCommand))
 	}
 
 	if m.form.State == huh.StateAborted {
@@ -129,14 +212,6 @@ func (m *ClearSubtasksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
-	case clearSubtasksCompleteMsg:
-		m.isProcessing = false
-		if msg.result.Success {
-			m.statusMsg = fmt.Sprintf("✅ Success!\n\n%s", msg.result.Output)
-		} else {
-			m.statusMsg = fmt.Sprintf("❌ Error: %s\n\n%s", msg.result.Error, msg.result.Output)
-		}
-		return m, nil
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
@@ -144,8 +219,6 @@ func (m *ClearSubtasksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.aborted = true; return m, func() tea.Msg { return backToMenuMsg{} }
 			}
 		}
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
 	}
 
 	return m, tea.Batch(cmds...)
@@ -159,50 +232,93 @@ func (m *ClearSubtasksModel) View() string {
 
 	if m.statusMsg != "" {
 		viewBuilder.WriteString("\n\n")
-		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-		if strings.HasPrefix(m.statusMsg, "Error:") {
-			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
-		}
-		viewBuilder.WriteString(statusStyle.Render(m.statusMsg))
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
 	}
 
 	helpStyle := lipgloss.NewStyle().Faint(true)
 	if m.isProcessing {
-		viewBuilder.WriteString(helpStyle.Render("\n\nProcessing... Press Ctrl+C to force quit."))
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
 	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
-		viewBuilder.WriteString(helpStyle.Render("\n\nCommand completed! Press Esc to return to main menu."))
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
 	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
 		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
 	}
 	return lipgloss.NewStyle().Width(m.width).Padding(1, 2).Render(viewBuilder.String())
 }
 
+func (m *ClearSubtasksModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
 // GetFormValues retrieves the structured data after completion.
 func (m *ClearSubtasksModel) GetFormValues() (map[string]interface{}, error) {
 	if m.form.State != huh.StateCompleted {
 		return nil, fmt.Errorf("form is not yet completed")
 	}
 	// Ensure TaskIDs is empty if AllTasks is true for command logic if needed
-	taskIDsForCmd := m.TaskIDs
+	taskIDsForCmd := strings.Join(m.taskIDs(), ",")
 	if m.AllTasks {
 		taskIDsForCmd = ""
 	}
 	return map[string]interface{}{
-		clearSubtasksFormKeyFile: m.FilePath,
-		clearSubtasksFormKeyIDs:  taskIDsForCmd,
-		clearSubtasksFormKeyAll:  m.AllTasks,
+		clearSubtasksFormKeyFile:    m.FilePath,
+		clearSubtasksFormKeyIDs:     taskIDsForCmd,
+		clearSubtasksFormKeyAll:     m.AllTasks,
+		clearSubtasksFormKeyConfirm: m.Confirmed,
+		advancedFlagsFormKey:        m.AdvancedFlags,
 	}, nil
 }
 
+// confirmTitle is the title of the final confirm, which stands out when every task is affected.
+func (m *ClearSubtasksModel) confirmTitle() string {
+	if m.AllTasks {
+		return "⚠️  Clear Subtasks from EVERY Task"
+	}
+	return "Clear Subtasks"
+}
+
+// confirmDescription summarizes exactly what will be cleared.
+func (m *ClearSubtasksModel) confirmDescription() string {
+	if m.AllTasks {
+		return fmt.Sprintf("This removes the subtasks of every task in %s. It can't be undone.", m.FilePath)
+	}
+	ids := m.taskIDs()
+	if len(ids) == 0 {
+		return "No tasks selected."
+	}
+	return fmt.Sprintf("This removes the subtasks of task(s) %s in %s. It can't be undone.", strings.Join(ids, ", "), m.FilePath)
+}
+
+// taskIDs returns the IDs to clear: those picked in the multi-select, or the
+// typed list when the tasks file couldn't be parsed.
+func (m *ClearSubtasksModel) taskIDs() []string {
+	if m.tasks.available(m.FilePath) {
+		return m.SelectedIDs
+	}
+	return splitTaskIDs(m.TaskIDs)
+}
+
 // clearSubtasksCompleteMsg is sent when the command execution is complete
 type clearSubtasksCompleteMsg struct {
-	result CLIResult
+	result   CLIResult
+	problems []string // Inputs ValidateBatch rejected, in which case nothing ran
 }
 
 // executeClearSubtasksCommand executes the actual clear-subtasks CLI command
 // The CLI method expects a single taskID, so we'll handle multiple IDs by calling it for each one
 func (m *ClearSubtasksModel) executeClearSubtasksCommand() tea.Cmd {
-	return func() tea.Msg {
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+
 		if m.AllTasks {
 			// For "all tasks", we would need a different approach
 			// Since CLI expects a specific taskID, we'll return an error for now
@@ -211,20 +327,18 @@ func (m *ClearSubtasksModel) executeClearSubtasksCommand() tea.Cmd {
 				Error:   "Clearing subtasks for all tasks is not yet supported via CLI",
 			}}
 		}
+
+		// Check every ID before clearing any, so a typo doesn't stop the batch partway
+		if problems := ValidateBatch(m.FilePath, m.taskIDs(), ""); len(problems) > 0 {
+			return clearSubtasksCompleteMsg{problems: problems}
+		}
 		
-		// Parse task IDs from comma-separated string
-		taskIDs := strings.Split(m.TaskIDs, ",")
 		var results []string
 		var hasError bool
 		var lastError string
 		
-		for _, taskID := range taskIDs {
-			trimmedID := strings.TrimSpace(taskID)
-			if trimmedID == "" {
-				continue
-			}
-			
-			result := cliExecutor.ClearSubtasks(m.FilePath, trimmedID)
+		for _, trimmedID := range m.taskIDs() {
+			result := executor.ClearSubtasks(m.FilePath, trimmedID)
 			if result.Success {
 				results = append(results, fmt.Sprintf("✅ Task %s: %s", trimmedID, result.Output))
 			} else {
@@ -246,7 +360,7 @@ func (m *ClearSubtasksModel) executeClearSubtasksCommand() tea.Cmd {
 			Error:   lastError,
 			Output:  strings.Join(results, "\n"),
 		}}
-	}
+	})
 }
 
 var _ tea.Model = &ClearSubtasksModel{}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildAddTaskArgs(t *testing.T) {
+	tests := []struct {
+		name                                       string
+		prompt, title, description, details, test  string
+		dependencies, priority, taskType, criteria string
+		useResearch                                bool
+		want                                       []string
+	}{
+		{
+			name:   "prompt",
+			prompt: "Add login",
+			want:   []string{"add-task", "tasks.json", "--prompt", "Add login"},
+		},
+		{
+			name:        "prompt ignores manual fields",
+			prompt:      "Add login",
+			title:       "Login",
+			description: "Users can log in",
+			details:     "Use OAuth",
+			want:        []string{"add-task", "tasks.json", "--prompt", "Add login"},
+		},
+		{
+			name:        "manual",
+			title:       "Login",
+			description: "Users can log in",
+			want:        []string{"add-task", "tasks.json", "--title", "Login", "--description", "Users can log in"},
+		},
+		{
+			name:        "manual with details and test strategy",
+			title:       "Login",
+			description: "Users can log in",
+			details:     "Use OAuth",
+			test:        "Log in and out",
+			want: []string{"add-task", "tasks.json", "--title", "Login", "--description", "Users can log in",
+				"--details", "Use OAuth", "--test-strategy", "Log in and out"},
+		},
+		{
+			name:         "options",
+			prompt:       "Add login",
+			dependencies: "1,2",
+			priority:     "high",
+			taskType:     "checkpoint",
+			useResearch:  true,
+			want: []string{"add-task", "tasks.json", "--prompt", "Add login",
+				"--dependencies", "1,2", "--priority", "high", "--type", "checkpoint", "--research"},
+		},
+		{
+			name:        "manual checkpoint",
+			title:       "Release",
+			description: "Ship it",
+			taskType:    "checkpoint",
+			criteria:    "Tagged and published",
+			want: []string{"add-task", "tasks.json", "--title", "Release", "--description", "Ship it",
+				"--acceptance-criteria", "Tagged and published", "--type", "checkpoint"},
+		},
+		{
+			name:        "criteria only for checkpoints",
+			title:       "Login",
+			description: "Users can log in",
+			criteria:    "Works",
+			want:        []string{"add-task", "tasks.json", "--title", "Login", "--description", "Users can log in"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildAddTaskArgs("tasks.json", tt.prompt, tt.title, tt.description, tt.details, tt.test,
+				tt.dependencies, tt.priority, tt.taskType, tt.criteria, tt.useResearch)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildAddTaskArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildExpandTaskArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		numSubtasks int
+		useResearch bool
+		want        []string
+	}{
+		{name: "defaults", want: []string{"expand-task", "tasks.json", "3", "--prompt", "More detail"}},
+		{name: "num subtasks", numSubtasks: 5, want: []string{"expand-task", "tasks.json", "3", "--prompt", "More detail", "--num-subtasks=5"}},
+		{name: "research", useResearch: true, want: []string{"expand-task", "tasks.json", "3", "--prompt", "More detail", "--research"}},
+		{name: "both", numSubtasks: 2, useResearch: true, want: []string{"expand-task", "tasks.json", "3", "--prompt", "More detail", "--num-subtasks=2", "--research"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildExpandTaskArgs("tasks.json", "3", "More detail", tt.numSubtasks, tt.useResearch)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildExpandTaskArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildExpandTaskArgsIDs(t *testing.T) {
+	// IDs go to the CLI as typed; it's the CLI that refuses a subtask
+	for _, id := range []string{"2", "2.3"} {
+		got := buildExpandTaskArgs("tasks.json", id, "", 0, false)
+		if want := []string{"expand-task", "tasks.json", id, "--prompt", ""}; !reflect.DeepEqual(got, want) {
+			t.Errorf("buildExpandTaskArgs(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestBuildCommandArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{
+			name: "parse-prd",
+			got:  buildParsePRDArgs("prd.txt", "tasks.json", 10, false, false),
+			want: []string{"parse-prd", "prd.txt", "tasks.json", "--num-tasks=10"},
+		},
+		{
+			name: "parse-prd force and append",
+			got:  buildParsePRDArgs("prd.txt", "tasks.json", 10, true, true),
+			want: []string{"parse-prd", "prd.txt", "tasks.json", "--num-tasks=10", "--force", "--append"},
+		},
+		{
+			name: "set-task-status",
+			got:  buildSetTaskStatusArgs("tasks.json", "4", "in-progress", true),
+			want: []string{"set-task-status", "tasks.json", "4", "in-progress"},
+		},
+		{
+			name: "set-task-status done with criteria met",
+			got:  buildSetTaskStatusArgs("tasks.json", "4", "done", true),
+			want: []string{"set-task-status", "tasks.json", "4", "done", "--criteria-met"},
+		},
+		{
+			name: "update-tasks",
+			got:  buildUpdateTasksArgs("tasks.json", "Use Postgres", nil, false),
+			want: []string{"update-tasks", "tasks.json", "--prompt", "Use Postgres"},
+		},
+		{
+			name: "update-tasks with ids and research",
+			got:  buildUpdateTasksArgs("tasks.json", "Use Postgres", []string{"2", "5"}, true),
+			want: []string{"update-tasks", "tasks.json", "--prompt", "Use Postgres", "--task-ids", "2,5", "--research"},
+		},
+		{
+			name: "update-subtask",
+			got:  buildUpdateSubtaskArgs("tasks.json", "2", "1", "Note", false),
+			want: []string{"update-subtask", "tasks.json", "2", "1", "--prompt", "Note"},
+		},
+		{
+			name: "list-tasks",
+			got:  buildListTasksArgs("tasks.json", "", "", false),
+			want: []string{"list-tasks", "tasks.json"},
+		},
+		{
+			name: "list-tasks filtered",
+			got:  buildListTasksArgs("tasks.json", "pending", "high", true),
+			want: []string{"list-tasks", "tasks.json", "--status", "pending", "--priority", "high", "--show-subtasks"},
+		},
+		{
+			name: "analyze-complexity",
+			got:  buildAnalyzeComplexityArgs("tasks.json", 0, ""),
+			want: []string{"analyze-complexity", "tasks.json"},
+		},
+		{
+			name: "analyze-complexity with options",
+			got:  buildAnalyzeComplexityArgs("tasks.json", 7, "report.json"),
+			want: []string{"analyze-complexity", "tasks.json", "--threshold=7", "--output", "report.json"},
+		},
+		{
+			name: "validate-dependencies",
+			got:  buildValidateDependenciesArgs("tasks.json"),
+			want: []string{"validate-dependencies", "--file", "tasks.json"},
+		},
+		{
+			name: "models shows configuration",
+			got:  buildModelsArgs("", "", ""),
+			want: []string{"models"},
+		},
+		{
+			name: "models sets research only",
+			got:  buildModelsArgs("", "sonar-pro", ""),
+			want: []string{"models", "--set-research", "sonar-pro"},
+		},
+		{
+			name: "remove-subtask",
+			got:  buildRemoveSubtaskArgs("tasks.json", "5.2", false),
+			want: []string{"remove-subtask", "--file", "tasks.json", "--id", "5.2"},
+		},
+		{
+			name: "remove-subtask convert",
+			got:  buildRemoveSubtaskArgs("tasks.json", "5.2", true),
+			want: []string{"remove-subtask", "--file", "tasks.json", "--id", "5.2", "--convert"},
+		},
+		{
+			name: "move subtask",
+			got:  buildMoveTaskArgs("tasks.json", "1.2", "3.1"),
+			want: []string{"move", "--file", "tasks.json", "--from", "1.2", "--to", "3.1"},
+		},
+		{
+			name: "init",
+			got:  buildInitProjectArgs("demo", "", true, true),
+			want: []string{"init", "--yes", "--name", "demo", "--aliases", "--skip-install"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("args = %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveTaskArgsMatchCLI(t *testing.T) {
+	checkCLIArgs(t, buildMoveTaskArgs("tasks.json", "1.2", "3.1"))
+}
+
+func TestCheckAddTaskDestination(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		wantErr     bool
+	}{
+		{name: "none", destination: ""},
+		{name: "same file", destination: "tasks/tasks.json"},
+		{name: "same file spelled differently", destination: "tasks/../tasks/tasks.json"},
+		{name: "other file", destination: "tasks/feature.json", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAddTaskDestination("tasks/tasks.json", tt.destination)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkAddTaskDestination(%q) error = %v, wantErr %v", tt.destination, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAddTaskRejectsOtherDestination(t *testing.T) {
+	fake := &fakeRunner{}
+	useFakeRunner(t, fake)
+
+	result := cliExecutor.AddTask("tasks/tasks.json", "tasks/feature.json", "Add login", "", "", "", "", "", "", "", "", false)
+	if result.Success {
+		t.Fatal("AddTask() succeeded, want an error for another destination")
+	}
+	if len(fake.calls) != 0 {
+		t.Errorf("AddTask() ran %q, want nothing run", fake.calls)
+	}
+}
+
+func TestAddTaskRequiresCheckpointCriteria(t *testing.T) {
+	fake := &fakeRunner{}
+	useFakeRunner(t, fake)
+
+	result := cliExecutor.AddTask("tasks/tasks.json", "", "", "Release", "Ship it", "", "", "", "", "checkpoint", " ", false)
+	if result.Success {
+		t.Fatal("AddTask() succeeded, want an error for a checkpoint without acceptance criteria")
+	}
+	if len(fake.calls) != 0 {
+		t.Errorf("AddTask() ran %q, want nothing run", fake.calls)
+	}
+}
+
+func TestAddTaskNormalizesDependencies(t *testing.T) {
+	fake := &fakeRunner{}
+	useFakeRunner(t, fake)
+
+	cliExecutor.AddTask("tasks.json", "", "", "Docs", "Write them", "", "", " 1, 2.1 ,1 ", "", "", "", false)
+	if len(fake.calls) != 1 || !strings.Contains(strings.Join(fake.calls[0], " "), "--dependencies 1,2.1") {
+		t.Errorf("AddTask() ran %q, want the dependencies as 1,2.1", fake.calls)
+	}
+
+	fake.calls = nil
+	result := cliExecutor.AddTask("tasks.json", "", "", "Docs", "Write them", "", "", "1,,2", "", "", "", false)
+	if result.Success || len(fake.calls) != 0 {
+		t.Errorf("AddTask() with malformed dependencies = %+v, ran %q, want nothing run", result, fake.calls)
+	}
+}
+
+func TestBuildGenerateTaskFilesArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		taskIDs []string
+		want    []string
+	}{
+		{name: "all tasks", want: []string{"generate", "-f", "tasks.json", "-o", "tasks"}},
+		{name: "some tasks", taskIDs: []string{"2", "5"},
+			want: []string{"generate", "-f", "tasks.json", "-o", "tasks", "--id", "2,5"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildGenerateTaskFilesArgs("tasks.json", "tasks", tt.taskIDs)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildGenerateTaskFilesArgs() = %q, want %q", got, tt.want)
+			}
+			checkCLIArgs(t, got)
+		})
+	}
+}
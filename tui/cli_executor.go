@@ -1,37 +1,153 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // CLIExecutor handles execution of the actual taskmaster CLI commands
 type CLIExecutor struct {
-	cliPath string
+	cliPath    string
+	onOutput   func(line string) // Receives output lines as they are produced, if set
+	jsonOutput bool              // Request and parse structured output, see WithJSON
+	extraEnv   []string          // KEY=VALUE pairs added to each command's environment, see WithEnv
+	ctx        context.Context   // Cancels running commands, see WithContext
+	tag        string            // Task context for commands, see WithTag
+	extraArgs  []string          // Appended to each CLI command's arguments, see WithAdvancedFlags
+
+	researchModel string // Research model for research commands, see WithResearchModel
+
+	commandRunner CommandRunner // Runs commands in place of starting processes, see WithRunner
+
+	// Runner selects how the CLI is invoked; the zero value runs the local script with node
+	Runner Runner
+
+	// DryRun makes commands report the command line they would run instead of running it
+	DryRun bool
+
+	// Timeout kills each command that runs longer; zero means no limit
+	Timeout time.Duration
+
+	// MaxOutputMB caps how many megabytes of each command's output are kept,
+	// the rest discarded; zero means defaultMaxOutputMB
+	MaxOutputMB int
+
+	// WorkDir is the directory commands run in; empty means defaultWorkDir
+	WorkDir string
+
+	// AssumeYes answers the CLI's confirmation prompts by passing --yes to the
+	// commands that ask them; the forms confirm destructive changes instead
+	AssumeYes bool
+
+	// Result of the startup Node.js check, see CheckNode
+	NodeVersion string // Version reported by node, empty if it couldn't be run
+	NodeWarning string // Problem to show the user, empty if node is usable
 }
 
-// NewCLIExecutor creates a new CLI executor with the path to the taskmaster CLI
+// Runner is the program used to invoke the taskmaster CLI
+type Runner string
+
+const (
+	RunnerNode Runner = "node" // node ../scripts/dev.js, from a checkout of the repo
+	RunnerNpx  Runner = "npx"  // npx task-master-ai, the published package
+)
+
+// npxPackage is the published CLI package run by RunnerNpx
+const npxPackage = "task-master-ai"
+
+// runnerEnv selects the global executor's runner, "node" (the default) or "npx"
+const runnerEnv = "TASKMASTER_TUI_RUNNER"
+
+// workDirEnv sets the global executor's working directory, see CLIExecutor.WorkDir
+const workDirEnv = "TASKMASTER_TUI_WORKDIR"
+
+// dryRunEnv enables dry-run mode for the global executor when set to a true value
+const dryRunEnv = "TASKMASTER_TUI_DRY_RUN"
+
+// NewCLIExecutor creates a new CLI executor with the path to the taskmaster
+// CLI, configured by the settings and then the environment
 func NewCLIExecutor() *CLIExecutor {
 	// Find the CLI script relative to the TUI binary
 	cliPath := filepath.Join("..", "scripts", "dev.js")
-	return &CLIExecutor{cliPath: cliPath}
+	settings, _ := LoadSettings() // Unreadable settings leave the defaults
+
+	dryRun, _ := strconv.ParseBool(os.Getenv(dryRunEnv))
+	runner := RunnerNode
+	if settings.Runner == RunnerNpx {
+		runner = RunnerNpx
+	}
+	if env := Runner(os.Getenv(runnerEnv)); env == RunnerNpx || env == RunnerNode {
+		runner = env
+	}
+	workDir := settings.WorkDir
+	if env := os.Getenv(workDirEnv); env != "" {
+		workDir = env
+	}
+	return &CLIExecutor{
+		cliPath:     cliPath,
+		Runner:      runner,
+		DryRun:      dryRun || settings.DryRun,
+		Timeout:     time.Duration(settings.CommandTimeout) * time.Second,
+		MaxOutputMB: settings.MaxOutputMB,
+		WorkDir:     workDir,
+		AssumeYes:   true,
+	}
+}
+
+// WithOutput returns a copy of the executor that passes each line of command output to fn as it is produced
+func (e *CLIExecutor) WithOutput(fn func(line string)) *CLIExecutor {
+	streaming := *e
+	streaming.onOutput = fn
+	return &streaming
+}
+
+// WithContext returns a copy of the executor whose commands are killed when ctx is cancelled
+func (e *CLIExecutor) WithContext(ctx context.Context) *CLIExecutor {
+	cancellable := *e
+	cancellable.ctx = ctx
+	return &cancellable
 }
 
+// commandWaitDelay bounds how long a killed command's output is drained, in
+// case the process left children holding its output open
+const commandWaitDelay = 2 * time.Second
+
 // CLIResult represents the result of a CLI command execution
 type CLIResult struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
-	Output  string `json:"output"`
-	Error   string `json:"error"`
+	Success  bool            `json:"success"`
+	Message  string          `json:"message"`
+	Output   string          `json:"output"`
+	Error    string          `json:"error"`
+	ExitCode int             `json:"exitCode"`         // Of the CLI process, if it ran; -1 if it was killed
+	Stdout   string          `json:"stdout,omitempty"` // The parts of Output from each stream, for diagnosing failures
+	Stderr   string          `json:"stderr,omitempty"`
+	Data     json.RawMessage `json:"data,omitempty"` // Structured result, set only in JSON mode
 }
 
 // ParsePRD executes the parse-prd command
 func (e *CLIExecutor) ParsePRD(filePath, outputPath string, numTasks int, force, appendMode bool) CLIResult {
-	args := []string{e.cliPath, "parse-prd", filePath, outputPath, fmt.Sprintf("--num-tasks=%d", numTasks)}
-	
+	if err := e.requireProviderKeys(false); err != nil {
+		return missingKeyResult(err)
+	}
+
+	return e.runCLILocked(outputPath, buildParsePRDArgs(filePath, outputPath, numTasks, force, appendMode)...)
+}
+
+// buildParsePRDArgs returns the CLI arguments for ParsePRD
+func buildParsePRDArgs(filePath, outputPath string, numTasks int, force, appendMode bool) []string {
+	args := []string{"parse-prd", filePath, outputPath, fmt.Sprintf("--num-tasks=%d", numTasks)}
+
 	if force {
 		args = append(args, "--force")
 	}
@@ -39,13 +155,69 @@ func (e *CLIExecutor) ParsePRD(filePath, outputPath string, numTasks int, force,
 		args = append(args, "--append")
 	}
 
-	return e.executeCommand("node", args...)
+	return args
 }
 
-// AddTask executes the add-task command
-func (e *CLIExecutor) AddTask(filePath, prompt, title, description, details, testStrategy, dependencies, priority, taskType string, useResearch bool) CLIResult {
-	args := []string{e.cliPath, "add-task", filePath}
-	
+// AddTask executes the add-task command. The CLI adds the task to the tasks
+// file it reads, so destinationPath may only name that same file; leave it
+// empty to add to filePath. Any other destination fails without running the
+// command, as the task would otherwise silently land in filePath.
+// acceptanceCriteria is required for a checkpoint task entered by hand; with
+// a prompt the AI writes them. dependencies is passed on as normalizeDependencies
+// returns it, and a malformed list fails without running the command.
+func (e *CLIExecutor) AddTask(filePath, destinationPath, prompt, title, description, details, testStrategy, dependencies, priority, taskType, acceptanceCriteria string, useResearch bool) CLIResult {
+	if err := checkAddTaskDestination(filePath, destinationPath); err != nil {
+		return CLIResult{
+			Success: false,
+			Error:   err.Error(),
+			Message: fmt.Sprintf("Command not run: %s", err.Error()),
+		}
+	}
+	dependencies, err := normalizeDependencies(dependencies)
+	if err != nil {
+		return CLIResult{
+			Success: false,
+			Error:   err.Error(),
+			Message: fmt.Sprintf("Command not run: %s", err.Error()),
+		}
+	}
+	if prompt == "" && taskType == string(TypeCheckpoint) && strings.TrimSpace(acceptanceCriteria) == "" {
+		err := errors.New("a checkpoint task needs acceptance criteria")
+		return CLIResult{
+			Success: false,
+			Error:   err.Error(),
+			Message: fmt.Sprintf("Command not run: %s", err.Error()),
+		}
+	}
+
+	// Only AI generation from a prompt needs a provider
+	if prompt != "" {
+		if err := e.requireProviderKeys(useResearch); err != nil {
+			return missingKeyResult(err)
+		}
+	}
+
+	return e.runCLILocked(filePath, buildAddTaskArgs(filePath, prompt, title, description, details, testStrategy, dependencies, priority, taskType, acceptanceCriteria, useResearch)...)
+}
+
+// checkAddTaskDestination reports an error if destinationPath is set to a file
+// other than filePath, which add-task can't write to.
+func checkAddTaskDestination(filePath, destinationPath string) error {
+	if destinationPath == "" || sameFilePath(filePath, destinationPath) {
+		return nil
+	}
+	return fmt.Errorf("add-task can only add to the tasks file it reads; to add the task to %s, use it as the tasks file", destinationPath)
+}
+
+// sameFilePath reports whether two tasks file paths, as given to the CLI, name the same file
+func sameFilePath(a, b string) bool {
+	return filepath.Clean(resolveTasksPath(a)) == filepath.Clean(resolveTasksPath(b))
+}
+
+// buildAddTaskArgs returns the CLI arguments for AddTask
+func buildAddTaskArgs(filePath, prompt, title, description, details, testStrategy, dependencies, priority, taskType, acceptanceCriteria string, useResearch bool) []string {
+	args := []string{"add-task", filePath}
+
 	if prompt != "" {
 		args = append(args, "--prompt", prompt)
 	} else {
@@ -56,8 +228,11 @@ func (e *CLIExecutor) AddTask(filePath, prompt, title, description, details, tes
 		if testStrategy != "" {
 			args = append(args, "--test-strategy", testStrategy)
 		}
+		if taskType == string(TypeCheckpoint) && acceptanceCriteria != "" {
+			args = append(args, "--acceptance-criteria", acceptanceCriteria)
+		}
 	}
-	
+
 	if dependencies != "" {
 		args = append(args, "--dependencies", dependencies)
 	}
@@ -71,31 +246,61 @@ func (e *CLIExecutor) AddTask(filePath, prompt, title, description, details, tes
 		args = append(args, "--research")
 	}
 
-	return e.executeCommand("node", args...)
+	return args
 }
 
 // NextTask executes the next-task command
 func (e *CLIExecutor) NextTask(filePath string) CLIResult {
-	args := []string{e.cliPath, "next-task", filePath}
-	return e.executeCommand("node", args...)
+	return e.runCLI(buildNextTaskArgs(filePath)...)
+}
+
+// buildNextTaskArgs returns the CLI arguments for NextTask
+func buildNextTaskArgs(filePath string) []string {
+	return []string{"next-task", filePath}
 }
 
 // ShowTask executes the show-task command
 func (e *CLIExecutor) ShowTask(filePath, taskID string) CLIResult {
-	args := []string{e.cliPath, "show-task", filePath, taskID}
-	return e.executeCommand("node", args...)
+	return e.runCLI(buildShowTaskArgs(filePath, taskID)...)
+}
+
+// buildShowTaskArgs returns the CLI arguments for ShowTask
+func buildShowTaskArgs(filePath, taskID string) []string {
+	return []string{"show-task", filePath, taskID}
 }
 
 // AddDependency executes the add-dependency command
 func (e *CLIExecutor) AddDependency(filePath, taskID, dependencyID string) CLIResult {
-	args := []string{e.cliPath, "add-dependency", filePath, taskID, dependencyID}
-	return e.executeCommand("node", args...)
+	return e.runCLILocked(filePath, buildAddDependencyArgs(filePath, taskID, dependencyID)...)
+}
+
+// AddDependencies adds several dependencies to a task, running add-dependency
+// once for each. Every run rewrites the task's dependencies, so they run one at
+// a time and none is lost. Results are in the order of dependencyIDs.
+func (e *CLIExecutor) AddDependencies(filePath, taskID string, dependencyIDs []string) []CLIResult {
+	return e.runLockedBatch(filePath, len(dependencyIDs), func(i int) CLIResult {
+		return e.runCLI(buildAddDependencyArgs(filePath, taskID, dependencyIDs[i])...)
+	})
+}
+
+// buildAddDependencyArgs returns the CLI arguments for AddDependency and each dependency of AddDependencies
+func buildAddDependencyArgs(filePath, taskID, dependencyID string) []string {
+	return []string{"add-dependency", filePath, taskID, dependencyID}
 }
 
 // UpdateTasks executes the update-tasks command
 func (e *CLIExecutor) UpdateTasks(filePath, prompt string, taskIDs []string, useResearch bool) CLIResult {
-	args := []string{e.cliPath, "update-tasks", filePath, "--prompt", prompt}
-	
+	if err := e.requireProviderKeys(useResearch); err != nil {
+		return missingKeyResult(err)
+	}
+
+	return e.runCLILocked(filePath, buildUpdateTasksArgs(filePath, prompt, taskIDs, useResearch)...)
+}
+
+// buildUpdateTasksArgs returns the CLI arguments for UpdateTasks
+func buildUpdateTasksArgs(filePath, prompt string, taskIDs []string, useResearch bool) []string {
+	args := []string{"update-tasks", filePath, "--prompt", prompt}
+
 	if len(taskIDs) > 0 {
 		args = append(args, "--task-ids", strings.Join(taskIDs, ","))
 	}
@@ -103,52 +308,161 @@ func (e *CLIExecutor) UpdateTasks(filePath, prompt string, taskIDs []string, use
 		args = append(args, "--research")
 	}
 
-	return e.executeCommand("node", args...)
+	return args
 }
 
 // UpdateOneTask executes the update-task command for a single task
 func (e *CLIExecutor) UpdateOneTask(filePath, taskID, prompt string, useResearch bool) CLIResult {
-	args := []string{e.cliPath, "update-task", filePath, taskID, "--prompt", prompt}
-	
+	if err := e.requireProviderKeys(useResearch); err != nil {
+		return missingKeyResult(err)
+	}
+
+	return e.runCLILocked(filePath, buildUpdateOneTaskArgs(filePath, taskID, prompt, useResearch)...)
+}
+
+// buildUpdateOneTaskArgs returns the CLI arguments for UpdateOneTask
+func buildUpdateOneTaskArgs(filePath, taskID, prompt string, useResearch bool) []string {
+	args := []string{"update-task", filePath, taskID, "--prompt", prompt}
+
 	if useResearch {
 		args = append(args, "--research")
 	}
 
-	return e.executeCommand("node", args...)
+	return args
 }
 
 // UpdateSubtask executes the update-subtask command
 func (e *CLIExecutor) UpdateSubtask(filePath, taskID, subtaskID, prompt string, useResearch bool) CLIResult {
-	args := []string{e.cliPath, "update-subtask", filePath, taskID, subtaskID, "--prompt", prompt}
-	
+	if err := e.requireProviderKeys(useResearch); err != nil {
+		return missingKeyResult(err)
+	}
+
+	return e.runCLILocked(filePath, buildUpdateSubtaskArgs(filePath, taskID, subtaskID, prompt, useResearch)...)
+}
+
+// buildUpdateSubtaskArgs returns the CLI arguments for UpdateSubtask
+func buildUpdateSubtaskArgs(filePath, taskID, subtaskID, prompt string, useResearch bool) []string {
+	args := []string{"update-subtask", filePath, taskID, subtaskID, "--prompt", prompt}
+
 	if useResearch {
 		args = append(args, "--research")
 	}
 
-	return e.executeCommand("node", args...)
+	return args
 }
 
-// GenerateTaskFiles executes the generate-task-files command
-func (e *CLIExecutor) GenerateTaskFiles(filePath, outputDir string, force bool) CLIResult {
-	args := []string{e.cliPath, "generate-task-files", filePath, outputDir}
-	
-	if force {
-		args = append(args, "--force")
+// GenerateTaskFiles executes the generate command. With taskIDs, only those
+// tasks' files are generated; otherwise every task's is. Existing task files
+// are always overwritten. The CLI fixes the file's dependencies before
+// generating, writing them back, so it runs under the file's lock like the
+// other commands that change it.
+func (e *CLIExecutor) GenerateTaskFiles(filePath, outputDir string, taskIDs []string) CLIResult {
+	return e.runCLILocked(filePath, buildGenerateTaskFilesArgs(filePath, outputDir, taskIDs)...)
+}
+
+// buildGenerateTaskFilesArgs returns the CLI arguments for GenerateTaskFiles
+func buildGenerateTaskFilesArgs(filePath, outputDir string, taskIDs []string) []string {
+	args := []string{"generate", "-f", filePath, "-o", outputDir}
+
+	if len(taskIDs) > 0 {
+		args = append(args, "--id", strings.Join(taskIDs, ","))
 	}
 
-	return e.executeCommand("node", args...)
+	return args
 }
 
 // SetTaskStatus executes the set-task-status command
-func (e *CLIExecutor) SetTaskStatus(filePath, taskID, status string) CLIResult {
-	args := []string{e.cliPath, "set-task-status", filePath, taskID, status}
-	return e.executeCommand("node", args...)
+// criteriaMet confirms a checkpoint's acceptance criteria and is only passed to the CLI when marking a task done
+func (e *CLIExecutor) SetTaskStatus(filePath, taskID, status string, criteriaMet bool) CLIResult {
+	return e.runCLILocked(filePath, buildSetTaskStatusArgs(filePath, taskID, status, criteriaMet)...)
+}
+
+// SetTaskStatuses sets the status of several tasks, running set-task-status
+// once for each. The runs are one at a time: running them concurrently isn't
+// safe, since each reads and rewrites the whole tasks file and would undo the
+// others' changes. Results are in the order of taskIDs.
+func (e *CLIExecutor) SetTaskStatuses(filePath string, taskIDs []string, status string, criteriaMet bool) []CLIResult {
+	return e.runLockedBatch(filePath, len(taskIDs), func(i int) CLIResult {
+		return e.runCLI(buildSetTaskStatusArgs(filePath, taskIDs[i], status, criteriaMet)...)
+	})
+}
+
+// buildSetTaskStatusArgs returns the CLI arguments for SetTaskStatus and each task of SetTaskStatuses
+func buildSetTaskStatusArgs(filePath, taskID, status string, criteriaMet bool) []string {
+	args := []string{"set-task-status", filePath, taskID, status}
+	// The flag only means something when completing a checkpoint
+	if criteriaMet && status == string(StatusDone) {
+		args = append(args, "--criteria-met")
+	}
+
+	return args
+}
+
+// ListTasks executes the list-tasks command. The CLI filters by a single
+// status and priority, so with several of either it runs once per combination
+// and merges the outputs under a heading for each, in the order given. A task
+// has one status and one priority, so no run lists a task another run did;
+// subtasks appear only under their parent. The merged result fails if any of
+// the runs fails.
+func (e *CLIExecutor) ListTasks(filePath string, statuses, priorities []string, showSubtasks bool) CLIResult {
+	if len(statuses) <= 1 && len(priorities) <= 1 {
+		return e.listTasks(filePath, firstOrEmpty(statuses), firstOrEmpty(priorities), showSubtasks)
+	}
+
+	merged := CLIResult{Success: true, Message: "Command executed successfully"}
+	var sections []string
+	for _, status := range orAll(statuses) {
+		for _, priority := range orAll(priorities) {
+			result := e.listTasks(filePath, status, priority, showSubtasks)
+			sections = append(sections, fmt.Sprintf("%s\n%s", listTasksHeading(status, priority), strings.TrimRight(result.Output, "\n")))
+			if !result.Success {
+				merged.Success = false
+				merged.Error = result.Error
+				merged.Message = result.Message
+			}
+		}
+	}
+	merged.Output = strings.Join(sections, "\n\n")
+	return merged
 }
 
-// ListTasks executes the list-tasks command
-func (e *CLIExecutor) ListTasks(filePath, status, priority string, showSubtasks bool) CLIResult {
-	args := []string{e.cliPath, "list-tasks", filePath}
-	
+// listTasksHeading labels the output of one run of a merged list-tasks
+func listTasksHeading(status, priority string) string {
+	var parts []string
+	if status != "" {
+		parts = append(parts, "Status: "+status)
+	}
+	if priority != "" {
+		parts = append(parts, "Priority: "+priority)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// firstOrEmpty returns the first value, or "" for no filter
+func firstOrEmpty(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// orAll returns values, or a single empty (unfiltered) value if there are none
+func orAll(values []string) []string {
+	if len(values) == 0 {
+		return []string{""}
+	}
+	return values
+}
+
+// listTasks runs list-tasks with at most one status and priority filter
+func (e *CLIExecutor) listTasks(filePath, status, priority string, showSubtasks bool) CLIResult {
+	return e.runCLI(buildListTasksArgs(filePath, status, priority, showSubtasks)...)
+}
+
+// buildListTasksArgs returns the CLI arguments for one run of ListTasks
+func buildListTasksArgs(filePath, status, priority string, showSubtasks bool) []string {
+	args := []string{"list-tasks", filePath}
+
 	if status != "" {
 		args = append(args, "--status", status)
 	}
@@ -159,13 +473,22 @@ func (e *CLIExecutor) ListTasks(filePath, status, priority string, showSubtasks
 		args = append(args, "--show-subtasks")
 	}
 
-	return e.executeCommand("node", args...)
+	return args
 }
 
 // ExpandTask executes the expand-task command
 func (e *CLIExecutor) ExpandTask(filePath, taskID, prompt string, numSubtasks int, useResearch bool) CLIResult {
-	args := []string{e.cliPath, "expand-task", filePath, taskID, "--prompt", prompt}
-	
+	if err := e.requireProviderKeys(useResearch); err != nil {
+		return missingKeyResult(err)
+	}
+
+	return e.runCLILocked(filePath, buildExpandTaskArgs(filePath, taskID, prompt, numSubtasks, useResearch)...)
+}
+
+// buildExpandTaskArgs returns the CLI arguments for ExpandTask
+func buildExpandTaskArgs(filePath, taskID, prompt string, numSubtasks int, useResearch bool) []string {
+	args := []string{"expand-task", filePath, taskID, "--prompt", prompt}
+
 	if numSubtasks > 0 {
 		args = append(args, fmt.Sprintf("--num-subtasks=%d", numSubtasks))
 	}
@@ -173,13 +496,22 @@ func (e *CLIExecutor) ExpandTask(filePath, taskID, prompt string, numSubtasks in
 		args = append(args, "--research")
 	}
 
-	return e.executeCommand("node", args...)
+	return args
 }
 
 // AnalyzeComplexity executes the analyze-complexity command
 func (e *CLIExecutor) AnalyzeComplexity(filePath string, threshold int, outputPath string) CLIResult {
-	args := []string{e.cliPath, "analyze-complexity", filePath}
-	
+	if err := e.requireProviderKeys(false); err != nil {
+		return missingKeyResult(err)
+	}
+
+	return e.runCLI(buildAnalyzeComplexityArgs(filePath, threshold, outputPath)...)
+}
+
+// buildAnalyzeComplexityArgs returns the CLI arguments for AnalyzeComplexity
+func buildAnalyzeComplexityArgs(filePath string, threshold int, outputPath string) []string {
+	args := []string{"analyze-complexity", filePath}
+
 	if threshold > 0 {
 		args = append(args, fmt.Sprintf("--threshold=%d", threshold))
 	}
@@ -187,32 +519,275 @@ func (e *CLIExecutor) AnalyzeComplexity(filePath string, threshold int, outputPa
 		args = append(args, "--output", outputPath)
 	}
 
-	return e.executeCommand("node", args...)
+	return args
 }
 
 // ClearSubtasks executes the clear-subtasks command
 func (e *CLIExecutor) ClearSubtasks(filePath, taskID string) CLIResult {
-	args := []string{e.cliPath, "clear-subtasks", filePath, taskID}
-	return e.executeCommand("node", args...)
+	return e.runCLILocked(filePath, buildClearSubtasksArgs(filePath, taskID)...)
+}
+
+// buildClearSubtasksArgs returns the CLI arguments for ClearSubtasks
+func buildClearSubtasksArgs(filePath, taskID string) []string {
+	return []string{"clear-subtasks", filePath, taskID}
+}
+
+// RemoveSubtask executes the remove-subtask command for a dotted subtask ID
+// like "5.2". With convert the subtask becomes a standalone task instead of
+// being deleted; the result's message says which happened.
+func (e *CLIExecutor) RemoveSubtask(filePath, subtaskID string, convert bool) CLIResult {
+	if err := validateSubtaskID(subtaskID); err != nil {
+		return CLIResult{Success: false, Error: err.Error(), Message: err.Error()}
+	}
+
+	result := e.runCLILocked(filePath, buildRemoveSubtaskArgs(filePath, subtaskID, convert)...)
+	if result.Success {
+		if convert {
+			result.Message = fmt.Sprintf("Subtask %s converted to a standalone task", subtaskID)
+		} else {
+			result.Message = fmt.Sprintf("Subtask %s removed", subtaskID)
+		}
+	}
+	return result
+}
+
+// buildRemoveSubtaskArgs returns the CLI arguments for RemoveSubtask
+func buildRemoveSubtaskArgs(filePath, subtaskID string, convert bool) []string {
+	args := []string{"remove-subtask", "--file", filePath, "--id", subtaskID}
+	if convert {
+		args = append(args, "--convert")
+	}
+	return args
+}
+
+// MoveTask executes the move command, which moves a task or subtask to the
+// destination ID. Either can be a dotted subtask ID, so "1.2" to "3.1" moves a
+// subtask under another parent; the CLI renumbers what it moves.
+func (e *CLIExecutor) MoveTask(filePath, fromID, toID string) CLIResult {
+	if err := validateMovePair(fromID, toID); err != nil {
+		return CLIResult{Success: false, Error: err.Error(), Message: err.Error()}
+	}
+
+	result := e.runCLILocked(filePath, buildMoveTaskArgs(filePath, fromID, toID)...)
+	if result.Success {
+		result.Message = fmt.Sprintf("Moved %s to %s", fromID, toID)
+	}
+	return result
+}
+
+// taskMove is one move of a task to a new ID, see MoveTasks.
+type taskMove struct {
+	from, to string
+}
+
+// MoveTasks runs the moves in order, holding the lock on the tasks file
+// throughout. Each move depends on the ones before it, so once one fails the
+// rest aren't run and are reported as skipped. Results are in the order of moves.
+func (e *CLIExecutor) MoveTasks(filePath string, moves []taskMove) []CLIResult {
+	failed := false
+	return e.runLockedBatch(filePath, len(moves), func(i int) CLIResult {
+		if failed {
+			return CLIResult{Success: false, Error: "not run: an earlier move failed"}
+		}
+		result := e.runCLI(buildMoveTaskArgs(filePath, moves[i].from, moves[i].to)...)
+		failed = !result.Success
+		return result
+	})
+}
+
+// buildMoveTaskArgs returns the CLI arguments for MoveTask
+func buildMoveTaskArgs(filePath, fromID, toID string) []string {
+	return []string{"move", "--file", filePath, "--from", fromID, "--to", toID}
+}
+
+// ValidateDependencies executes the validate-dependencies command, which
+// reports invalid dependencies without changing the file
+func (e *CLIExecutor) ValidateDependencies(filePath string) CLIResult {
+	return e.runCLI(buildValidateDependenciesArgs(filePath)...)
+}
+
+// buildValidateDependenciesArgs returns the CLI arguments for ValidateDependencies
+func buildValidateDependenciesArgs(filePath string) []string {
+	return []string{"validate-dependencies", "--file", filePath}
+}
+
+// FixDependencies executes the fix-dependencies command, which removes invalid
+// and circular dependencies from the file
+func (e *CLIExecutor) FixDependencies(filePath string) CLIResult {
+	return e.runCLILocked(filePath, buildFixDependenciesArgs(filePath)...)
+}
+
+// buildFixDependenciesArgs returns the CLI arguments for FixDependencies
+func buildFixDependenciesArgs(filePath string) []string {
+	return []string{"fix-dependencies", "--file", filePath}
+}
+
+// Models executes the models command. Empty model IDs leave that role
+// unchanged; with none set the CLI prints the current configuration.
+func (e *CLIExecutor) Models(mainModel, researchModel, fallbackModel string) CLIResult {
+	return e.runCLI(buildModelsArgs(mainModel, researchModel, fallbackModel)...)
+}
+
+// buildModelsArgs returns the CLI arguments for Models
+func buildModelsArgs(mainModel, researchModel, fallbackModel string) []string {
+	args := []string{"models"}
+
+	if mainModel != "" {
+		args = append(args, "--set-main", mainModel)
+	}
+	if researchModel != "" {
+		args = append(args, "--set-research", researchModel)
+	}
+	if fallbackModel != "" {
+		args = append(args, "--set-fallback", fallbackModel)
+	}
+
+	return args
+}
+
+// InitProject executes the init command in dir, creating a new project there.
+// Prompts are skipped, so name is required and the remaining options default.
+func (e *CLIExecutor) InitProject(dir, name, description string, addAliases, skipInstall bool) CLIResult {
+	// The command runs outside the usual directory, so the script path can't stay relative
+	cliPath, err := filepath.Abs(e.cliPath)
+	if err != nil {
+		return CLIResult{
+			Success: false,
+			Error:   err.Error(),
+			Message: fmt.Sprintf("Failed to resolve CLI path: %s", err.Error()),
+		}
+	}
+
+	local := *e
+	local.cliPath = cliPath
+	command, args := local.cliInvocation(buildInitProjectArgs(name, description, addAliases, skipInstall))
+	return e.executeCommandIn(dir, command, args...)
+}
+
+// buildInitProjectArgs returns the CLI arguments for InitProject
+func buildInitProjectArgs(name, description string, addAliases, skipInstall bool) []string {
+	args := []string{"init", "--yes", "--name", name}
+
+	if description != "" {
+		args = append(args, "--description", description)
+	}
+	if addAliases {
+		args = append(args, "--aliases")
+	}
+	if skipInstall {
+		args = append(args, "--skip-install")
+	}
+
+	return args
+}
+
+// cliInvocation returns the command and full argument list that run the CLI
+// with args using the executor's runner, in the executor's tag, with its
+// research model and followed by its extra arguments.
+func (e *CLIExecutor) cliInvocation(args []string) (string, []string) {
+	args = e.assumeYesArgs(e.extraArgsAppended(e.researchModelArgs(e.tagArgs(args))))
+	if e.Runner == RunnerNpx {
+		return "npx", append([]string{"--yes", npxPackage}, args...)
+	}
+	return "node", append([]string{e.cliPath}, args...)
+}
+
+// confirmingCommands are the CLI commands that ask for confirmation unless
+// given --yes. Nothing can answer them, see execRunner.
+var confirmingCommands = map[string]bool{"remove-task": true, "init": true}
+
+// assumeYesArgs appends --yes to a command that would otherwise ask for
+// confirmation, when the executor assumes yes and it isn't there already.
+func (e *CLIExecutor) assumeYesArgs(args []string) []string {
+	if !e.AssumeYes || len(args) == 0 || !confirmingCommands[args[0]] || slices.Contains(args, "--yes") {
+		return args
+	}
+	return append(args[:len(args):len(args)], "--yes")
+}
+
+// runCLI runs the CLI with args and returns the result
+func (e *CLIExecutor) runCLI(args ...string) CLIResult {
+	command, args := e.cliInvocation(args)
+	return e.executeCommand(command, args...)
+}
+
+// runCLILocked runs the CLI with args while holding the lock on filePath, see executeLocked
+func (e *CLIExecutor) runCLILocked(filePath string, args ...string) CLIResult {
+	command, args := e.cliInvocation(args)
+	return e.executeLocked(filePath, command, args...)
 }
 
 // executeCommand runs a command and returns the result
 func (e *CLIExecutor) executeCommand(command string, args ...string) CLIResult {
-	cmd := exec.Command(command, args...)
-	
-	// Set the working directory to the parent of the TUI directory
-	if wd, err := os.Getwd(); err == nil {
-		cmd.Dir = filepath.Join(wd, "..")
-	}
-	
-	// Capture both stdout and stderr
-	output, err := cmd.CombinedOutput()
-	
+	return e.executeCommandIn(e.workDir(), command, args...)
+}
+
+// executeCommandIn runs a command in dir and returns the result
+func (e *CLIExecutor) executeCommandIn(dir, command string, args ...string) CLIResult {
+	if e.jsonOutput {
+		args = append(args, jsonFlag)
+	}
+	if e.DryRun {
+		debugLog.Debug("dry run", "command", command, "args", args, "dir", dir)
+		result := dryRunResult(dir, command, args)
+		rememberResult(resultRecord{Time: time.Now(), Args: append([]string{command}, args...), Dir: dir, Result: result})
+		return result
+	}
+	ctx := e.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cancelled := ctx
+	if e.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.Timeout)
+		defer cancel()
+	}
+
+	// Capture stdout and stderr together, forwarding complete lines when
+	// streaming, up to the output limit
+	var output bytes.Buffer
+	lines := &lineWriter{buf: &output, onLine: e.onOutput}
+	writer := newLimitedWriter(lines, e.maxOutputBytes())
+	runner := e.commandRunner
+	if runner == nil {
+		runner = &execRunner{ctx: ctx, dir: dir, env: e.commandEnv(), output: writer, limit: e.maxOutputBytes()}
+	}
+
+	debugLog.Debug("running command", "command", command, "args", args, "dir", dir)
+	start := time.Now()
+	stdout, stderr, err, exitCode := runner.Run(command, args...)
+	if e.commandRunner != nil {
+		// Other runners only report output once the command is done
+		writer.Write(stdout)
+		writer.Write(stderr)
+	}
+	lines.flush()
+	if writer.wasTruncated() {
+		output.WriteString(truncatedNotice)
+		debugLog.Error("command output truncated", "command", command, "args", args, "limit", e.maxOutputBytes())
+	}
+
 	result := CLIResult{
-		Output: string(output),
+		Output:   output.String(),
+		ExitCode: exitCode,
+		Stdout:   string(stdout),
+		Stderr:   string(stderr),
 	}
-	
-	if err != nil {
+
+	if cancelled.Err() != nil {
+		result.Success = false
+		result.Error = "command cancelled"
+		result.Message = "Command was cancelled"
+	} else if ctx.Err() != nil {
+		result.Success = false
+		result.Error = fmt.Sprintf("command timed out after %s", e.Timeout)
+		result.Message = "Command timed out; raise the timeout in the settings if it needs longer"
+	} else if errors.Is(err, exec.ErrNotFound) && (command == "node" || command == "npx") {
+		result.Success = false
+		result.Error = nodeNotFoundMessage
+		result.Message = nodeNotFoundMessage
+	} else if err != nil {
 		result.Success = false
 		result.Error = err.Error()
 		result.Message = fmt.Sprintf("Command failed: %s", err.Error())
@@ -220,9 +795,174 @@ func (e *CLIExecutor) executeCommand(command string, args ...string) CLIResult {
 		result.Success = true
 		result.Message = "Command executed successfully"
 	}
-	
+	if e.jsonOutput {
+		applyJSONOutput(&result, stdout)
+	}
+	if result.Success {
+		debugLog.Info("command finished", "command", command, "args", args, "duration", time.Since(start))
+	} else {
+		debugLog.Error("command failed", "command", command, "args", args, "exit", exitCode,
+			"error", result.Error, "stderr", string(stderr), "duration", time.Since(start))
+	}
+
+	recordHistory(historyEntry{
+		Time:     start,
+		Args:     append([]string{command}, args...),
+		Dir:      dir,
+		Success:  result.Success,
+		Error:    result.Error,
+		Duration: time.Since(start),
+	})
+	rememberResult(resultRecord{Time: start, Args: append([]string{command}, args...), Dir: dir, Result: result})
+
 	return result
 }
 
+// lineWriter buffers command output and passes each complete line to onLine.
+// It is safe for stdout and stderr to write concurrently.
+type lineWriter struct {
+	mu      sync.Mutex
+	buf     *bytes.Buffer
+	onLine  func(line string)
+	partial []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf.Write(p)
+	if w.onLine == nil {
+		return len(p), nil
+	}
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.onLine(strings.TrimRight(string(w.partial[:i]), "\r"))
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush passes along any trailing output that didn't end with a newline.
+func (w *lineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.onLine != nil && len(w.partial) > 0 {
+		w.onLine(string(w.partial))
+		w.partial = nil
+	}
+}
+
+// executeLocked runs a command that mutates the given tasks file while holding its lock file,
+// so two TUI instances can't clobber each other's changes
+func (e *CLIExecutor) executeLocked(filePath, command string, args ...string) CLIResult {
+	lock, err := acquireFileLock(filePath)
+	if errors.Is(err, errLockHeld) {
+		return lockHeldResult(err)
+	}
+	if lock != nil {
+		defer lock.Release()
+	}
+	// Any other lock error (e.g. the directory doesn't exist yet) is left for the CLI to report
+
+	if result, ok := e.backupBeforeChange(filePath); !ok {
+		return result
+	}
+	return e.executeCommand(command, args...)
+}
+
+// runLockedBatch holds the lock on filePath for the whole batch while fn runs
+// for each of n items in turn. The per-item commands must not take the lock
+// themselves, since this process already holds it.
+func (e *CLIExecutor) runLockedBatch(filePath string, n int, fn func(i int) CLIResult) []CLIResult {
+	lock, err := acquireFileLock(filePath)
+	if errors.Is(err, errLockHeld) {
+		results := make([]CLIResult, n)
+		for i := range results {
+			results[i] = lockHeldResult(err)
+		}
+		return results
+	}
+	if lock != nil {
+		defer lock.Release()
+	}
+
+	// One backup covers the whole batch, so undo reverts all of it
+	if result, ok := e.backupBeforeChange(filePath); !ok {
+		results := make([]CLIResult, n)
+		for i := range results {
+			results[i] = result
+		}
+		return results
+	}
+	results := make([]CLIResult, n)
+	for i := range results {
+		results[i] = fn(i)
+	}
+	return results
+}
+
+// backupBeforeChange backs up a tasks file before a command changes it, see
+// UndoLastChange. If the backup fails it returns a result explaining why and
+// false, and the command must not run.
+func (e *CLIExecutor) backupBeforeChange(filePath string) (CLIResult, bool) {
+	if e.DryRun {
+		return CLIResult{}, true // Nothing will change
+	}
+	if err := backupTasksFile(filePath); err != nil {
+		return CLIResult{
+			Success: false,
+			Error:   fmt.Sprintf("failed to back up %s: %v", filePath, err),
+			Message: fmt.Sprintf("Command not run: failed to back up %s: %v", filePath, err),
+		}, false
+	}
+	return CLIResult{}, true
+}
+
+// lockHeldResult reports a command that was not run because the tasks file is locked.
+func lockHeldResult(err error) CLIResult {
+	return CLIResult{
+		Success: false,
+		Error:   err.Error(),
+		Message: fmt.Sprintf("Command not run: %s", err.Error()),
+	}
+}
+
+// commandDir returns the directory the global executor's commands run in.
+func commandDir() string {
+	return cliExecutor.workDir()
+}
+
+// workDir returns the directory the executor's commands run in: WorkDir,
+// made absolute, if set, otherwise defaultWorkDir.
+func (e *CLIExecutor) workDir() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return e.WorkDir
+	}
+	if e.WorkDir != "" {
+		if filepath.IsAbs(e.WorkDir) {
+			return e.WorkDir
+		}
+		return filepath.Join(wd, e.WorkDir)
+	}
+	return defaultWorkDir(wd)
+}
+
+// defaultWorkDir returns the directory commands run in when none is set, for
+// a TUI started in wd: the root of the checkout when started from its tui
+// directory, as in development, otherwise wd itself, e.g. a project directory
+// for an installed binary.
+func defaultWorkDir(wd string) string {
+	root := filepath.Dir(wd)
+	if _, err := os.Stat(filepath.Join(root, "scripts", "dev.js")); err == nil && filepath.Base(wd) == "tui" {
+		return root
+	}
+	return wd
+}
+
 // Global CLI executor instance
 var cliExecutor = NewCLIExecutor()
\ No newline at end of file
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// jsonFlag asks the CLI to print a structured result instead of text.
+const jsonFlag = "--json"
+
+// cliJSONPayload is the result the CLI prints to stdout in JSON mode.
+type cliJSONPayload struct {
+	Success *bool           `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Message string          `json:"message"`
+	Error   string          `json:"error"`
+}
+
+// WithJSON returns a copy of the executor that passes --json to commands and
+// parses their structured output. Only use it for commands whose CLI supports
+// the flag, which add-task does.
+func (e *CLIExecutor) WithJSON() *CLIExecutor {
+	structured := *e
+	structured.jsonOutput = true
+	return &structured
+}
+
+// applyJSONOutput fills result from the JSON payload on stdout. Anything the
+// CLI prints after the payload, such as an update notice, is ignored. If
+// stdout does not start with a valid payload the result is left as plain text.
+func applyJSONOutput(result *CLIResult, stdout []byte) {
+	trimmed := bytes.TrimSpace(stdout)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return
+	}
+	var payload cliJSONPayload
+	if err := json.NewDecoder(bytes.NewReader(trimmed)).Decode(&payload); err != nil {
+		return
+	}
+
+	result.Data = payload.Data
+	if payload.Message != "" {
+		result.Message = payload.Message
+	}
+	// A command can exit cleanly but still report failure in its payload
+	if payload.Success != nil && !*payload.Success {
+		result.Success = false
+	}
+	if payload.Error != "" {
+		result.Success = false
+		result.Error = payload.Error
+	}
+}
+
+// decodeResultData unmarshals the structured data of a JSON mode result.
+func decodeResultData[T any](result CLIResult) (T, error) {
+	var data T
+	if len(result.Data) == 0 {
+		return data, fmt.Errorf("command returned no structured data")
+	}
+	if err := json.Unmarshal(result.Data, &data); err != nil {
+		return data, fmt.Errorf("failed to parse command data: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestApplyJSONOutput(t *testing.T) {
+	tests := []struct {
+		name        string
+		stdout      string
+		exitSuccess bool
+		wantSuccess bool
+		wantData    string
+		wantError   string
+	}{
+		{
+			name:        "successful payload",
+			stdout:      `{"success": true, "data": {"id": 3}}`,
+			exitSuccess: true,
+			wantSuccess: true,
+			wantData:    `{"id": 3}`,
+		},
+		{
+			name:        "payload reports failure",
+			stdout:      `{"success": false, "error": "Task 9 not found"}`,
+			exitSuccess: true,
+			wantSuccess: false,
+			wantError:   "Task 9 not found",
+		},
+		{
+			name:        "plain text falls back",
+			stdout:      "Listing tasks from tasks.json\n1. Setup",
+			exitSuccess: true,
+			wantSuccess: true,
+		},
+		{
+			name:        "text after the payload",
+			stdout:      "{\"success\": true, \"data\": {\"id\": 3}}\nUpdate available: 1.2.0\n",
+			exitSuccess: true,
+			wantSuccess: true,
+			wantData:    `{"id": 3}`,
+		},
+		{
+			name:        "truncated JSON falls back",
+			stdout:      `{"success": true, "data": [`,
+			exitSuccess: true,
+			wantSuccess: true,
+		},
+		{
+			name:        "failed exit is not overridden by payload",
+			stdout:      `{"success": true, "data": []}`,
+			exitSuccess: false,
+			wantSuccess: false,
+			wantData:    `[]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CLIResult{Success: tt.exitSuccess, Output: tt.stdout}
+			applyJSONOutput(&result, []byte(tt.stdout))
+
+			if result.Success != tt.wantSuccess {
+				t.Errorf("Success = %v, want %v", result.Success, tt.wantSuccess)
+			}
+			if string(result.Data) != tt.wantData {
+				t.Errorf("Data = %s, want %s", result.Data, tt.wantData)
+			}
+			if result.Error != tt.wantError {
+				t.Errorf("Error = %q, want %q", result.Error, tt.wantError)
+			}
+			if result.Output != tt.stdout {
+				t.Errorf("Output = %q, want the raw output kept", result.Output)
+			}
+		})
+	}
+}
+
+func TestDecodeResultData(t *testing.T) {
+	type taskRef struct {
+		ID int `json:"id"`
+	}
+
+	got, err := decodeResultData[taskRef](CLIResult{Data: []byte(`{"id": 7}`)})
+	if err != nil || got.ID != 7 {
+		t.Errorf("decodeResultData() = %+v, %v, want ID 7", got, err)
+	}
+
+	if _, err := decodeResultData[taskRef](CLIResult{}); err == nil {
+		t.Error("decodeResultData() with no data should fail")
+	}
+	if _, err := decodeResultData[taskRef](CLIResult{Data: []byte(`"text"`)}); err == nil {
+		t.Error("decodeResultData() with mismatched data should fail")
+	}
+}
+
+func TestAddTaskInJSONMode(t *testing.T) {
+	fake := &fakeRunner{stdout: `{"success": true, "data": {"id": 12}, "message": "Added new standard task #12"}` + "\n"}
+	useFakeRunner(t, fake)
+
+	result := cliExecutor.WithJSON().AddTask("tasks.json", "", "", "Docs", "Write them", "", "", "", "", "", "", false)
+	if len(fake.calls) != 1 {
+		t.Fatalf("ran %q, want add-task", fake.calls)
+	}
+	checkCLIArgs(t, fake.calls[0][2:])
+	if got := createdTaskID(result); !result.Success || got != "12" || result.Message != "Added new standard task #12" {
+		t.Errorf("result = %+v with created ID %q, want task 12 added", result, got)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var (
+	cliCommandPattern  = regexp.MustCompile(`\.command\(\s*'([^' ]+)[^']*'`)
+	cliOptionPattern   = regexp.MustCompile(`\.(?:option|requiredOption)\(\s*'([^']+)'`)
+	cliFlagPattern     = regexp.MustCompile(`--?[A-Za-z][\w-]*`)
+	cliFunctionPattern = regexp.MustCompile(`\n(?:async )?function `)
+)
+
+// cliProgramCommand is the key cliOptions uses for the options of the program
+// itself, which every command accepts.
+const cliProgramCommand = ""
+
+// cliOptions reads the CLI's commands.js and returns the flags each command
+// defines, so tests can check the TUI only sends commands and options the CLI
+// actually has.
+func cliOptions(t *testing.T) map[string]map[string]bool {
+	t.Helper()
+	data, err := os.ReadFile("../scripts/modules/commands.js")
+	if err != nil {
+		t.Fatalf("reading the CLI's commands: %v", err)
+	}
+	src := string(data)
+	commands := map[string]map[string]bool{}
+	for _, loc := range cliCommandPattern.FindAllStringSubmatchIndex(src, -1) {
+		commands[src[loc[2]:loc[3]]] = cliFlags(src[loc[1]:])
+	}
+	if start := strings.Index(src, "function setupCLI("); start >= 0 {
+		commands[cliProgramCommand] = cliFlags(src[start+1:])
+	}
+	return commands
+}
+
+// cliFlags returns the flags of the options defined in src up to the next
+// command or function.
+func cliFlags(src string) map[string]bool {
+	for _, next := range []*regexp.Regexp{cliCommandPattern, cliFunctionPattern} {
+		if loc := next.FindStringIndex(src); loc != nil {
+			src = src[:loc[0]]
+		}
+	}
+	flags := map[string]bool{}
+	for _, opt := range cliOptionPattern.FindAllStringSubmatch(src, -1) {
+		for _, flag := range cliFlagPattern.FindAllString(opt[1], -1) {
+			flags[flag] = true
+		}
+	}
+	return flags
+}
+
+// checkCLIArgs fails t unless args, as built for the CLI, name a command the
+// CLI defines and only use options that command defines.
+func checkCLIArgs(t *testing.T, args []string) {
+	t.Helper()
+	if len(args) == 0 {
+		t.Fatal("no CLI arguments")
+	}
+	commands := cliOptions(t)
+	flags, ok := commands[args[0]]
+	if !ok {
+		t.Fatalf("the CLI has no %q command (args %q)", args[0], args)
+	}
+	for _, arg := range args[1:] {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, _, _ := strings.Cut(arg, "=")
+		if !flags[name] && !commands[cliProgramCommand][name] {
+			t.Errorf("the CLI's %s command has no %s option (args %q)", args[0], name, args)
+		}
+	}
+}
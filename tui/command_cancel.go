@@ -0,0 +1,40 @@
+package main
+
+import "context"
+
+// commandCancel lets a form cancel the command it is running, killing the CLI
+// process without quitting the TUI.
+type commandCancel struct {
+	cancel    context.CancelFunc
+	requested bool
+}
+
+// start returns the context for a new run, replacing any previous one.
+func (c *commandCancel) start() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.requested = false
+	return ctx
+}
+
+// request cancels the running command. It reports false if nothing is running.
+func (c *commandCancel) request() bool {
+	if c.cancel == nil {
+		return false
+	}
+	c.cancel()
+	c.requested = true
+	return true
+}
+
+// finish releases the run's context once its command has completed and
+// reports whether the run was cancelled.
+func (c *commandCancel) finish() bool {
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+	requested := c.requested
+	c.requested = false
+	return requested
+}
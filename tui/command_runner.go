@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+)
+
+// CommandRunner runs an external command, returning its stdout, its stderr,
+// the error from running it and its exit code (-1 if it didn't exit normally).
+type CommandRunner interface {
+	Run(name string, args ...string) ([]byte, []byte, error, int)
+}
+
+// WithRunner returns a copy of the executor that runs commands with runner
+// instead of starting processes, e.g. a fake in tests.
+func (e *CLIExecutor) WithRunner(runner CommandRunner) *CLIExecutor {
+	withRunner := *e
+	withRunner.commandRunner = runner
+	return &withRunner
+}
+
+// execRunner is the CommandRunner that starts real processes.
+type execRunner struct {
+	ctx    context.Context // Kills the process when cancelled
+	dir    string
+	env    []string
+	output io.Writer // Also receives stdout and stderr, interleaved, as they are produced
+	limit  int64     // Bytes of stdout and of stderr kept, the rest discarded
+}
+
+func (r *execRunner) Run(name string, args ...string) ([]byte, []byte, error, int) {
+	cmd := exec.CommandContext(r.ctx, name, args...)
+	cmd.WaitDelay = commandWaitDelay
+	cmd.Dir = r.dir
+	cmd.Env = r.env
+	// Stdin is left unset, so it is the null device: a prompt the CLI shows
+	// reads end of input and fails at once rather than waiting for an answer
+	// that can't come
+
+	var stdout, stderr bytes.Buffer
+	limitedStdout, limitedStderr := newLimitedWriter(&stdout, r.limit), newLimitedWriter(&stderr, r.limit)
+	cmd.Stdout = io.MultiWriter(r.output, limitedStdout)
+	cmd.Stderr = io.MultiWriter(r.output, limitedStderr)
+
+	err := cmd.Run()
+	if limitedStdout.wasTruncated() {
+		stdout.WriteString(truncatedNotice)
+	}
+	if limitedStderr.wasTruncated() {
+		stderr.WriteString(truncatedNotice)
+	}
+	exitCode := -1
+	var exitErr *exec.ExitError
+	if err == nil {
+		exitCode = 0
+	} else if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	}
+	return stdout.Bytes(), stderr.Bytes(), err, exitCode
+}
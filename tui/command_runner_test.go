@@ -0,0 +1,167 @@
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRunner is a CommandRunner that records the commands it is asked to run
+// and returns a canned result instead of running them.
+type fakeRunner struct {
+	stdout, stderr string
+	err            error
+	exitCode       int
+
+	mu    sync.Mutex // Batches run commands from several goroutines
+	calls [][]string // Name and arguments of each command run
+}
+
+func (f *fakeRunner) Run(name string, args ...string) ([]byte, []byte, error, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, append([]string{name}, args...))
+	return []byte(f.stdout), []byte(f.stderr), f.err, f.exitCode
+}
+
+// useFakeRunner points the global executor at fake for the rest of the test.
+// History is written to a temporary config directory.
+func useFakeRunner(t *testing.T, fake *fakeRunner) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	saved := cliExecutor
+	cliExecutor = (&CLIExecutor{cliPath: "../scripts/dev.js"}).WithRunner(fake)
+	t.Cleanup(func() { cliExecutor = saved })
+}
+
+func TestExecutorUsesRunner(t *testing.T) {
+	fake := &fakeRunner{stdout: "Task 3: Write docs\n"}
+	useFakeRunner(t, fake)
+
+	var streamed []string
+	result := cliExecutor.WithOutput(func(line string) { streamed = append(streamed, line) }).ShowTask("tasks.json", "3")
+
+	if !result.Success || result.ExitCode != 0 {
+		t.Fatalf("ShowTask() = %+v, want success", result)
+	}
+	if result.Output != fake.stdout {
+		t.Errorf("Output = %q, want %q", result.Output, fake.stdout)
+	}
+	if want := []string{"Task 3: Write docs"}; !reflect.DeepEqual(streamed, want) {
+		t.Errorf("streamed %q, want %q", streamed, want)
+	}
+	if want := [][]string{{"node", "../scripts/dev.js", "show-task", "tasks.json", "3"}}; !reflect.DeepEqual(fake.calls, want) {
+		t.Errorf("ran %q, want %q", fake.calls, want)
+	}
+}
+
+func TestExecutorReportsRunnerFailure(t *testing.T) {
+	fake := &fakeRunner{stderr: "Error: Task 9 not found\n", err: errors.New("exit status 1"), exitCode: 1}
+	useFakeRunner(t, fake)
+
+	result := cliExecutor.ShowTask("tasks.json", "9")
+	if result.Success {
+		t.Fatal("ShowTask() succeeded, want failure")
+	}
+	if result.ExitCode != 1 || result.Error != "exit status 1" {
+		t.Errorf("ShowTask() = %+v, want exit code 1 and its error", result)
+	}
+	if !strings.Contains(result.Output, "Task 9 not found") {
+		t.Errorf("Output = %q, want the command's stderr", result.Output)
+	}
+}
+
+func TestFixDependenciesFormShowsFakeResult(t *testing.T) {
+	fake := &fakeRunner{stdout: "[WARN] Removing duplicate dependency from task 2: 1\n"}
+	useFakeRunner(t, fake)
+	filePath := filepath.Join(t.TempDir(), "tasks.json")
+
+	m := NewFixDependenciesForm()
+	m.FilePath = filePath
+	m.Init()
+	m.Update(fixDependenciesCompleteMsg{result: cliExecutor.FixDependencies(filePath)})
+
+	if m.statusMsg != "✅ Success!" {
+		t.Errorf("statusMsg = %q, want success", m.statusMsg)
+	}
+	if want := [][]string{{"node", "../scripts/dev.js", "fix-dependencies", "--file", filePath}}; !reflect.DeepEqual(fake.calls, want) {
+		t.Errorf("ran %q, want %q", fake.calls, want)
+	}
+}
+
+func TestAssumeYesArgs(t *testing.T) {
+	e := &CLIExecutor{AssumeYes: true}
+	tests := []struct {
+		name string
+		e    *CLIExecutor
+		args []string
+		want []string
+	}{
+		{name: "confirming command", e: e, args: []string{"remove-task", "--id=3"}, want: []string{"remove-task", "--id=3", "--yes"}},
+		{name: "already there", e: e, args: []string{"init", "--yes"}, want: []string{"init", "--yes"}},
+		{name: "other command", e: e, args: []string{"show-task", "tasks.json", "3"}, want: []string{"show-task", "tasks.json", "3"}},
+		{name: "not assumed", e: &CLIExecutor{}, args: []string{"remove-task", "--id=3"}, want: []string{"remove-task", "--id=3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.assumeYesArgs(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("assumeYesArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptDoesNotWaitForInput(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// A prompt reading its answer gets end of input at once
+	e := &CLIExecutor{Timeout: 5 * time.Second}
+	result := e.executeCommand("sh", "-c", `printf 'Continue? (y/N) '; read answer || { echo "no input"; exit 3; }`)
+	if result.Success || result.ExitCode != 3 || !strings.Contains(result.Output, "no input") {
+		t.Errorf("prompting command = %+v, want it to fail on end of input", result)
+	}
+}
+
+func TestExecutorTruncatesRunawayOutput(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// Prints 3 MB to stdout and a line to stderr, over a 1 MB limit
+	e := &CLIExecutor{Timeout: 10 * time.Second, MaxOutputMB: 1}
+	result := e.executeCommand("sh", "-c", `head -c 3145728 /dev/zero | tr '\0' x; echo done >&2`)
+	if !result.Success {
+		t.Fatalf("runaway command = %+v, want it to run to completion", result.Error)
+	}
+	if !strings.HasSuffix(result.Output, truncatedNotice) || len(result.Output) > 1<<20+len(truncatedNotice) {
+		t.Errorf("Output is %d bytes ending %q, want 1 MB and the truncation noted", len(result.Output), result.Output[max(len(result.Output)-30, 0):])
+	}
+	if !strings.HasSuffix(result.Stdout, truncatedNotice) || len(result.Stdout) != 1<<20+len(truncatedNotice) {
+		t.Errorf("Stdout is %d bytes, want 1 MB and the truncation noted", len(result.Stdout))
+	}
+	if result.Stderr != "done\n" {
+		t.Errorf("Stderr = %q, want it kept under its own limit", result.Stderr)
+	}
+}
+
+func TestLimitedWriter(t *testing.T) {
+	var b strings.Builder
+	w := newLimitedWriter(&b, 5)
+	for _, s := range []string{"abc", "def", "ghi"} {
+		if n, err := w.Write([]byte(s)); n != len(s) || err != nil {
+			t.Errorf("Write(%q) = %d, %v, want every byte reported written", s, n, err)
+		}
+	}
+	if b.String() != "abcde" || !w.wasTruncated() {
+		t.Errorf("kept %q, truncated %v, want \"abcde\" and truncated", b.String(), w.wasTruncated())
+	}
+}
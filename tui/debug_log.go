@@ -0,0 +1,64 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// logLevelEnv turns on the debug log at a level, "debug", "info" or "error".
+// The log is off when it's unset.
+const logLevelEnv = "TASKMASTER_LOG_LEVEL"
+
+// debugLog records what the TUI does, for attaching to bug reports. It writes
+// to debugLogPath only, never to the terminal, which bubbletea owns, and
+// discards everything until openDebugLog turns it on.
+var debugLog = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// debugLogPath returns the location of the debug log under the user's config directory.
+func debugLogPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "taskmaster-tui", "debug.log"), nil
+}
+
+// parseLogLevel returns the level named by s, in any case.
+func parseLogLevel(s string) (slog.Level, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, true
+	case "info":
+		return slog.LevelInfo, true
+	case "error":
+		return slog.LevelError, true
+	}
+	return 0, false
+}
+
+// openDebugLog turns the debug log on at the level in TASKMASTER_LOG_LEVEL,
+// appending to debugLogPath, and returns a function that closes it. The log
+// stays off if the level is unset or unknown, or the file can't be opened.
+func openDebugLog() (closeLog func()) {
+	level, ok := parseLogLevel(os.Getenv(logLevelEnv))
+	if !ok {
+		return func() {}
+	}
+	path, err := debugLogPath()
+	if err != nil {
+		return func() {}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return func() {}
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return func() {}
+	}
+	debugLog = slog.New(slog.NewTextHandler(file, &slog.HandlerOptions{Level: level}))
+	debugLog.Info("debug log opened", "level", level, "pid", os.Getpid())
+	return func() { file.Close() }
+}
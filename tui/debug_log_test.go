@@ -0,0 +1,87 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		in   string
+		want slog.Level
+		ok   bool
+	}{
+		{in: "debug", want: slog.LevelDebug, ok: true},
+		{in: "INFO", want: slog.LevelInfo, ok: true},
+		{in: " error ", want: slog.LevelError, ok: true},
+		{in: ""},
+		{in: "verbose"},
+	}
+	for _, tt := range tests {
+		got, ok := parseLogLevel(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseLogLevel(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// useDebugLog opens the debug log at level in a temporary config directory
+// for the rest of the test and returns a function that reads it.
+func useDebugLog(t *testing.T, level string) func() string {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(logLevelEnv, level)
+	saved := debugLog
+	closeLog := openDebugLog()
+	t.Cleanup(func() {
+		closeLog()
+		debugLog = saved
+	})
+	return func() string {
+		path, err := debugLogPath()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+}
+
+func TestDebugLogOff(t *testing.T) {
+	read := useDebugLog(t, "")
+	debugLog.Error("something failed")
+	if got := read(); got != "" {
+		t.Errorf("log written with %s unset:\n%s", logLevelEnv, got)
+	}
+}
+
+func TestDebugLogLevel(t *testing.T) {
+	read := useDebugLog(t, "info")
+	debugLog.Debug("hidden detail")
+	debugLog.Error("something failed", "error", "boom")
+
+	got := read()
+	if strings.Contains(got, "hidden detail") {
+		t.Errorf("debug message logged at info level:\n%s", got)
+	}
+	if !strings.Contains(got, `msg="something failed" error=boom`) {
+		t.Errorf("error message missing from the log:\n%s", got)
+	}
+}
+
+func TestCommandFailureLogged(t *testing.T) {
+	fake := &fakeRunner{stderr: "API key missing", exitCode: 1, err: os.ErrInvalid}
+	useFakeRunner(t, fake)
+	read := useDebugLog(t, "error")
+
+	cliExecutor.ShowTask("tasks.json", "3")
+	got := read()
+	if !strings.Contains(got, `msg="command failed"`) || !strings.Contains(got, `stderr="API key missing"`) {
+		t.Errorf("failed command not logged:\n%s", got)
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// dependencyGraph is the dependency graph of a tasks file, as used to render it.
+type dependencyGraph struct {
+	order      []string            // Task IDs in file order
+	titles     map[string]string   // Task titles by ID
+	deps       map[string][]string // IDs each task depends on
+	dependents map[string][]string // IDs depending on each task, in file order
+}
+
+// newDependencyGraph builds the graph of the tasks in tf and, with
+// withSubtasks, their subtasks.
+func newDependencyGraph(tf *TasksFile, withSubtasks bool) dependencyGraph {
+	g := dependencyGraph{
+		order:      tf.TaskIDs(withSubtasks),
+		titles:     make(map[string]string),
+		deps:       make(map[string][]string),
+		dependents: make(map[string][]string),
+	}
+	for _, task := range tf.Tasks {
+		taskID := strconv.Itoa(task.ID)
+		g.titles[taskID] = task.Title
+		for _, sub := range task.Subtasks {
+			g.titles[taskID+"."+strconv.Itoa(sub.ID)] = sub.Title
+		}
+	}
+
+	edges := tf.DependencyEdges()
+	for _, id := range g.order {
+		g.deps[id] = edges[id]
+		for _, dep := range edges[id] {
+			g.dependents[dep] = append(g.dependents[dep], id)
+		}
+	}
+	return g
+}
+
+// label names a task by ID and title.
+func (g dependencyGraph) label(id string) string {
+	if title, ok := g.titles[id]; ok {
+		return id + " " + title
+	}
+	return id + " (not in the file)"
+}
+
+// cycles returns each dependency cycle once, as a path starting and ending
+// with the same ID. Each cycle starts at its earliest task in file order.
+func (g dependencyGraph) cycles() [][]string {
+	const (
+		unvisited = iota
+		onPath
+		finished
+	)
+	state := make(map[string]int)
+	var path []string
+	var found [][]string
+	seen := make(map[string]bool)
+
+	var walk func(id string)
+	walk = func(id string) {
+		state[id] = onPath
+		path = append(path, id)
+		for _, dep := range g.deps[id] {
+			switch state[dep] {
+			case onPath:
+				cycle := append([]string{}, path[slices.Index(path, dep):]...)
+				cycle = g.rotateCycle(cycle)
+				if key := strings.Join(cycle, " "); !seen[key] {
+					seen[key] = true
+					found = append(found, append(cycle, cycle[0]))
+				}
+			case unvisited:
+				walk(dep)
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = finished
+	}
+	for _, id := range g.order {
+		if state[id] == unvisited {
+			walk(id)
+		}
+	}
+	return found
+}
+
+// rotateCycle rotates a cycle to start at its earliest task in file order.
+func (g dependencyGraph) rotateCycle(cycle []string) []string {
+	start := 0
+	for i, id := range cycle {
+		if g.position(id) < g.position(cycle[start]) {
+			start = i
+		}
+	}
+	return append(cycle[start:], cycle[:start]...)
+}
+
+// position returns where a task is in file order; tasks not in the file come last.
+func (g dependencyGraph) position(id string) int {
+	if i := slices.Index(g.order, id); i >= 0 {
+		return i
+	}
+	return len(g.order)
+}
+
+// renderDependencyGraph describes the dependencies of the tasks in tf: what
+// each task depends on and blocks, then a tree of the tasks waiting on each
+// task that has no dependencies. Cycles and dependencies on missing tasks are
+// flagged; a task already in the tree is not expanded again, so a cycle
+// can't recurse forever.
+func renderDependencyGraph(tf *TasksFile, withSubtasks bool) string {
+	g := newDependencyGraph(tf, withSubtasks)
+	if len(g.order) == 0 {
+		return "The tasks file has no tasks."
+	}
+
+	var b strings.Builder
+	b.WriteString("Dependencies\n")
+	var missing []string
+	for _, id := range g.order {
+		dependsOn := "no dependencies"
+		if deps := g.deps[id]; len(deps) > 0 {
+			dependsOn = "depends on " + strings.Join(deps, ", ")
+		}
+		fmt.Fprintf(&b, "  Task %s: %s", g.label(id), dependsOn)
+		if blocks := g.dependents[id]; len(blocks) > 0 {
+			fmt.Fprintf(&b, "; blocks %s", strings.Join(blocks, ", "))
+		}
+		b.WriteString("\n")
+		for _, dep := range g.deps[id] {
+			if _, ok := g.titles[dep]; !ok {
+				missing = append(missing, fmt.Sprintf("  Task %s depends on %s, which is not in the file", id, dep))
+			}
+		}
+	}
+
+	if cycles := g.cycles(); len(cycles) > 0 {
+		b.WriteString("\n⚠️  Dependency cycles (each task depends on the next)\n")
+		for _, cycle := range cycles {
+			fmt.Fprintf(&b, "  %s\n", strings.Join(cycle, " → "))
+		}
+	}
+	if len(missing) > 0 {
+		b.WriteString("\n⚠️  Missing dependencies\n")
+		b.WriteString(strings.Join(missing, "\n") + "\n")
+	}
+
+	b.WriteString("\nBlocking tree (each task is followed by the tasks waiting on it)\n")
+	shown := make(map[string]bool)
+	var walk func(id, indent string, path []string)
+	walk = func(id, indent string, path []string) {
+		children := g.dependents[id]
+		for i, child := range children {
+			branch, next := "├── ", "│   "
+			if i == len(children)-1 {
+				branch, next = "└── ", "    "
+			}
+			switch {
+			case slices.Contains(path, child):
+				fmt.Fprintf(&b, "%s%s%s ↻ cycle\n", indent, branch, g.label(child))
+			case shown[child]:
+				fmt.Fprintf(&b, "%s%s%s (see above)\n", indent, branch, g.label(child))
+			default:
+				shown[child] = true
+				fmt.Fprintf(&b, "%s%s%s\n", indent, branch, g.label(child))
+				walk(child, indent+next, append(path, child))
+			}
+		}
+	}
+	root := func(id string) {
+		shown[id] = true
+		fmt.Fprintf(&b, "%s\n", g.label(id))
+		walk(id, "", []string{id})
+	}
+	for _, id := range g.order {
+		if len(g.deps[id]) == 0 {
+			root(id)
+		}
+	}
+	// Tasks waiting only on cycles or missing tasks aren't under any root
+	for _, id := range g.order {
+		if !shown[id] {
+			root(id)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	dependencyGraphFormKeyFile     = "file"
+	dependencyGraphFormKeySubtasks = "subtasks"
+)
+
+// DependencyGraphModel holds the state for the dependency graph view.
+type DependencyGraphModel struct {
+	form      *huh.Form
+	aborted   bool
+	statusMsg string
+	width     int
+	height    int
+	result    resultView // Scrollable rendering of the graph
+
+	// Form values
+	FilePath     string
+	WithSubtasks bool
+}
+
+// NewDependencyGraphForm creates a new form for viewing a tasks file's dependency graph.
+func NewDependencyGraphForm() *DependencyGraphModel {
+	m := &DependencyGraphModel{
+		FilePath: lastFilePath(), // Pre-populate with the last used tasks file
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			newFilePathField(dependencyGraphFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json) to show the dependencies of.", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key(dependencyGraphFormKeySubtasks).
+				Title("Include Subtasks").
+				Description("Show subtasks and their dependencies too.").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&m.WithSubtasks),
+		),
+	).WithTheme(huh.ThemeDracula())
+
+	return m
+}
+
+func (m *DependencyGraphModel) Init() tea.Cmd {
+	m.statusMsg = ""
+	m.aborted = false
+	return m.form.Init()
+}
+
+func (m *DependencyGraphModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result)
+	}
+
+	// Once the graph is shown, keep showing it rather than letting the
+	// completed form render it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
+	var cmds []tea.Cmd
+	formModel, cmd := m.form.Update(msg)
+	if updatedForm, ok := formModel.(*huh.Form); ok {
+		m.form = updatedForm
+	} else {
+		m.statusMsg = "Error: Form update returned unexpected type."
+		debugLog.Error("form update did not return *huh.Form", "file", "dependency_graph_form.go", "type", fmt.Sprintf("%T", formModel))
+		return m, tea.Quit
+	}
+	cmds = append(cmds, cmd)
+
+	if m.form.State == huh.StateCompleted {
+		// Reading the file is quick, so the graph is rendered right away
+		tf, err := LoadTasksFile(m.FilePath)
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("Error: failed to read tasks: %v", err)
+			m.form.State = huh.StateNormal // Back to the form to choose another file
+			return m, nil
+		}
+		rememberFilePath(m.FilePath)
+		m.statusMsg = "✅ Dependency graph"
+		m.result.setContent(renderDependencyGraph(tf, m.WithSubtasks))
+		return m, nil
+	}
+
+	if m.form.State == huh.StateAborted {
+		m.aborted = true
+		return m, func() tea.Msg { return backToMenuMsg{} }
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.aborted = true
+			return m, func() tea.Msg { return backToMenuMsg{} }
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *DependencyGraphModel) View() string {
+	if m.aborted {
+		return "Form aborted. Returning to main menu..."
+	}
+
+	var viewBuilder strings.Builder
+	viewBuilder.WriteString(m.form.View())
+
+	if m.statusMsg != "" {
+		viewBuilder.WriteString("\n\n")
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
+	}
+
+	helpStyle := lipgloss.NewStyle().Faint(true)
+	if m.form.State == huh.StateCompleted {
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State != huh.StateAborted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Padding(1, 2).
+		Render(viewBuilder.String())
+}
+
+func (m *DependencyGraphModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, false)) }
+
+// GetFormValues retrieves the structured data after completion.
+func (m *DependencyGraphModel) GetFormValues() (map[string]interface{}, error) {
+	if m.form.State != huh.StateCompleted {
+		return nil, fmt.Errorf("form is not yet completed")
+	}
+	return map[string]interface{}{
+		dependencyGraphFormKeyFile:     m.FilePath,
+		dependencyGraphFormKeySubtasks: m.WithSubtasks,
+	}, nil
+}
+
+var _ tea.Model = &DependencyGraphModel{}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRenderDependencyGraph(t *testing.T) {
+	got := renderDependencyGraph(loadSampleTasks(t), false)
+
+	for _, want := range []string{
+		"  Task 1 Setup: no dependencies; blocks 2\n",
+		"  Task 2 Core: depends on 1; blocks 3\n",
+		"  Task 3 UI: depends on 2\n",
+		"1 Setup\n└── 2 Core\n    └── 3 UI",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("graph is missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "cycle") {
+		t.Errorf("graph without cycles flags one:\n%s", got)
+	}
+
+	withSubtasks := renderDependencyGraph(loadSampleTasks(t), true)
+	if !strings.Contains(withSubtasks, "  Task 3.2 Styling: depends on 3.1, 2.1\n") {
+		t.Errorf("graph with subtasks is missing 3.2's dependencies:\n%s", withSubtasks)
+	}
+}
+
+func TestRenderDependencyGraphCycles(t *testing.T) {
+	var tf TasksFile
+	if err := json.Unmarshal([]byte(`{"tasks": [
+		{"id": 1, "title": "A", "dependencies": [3]},
+		{"id": 2, "title": "B", "dependencies": [1]},
+		{"id": 3, "title": "C", "dependencies": [2]},
+		{"id": 4, "title": "D", "dependencies": [9]}
+	]}`), &tf); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every task depends on something, so this also checks the tree terminates
+	got := renderDependencyGraph(&tf, false)
+	for _, want := range []string{
+		"Dependency cycles (each task depends on the next)\n  1 → 3 → 2 → 1\n",
+		"Task 4 depends on 9, which is not in the file",
+		"1 A\n└── 2 B\n    └── 3 C\n        └── 1 A ↻ cycle",
+		"4 D",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("graph is missing %q:\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "1 → 3 → 2 → 1"); n != 1 {
+		t.Errorf("cycle listed %d times, want once:\n%s", n, got)
+	}
+}
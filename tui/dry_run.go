@@ -0,0 +1,41 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// shellSafeArg matches arguments that need no quoting in a POSIX shell.
+var shellSafeArg = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// dryRunResult is the result of a command in dry-run mode: the command line it
+// would have run, in a form that can be pasted into a shell.
+func dryRunResult(dir, command string, args []string) CLIResult {
+	return CLIResult{
+		Success: true,
+		Message: "Dry run: command not executed",
+		Output:  formatCommandLine(dir, command, args),
+	}
+}
+
+// formatCommandLine renders a command and its working directory as a shell command line.
+func formatCommandLine(dir, command string, args []string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, shellQuote(command))
+	for _, arg := range args {
+		parts = append(parts, shellQuote(arg))
+	}
+	line := strings.Join(parts, " ")
+	if dir != "" {
+		line = "cd " + shellQuote(dir) + " && " + line
+	}
+	return line
+}
+
+// shellQuote quotes s for a POSIX shell if it contains anything special.
+func shellQuote(s string) string {
+	if shellSafeArg.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
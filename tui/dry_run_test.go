@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "tasks/tasks.json", want: "tasks/tasks.json"},
+		{input: "--num-tasks=10", want: "--num-tasks=10"},
+		{input: "Add a login page", want: "'Add a login page'"},
+		{input: "it's done", want: `'it'\''s done'`},
+		{input: "", want: "''"},
+	}
+
+	for _, tt := range tests {
+		if got := shellQuote(tt.input); got != tt.want {
+			t.Errorf("shellQuote(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestDryRunSkipsExecution(t *testing.T) {
+	executor := &CLIExecutor{cliPath: "../scripts/dev.js", DryRun: true}
+
+	result := executor.ShowTask("tasks/tasks.json", "3")
+	if !result.Success {
+		t.Fatalf("dry run failed: %s", result.Error)
+	}
+	want := "cd " + shellQuote(commandDir()) + " && node ../scripts/dev.js show-task tasks/tasks.json 3"
+	if result.Output != want {
+		t.Errorf("dry run output = %q, want %q", result.Output, want)
+	}
+}
+
+func TestDryRunNpxRunner(t *testing.T) {
+	executor := &CLIExecutor{cliPath: "../scripts/dev.js", Runner: RunnerNpx, DryRun: true}
+
+	result := executor.NextTask("tasks/tasks.json")
+	want := "cd " + shellQuote(commandDir()) + " && npx --yes task-master-ai next-task tasks/tasks.json"
+	if result.Output != want {
+		t.Errorf("dry run output = %q, want %q", result.Output, want)
+	}
+}
+
+func TestWorkDir(t *testing.T) {
+	root := t.TempDir()
+	tuiDir := filepath.Join(root, "tui")
+	for _, dir := range []string{tuiDir, filepath.Join(root, "scripts")} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "scripts", "dev.js"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	project := t.TempDir()
+
+	if got := defaultWorkDir(tuiDir); got != root {
+		t.Errorf("defaultWorkDir(checkout's tui dir) = %q, want the checkout root %q", got, root)
+	}
+	if got := defaultWorkDir(project); got != project {
+		t.Errorf("defaultWorkDir(%q) = %q, want it unchanged", project, got)
+	}
+
+	executor := &CLIExecutor{cliPath: "../scripts/dev.js", DryRun: true, WorkDir: project}
+	result := executor.ShowTask("tasks/tasks.json", "3")
+	if want := "cd " + shellQuote(project) + " && "; !strings.HasPrefix(result.Output, want) {
+		t.Errorf("dry run output = %q, want it to start with %q", result.Output, want)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	executor.WorkDir = "sub"
+	if got, want := executor.workDir(), filepath.Join(wd, "sub"); got != want {
+		t.Errorf("workDir() = %q, want %q relative to the current directory", got, want)
+	}
+}
+
+func TestValidateWorkDir(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "tasks.json")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		input   string
+		wantErr bool
+	}{
+		{input: ""},
+		{input: dir},
+		{input: file, wantErr: true},
+		{input: filepath.Join(dir, "missing"), wantErr: true},
+	} {
+		if err := validateWorkDir(tt.input); (err != nil) != tt.wantErr {
+			t.Errorf("validateWorkDir(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+	}
+}
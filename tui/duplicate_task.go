@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// taskContent holds the fields of a task or subtask that add-task can set.
+type taskContent struct {
+	ID           int           `json:"id"`
+	Title        string        `json:"title"`
+	Description  string        `json:"description"`
+	Details      string        `json:"details"`
+	TestStrategy string        `json:"testStrategy"`
+	Priority     string        `json:"priority"`
+	Type         string        `json:"type"`
+	Criteria     string        `json:"acceptanceCriteria"`
+	Dependencies taskIDList    `json:"dependencies"`
+	Subtasks     []taskContent `json:"subtasks"`
+}
+
+// loadTaskContent reads the task or dotted subtask id from a tasks file, in
+// the active tag.
+func loadTaskContent(filePath, id string) (taskContent, error) {
+	data, err := os.ReadFile(resolveTasksPath(filePath))
+	if err != nil {
+		return taskContent{}, err
+	}
+	if data, err = tagTasks(data, activeTag()); err != nil {
+		return taskContent{}, err
+	}
+	var file struct {
+		Tasks []taskContent `json:"tasks"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return taskContent{}, fmt.Errorf("failed to parse tasks file: %w", err)
+	}
+
+	tasks := file.Tasks
+	var found *taskContent
+	for _, part := range strings.Split(id, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return taskContent{}, fmt.Errorf("invalid task ID %q", id)
+		}
+		found = nil
+		for i := range tasks {
+			if tasks[i].ID == n {
+				found = &tasks[i]
+				break
+			}
+		}
+		if found == nil {
+			return taskContent{}, fmt.Errorf("task %s not found", id)
+		}
+		tasks = found.Subtasks
+	}
+	return *found, nil
+}
+
+// duplicateTitle is the title of a copy of a task when no new title is given.
+func duplicateTitle(title string) string {
+	return title + " (copy)"
+}
+
+// DuplicateTask adds a new task with the content of the task or subtask
+// sourceID: its description, details, test strategy, dependencies, priority,
+// type and acceptance criteria. The CLI has no duplicate command, so the task is read here and
+// added with add-task's manual fields. An empty title names the copy after
+// the source. On success the message and output give the new task's ID.
+func (e *CLIExecutor) DuplicateTask(filePath, sourceID, title string) CLIResult {
+	source, err := loadTaskContent(filePath, sourceID)
+	if err != nil {
+		return CLIResult{
+			Success: false,
+			Error:   err.Error(),
+			Message: fmt.Sprintf("Command not run: %s", err.Error()),
+		}
+	}
+	if title == "" {
+		title = duplicateTitle(source.Title)
+	}
+	description := source.Description
+	if description == "" {
+		description = source.Title // add-task needs one for a manual task
+	}
+
+	before, _ := LoadTasksFile(filePath)
+	result := e.AddTask(filePath, "", "", title, description, source.Details, source.TestStrategy,
+		strings.Join(source.Dependencies, ","), source.Priority, source.Type, source.Criteria, false)
+	if !result.Success {
+		return result
+	}
+	result.Message = fmt.Sprintf("Duplicated task %s", sourceID)
+	if after, err := LoadTasksFile(filePath); err == nil {
+		if first, _, ok := appendedIDRange(before, after); ok {
+			result.Message = fmt.Sprintf("Duplicated task %s as task %d", sourceID, first)
+			result.Output = result.Message + "\n\n" + result.Output
+		}
+	}
+	return result
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	duplicateTaskFormKeyFile   = "file"
+	duplicateTaskFormKeySource = "source"
+	duplicateTaskFormKeyTitle  = "title"
+)
+
+// DuplicateTaskModel holds the state for the duplicate task form.
+type DuplicateTaskModel struct {
+	form         *huh.Form
+	aborted      bool
+	isProcessing bool
+	statusMsg    string
+	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
+
+	// Form values
+	FilePath string
+	SourceID string // Task or dotted subtask ID to copy
+	Title    string // Title of the new task; empty to name it after the source
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
+}
+
+// NewDuplicateTaskForm creates a new form for adding a copy of a task.
+func NewDuplicateTaskForm() *DuplicateTaskModel {
+	m := &DuplicateTaskModel{
+		FilePath: lastFilePath(), // Pre-populate with the last used tasks file
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			newFilePathField(duplicateTaskFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json).", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		huh.NewGroup(
+			huh.NewInput().
+				Key(duplicateTaskFormKeySource).
+				Title("Task to Duplicate").
+				Description("ID of the task or subtask to copy, e.g. 5 or 1.2. The copy is a new task.").
+				Prompt("🆔 ").
+				Validate(func(s string) error {
+					if err := validateTaskID(s); err != nil {
+						return err
+					}
+					return validateTaskExists(m.FilePath, s)
+				}).
+				SuggestionsFunc(taskIDSuggestions(&m.FilePath, true), &m.FilePath).
+				Value(&m.SourceID),
+
+			huh.NewInput().
+				Key(duplicateTaskFormKeyTitle).
+				Title("New Title (Optional)").
+				PlaceholderFunc(m.titlePlaceholder, []any{&m.FilePath, &m.SourceID}).
+				Description("Leave blank to name the copy after the task.").
+				Prompt("🏷️ ").
+				Value(&m.Title),
+		),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
+	).WithTheme(huh.ThemeDracula())
+
+	return m
+}
+
+// titlePlaceholder shows the title the copy gets when none is typed.
+func (m *DuplicateTaskModel) titlePlaceholder() string {
+	source, err := loadTaskContent(m.FilePath, m.SourceID)
+	if err != nil {
+		return ""
+	}
+	return duplicateTitle(source.Title)
+}
+
+func (m *DuplicateTaskModel) Init() tea.Cmd {
+	m.isProcessing = false
+	m.statusMsg = ""
+	m.aborted = false
+	return m.form.Init()
+}
+
+func (m *DuplicateTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case duplicateTaskCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			m.statusMsg = "✅ " + msg.result.Message + "."
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
+	if m.isProcessing {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, nil
+	}
+
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.statusMsg = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
+	var cmds []tea.Cmd
+	formModel, cmd := m.form.Update(msg)
+	if updatedForm, ok := formModel.(*huh.Form); ok {
+		m.form = updatedForm
+	} else {
+		m.statusMsg = "Error: Form update returned unexpected type."
+		debugLog.Error("form update did not return *huh.Form", "file", "duplicate_task_form.go", "type", fmt.Sprintf("%T", formModel))
+		return m, tea.Quit
+	}
+	cmds = append(cmds, cmd)
+
+	if m.form.State == huh.StateCompleted {
+		m.statusMsg = "Duplicating the task..."
+		m.isProcessing = true
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeDuplicateTaskCommand))
+	}
+
+	if m.form.State == huh.StateAborted {
+		m.aborted = true
+		return m, func() tea.Msg { return backToMenuMsg{} }
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if !m.isProcessing {
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			}
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *DuplicateTaskModel) View() string {
+	if m.aborted {
+		return "Form aborted. Returning to main menu..."
+	}
+
+	var viewBuilder strings.Builder
+	viewBuilder.WriteString(m.form.View())
+
+	if m.statusMsg != "" {
+		viewBuilder.WriteString("\n\n")
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
+	}
+
+	helpStyle := lipgloss.NewStyle().Faint(true)
+	if m.isProcessing {
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
+	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
+	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Padding(1, 2).
+		Render(viewBuilder.String())
+}
+
+func (m *DuplicateTaskModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
+// GetFormValues retrieves the structured data after completion.
+func (m *DuplicateTaskModel) GetFormValues() (map[string]interface{}, error) {
+	if m.form.State != huh.StateCompleted {
+		return nil, fmt.Errorf("form is not yet completed")
+	}
+	return map[string]interface{}{
+		duplicateTaskFormKeyFile:   m.FilePath,
+		duplicateTaskFormKeySource: m.SourceID,
+		duplicateTaskFormKeyTitle:  m.Title,
+		advancedFlagsFormKey:       m.AdvancedFlags,
+	}, nil
+}
+
+// duplicateTaskCompleteMsg is sent when the command execution is complete
+type duplicateTaskCompleteMsg struct {
+	result CLIResult
+}
+
+// executeDuplicateTaskCommand adds the copy with the add-task CLI command
+func (m *DuplicateTaskModel) executeDuplicateTaskCommand() tea.Cmd {
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+		return duplicateTaskCompleteMsg{result: executor.DuplicateTask(m.FilePath, m.SourceID, m.Title)}
+	})
+}
+
+var _ tea.Model = &DuplicateTaskModel{}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadTaskContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	writeTasks(t, path, `{"tasks": [
+		{"id": 2, "title": "API", "description": "Build the API", "priority": "high", "dependencies": [1, "1.2"], "subtasks": [
+			{"id": 1, "title": "Routes", "details": "REST"}
+		]}
+	]}`)
+
+	task, err := loadTaskContent(path, "2")
+	if err != nil {
+		t.Fatalf("loadTaskContent(2) unexpected error: %v", err)
+	}
+	if task.Description != "Build the API" || task.Priority != "high" || !reflect.DeepEqual([]string(task.Dependencies), []string{"1", "1.2"}) {
+		t.Errorf("loadTaskContent(2) = %+v", task)
+	}
+	if sub, err := loadTaskContent(path, "2.1"); err != nil || sub.Details != "REST" {
+		t.Errorf("loadTaskContent(2.1) = %+v, %v", sub, err)
+	}
+	if _, err := loadTaskContent(path, "3"); err == nil || err.Error() != "task 3 not found" {
+		t.Errorf("loadTaskContent(3) error = %v, want %q", err, "task 3 not found")
+	}
+}
+
+func TestDuplicateTask(t *testing.T) {
+	fake := &fakeRunner{}
+	useFakeRunner(t, fake)
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	writeTasks(t, path, `{"tasks": [{"id": 1, "title": "Setup"}, {"id": 2, "title": "API", "priority": "high", "dependencies": [1]}]}`)
+
+	result := cliExecutor.DuplicateTask(path, "2", "")
+	if !result.Success {
+		t.Fatalf("DuplicateTask() failed: %s", result.Error)
+	}
+	want := [][]string{{"node", "../scripts/dev.js", "add-task", path, "--title", "API (copy)", "--description", "API",
+		"--dependencies", "1", "--priority", "high"}}
+	if !reflect.DeepEqual(fake.calls, want) {
+		t.Errorf("ran %q, want %q", fake.calls, want)
+	}
+
+	// A source that isn't in the file doesn't run anything
+	fake.calls = nil
+	if result := cliExecutor.DuplicateTask(path, "9", ""); result.Success || fake.calls != nil {
+		t.Errorf("DuplicateTask(9) = %+v, ran %q", result, fake.calls)
+	}
+}
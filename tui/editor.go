@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editKey opens the generated task files in the user's editor.
+const editKey = "e"
+
+// fallbackEditor is run when neither EDITOR nor VISUAL is set.
+const fallbackEditor = "vi"
+
+// editorFinishedMsg reports that the editor has exited and the TUI is back.
+type editorFinishedMsg struct {
+	err     error
+	warning string // About the editor chosen, for the user to see on return
+}
+
+// editorCommand returns the command that opens path in the user's editor:
+// $EDITOR, else $VISUAL, else fallbackEditor with a warning saying so. The
+// variable may include arguments, e.g. "code --wait".
+func editorCommand(path string) (*exec.Cmd, string) {
+	editor, warning := os.Getenv("EDITOR"), ""
+	if strings.TrimSpace(editor) == "" {
+		editor = os.Getenv("VISUAL")
+	}
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		fields = []string{fallbackEditor}
+		warning = fmt.Sprintf("EDITOR isn't set, so %s was used.", fallbackEditor)
+	}
+	return exec.Command(fields[0], append(fields[1:], path)...), warning
+}
+
+// openInEditor suspends the TUI while the user's editor has path open,
+// resuming with an editorFinishedMsg when it exits.
+func openInEditor(path string) tea.Cmd {
+	cmd, warning := editorCommand(path)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorFinishedMsg{err: err, warning: warning}
+	})
+}
+
+// editorFinishedNotice describes how editing went, "" if there is nothing to say.
+func editorFinishedNotice(msg editorFinishedMsg) string {
+	notice := msg.warning
+	if msg.err != nil {
+		notice = strings.TrimSpace(fmt.Sprintf("%s Couldn't run the editor: %v.", notice, msg.err))
+	}
+	return notice
+}
+
+// generatedTaskFile returns the file generate writes for a task in dir, named
+// as the CLI names it.
+func generatedTaskFile(dir string, taskID int) string {
+	return filepath.Join(dir, fmt.Sprintf("task_%03d.txt", taskID))
+}
+
+// generatedPath returns what to open after generating task files in dir: the
+// task's file when one task was generated and its file exists, otherwise the
+// directory. Relative paths are resolved the same way the CLI resolves them.
+func generatedPath(dir string, taskIDs []string) string {
+	dir = resolveTasksPath(dir)
+	if len(taskIDs) == 1 {
+		if id, err := strconv.Atoi(taskIDs[0]); err == nil {
+			if file := generatedTaskFile(dir, id); fileExists(file) {
+				return file
+			}
+		}
+	}
+	return dir
+}
+
+// fileExists reports whether path is an existing regular file.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
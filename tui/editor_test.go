@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEditorCommand(t *testing.T) {
+	tests := []struct {
+		editor, visual string
+		wantArgs       []string
+		wantWarning    bool
+	}{
+		{editor: "code --wait", visual: "vim", wantArgs: []string{"code", "--wait", "tasks"}},
+		{visual: "nano", wantArgs: []string{"nano", "tasks"}},
+		{editor: " ", wantArgs: []string{fallbackEditor, "tasks"}, wantWarning: true},
+	}
+	for _, tt := range tests {
+		t.Setenv("EDITOR", tt.editor)
+		t.Setenv("VISUAL", tt.visual)
+		cmd, warning := editorCommand("tasks")
+		if !reflect.DeepEqual(cmd.Args, tt.wantArgs) {
+			t.Errorf("EDITOR=%q VISUAL=%q: args = %q, want %q", tt.editor, tt.visual, cmd.Args, tt.wantArgs)
+		}
+		if (warning != "") != tt.wantWarning {
+			t.Errorf("EDITOR=%q VISUAL=%q: warning = %q, want one: %v", tt.editor, tt.visual, warning, tt.wantWarning)
+		}
+	}
+
+	msg := editorFinishedMsg{err: errors.New("not found"), warning: "EDITOR isn't set, so vi was used."}
+	if got, want := editorFinishedNotice(msg), "EDITOR isn't set, so vi was used. Couldn't run the editor: not found."; got != want {
+		t.Errorf("editorFinishedNotice() = %q, want %q", got, want)
+	}
+}
+
+func TestGeneratedPath(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(generatedTaskFile(dir, 2), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		taskIDs []string
+		want    string
+	}{
+		{taskIDs: []string{"2"}, want: filepath.Join(dir, "task_002.txt")},
+		{taskIDs: []string{"3"}, want: dir},
+		{taskIDs: []string{"2", "3"}, want: dir},
+		{want: dir},
+	}
+	for _, tt := range tests {
+		if got := generatedPath(dir, tt.taskIDs); got != tt.want {
+			t.Errorf("generatedPath(%q) = %q, want %q", tt.taskIDs, got, tt.want)
+		}
+	}
+}
@@ -2,25 +2,30 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 )
 
 const (
-	expandTaskFormKeyFile     = "file"
-	expandTaskFormKeyID       = "id"
-	expandTaskFormKeyAll      = "all"
-	expandTaskFormKeyNum      = "num"
-	expandTaskFormKeyResearch = "research"
-	expandTaskFormKeyPrompt   = "prompt"
-	expandTaskFormKeyForce    = "force"
+	expandTaskFormKeyFile          = "file"
+	expandTaskFormKeyID            = "id"
+	expandTaskFormKeyAll           = "all"
+	expandTaskFormKeyNum           = "num"
+	expandTaskFormKeyResearch      = "research"
+	expandTaskFormKeyResearchModel = "research-model"
+	expandTaskFormKeyPrompt        = "prompt"
+	expandTaskFormKeyForce         = "force"
 )
 
+// showExpandedTaskKey shows the expanded task, with its new subtasks, once
+// the expansion has succeeded.
+const showExpandedTaskKey = "s"
+
 // ExpandTaskModel holds the state for the expand task form.
 type ExpandTaskModel struct {
 	form         *huh.Form
@@ -28,52 +33,50 @@ type ExpandTaskModel struct {
 	isProcessing bool
 	statusMsg    string
 	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	progress     progressBar         // Filled from progress lines in the output
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
+	expanded     bool                // The expansion succeeded
+	shown        bool                // The result is the expanded task rather than the expand output
 
 	// Form values
-	FilePath     string
-	TaskID       string // Can be empty if 'all' is true
-	AllPending   bool   // Expand all pending tasks
-	NumSubtasks  int    // Number of subtasks to generate
-	UseResearch  bool
-	Prompt       string // Additional context
-	ForceExpand  bool   // Force expansion even if subtasks exist
+	FilePath      string
+	TaskID        string // Can be empty if 'all' is true
+	AllPending    bool   // Expand all pending tasks
+	NumSubtasks   int    // Number of subtasks to generate
+	UseResearch   bool
+	ResearchModel string // Research model for this command only; empty for the configured one
+	Prompt        string // Additional context
+	ForceExpand   bool   // Force expansion even if subtasks exist
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
 }
 
 // NewExpandTaskForm creates a new form for the expand task command.
 func NewExpandTaskForm() *ExpandTaskModel {
 	m := &ExpandTaskModel{
-		NumSubtasks: 3,  // Default number of subtasks
-		UseResearch: false,
+		FilePath:    lastFilePath(),                // Pre-populate with the last used tasks file
+		Prompt:      LoadLastPrompt("expand-task"), // Offer the last prompt again, e.g. after a failure
+		NumSubtasks: 3,                             // Default number of subtasks
+		UseResearch: defaultResearch(),
 		ForceExpand: false,
 		AllPending:  false,
 	}
 
 	// Temporary string for NumSubtasks input
 	numSubtasksStr := strconv.Itoa(m.NumSubtasks)
+	maxCount := maxGenerateCount()
 
 	m.form = huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().
-				Key(expandTaskFormKeyFile).
-				Title("Tasks File Path").
-				Description("Path to the tasks file (e.g., tasks.md).").
-				Prompt("📄 ").
-				Validate(func(s string) error {
-					if s == "" {
-						return fmt.Errorf("tasks file path cannot be empty")
-					}
-					return nil
-				}).
-				Value(&m.FilePath),
-
-			huh.NewInput().
-				Key(expandTaskFormKeyID).
-				Title("Task ID (Optional)").
-				Description("ID of the task to expand. Leave empty if 'Expand All' is Yes.").
-				Prompt("🆔 ").
-				// Validate based on whether 'AllPending' is true or false during form processing
-				Value(&m.TaskID),
-
+			newFilePathField(expandTaskFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json).", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		huh.NewGroup(
 			huh.NewConfirm().
 				Key(expandTaskFormKeyAll).
 				Title("Expand All Pending Tasks").
@@ -81,20 +84,32 @@ func NewExpandTaskForm() *ExpandTaskModel {
 				Affirmative("Yes").
 				Negative("No").
 				Value(&m.AllPending),
+
+			huh.NewInput().
+				Key(expandTaskFormKeyID).
+				Title("Task ID").
+				Description("ID of the task to expand, e.g. \"2\". Subtasks such as \"2.3\" must be converted to tasks first. Ignored when expanding all pending tasks.").
+				Prompt("🆔 ").
+				// Asked after 'Expand All', so its answer is known here
+				Validate(func(s string) error {
+					if m.AllPending {
+						return nil
+					}
+					if s == "" {
+						return fmt.Errorf("task ID is required unless expanding all pending tasks")
+					}
+					return validateExpandTaskID(m.FilePath, s)
+				}).
+				SuggestionsFunc(taskIDSuggestions(&m.FilePath, false), &m.FilePath).
+				Value(&m.TaskID),
 		),
 		huh.NewGroup(
 			huh.NewInput().
 				Key(expandTaskFormKeyNum).
 				Title("Number of Subtasks").
-				Description("How many subtasks to generate for each expansion?").
+				Description(fmt.Sprintf("How many subtasks to generate for each expansion? At most %d.", maxCount)).
 				Prompt("🔢 ").
-				Validate(func(s string) error {
-					if s == "" { return fmt.Errorf("number of subtasks cannot be empty") }
-					val, err := strconv.Atoi(s)
-					if err != nil { return fmt.Errorf("must be a valid integer") }
-					if val <= 0 { return fmt.Errorf("must be greater than 0") }
-					return nil
-				}).
+				Validate(func(s string) error { return validateCount(s, "number of subtasks", maxCount) }).
 				Value(&numSubtasksStr), // Use temporary string, parse on completion
 
 			huh.NewConfirm().
@@ -105,6 +120,7 @@ func NewExpandTaskForm() *ExpandTaskModel {
 				Negative("No").
 				Value(&m.UseResearch),
 		),
+		newResearchModelGroup(expandTaskFormKeyResearchModel, &m.UseResearch, &m.ResearchModel),
 		huh.NewGroup(
 			huh.NewText().
 				Key(expandTaskFormKeyPrompt).
@@ -121,6 +137,7 @@ func NewExpandTaskForm() *ExpandTaskModel {
 				Negative("No").
 				Value(&m.ForceExpand),
 		),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
 	).WithTheme(huh.ThemeDracula())
 
 	return m
@@ -134,61 +151,123 @@ func (m *ExpandTaskModel) Init() tea.Cmd {
 }
 
 func (m *ExpandTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		if msg.stream == m.output.stream {
+			m.progress.observe(msg.line)
+		}
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case expandTaskCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		m.progress.finish(msg.result.Success)
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			promptSucceeded("expand-task")
+			m.statusMsg = "✅ Success!" + researchBadge(m.UseResearch)
+			m.expanded = true
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	case expandShowTaskMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		switch {
+		case m.cancel.finish():
+			m.statusMsg = "✅ Success!" + researchBadge(m.UseResearch) // Still showing the expand output
+		case msg.result.Success:
+			m.shown = true
+			m.statusMsg = fmt.Sprintf("✅ Task %s after expanding:", m.TaskID) + researchBadge(m.UseResearch)
+			m.result.setContent(msg.result.Output)
+		default:
+			m.statusMsg = fmt.Sprintf("✅ Success!%s Showing task %s failed: %s", researchBadge(m.UseResearch), m.TaskID, msg.result.Error)
+		}
+		return m, nil
+	}
+
 	if m.isProcessing { // Standard processing lock
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
-			if keyMsg.String() == "ctrl+c" || keyMsg.String() == "q" { return m, tea.Quit }
+			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
 		}
 		return m, nil
 	}
 
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.statusMsg = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			case showExpandedTaskKey:
+				if m.canShowTask() {
+					m.statusMsg = fmt.Sprintf("Showing task %s...", m.TaskID)
+					m.isProcessing = true
+					return m, tea.Batch(m.spinner.start(), m.executeShowTaskCommand())
+				}
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
 	var cmds []tea.Cmd
 	formModel, cmd := m.form.Update(msg)
 	if updatedForm, ok := formModel.(*huh.Form); ok {
 		m.form = updatedForm
 	} else {
 		m.statusMsg = "Error: Form update returned unexpected type."
-		fmt.Fprintf(os.Stderr, "Critical Error: expand_task_form.go - form update did not return *huh.Form. Got: %T\n", formModel)
+		debugLog.Error("form update did not return *huh.Form", "file", "expand_task_form.go", "type", fmt.Sprintf("%T", formModel))
 		return m, tea.Quit
 	}
 	cmds = append(cmds, cmd)
 
-	// Custom validation: If 'AllPending' is false, 'TaskID' must not be empty.
-	// This is checked after form processes its own state, before completion.
-	if !m.form.GetBool(expandTaskFormKeyAll) && m.form.GetString(expandTaskFormKeyID) == "" {
-		// This logic might be better placed within a ValidateFunc on the group or form,
-		// or handled upon completion attempt. For now, let's ensure it doesn't go to completed state.
-		if m.form.State == huh.StateCompleted { // Prevent completion if invalid
-			m.form.State = huh.StateNormal
-		}
-		// Note: Direct field access for validation is not available in huh v0.7.0
-		// We'll handle validation through the form's overall validation state
-	}
-
-
 	if m.form.State == huh.StateCompleted {
-		// Final check for TaskID if 'All' is not selected
-		if !m.AllPending && m.TaskID == "" {
-			m.statusMsg = "Error: Task ID is required when not expanding all pending tasks."
-			m.form.State = huh.StateNormal // Revert to allow correction
-			// Attempt to focus the TaskID field or show error more directly if possible
-			// For now, statusMsg will have to do, or the field-specific error above.
-			return m, nil
-		}
-
 		numSubtasksStrValue := m.form.GetString(expandTaskFormKeyNum)
 		parsedNumSubtasks, err := strconv.Atoi(numSubtasksStrValue)
 		if err != nil {
 			m.statusMsg = fmt.Sprintf("Error parsing number of subtasks: %v. Please correct.", err)
 			m.form.State = huh.StateNormal
-			// Note: Direct field access for error setting is not available in huh v0.7.0
-			// Error handling is managed through form validation state
 			return m, nil
 		}
 		m.NumSubtasks = parsedNumSubtasks
 
+		SaveLastPrompt("expand-task", m.Prompt)
 		m.statusMsg = "Executing expand-task command..."
 		m.isProcessing = true
-		return m, m.executeExpandTaskCommand()
+		m.expanded, m.shown = false, false
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeExpandTaskCommand))
 	}
 
 	if m.form.State == huh.StateAborted {
@@ -197,14 +276,6 @@ func (m *ExpandTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
-	case expandTaskCompleteMsg:
-		m.isProcessing = false
-		if msg.result.Success {
-			m.statusMsg = fmt.Sprintf("✅ Success!\n\n%s", msg.result.Output)
-		} else {
-			m.statusMsg = fmt.Sprintf("❌ Error: %s\n\n%s", msg.result.Error, msg.result.Output)
-		}
-		return m, nil
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
@@ -212,8 +283,6 @@ func (m *ExpandTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.aborted = true; return m, func() tea.Msg { return backToMenuMsg{} }
 			}
 		}
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
 	}
 
 	return m, tea.Batch(cmds...)
@@ -227,37 +296,53 @@ func (m *ExpandTaskModel) View() string {
 
 	if m.statusMsg != "" {
 		viewBuilder.WriteString("\n\n")
-		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-		if strings.HasPrefix(m.statusMsg, "Error:") {
-			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
-		}
-		viewBuilder.WriteString(statusStyle.Render(m.statusMsg))
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
+	}
+
+	if bar := m.progress.View(m.width); bar != "" && (m.isProcessing || m.form.State == huh.StateCompleted) {
+		viewBuilder.WriteString("\n\n" + bar)
 	}
 
 	helpStyle := lipgloss.NewStyle().Faint(true)
 	if m.isProcessing {
-		viewBuilder.WriteString(helpStyle.Render("\n\nProcessing... Press Ctrl+C to force quit."))
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
 	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
-		viewBuilder.WriteString(helpStyle.Render("\n\nCommand completed! Press Esc to return to main menu."))
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		help := m.result.help()
+		if m.canShowTask() {
+			help += fmt.Sprintf(" Press %s to show task %s.", showExpandedTaskKey, m.TaskID)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + help))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
 	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
 		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
 	}
 	return lipgloss.NewStyle().Width(m.width).Padding(1, 2).Render(viewBuilder.String())
 }
 
+func (m *ExpandTaskModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
 // GetFormValues retrieves the structured data after completion.
 func (m *ExpandTaskModel) GetFormValues() (map[string]interface{}, error) {
 	if m.form.State != huh.StateCompleted {
 		return nil, fmt.Errorf("form is not yet completed")
 	}
 	return map[string]interface{}{
-		expandTaskFormKeyFile:     m.FilePath,
-		expandTaskFormKeyID:       m.TaskID,
-		expandTaskFormKeyAll:      m.AllPending,
-		expandTaskFormKeyNum:      m.NumSubtasks,
-		expandTaskFormKeyResearch: m.UseResearch,
-		expandTaskFormKeyPrompt:   m.Prompt,
-		expandTaskFormKeyForce:    m.ForceExpand,
+		expandTaskFormKeyFile:          m.FilePath,
+		expandTaskFormKeyID:            m.TaskID,
+		expandTaskFormKeyAll:           m.AllPending,
+		expandTaskFormKeyNum:           m.NumSubtasks,
+		expandTaskFormKeyResearch:      m.UseResearch,
+		expandTaskFormKeyResearchModel: m.ResearchModel,
+		expandTaskFormKeyPrompt:        m.Prompt,
+		expandTaskFormKeyForce:         m.ForceExpand,
+		advancedFlagsFormKey:           m.AdvancedFlags,
 	}, nil
 }
 
@@ -268,7 +353,13 @@ type expandTaskCompleteMsg struct {
 
 // executeExpandTaskCommand executes the actual expand-task CLI command
 func (m *ExpandTaskModel) executeExpandTaskCommand() tea.Cmd {
-	return func() tea.Msg {
+	stream := m.output.start()
+	m.progress.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags).WithResearchModel(m.ResearchModel)
+
 		// Check if we should expand all pending tasks or a specific task
 		if m.AllPending {
 			// For "all pending", we would need a different CLI method or empty taskID
@@ -279,8 +370,27 @@ func (m *ExpandTaskModel) executeExpandTaskCommand() tea.Cmd {
 			}}
 		}
 		
-		result := cliExecutor.ExpandTask(m.FilePath, m.TaskID, m.Prompt, m.NumSubtasks, m.UseResearch)
+		result := executor.ExpandTask(m.FilePath, m.TaskID, m.Prompt, m.NumSubtasks, m.UseResearch)
 		return expandTaskCompleteMsg{result: result}
+	})
+}
+
+// canShowTask reports whether the expanded task can be shown: a single task
+// was expanded and isn't already shown.
+func (m *ExpandTaskModel) canShowTask() bool {
+	return m.expanded && !m.shown && !m.AllPending && m.TaskID != ""
+}
+
+// expandShowTaskMsg is sent when showing the expanded task is complete
+type expandShowTaskMsg struct {
+	result CLIResult
+}
+
+// executeShowTaskCommand shows the task that was just expanded
+func (m *ExpandTaskModel) executeShowTaskCommand() tea.Cmd {
+	ctx := m.cancel.start()
+	return func() tea.Msg {
+		return expandShowTaskMsg{result: cliExecutor.WithContext(ctx).ShowTask(m.FilePath, m.TaskID)}
 	}
 }
 
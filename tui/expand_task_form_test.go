@@ -0,0 +1,35 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/huh"
+)
+
+func TestExpandShowsExpandedTask(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	fake := &fakeRunner{stdout: "Task 3: Write docs\n  3.1 Outline\n"}
+	useFakeRunner(t, fake)
+
+	m := NewExpandTaskForm()
+	m.FilePath, m.TaskID = "tasks.json", "3"
+	m.form.State = huh.StateCompleted
+	m.Update(expandTaskCompleteMsg{result: CLIResult{Success: true, Output: "Expanded task 3"}})
+	if !m.canShowTask() {
+		t.Fatal("canShowTask() = false after a successful expansion")
+	}
+	if help := m.View(); !strings.Contains(help, "Press s to show task 3.") {
+		t.Errorf("View() doesn't offer showing the task:\n%s", help)
+	}
+
+	m.Update(m.executeShowTaskCommand()())
+	want := [][]string{{"node", "../scripts/dev.js", "show-task", "tasks.json", "3"}}
+	if !reflect.DeepEqual(fake.calls, want) {
+		t.Errorf("ran %q, want %q", fake.calls, want)
+	}
+	if m.result.content != fake.stdout || m.canShowTask() {
+		t.Errorf("after showing, result = %q, canShowTask() = %v", m.result.content, m.canShowTask())
+	}
+}
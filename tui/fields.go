@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+)
+
+// File types offered by the file picker for each kind of input file.
+var (
+	tasksFileTypes = []string{".md", ".json"}
+	prdFileTypes   = []string{".txt", ".md"}
+	batchFileTypes = []string{".txt", ".json", ".batch"}
+)
+
+// newFilePathField builds the field for an input file path. When the path is
+// empty the user browses for it with a file picker starting in the current
+// directory; when a path has already been supplied (e.g. programmatically) a
+// plain text input is used so it can be reviewed and edited.
+// Either way the selected path is written to value.
+func newFilePathField(key, title, description string, value *string, allowedTypes []string) huh.Field {
+	if *value != "" {
+		return huh.NewInput().
+			Key(key).
+			Title(title).
+			Description(description).
+			Prompt("📄 ").
+			Validate(validateExistingFile).
+			Value(value)
+	}
+
+	picker := huh.NewFilePicker().
+		Key(key).
+		Title(title).
+		Description(description + " Press enter to browse.").
+		AllowedTypes(allowedTypes).
+		Height(10).
+		Validate(validateExistingFile).
+		Value(value)
+
+	// Picked files are absolute so they resolve the same way for the CLI
+	if wd, err := os.Getwd(); err == nil {
+		picker = picker.CurrentDirectory(wd)
+	}
+	return picker
+}
+
+// taskIDSuggestions returns a suggestions func for a task ID input that offers
+// the IDs in the tasks file at *filePath. Bind it to filePath so the
+// suggestions are reloaded when the path changes:
+//
+//	SuggestionsFunc(taskIDSuggestions(&m.FilePath, true), &m.FilePath)
+func taskIDSuggestions(filePath *string, includeSubtasks bool) func() []string {
+	return func() []string {
+		tf, err := LoadTasksFile(*filePath)
+		if err != nil {
+			return nil
+		}
+		return tf.TaskIDs(includeSubtasks)
+	}
+}
+
+// taskIDListSuggestions is taskIDSuggestions for a comma-separated list of IDs
+// held in *value. It completes the ID after the last comma and skips IDs already
+// in the list. Bind it to both filePath and value.
+func taskIDListSuggestions(filePath, value *string, includeSubtasks bool) func() []string {
+	return func() []string {
+		ids := taskIDSuggestions(filePath, includeSubtasks)()
+		prefix, entered := "", []string(nil)
+		if i := strings.LastIndex(*value, ","); i >= 0 {
+			// Keep any space typed after the comma so suggestions still match
+			rest := (*value)[i+1:]
+			prefix = (*value)[:i+1] + rest[:len(rest)-len(strings.TrimLeft(rest, " "))]
+			for _, id := range strings.Split((*value)[:i], ",") {
+				entered = append(entered, strings.TrimSpace(id))
+			}
+		}
+
+		suggestions := make([]string, 0, len(ids))
+		for _, id := range ids {
+			if !slices.Contains(entered, id) {
+				suggestions = append(suggestions, prefix+id)
+			}
+		}
+		return suggestions
+	}
+}
+
+// taskChoices lists the tasks in the selected file as multi-select options.
+// The file is only re-read when the path changes.
+type taskChoices struct {
+	includeSubtasks bool
+	path            string
+	loaded          bool
+	options         []huh.Option[string]
+}
+
+// load returns the options for the tasks file at path, or nil if it can't be parsed.
+func (c *taskChoices) load(path string) []huh.Option[string] {
+	if c.loaded && c.path == path {
+		return c.options
+	}
+	c.path, c.loaded, c.options = path, true, nil
+
+	tf, err := LoadTasksFile(path)
+	if err != nil {
+		return nil
+	}
+	for _, task := range tf.Tasks {
+		taskID := strconv.Itoa(task.ID)
+		c.options = append(c.options, huh.NewOption(fmt.Sprintf("%s  %s", taskID, task.Title), taskID))
+		if !c.includeSubtasks {
+			continue
+		}
+		for _, sub := range task.Subtasks {
+			subID := taskID + "." + strconv.Itoa(sub.ID)
+			c.options = append(c.options, huh.NewOption(fmt.Sprintf("  %s  %s", subID, sub.Title), subID))
+		}
+	}
+	return c.options
+}
+
+// available reports whether tasks can be picked from the file at path. When
+// they can't, forms fall back to a free-text ID input.
+func (c *taskChoices) available(path string) bool {
+	return len(c.load(path)) > 0
+}
+
+// splitTaskIDs splits a comma-separated list of task IDs, dropping empty entries.
+func splitTaskIDs(s string) []string {
+	var ids []string
+	for _, id := range strings.Split(s, ",") {
+		if trimmed := strings.TrimSpace(id); trimmed != "" {
+			ids = append(ids, trimmed)
+		}
+	}
+	return ids
+}
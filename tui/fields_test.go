@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeSampleTasks(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	if err := os.WriteFile(path, []byte(sampleTasksJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestTaskIDSuggestions(t *testing.T) {
+	path := writeSampleTasks(t)
+
+	if got, want := taskIDSuggestions(&path, false)(), []string{"1", "2", "3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("suggestions = %v, want %v", got, want)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	if got := taskIDSuggestions(&missing, true)(); got != nil {
+		t.Errorf("suggestions for a missing file = %v, want nil", got)
+	}
+}
+
+func TestTaskIDListSuggestions(t *testing.T) {
+	path := writeSampleTasks(t)
+
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{name: "first ID", value: "", want: []string{"1", "2", "3"}},
+		{name: "after comma", value: "2,", want: []string{"2,1", "2,3"}},
+		{name: "after comma and space", value: "1, 3", want: []string{"1, 2", "1, 3"}},
+		{name: "skips all entered IDs", value: "1,3,", want: []string{"1,3,2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := tt.value
+			got := taskIDListSuggestions(&path, &value, false)()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("suggestions for %q = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitTaskIDs(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{input: "1", want: []string{"1"}},
+		{input: "1, 2.1 ,3", want: []string{"1", "2.1", "3"}},
+		{input: "1,,2,", want: []string{"1", "2"}},
+		{input: " ", want: nil},
+	}
+
+	for _, tt := range tests {
+		if got := splitTaskIDs(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitTaskIDs(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestTaskChoices(t *testing.T) {
+	path := writeSampleTasks(t)
+
+	choices := taskChoices{includeSubtasks: true}
+	var values []string
+	for _, opt := range choices.load(path) {
+		values = append(values, opt.Value)
+	}
+	if want := []string{"1", "2", "2.1", "3", "3.1", "3.2"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("option values = %v, want %v", values, want)
+	}
+	if !choices.available(path) {
+		t.Error("available() = false for a parseable tasks file")
+	}
+
+	notJSON := filepath.Join(t.TempDir(), "tasks.md")
+	if err := os.WriteFile(notJSON, []byte("# Tasks\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if choices.available(notJSON) {
+		t.Error("available() = true for a file that can't be parsed")
+	}
+}
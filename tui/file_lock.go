@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// errLockHeld is returned when another live process holds the lock on a tasks file.
+var errLockHeld = errors.New("another taskmaster-tui instance appears to be editing this file")
+
+// fileLock is an advisory lock stored next to a tasks file as <file>.tui.lock.
+// The lock file contains the PID of the holder so stale locks can be detected.
+type fileLock struct {
+	path string
+}
+
+// lockPathFor returns the lock file path for a tasks file.
+func lockPathFor(tasksPath string) string {
+	return resolveTasksPath(tasksPath) + ".tui.lock"
+}
+
+// acquireFileLock takes the lock for a tasks file. A lock left behind by a
+// process that is no longer running is reclaimed.
+func acquireFileLock(tasksPath string) (*fileLock, error) {
+	path := lockPathFor(tasksPath)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_, writeErr := fmt.Fprintf(f, "%d\n", os.Getpid())
+			closeErr := f.Close()
+			if writeErr != nil || closeErr != nil {
+				os.Remove(path)
+				return nil, fmt.Errorf("failed to write lock file: %v", errors.Join(writeErr, closeErr))
+			}
+			return &fileLock{path: path}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+
+		if pid, err := readLockPID(path); err == nil && processAlive(pid) {
+			return nil, fmt.Errorf("%w (PID %d)", errLockHeld, pid)
+		}
+		// Stale or unreadable lock, remove it and try again
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove stale lock file: %w", err)
+		}
+	}
+
+	return nil, errLockHeld
+}
+
+// Release removes the lock file.
+func (l *fileLock) Release() error {
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// readLockPID reads the PID recorded in a lock file.
+func readLockPID(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// processAlive reports whether a process with the given PID is running.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = proc.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, os.ErrPermission)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestAcquireFileLock(t *testing.T) {
+	tasksPath := filepath.Join(t.TempDir(), "tasks.json")
+
+	lock, err := acquireFileLock(tasksPath)
+	if err != nil {
+		t.Fatalf("acquireFileLock() unexpected error: %v", err)
+	}
+
+	pid, err := readLockPID(lockPathFor(tasksPath))
+	if err != nil {
+		t.Fatalf("readLockPID() unexpected error: %v", err)
+	}
+	if pid != os.Getpid() {
+		t.Errorf("lock PID = %d, want %d", pid, os.Getpid())
+	}
+
+	if _, err := acquireFileLock(tasksPath); !errors.Is(err, errLockHeld) {
+		t.Errorf("second acquireFileLock() error = %v, want errLockHeld", err)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(lockPathFor(tasksPath)); !os.IsNotExist(err) {
+		t.Errorf("lock file still exists after Release(): %v", err)
+	}
+
+	relock, err := acquireFileLock(tasksPath)
+	if err != nil {
+		t.Fatalf("acquireFileLock() after release unexpected error: %v", err)
+	}
+	relock.Release()
+}
+
+func TestAcquireFileLockReclaimsStaleLock(t *testing.T) {
+	// Run a short-lived process so its PID is known to be dead
+	cmd := exec.Command("go", "version")
+	if err := cmd.Run(); err != nil {
+		t.Skipf("cannot start helper process: %v", err)
+	}
+	deadPID := cmd.ProcessState.Pid()
+
+	tests := []struct {
+		name     string
+		contents string
+	}{
+		{name: "dead PID", contents: strconv.Itoa(deadPID)},
+		{name: "garbage", contents: "not-a-pid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasksPath := filepath.Join(t.TempDir(), "tasks.json")
+			if err := os.WriteFile(lockPathFor(tasksPath), []byte(tt.contents), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			lock, err := acquireFileLock(tasksPath)
+			if err != nil {
+				t.Fatalf("acquireFileLock() unexpected error: %v", err)
+			}
+			defer lock.Release()
+
+			if pid, _ := readLockPID(lockPathFor(tasksPath)); pid != os.Getpid() {
+				t.Errorf("lock PID = %d, want %d", pid, os.Getpid())
+			}
+		})
+	}
+}
+
+func TestReleaseMissingLock(t *testing.T) {
+	lock := &fileLock{path: filepath.Join(t.TempDir(), "tasks.json.tui.lock")}
+	if err := lock.Release(); err != nil {
+		t.Errorf("Release() of missing lock unexpected error: %v", err)
+	}
+}
+
+func TestGenerateTaskFilesWaitsForLock(t *testing.T) {
+	fake := &fakeRunner{}
+	useFakeRunner(t, fake)
+	tasksPath := filepath.Join(t.TempDir(), "tasks.json")
+	writeTasks(t, tasksPath, `{"tasks": []}`)
+
+	// A live process other than this one holds the lock
+	holder := exec.Command("sleep", "10")
+	if err := holder.Start(); err != nil {
+		t.Skipf("can't start a lock holder: %v", err)
+	}
+	defer holder.Process.Kill()
+	if err := os.WriteFile(lockPathFor(tasksPath), []byte(strconv.Itoa(holder.Process.Pid)+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := cliExecutor.GenerateTaskFiles(tasksPath, t.TempDir(), nil)
+	if result.Success || len(fake.calls) != 0 {
+		t.Errorf("GenerateTaskFiles() with the lock held = %+v after running %q, want it refused", result, fake.calls)
+	}
+}
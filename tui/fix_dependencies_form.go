@@ -0,0 +1,274 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	fixDepsFormKeyFile    = "file"
+	fixDepsFormKeyConfirm = "confirm"
+)
+
+// FixDependenciesModel holds the state for the fix dependencies form.
+type FixDependenciesModel struct {
+	form         *huh.Form
+	aborted      bool
+	isProcessing bool
+	statusMsg    string
+	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
+
+	// Form values
+	FilePath  string
+	Confirmed bool // Must be set before the file is modified
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
+}
+
+// NewFixDependenciesForm creates a new form for the fix-dependencies command.
+func NewFixDependenciesForm() *FixDependenciesModel {
+	m := &FixDependenciesModel{
+		FilePath: lastFilePath(), // Pre-populate with the last used tasks file
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			newFilePathField(fixDepsFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json) to fix.", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key(fixDepsFormKeyConfirm).
+				Title("Fix Dependencies").
+				Description("Remove invalid, duplicate, and circular dependencies from the tasks file? This modifies the file.").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&m.Confirmed), // Defaults to No
+		),
+	).WithTheme(huh.ThemeDracula())
+
+	return m
+}
+
+func (m *FixDependenciesModel) Init() tea.Cmd {
+	m.isProcessing = false
+	m.statusMsg = ""
+	m.aborted = false
+	return m.form.Init()
+}
+
+func (m *FixDependenciesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case fixDependenciesCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			m.statusMsg = "✅ Success!"
+			m.result.setContent(summarizeDependencyFixes(msg.result.Output))
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
+	if m.isProcessing {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, nil
+	}
+
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.statusMsg = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
+	var cmds []tea.Cmd
+	formModel, cmd := m.form.Update(msg)
+	if updatedForm, ok := formModel.(*huh.Form); ok {
+		m.form = updatedForm
+	} else {
+		m.statusMsg = "Error: Form update returned unexpected type."
+		debugLog.Error("form update did not return *huh.Form", "file", "fix_dependencies_form.go", "type", fmt.Sprintf("%T", formModel))
+		return m, tea.Quit
+	}
+	cmds = append(cmds, cmd)
+
+	if m.form.State == huh.StateCompleted {
+		if !m.Confirmed {
+			// Declining the confirm leaves the file untouched
+			m.aborted = true
+			return m, func() tea.Msg { return backToMenuMsg{} }
+		}
+
+		m.statusMsg = "Executing fix-dependencies command..."
+		m.isProcessing = true
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeFixDependenciesCommand))
+	}
+
+	if m.form.State == huh.StateAborted {
+		m.aborted = true
+		return m, func() tea.Msg { return backToMenuMsg{} }
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if !m.isProcessing {
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			}
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *FixDependenciesModel) View() string {
+	if m.aborted {
+		return "Form aborted. Returning to main menu..."
+	}
+
+	var viewBuilder strings.Builder
+	viewBuilder.WriteString(m.form.View())
+
+	if m.statusMsg != "" {
+		viewBuilder.WriteString("\n\n")
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
+	}
+
+	helpStyle := lipgloss.NewStyle().Faint(true)
+	if m.isProcessing {
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
+	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
+	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Padding(1, 2).
+		Render(viewBuilder.String())
+}
+
+func (m *FixDependenciesModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
+// GetFormValues retrieves the structured data after completion.
+func (m *FixDependenciesModel) GetFormValues() (map[string]interface{}, error) {
+	if m.form.State != huh.StateCompleted {
+		return nil, fmt.Errorf("form is not yet completed")
+	}
+	return map[string]interface{}{
+		fixDepsFormKeyFile:    m.FilePath,
+		fixDepsFormKeyConfirm: m.Confirmed,
+		advancedFlagsFormKey:  m.AdvancedFlags,
+	}, nil
+}
+
+// Styles for the lines of a dependency fix summary
+var (
+	removedDependencyStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	fixSummaryHeaderStyle  = lipgloss.NewStyle().Bold(true)
+)
+
+// summarizeDependencyFixes lists each change reported by fix-dependencies as a
+// removed ("-") line, diff style, above the full command output.
+func summarizeDependencyFixes(output string) string {
+	var changes []string
+	for _, line := range strings.Split(output, "\n") {
+		for _, marker := range []string{"Breaking circular dependency", "Removing "} {
+			if i := strings.Index(line, marker); i >= 0 {
+				changes = append(changes, removedDependencyStyle.Render("- "+strings.TrimSpace(line[i:])))
+				break
+			}
+		}
+	}
+
+	if len(changes) == 0 {
+		return fixSummaryHeaderStyle.Render("No dependency changes were needed.") + "\n\n" + output
+	}
+	header := fixSummaryHeaderStyle.Render(fmt.Sprintf("%d dependency change(s):", len(changes)))
+	return header + "\n" + strings.Join(changes, "\n") + "\n\n" + output
+}
+
+// fixDependenciesCompleteMsg is sent when the command execution is complete
+type fixDependenciesCompleteMsg struct {
+	result CLIResult
+}
+
+// executeFixDependenciesCommand executes the actual fix-dependencies CLI command
+func (m *FixDependenciesModel) executeFixDependenciesCommand() tea.Cmd {
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+
+		result := executor.FixDependencies(m.FilePath)
+		return fixDependenciesCompleteMsg{result: result}
+	})
+}
+
+var _ tea.Model = &FixDependenciesModel{}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSummarizeDependencyFixes(t *testing.T) {
+	output := "[INFO] Checking for and fixing invalid dependencies in tasks.json...\n" +
+		"[WARN] Removing duplicate dependency from task 2: 1\n" +
+		"[WARN] Removing invalid task dependency from task 4: 9 (task does not exist)\n" +
+		"[INFO] Checking for circular dependencies...\n" +
+		"[WARN] Breaking circular dependency: Removing 3.1 from subtask 3.2\n" +
+		"[SUCCESS] Fixed dependency issues in tasks.json"
+
+	got := summarizeDependencyFixes(output)
+	wantLines := []string{
+		fixSummaryHeaderStyle.Render("3 dependency change(s):"),
+		removedDependencyStyle.Render("- Removing duplicate dependency from task 2: 1"),
+		removedDependencyStyle.Render("- Removing invalid task dependency from task 4: 9 (task does not exist)"),
+		removedDependencyStyle.Render("- Breaking circular dependency: Removing 3.1 from subtask 3.2"),
+	}
+	if want := strings.Join(wantLines, "\n") + "\n\n" + output; got != want {
+		t.Errorf("summarizeDependencyFixes() =\n%q\nwant\n%q", got, want)
+	}
+
+	clean := "[INFO] No changes needed to fix dependencies"
+	if got, want := summarizeDependencyFixes(clean), fixSummaryHeaderStyle.Render("No dependency changes were needed.")+"\n\n"+clean; got != want {
+		t.Errorf("summarizeDependencyFixes(clean) = %q, want %q", got, want)
+	}
+}
@@ -5,15 +5,17 @@ import (
 	"os"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 )
 
 const (
-	generateFormKeyFile   = "file"
-	generateFormKeyOutput = "output" // Directory path
-	generateFormKeyForce  = "force"
+	generateFormKeyFile      = "file"
+	generateFormKeyOutput    = "output" // Directory path
+	generateFormKeyTaskIDs   = "task-ids"
+	generateFormKeyCreateDir = "create-dir"
 )
 
 // GenerateFilesModel holds the state for the generate (task files) form.
@@ -23,57 +25,70 @@ type GenerateFilesModel struct {
 	isProcessing bool
 	status       string
 	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
 
 	// Form values
-	FilePath      string // Path to the input tasks file
+	FilePath        string // Path to the input tasks file
 	OutputDirectory string // Path to the output directory
-	Force         bool   // Force overwrite existing files
+	TaskIDs         string // Comma-separated IDs of the tasks to generate; empty for all
+	CreateDir       bool   // Create the output directory if it doesn't exist
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
 }
 
 // NewGenerateFilesForm creates a new form for the generate command.
 func NewGenerateFilesForm() *GenerateFilesModel {
 	m := &GenerateFilesModel{
-		Force: false, // Default to not force overwrite
+		FilePath: lastFilePath(), // Pre-populate with the last used tasks file
 	}
 
 	m.form = huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().
-				Key(generateFormKeyFile).
-				Title("Tasks File Path").
-				Description("Path to the input tasks file (e.g., tasks.md).").
-				Prompt("📄 ").
-				Validate(func(s string) error {
-					if s == "" {
-						return fmt.Errorf("tasks file path cannot be empty")
-					}
-					// Consider adding file existence validation if needed
-					return nil
-				}).
-				Value(&m.FilePath),
-
+			newFilePathField(generateFormKeyFile, "Tasks File Path", "Path to the input tasks file (e.g., tasks.json).", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		huh.NewGroup(
 			huh.NewInput().
 				Key(generateFormKeyOutput).
 				Title("Output Directory").
 				Description("Path to the directory where task files will be generated.").
 				Prompt("📁 ").
-				Validate(func(s string) error {
-					if s == "" {
-						return fmt.Errorf("output directory cannot be empty")
-					}
-					// Consider adding directory validation (e.g., check if exists, or creatable)
-					return nil
-				}).
+				Validate(validateOutputDir).
 				Value(&m.OutputDirectory),
 
+			huh.NewInput().
+				Key(generateFormKeyTaskIDs).
+				Title("Task IDs (Optional)").
+				Description("Comma-separated IDs of the tasks to generate files for. Leave empty for all tasks.").
+				Prompt("🆔 ").
+				SuggestionsFunc(taskIDListSuggestions(&m.FilePath, &m.TaskIDs, false), []any{&m.FilePath, &m.TaskIDs}).
+				Validate(func(s string) error { return validateTopLevelTaskIDs(m.FilePath, s) }).
+				Value(&m.TaskIDs),
+		),
+		// Only asked when the output directory doesn't exist yet
+		huh.NewGroup(
 			huh.NewConfirm().
-				Key(generateFormKeyForce).
-				Title("Force Overwrite").
-				Description("Overwrite existing task files if they exist?").
+				Key(generateFormKeyCreateDir).
+				Title("Create Output Directory").
+				DescriptionFunc(func() string {
+					return fmt.Sprintf("%s doesn't exist. Create it?", m.OutputDirectory)
+				}, &m.OutputDirectory).
 				Affirmative("Yes").
 				Negative("No").
-				Value(&m.Force),
-		),
+				Validate(func(create bool) error {
+					if !create {
+						return fmt.Errorf("choose Yes to create it, or go back and choose an existing directory")
+					}
+					return nil
+				}).
+				Value(&m.CreateDir),
+		).WithHideFunc(func() bool { return dirExists(m.OutputDirectory) }),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
 	).WithTheme(huh.ThemeDracula())
 
 	return m
@@ -87,9 +102,43 @@ func (m *GenerateFilesModel) Init() tea.Cmd {
 }
 
 func (m *GenerateFilesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case generateTaskFilesCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.status = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			m.status = "✅ Success!"
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.status = renderResult(msg.result)
+		}
+		return m, nil
+	case editorFinishedMsg:
+		m.result.notice = editorFinishedNotice(msg)
+		return m, nil
+	}
+
 	if m.isProcessing {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
 			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.status = "Cancelling..."
+				}
 			case "ctrl+c", "q":
 				return m, tea.Quit
 			}
@@ -97,21 +146,57 @@ func (m *GenerateFilesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.status = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			case editKey:
+				if strings.HasPrefix(m.status, "✅") {
+					return m, openInEditor(generatedPath(m.OutputDirectory, splitTaskIDs(m.TaskIDs)))
+				}
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
 	var cmds []tea.Cmd
 	formModel, cmd := m.form.Update(msg)
 	if updatedForm, ok := formModel.(*huh.Form); ok {
 		m.form = updatedForm
 	} else {
 		m.status = "Error: Form update returned unexpected type."
-		fmt.Fprintf(os.Stderr, "Critical Error: generate_form.go - form update did not return *huh.Form. Got: %T\n", formModel)
+		debugLog.Error("form update did not return *huh.Form", "file", "generate_form.go", "type", fmt.Sprintf("%T", formModel))
 		return m, tea.Quit
 	}
 	cmds = append(cmds, cmd)
 
 	if m.form.State == huh.StateCompleted {
-		m.status = "Executing generate-task-files command..."
+		if m.CreateDir && !dirExists(m.OutputDirectory) {
+			if err := os.MkdirAll(resolveTasksPath(m.OutputDirectory), 0o755); err != nil {
+				m.status = fmt.Sprintf("Error: couldn't create the output directory: %v", err)
+				m.form.State = huh.StateNormal
+				return m, nil
+			}
+		}
+
+		m.status = "Executing generate command..."
 		m.isProcessing = true
-		return m, m.executeGenerateTaskFilesCommand()
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeGenerateTaskFilesCommand))
 	}
 
 	if m.form.State == huh.StateAborted {
@@ -120,14 +205,6 @@ func (m *GenerateFilesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
-	case generateTaskFilesCompleteMsg:
-		m.isProcessing = false
-		if msg.result.Success {
-			m.status = fmt.Sprintf("✅ Success!\n\n%s", msg.result.Output)
-		} else {
-			m.status = fmt.Sprintf("❌ Error: %s\n\n%s", msg.result.Error, msg.result.Output)
-		}
-		return m, nil
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
@@ -136,8 +213,6 @@ func (m *GenerateFilesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, func() tea.Msg { return backToMenuMsg{} }
 			}
 		}
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
 	}
 
 	return m, tea.Batch(cmds...)
@@ -153,18 +228,23 @@ func (m *GenerateFilesModel) View() string {
 
 	if m.status != "" {
 		viewBuilder.WriteString("\n\n")
-		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-		if strings.HasPrefix(m.status, "Error:") {
-			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
-		}
-		viewBuilder.WriteString(statusStyle.Render(m.status))
+		viewBuilder.WriteString(renderStatus(m.status))
 	}
 
 	helpStyle := lipgloss.NewStyle().Faint(true)
 	if m.isProcessing {
-		viewBuilder.WriteString(helpStyle.Render("\n\nProcessing... Press Ctrl+C to force quit."))
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
 	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.status, "✅") {
-		viewBuilder.WriteString(helpStyle.Render("\n\nCommand completed! Press Esc to return to main menu."))
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		help := m.result.help() + fmt.Sprintf(" Press %s to open the generated files in your editor.", editKey)
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + help))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
 	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
 		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
 	}
@@ -175,15 +255,19 @@ func (m *GenerateFilesModel) View() string {
 		Render(viewBuilder.String())
 }
 
+func (m *GenerateFilesModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
 // GetFormValues retrieves the structured data after completion.
 func (m *GenerateFilesModel) GetFormValues() (map[string]interface{}, error) {
 	if m.form.State != huh.StateCompleted {
 		return nil, fmt.Errorf("form is not yet completed")
 	}
 	return map[string]interface{}{
-		generateFormKeyFile:   m.FilePath,
-		generateFormKeyOutput: m.OutputDirectory,
-		generateFormKeyForce:  m.Force,
+		generateFormKeyFile:      m.FilePath,
+		generateFormKeyOutput:    m.OutputDirectory,
+		generateFormKeyTaskIDs:   m.TaskIDs,
+		generateFormKeyCreateDir: m.CreateDir,
+		advancedFlagsFormKey:     m.AdvancedFlags,
 	}, nil
 }
 
@@ -192,12 +276,17 @@ type generateTaskFilesCompleteMsg struct {
 	result CLIResult
 }
 
-// executeGenerateTaskFilesCommand executes the actual generate-task-files CLI command
+// executeGenerateTaskFilesCommand executes the actual generate CLI command
 func (m *GenerateFilesModel) executeGenerateTaskFilesCommand() tea.Cmd {
-	return func() tea.Msg {
-		result := cliExecutor.GenerateTaskFiles(m.FilePath, m.OutputDirectory, m.Force)
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+
+		result := executor.GenerateTaskFiles(m.FilePath, m.OutputDirectory, splitTaskIDs(m.TaskIDs))
 		return generateTaskFilesCompleteMsg{result: result}
-	}
+	})
 }
 
 // Ensure GenerateFilesModel implements tea.Model.
@@ -0,0 +1,432 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Exit codes of a command run without the TUI
+const (
+	headlessExitOK     = 0
+	headlessExitFailed = 1 // The command ran and failed
+	headlessExitUsage  = 2 // The command line was wrong, nothing ran
+)
+
+// headlessCommand defines a command's flags on fs and returns the function
+// that runs it once the flags are parsed.
+type headlessCommand func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error)
+
+// headlessCommands are the commands that can be run without the TUI, by CLI
+// command name. The flags match the fields of each command's form.
+var headlessCommands = map[string]headlessCommand{
+	"parse-prd": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		input := fs.String("input", "", "PRD file to parse")
+		file := fs.String("file", "", "tasks file to write")
+		numTasks := fs.Int("num-tasks", batchDefaultNumTasks, "number of tasks to generate")
+		force := fs.Bool("force", false, "overwrite an existing tasks file")
+		appendMode := fs.Bool("append", false, "add to an existing tasks file")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "input", "file"); err != nil {
+				return CLIResult{}, err
+			}
+			return e.ParsePRD(*input, *file, *numTasks, *force, *appendMode), nil
+		}
+	},
+	"add-task": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		prompt := fs.String("prompt", "", "describe the task for AI generation")
+		title := fs.String("title", "", "task title, without a prompt")
+		description := fs.String("description", "", "task description, without a prompt")
+		details := fs.String("details", "", "implementation details, without a prompt")
+		testStrategy := fs.String("test-strategy", "", "test strategy, without a prompt")
+		dependencies := fs.String("dependencies", "", "comma-separated IDs the task depends on")
+		priority := fs.String("priority", "", "task priority")
+		taskType := fs.String("type", "", "task type")
+		criteria := fs.String("acceptance-criteria", "", "acceptance criteria of a checkpoint, without a prompt")
+		research := fs.Bool("research", false, "use research")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file"); err != nil {
+				return CLIResult{}, err
+			}
+			if *prompt == "" && *title == "" {
+				return CLIResult{}, errors.New("either -prompt or -title is required")
+			}
+			return e.AddTask(*file, "", *prompt, *title, *description, *details, *testStrategy, *dependencies, *priority, *taskType, *criteria, *research), nil
+		}
+	},
+	"next-task": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		count := fs.Int("count", 1, "number of tasks to list")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file"); err != nil {
+				return CLIResult{}, err
+			}
+			if *count < 1 {
+				return CLIResult{}, errors.New("-count must be at least 1")
+			}
+			return e.NextTasks(*file, *count), nil
+		}
+	},
+	"show-task": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		id := fs.String("id", "", "task or subtask ID")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file", "id"); err != nil {
+				return CLIResult{}, err
+			}
+			return e.ShowTask(*file, *id), nil
+		}
+	},
+	"add-dependency": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		id := fs.String("id", "", "task that gains the dependency")
+		dependsOn := fs.String("depends-on", "", "task it depends on")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file", "id", "depends-on"); err != nil {
+				return CLIResult{}, err
+			}
+			if err := validateDependencyPair(*id, *dependsOn); err != nil {
+				return CLIResult{}, err
+			}
+			return e.AddDependency(*file, *id, *dependsOn), nil
+		}
+	},
+	"update-tasks": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		prompt := fs.String("prompt", "", "what changed")
+		ids := fs.String("ids", "", "comma-separated task IDs to update, default all pending")
+		research := fs.Bool("research", false, "use research")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file", "prompt"); err != nil {
+				return CLIResult{}, err
+			}
+			return e.UpdateTasks(*file, *prompt, splitList(*ids), *research), nil
+		}
+	},
+	"update-task": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		id := fs.String("id", "", "task ID")
+		prompt := fs.String("prompt", "", "what changed")
+		research := fs.Bool("research", false, "use research")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file", "id", "prompt"); err != nil {
+				return CLIResult{}, err
+			}
+			return e.UpdateOneTask(*file, *id, *prompt, *research), nil
+		}
+	},
+	"update-subtask": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		id := fs.String("id", "", "parent task ID")
+		subtaskID := fs.String("subtask-id", "", "subtask ID within the task")
+		prompt := fs.String("prompt", "", "information to add")
+		research := fs.Bool("research", false, "use research")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file", "id", "subtask-id", "prompt"); err != nil {
+				return CLIResult{}, err
+			}
+			return e.UpdateSubtask(*file, *id, *subtaskID, *prompt, *research), nil
+		}
+	},
+	"generate-task-files": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		outputDir := fs.String("output-dir", "", "directory for the task files")
+		ids := fs.String("id", "", "comma-separated IDs of the tasks to generate files for; all when empty")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file", "output-dir"); err != nil {
+				return CLIResult{}, err
+			}
+			return e.GenerateTaskFiles(*file, *outputDir, splitTaskIDs(*ids)), nil
+		}
+	},
+	"set-task-status": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		ids := fs.String("id", "", "comma-separated task or subtask IDs")
+		status := fs.String("status", "", "new status")
+		criteriaMet := fs.Bool("criteria-met", false, "confirm a checkpoint's criteria are met")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file", "id", "status"); err != nil {
+				return CLIResult{}, err
+			}
+			if problems := ValidateBatch(*file, splitList(*ids), *status); len(problems) > 0 {
+				return CLIResult{}, fmt.Errorf("nothing was run: %s", strings.Join(problems, "; "))
+			}
+			return mergeResults(e.SetTaskStatuses(*file, splitList(*ids), *status, *criteriaMet)), nil
+		}
+	},
+	"set-task-statuses": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		mapping := fs.String("map", "", "IDs and their new statuses, e.g. 1=done,2.1=in-progress")
+		criteriaMet := fs.Bool("criteria-met", false, "confirm a checkpoint's criteria are met")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file", "map"); err != nil {
+				return CLIResult{}, err
+			}
+			assignments, err := parseStatusMapping(*mapping)
+			if err != nil {
+				return CLIResult{}, err
+			}
+			return statusMappingResult(assignments, e.SetTaskStatusMapping(*file, assignments, *criteriaMet)), nil
+		}
+	},
+	"list-tasks": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		statuses := fs.String("status", "", "comma-separated statuses to show")
+		priorities := fs.String("priority", "", "comma-separated priorities to show")
+		withSubtasks := fs.Bool("with-subtasks", false, "show subtasks")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file"); err != nil {
+				return CLIResult{}, err
+			}
+			return e.ListTasks(*file, splitList(*statuses), splitList(*priorities), *withSubtasks), nil
+		}
+	},
+	"expand-task": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		id := fs.String("id", "", "task ID")
+		prompt := fs.String("prompt", "", "additional context")
+		numSubtasks := fs.Int("num-subtasks", 0, "number of subtasks, default the CLI's")
+		research := fs.Bool("research", false, "use research")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file", "id"); err != nil {
+				return CLIResult{}, err
+			}
+			return e.ExpandTask(*file, *id, *prompt, *numSubtasks, *research), nil
+		}
+	},
+	"analyze-complexity": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		threshold := fs.Int("threshold", 0, "complexity score above which to recommend expanding")
+		output := fs.String("output", "", "report file")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file"); err != nil {
+				return CLIResult{}, err
+			}
+			return e.AnalyzeComplexity(*file, *threshold, *output), nil
+		}
+	},
+	"clear-subtasks": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		ids := fs.String("id", "", "comma-separated task IDs")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file", "id"); err != nil {
+				return CLIResult{}, err
+			}
+			if problems := ValidateBatch(*file, splitList(*ids), ""); len(problems) > 0 {
+				return CLIResult{}, fmt.Errorf("nothing was run: %s", strings.Join(problems, "; "))
+			}
+			return e.ClearSubtasks(*file, *ids), nil
+		}
+	},
+	"remove-subtask": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		id := fs.String("id", "", "subtask ID as parent.subtask")
+		convert := fs.Bool("convert", false, "make it a standalone task instead of deleting it")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file", "id"); err != nil {
+				return CLIResult{}, err
+			}
+			if err := validateSubtaskID(*id); err != nil {
+				return CLIResult{}, err
+			}
+			return e.RemoveSubtask(*file, *id, *convert), nil
+		}
+	},
+	"move-task": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		from := fs.String("from", "", "ID of the task or subtask to move")
+		to := fs.String("to", "", "destination task or subtask ID")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file", "from", "to"); err != nil {
+				return CLIResult{}, err
+			}
+			if err := validateMovePair(*from, *to); err != nil {
+				return CLIResult{}, err
+			}
+			return e.MoveTask(*file, *from, *to), nil
+		}
+	},
+	"duplicate-task": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		id := fs.String("id", "", "ID of the task or subtask to copy")
+		title := fs.String("title", "", "title of the new task (default: the source's title with \"(copy)\")")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file", "id"); err != nil {
+				return CLIResult{}, err
+			}
+			if err := validateTaskID(*id); err != nil {
+				return CLIResult{}, err
+			}
+			return e.DuplicateTask(*file, *id, *title), nil
+		}
+	},
+	"set-priority": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		id := fs.String("id", "", "ID of the task")
+		priority := fs.String("priority", "", "new priority: high, medium or low")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file", "id", "priority"); err != nil {
+				return CLIResult{}, err
+			}
+			if err := validatePriority(*priority); err != nil {
+				return CLIResult{}, err
+			}
+			return e.SetPriority(*file, *id, *priority), nil
+		}
+	},
+	"validate-dependencies": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file"); err != nil {
+				return CLIResult{}, err
+			}
+			return e.ValidateDependencies(*file), nil
+		}
+	},
+	"fix-dependencies": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file"); err != nil {
+				return CLIResult{}, err
+			}
+			return e.FixDependencies(*file), nil
+		}
+	},
+	"undo": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "tasks file to restore")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file"); err != nil {
+				return CLIResult{}, err
+			}
+			return e.UndoLastChange(*file), nil
+		}
+	},
+	"batch": func(fs *flag.FlagSet) func(e *CLIExecutor) (CLIResult, error) {
+		file := fs.String("file", "", "batch file of commands")
+		continueOnError := fs.Bool("continue", false, "run the remaining commands after one fails")
+		return func(e *CLIExecutor) (CLIResult, error) {
+			if err := requireFlags(fs, "file"); err != nil {
+				return CLIResult{}, err
+			}
+			commands, err := LoadBatchFile(*file)
+			if err != nil {
+				return CLIResult{}, err
+			}
+			return e.BatchRun(commands, *continueOnError), nil
+		}
+	},
+}
+
+// runHeadless runs the command named by args[0] with the flags that follow,
+// without the TUI, and writes its result to stdout as JSON. Flag errors and
+// help go to stderr. It returns the process exit code.
+func runHeadless(e *CLIExecutor, args []string, stdout, stderr io.Writer) int {
+	define, ok := headlessCommands[args[0]]
+	if !ok {
+		return writeUsageError(stdout, fmt.Errorf("unknown command %q; commands are: %s", args[0], strings.Join(headlessCommandNames(), ", ")))
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	tag := fs.String("tag", "", "task context (tag), default the active one")
+	run := define(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return headlessExitOK
+		}
+		return writeUsageError(stdout, err)
+	}
+	if fs.NArg() > 0 {
+		return writeUsageError(stdout, fmt.Errorf("unexpected argument %q; use flags", fs.Arg(0)))
+	}
+
+	result, err := run(e.WithTag(*tag))
+	if err != nil {
+		return writeUsageError(stdout, err)
+	}
+	writeHeadlessResult(stdout, result)
+	if !result.Success {
+		return headlessExitFailed
+	}
+	return headlessExitOK
+}
+
+// writeHeadlessResult prints result as JSON.
+func writeHeadlessResult(w io.Writer, result CLIResult) {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(result)
+}
+
+// writeUsageError prints the result for a command line that couldn't be run
+// and returns its exit code.
+func writeUsageError(w io.Writer, err error) int {
+	writeHeadlessResult(w, usageResult(err))
+	return headlessExitUsage
+}
+
+// usageResult is the result for a command line that couldn't be run.
+func usageResult(err error) CLIResult {
+	return CLIResult{
+		Success:  false,
+		Message:  "Invalid command line",
+		Error:    err.Error(),
+		ExitCode: headlessExitUsage,
+	}
+}
+
+// requireFlags returns an error naming the first of names that wasn't given a value.
+func requireFlags(fs *flag.FlagSet, names ...string) error {
+	for _, name := range names {
+		if f := fs.Lookup(name); f != nil && f.Value.String() == "" {
+			return fmt.Errorf("-%s is required", name)
+		}
+	}
+	return nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// mergeResults combines the results of a command run once per item into
+// one, which fails if any run did.
+func mergeResults(results []CLIResult) CLIResult {
+	if len(results) == 1 {
+		return results[0]
+	}
+	merged := CLIResult{Success: true, Message: "Command executed successfully"}
+	var outputs []string
+	for _, result := range results {
+		outputs = append(outputs, strings.TrimRight(result.Output, "\n"))
+		if !result.Success {
+			merged.Success = false
+			merged.Error = result.Error
+			merged.Message = result.Message
+		}
+	}
+	merged.Output = strings.Join(outputs, "\n\n")
+	return merged
+}
+
+// headlessCommandNames returns the names of the headless commands, sorted.
+func headlessCommandNames() []string {
+	names := make([]string, 0, len(headlessCommands))
+	for name := range headlessCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
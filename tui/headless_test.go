@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunHeadless(t *testing.T) {
+	fake := &fakeRunner{stdout: "Added task 4\n"}
+	useFakeRunner(t, fake)
+
+	var stdout bytes.Buffer
+	code := runHeadless(cliExecutor, []string{"add-task", "--file", "tasks.json", "--title", "Login", "--description", "Users can log in", "--priority", "high"}, &stdout, io.Discard)
+	if code != headlessExitOK {
+		t.Fatalf("runHeadless() = %d, want %d; output %s", code, headlessExitOK, stdout.String())
+	}
+
+	var result CLIResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("output is not a JSON result: %v\n%s", err, stdout.String())
+	}
+	if !result.Success || result.Output != fake.stdout {
+		t.Errorf("result = %+v, want success with the command's output", result)
+	}
+	want := [][]string{{"node", "../scripts/dev.js", "add-task", "tasks.json", "--title", "Login", "--description", "Users can log in", "--priority", "high"}}
+	if !reflect.DeepEqual(fake.calls, want) {
+		t.Errorf("ran %q, want %q", fake.calls, want)
+	}
+}
+
+func TestRunHeadlessFailure(t *testing.T) {
+	useFakeRunner(t, &fakeRunner{stderr: "Task 9 not found\n", err: errors.New("exit status 1"), exitCode: 1})
+
+	var stdout bytes.Buffer
+	if code := runHeadless(cliExecutor, []string{"show-task", "-file", "tasks.json", "-id", "9"}, &stdout, io.Discard); code != headlessExitFailed {
+		t.Errorf("runHeadless() = %d, want %d", code, headlessExitFailed)
+	}
+	if !strings.Contains(stdout.String(), `"success": false`) {
+		t.Errorf("output = %s, want a failed JSON result", stdout.String())
+	}
+}
+
+func TestRunHeadlessUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "unknown command", args: []string{"deploy"}},
+		{name: "missing flag", args: []string{"show-task", "--file", "tasks.json"}},
+		{name: "unknown flag", args: []string{"next-task", "--file", "tasks.json", "--verbose"}},
+		{name: "positional argument", args: []string{"next-task", "tasks.json"}},
+		{name: "add-task without prompt or title", args: []string{"add-task", "--file", "tasks.json"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeRunner{}
+			useFakeRunner(t, fake)
+
+			var stdout bytes.Buffer
+			if code := runHeadless(cliExecutor, tt.args, &stdout, io.Discard); code != headlessExitUsage {
+				t.Errorf("runHeadless() = %d, want %d", code, headlessExitUsage)
+			}
+			var result CLIResult
+			if err := json.Unmarshal(stdout.Bytes(), &result); err != nil || result.Success || result.Error == "" {
+				t.Errorf("output = %s, want a JSON result with the error", stdout.String())
+			}
+			if len(fake.calls) != 0 {
+				t.Errorf("ran %q, want nothing run", fake.calls)
+			}
+		})
+	}
+}
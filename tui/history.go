@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// historyMaxBytes is the size at which the history log is rotated. The previous
+// log is kept alongside it with a ".1" suffix.
+const historyMaxBytes = 512 * 1024
+
+// historyEntry records one command run by the TUI.
+type historyEntry struct {
+	Time     time.Time     `json:"time"`
+	Args     []string      `json:"args"`
+	Dir      string        `json:"dir"`
+	Success  bool          `json:"success"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// historyMu serialises writes from commands that run concurrently.
+var historyMu sync.Mutex
+
+// historyPath returns the location of the command history log under the user's config directory.
+func historyPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "taskmaster-tui", "history.jsonl"), nil
+}
+
+// recordHistory appends entry to the history log. Failures are ignored; the
+// history is only a debugging aid.
+func recordHistory(entry historyEntry) {
+	path, err := historyPath()
+	if err != nil {
+		return
+	}
+	_ = appendHistory(path, entry)
+}
+
+// appendHistory appends entry to the log at path, rotating the log first if it
+// has grown past historyMaxBytes.
+func appendHistory(path string, entry historyEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() >= historyMaxBytes {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// RecentHistory returns up to n of the most recently run commands, newest first.
+func RecentHistory(n int) ([]historyEntry, error) {
+	path, err := historyPath()
+	if err != nil {
+		return nil, err
+	}
+	return readHistory(path, n)
+}
+
+// readHistory returns up to n entries from the log at path and its rotated
+// predecessor, newest first. Lines that can't be parsed are skipped.
+func readHistory(path string, n int) ([]historyEntry, error) {
+	var entries []historyEntry
+	for _, file := range []string{path, path + ".1"} {
+		fileEntries, err := readHistoryFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for i := len(fileEntries) - 1; i >= 0 && len(entries) < n; i-- {
+			entries = append(entries, fileEntries[i])
+		}
+		if len(entries) >= n {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// readHistoryFile reads the entries of one log file in file order. A missing file has none.
+func readHistoryFile(path string) ([]historyEntry, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []historyEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), historyMaxBytes)
+	for scanner.Scan() {
+		var entry historyEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// formatHistoryEntry renders an entry as a single line for display.
+func formatHistoryEntry(entry historyEntry) string {
+	mark := "✅"
+	if !entry.Success {
+		mark = "❌"
+	}
+	line := fmt.Sprintf("%s %s  %6s  %s", mark, entry.Time.Local().Format("2006-01-02 15:04:05"),
+		entry.Duration.Round(100*time.Millisecond), strings.Join(entry.Args, " "))
+	if entry.Error != "" {
+		line += "  (" + entry.Error + ")"
+	}
+	return line
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	historyFormKeyCount = "count"
+)
+
+// defaultHistoryCount is the number of entries shown unless the user asks for more
+const defaultHistoryCount = 20
+
+// HistoryModel holds the state for the command history form.
+type HistoryModel struct {
+	form         *huh.Form
+	aborted      bool
+	isProcessing bool
+	statusMsg    string
+	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+
+	// Form value
+	Count int // Number of entries to show
+}
+
+// NewHistoryForm creates a new form for viewing recently run commands.
+func NewHistoryForm() *HistoryModel {
+	m := &HistoryModel{
+		Count: defaultHistoryCount,
+	}
+
+	// Temporary string for Count input
+	countStr := strconv.Itoa(m.Count)
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key(historyFormKeyCount).
+				Title("Number of Entries").
+				Description("How many of the most recent commands to show.").
+				Prompt("🔢 ").
+				Validate(func(s string) error {
+					val, err := strconv.Atoi(s)
+					if err != nil {
+						return fmt.Errorf("must be a valid integer")
+					}
+					if val <= 0 {
+						return fmt.Errorf("number of entries must be greater than 0")
+					}
+					return nil
+				}).
+				Value(&countStr), // Use temporary string, parse on completion
+		),
+	).WithTheme(huh.ThemeDracula())
+
+	return m
+}
+
+func (m *HistoryModel) Init() tea.Cmd {
+	m.isProcessing = false
+	m.statusMsg = ""
+	m.aborted = false
+	return m.form.Init()
+}
+
+func (m *HistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case historyCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if msg.result.Success {
+			m.statusMsg = "✅ Success!"
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
+	if m.isProcessing {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			if keyMsg.String() == "ctrl+c" || keyMsg.String() == "q" {
+				return m, tea.Quit
+			}
+		}
+		return m, nil
+	}
+
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
+	var cmds []tea.Cmd
+	formModel, cmd := m.form.Update(msg)
+	if updatedForm, ok := formModel.(*huh.Form); ok {
+		m.form = updatedForm
+	} else {
+		m.statusMsg = "Error: Form update returned unexpected type."
+		debugLog.Error("form update did not return *huh.Form", "file", "history_form.go", "type", fmt.Sprintf("%T", formModel))
+		return m, tea.Quit
+	}
+	cmds = append(cmds, cmd)
+
+	if m.form.State == huh.StateCompleted {
+		// Validated by the field, so the parse can't fail
+		m.Count, _ = strconv.Atoi(m.form.GetString(historyFormKeyCount))
+
+		m.statusMsg = "Reading command history..."
+		m.isProcessing = true
+		return m, tea.Batch(m.spinner.start(), m.executeHistoryCommand())
+	}
+
+	if m.form.State == huh.StateAborted {
+		m.aborted = true
+		return m, func() tea.Msg { return backToMenuMsg{} }
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if !m.isProcessing {
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			}
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *HistoryModel) View() string {
+	if m.aborted {
+		return "Form aborted. Returning to main menu..."
+	}
+
+	var viewBuilder strings.Builder
+	viewBuilder.WriteString(m.form.View())
+
+	if m.statusMsg != "" {
+		viewBuilder.WriteString("\n\n")
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
+	}
+
+	helpStyle := lipgloss.NewStyle().Faint(true)
+	if m.isProcessing {
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Ctrl+C to force quit."))
+	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Padding(1, 2).
+		Render(viewBuilder.String())
+}
+
+func (m *HistoryModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
+// GetFormValues retrieves the structured data after completion.
+func (m *HistoryModel) GetFormValues() (map[string]interface{}, error) {
+	if m.form.State != huh.StateCompleted {
+		return nil, fmt.Errorf("form is not yet completed")
+	}
+	return map[string]interface{}{
+		historyFormKeyCount: m.Count,
+	}, nil
+}
+
+// historyCompleteMsg is sent when the history has been read
+type historyCompleteMsg struct {
+	result CLIResult
+}
+
+// executeHistoryCommand reads the most recent entries of the command history log
+func (m *HistoryModel) executeHistoryCommand() tea.Cmd {
+	return func() tea.Msg {
+		entries, err := RecentHistory(m.Count)
+		if err != nil {
+			return historyCompleteMsg{result: CLIResult{
+				Success: false,
+				Error:   fmt.Sprintf("Failed to read command history: %v", err),
+			}}
+		}
+		if len(entries) == 0 {
+			return historyCompleteMsg{result: CLIResult{Success: true, Output: "No commands have been run yet."}}
+		}
+
+		lines := make([]string, len(entries))
+		for i, entry := range entries {
+			lines[i] = formatHistoryEntry(entry)
+		}
+		return historyCompleteMsg{result: CLIResult{Success: true, Output: strings.Join(lines, "\n")}}
+	}
+}
+
+var _ tea.Model = &HistoryModel{}
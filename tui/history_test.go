@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadHistoryNewestFirst(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	base := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		entry := historyEntry{Time: base.Add(time.Duration(i) * time.Minute), Args: []string{"node", "cmd", string(rune('a' + i))}, Success: true}
+		if err := appendHistory(path, entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := readHistory(path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Args[2])
+	}
+	if want := []string{"e", "d", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("readHistory() args = %v, want %v", got, want)
+	}
+}
+
+func TestReadHistoryIncludesRotatedLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	if err := appendHistory(path+".1", historyEntry{Args: []string{"old"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := appendHistory(path, historyEntry{Args: []string{"new"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := readHistory(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Args[0] != "new" || entries[1].Args[0] != "old" {
+		t.Errorf("readHistory() = %+v, want new then old", entries)
+	}
+}
+
+func TestAppendHistoryRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	if err := os.WriteFile(path, make([]byte, historyMaxBytes), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := appendHistory(path, historyEntry{Args: []string{"next"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, err := os.Stat(path + ".1"); err != nil || info.Size() != historyMaxBytes {
+		t.Errorf("rotated log = %v, %v; want the full previous log", info, err)
+	}
+	entries, err := readHistoryFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Args[0] != "next" {
+		t.Errorf("current log = %+v, want only the new entry", entries)
+	}
+}
+
+func TestReadHistoryMissingFile(t *testing.T) {
+	entries, err := readHistory(filepath.Join(t.TempDir(), "history.jsonl"), 5)
+	if err != nil || len(entries) != 0 {
+		t.Errorf("readHistory(missing) = %v, %v; want no entries", entries, err)
+	}
+}
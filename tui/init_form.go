@@ -0,0 +1,294 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	initFormKeyDir         = "dir"
+	initFormKeyName        = "name"
+	initFormKeyDescription = "description"
+	initFormKeyAliases     = "aliases"
+	initFormKeySkipInstall = "skip-install"
+)
+
+// InitModel holds the state for the init project form.
+type InitModel struct {
+	form         *huh.Form
+	aborted      bool
+	isProcessing bool
+	statusMsg    string
+	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
+
+	// Form values
+	Dir         string // Directory to initialize the project in
+	Name        string
+	Description string
+	AddAliases  bool // Add shell aliases for the CLI
+	SkipInstall bool // Skip installing dependencies
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
+}
+
+// NewInitForm creates a new form for the init command.
+func NewInitForm() *InitModel {
+	m := &InitModel{
+		Dir: commandDir(), // Default to the directory other commands run in
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key(initFormKeyDir).
+				Title("Project Directory").
+				Description("Existing directory to initialize the project in.").
+				Prompt("📁 ").
+				Validate(func(s string) error {
+					if s == "" {
+						return fmt.Errorf("project directory cannot be empty")
+					}
+					info, err := os.Stat(s)
+					if err != nil {
+						return fmt.Errorf("directory not found: %s", s)
+					}
+					if !info.IsDir() {
+						return fmt.Errorf("not a directory: %s", s)
+					}
+					return nil
+				}).
+				Value(&m.Dir),
+
+			huh.NewInput().
+				Key(initFormKeyName).
+				Title("Project Name").
+				Prompt("🏷️ ").
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("project name cannot be empty")
+					}
+					return nil
+				}).
+				Value(&m.Name),
+
+			huh.NewInput().
+				Key(initFormKeyDescription).
+				Title("Project Description (Optional)").
+				Prompt("📝 ").
+				Value(&m.Description),
+		),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key(initFormKeyAliases).
+				Title("Add Shell Aliases").
+				Description("Add shell aliases for the task-master command?").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&m.AddAliases),
+
+			huh.NewConfirm().
+				Key(initFormKeySkipInstall).
+				Title("Skip Install").
+				Description("Skip installing the project's dependencies?").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&m.SkipInstall),
+		),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
+	).WithTheme(huh.ThemeDracula())
+
+	return m
+}
+
+func (m *InitModel) Init() tea.Cmd {
+	m.isProcessing = false
+	m.statusMsg = ""
+	m.aborted = false
+	return m.form.Init()
+}
+
+func (m *InitModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case initCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			m.statusMsg = "✅ Success!"
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
+	if m.isProcessing {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, nil
+	}
+
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.statusMsg = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
+	var cmds []tea.Cmd
+	formModel, cmd := m.form.Update(msg)
+	if updatedForm, ok := formModel.(*huh.Form); ok {
+		m.form = updatedForm
+	} else {
+		m.statusMsg = "Error: Form update returned unexpected type."
+		debugLog.Error("form update did not return *huh.Form", "file", "init_form.go", "type", fmt.Sprintf("%T", formModel))
+		return m, tea.Quit
+	}
+	cmds = append(cmds, cmd)
+
+	if m.form.State == huh.StateCompleted {
+		m.statusMsg = "Executing init command..."
+		m.isProcessing = true
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeInitCommand))
+	}
+
+	if m.form.State == huh.StateAborted {
+		m.aborted = true
+		return m, func() tea.Msg { return backToMenuMsg{} }
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if !m.isProcessing {
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			}
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *InitModel) View() string {
+	if m.aborted {
+		return "Form aborted. Returning to main menu..."
+	}
+
+	var viewBuilder strings.Builder
+	viewBuilder.WriteString(m.form.View())
+
+	if m.statusMsg != "" {
+		viewBuilder.WriteString("\n\n")
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
+	}
+
+	helpStyle := lipgloss.NewStyle().Faint(true)
+	if m.isProcessing {
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
+	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
+	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Padding(1, 2).
+		Render(viewBuilder.String())
+}
+
+func (m *InitModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
+// GetFormValues retrieves the structured data after completion.
+func (m *InitModel) GetFormValues() (map[string]interface{}, error) {
+	if m.form.State != huh.StateCompleted {
+		return nil, fmt.Errorf("form is not yet completed")
+	}
+	return map[string]interface{}{
+		initFormKeyDir:         m.Dir,
+		initFormKeyName:        m.Name,
+		initFormKeyDescription: m.Description,
+		initFormKeyAliases:     m.AddAliases,
+		initFormKeySkipInstall: m.SkipInstall,
+		advancedFlagsFormKey:   m.AdvancedFlags,
+	}, nil
+}
+
+// initCompleteMsg is sent when the command execution is complete
+type initCompleteMsg struct {
+	result CLIResult
+}
+
+// executeInitCommand executes the actual init CLI command
+func (m *InitModel) executeInitCommand() tea.Cmd {
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+
+		result := executor.InitProject(m.Dir, strings.TrimSpace(m.Name), m.Description, m.AddAliases, m.SkipInstall)
+		return initCompleteMsg{result: result}
+	})
+}
+
+var _ tea.Model = &InitModel{}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// keyContext is what the current screen is doing, which decides the keys that work.
+type keyContext int
+
+const (
+	contextMenu       keyContext = iota // Choosing a command from the main menu
+	contextForm                         // Filling in a form
+	contextProcessing                   // Waiting for a command
+	contextResult                       // Showing a command's result
+	contextSearch                       // Typing a search of a command's result
+)
+
+// String names the context, for the debug log.
+func (c keyContext) String() string {
+	switch c {
+	case contextMenu:
+		return "menu"
+	case contextProcessing:
+		return "processing"
+	case contextResult:
+		return "result"
+	case contextSearch:
+		return "search"
+	default:
+		return "form"
+	}
+}
+
+// keyMap lists the key bindings shared by the screens, for the help overlay.
+type keyMap struct {
+	Help        key.Binding
+	Navigate    key.Binding
+	Select      key.Binding
+	NextField   key.Binding
+	PrevField   key.Binding
+	Back        key.Binding
+	Cancel      key.Binding
+	Scroll      key.Binding
+	Copy        key.Binding
+	Full        key.Binding
+	Export      key.Binding
+	Rerun       key.Binding
+	Search      key.Binding
+	NextMatch   key.Binding
+	KeepSearch  key.Binding
+	ClearSearch key.Binding
+	Quit        key.Binding
+	ForceQuit   key.Binding
+}
+
+var keys = keyMap{
+	// "?" is text in a form's inputs, so there only F1 opens the help
+	Help:        key.NewBinding(key.WithKeys("?", "f1"), key.WithHelp("?/F1", "toggle help")),
+	Navigate:    key.NewBinding(key.WithKeys("up", "down"), key.WithHelp("↑/↓", "move")),
+	Select:      key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select")),
+	NextField:   key.NewBinding(key.WithKeys("enter", "tab"), key.WithHelp("enter/tab", "next field")),
+	PrevField:   key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "previous field")),
+	Back:        key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back to menu")),
+	Cancel:      key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel command")),
+	Scroll:      key.NewBinding(key.WithKeys("up", "down", "pgup", "pgdown"), key.WithHelp("↑/↓/pgup/pgdn", "scroll")),
+	Copy:        key.NewBinding(key.WithKeys(copyKey), key.WithHelp(copyKey, "copy output")),
+	Full:        key.NewBinding(key.WithKeys(fullOutputKey), key.WithHelp(fullOutputKey, "toggle full output")),
+	Export:      key.NewBinding(key.WithKeys(exportKey), key.WithHelp(exportKey, "save result as JSON")),
+	Search:      key.NewBinding(key.WithKeys(searchKey), key.WithHelp(searchKey, "search output")),
+	NextMatch:   key.NewBinding(key.WithKeys(nextMatchKey, prevMatchKey), key.WithHelp(nextMatchKey+"/"+prevMatchKey, "next/previous match")),
+	KeepSearch:  key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "keep the search")),
+	ClearSearch: key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "clear the search")),
+	Rerun:       key.NewBinding(key.WithKeys(rerunKey), key.WithHelp(rerunKey, "rerun a failed command")),
+	Quit:        key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit")),
+	ForceQuit:   key.NewBinding(key.WithKeys("ctrl+c", "q"), key.WithHelp("ctrl+c/q", "quit")),
+}
+
+// formKeyContext returns the context of a form screen from its form and whether its command is running.
+func formKeyContext(form *huh.Form, processing bool) keyContext {
+	switch {
+	case processing:
+		return contextProcessing
+	case form.State == huh.StateCompleted:
+		return contextResult
+	default:
+		return contextForm
+	}
+}
+
+// opensHelp reports whether msg opens the help overlay in ctx.
+func opensHelp(msg tea.KeyMsg, ctx keyContext) bool {
+	return key.Matches(msg, keys.Help) && (msg.String() != "?" || (ctx != contextForm && ctx != contextSearch))
+}
+
+// helpBindings returns the keys that work in ctx, in columns.
+func helpBindings(ctx keyContext) [][]key.Binding {
+	help := keys.Help
+	if ctx == contextForm || ctx == contextSearch {
+		help.SetHelp("F1", "toggle help")
+	}
+	switch ctx {
+	case contextMenu:
+		return [][]key.Binding{{keys.Navigate, keys.Select}, {help, keys.Quit}}
+	case contextProcessing:
+		return [][]key.Binding{{keys.Cancel}, {help, keys.ForceQuit}}
+	case contextSearch:
+		return [][]key.Binding{{keys.KeepSearch, keys.ClearSearch}, {help, keys.Quit}}
+	case contextResult:
+		return [][]key.Binding{{keys.Scroll, keys.Search, keys.NextMatch, keys.Copy, keys.Full, keys.Export, keys.Rerun}, {keys.Back, help, keys.ForceQuit}}
+	default:
+		return [][]key.Binding{{keys.NextField, keys.PrevField}, {keys.Back, help, keys.Quit}}
+	}
+}
+
+// helpOverlayStyle frames the help overlay
+var helpOverlayStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("205")).
+	Padding(1, 2).
+	Margin(1, 2)
+
+// renderHelpOverlay lists the keys that work in ctx.
+func renderHelpOverlay(ctx keyContext, width int) string {
+	h := help.New()
+	h.Width = max(width-10, 0) // Inside the border, padding and margin
+	title := lipgloss.NewStyle().Bold(true).Render("Keys")
+	body := h.FullHelpView(helpBindings(ctx))
+	return helpOverlayStyle.Render(title + "\n\n" + body + "\n\n" + lipgloss.NewStyle().Faint(true).Render("Press any key to close."))
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestOpensHelp(t *testing.T) {
+	question := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")}
+	f1 := tea.KeyMsg{Type: tea.KeyF1}
+
+	tests := []struct {
+		name string
+		msg  tea.KeyMsg
+		ctx  keyContext
+		want bool
+	}{
+		{name: "? on the menu", msg: question, ctx: contextMenu, want: true},
+		{name: "? while processing", msg: question, ctx: contextProcessing, want: true},
+		{name: "? on a result", msg: question, ctx: contextResult, want: true},
+		{name: "? is typed into a form", msg: question, ctx: contextForm, want: false},
+		{name: "F1 in a form", msg: f1, ctx: contextForm, want: true},
+		{name: "other key", msg: tea.KeyMsg{Type: tea.KeyEsc}, ctx: contextResult, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := opensHelp(tt.msg, tt.ctx); got != tt.want {
+				t.Errorf("opensHelp(%q) = %v, want %v", tt.msg.String(), got, tt.want)
+			}
+		})
+	}
+}
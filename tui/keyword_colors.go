@@ -0,0 +1,70 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// keywordColors maps task statuses and priorities to the color they are shown
+// in, in the task table and wherever they appear in a command's output. The
+// settings' KeywordColors override it.
+var keywordColors = map[string]lipgloss.Color{
+	"done":        lipgloss.Color("42"),
+	"in-progress": lipgloss.Color("214"),
+	"review":      lipgloss.Color("141"),
+	"pending":     lipgloss.Color("245"),
+	"todo":        lipgloss.Color("245"),
+	"deferred":    lipgloss.Color("39"),
+	"cancelled":   lipgloss.Color("196"),
+	"high":        lipgloss.Color("203"),
+	"medium":      lipgloss.Color("221"),
+	"low":         lipgloss.Color("110"),
+}
+
+// keywordPattern matches any of the keywords as a whole word, in any case.
+var keywordPattern = regexp.MustCompile(`(?i)\b(in-progress|done|review|pending|todo|deferred|cancelled|high|medium|low)\b`)
+
+// keywordColorMap returns keywordColors with the settings' overrides applied.
+// An override with an empty color leaves that keyword uncolored.
+func keywordColorMap() map[string]lipgloss.Color {
+	s, err := LoadSettings()
+	if err != nil || len(s.KeywordColors) == 0 {
+		return keywordColors
+	}
+	colors := make(map[string]lipgloss.Color, len(keywordColors))
+	for keyword, color := range keywordColors {
+		colors[keyword] = color
+	}
+	for keyword, color := range s.KeywordColors {
+		keyword = strings.ToLower(keyword)
+		if color == "" {
+			delete(colors, keyword)
+		} else {
+			colors[keyword] = lipgloss.Color(color)
+		}
+	}
+	return colors
+}
+
+// colorKeywords colors the status and priority keywords in plain-text output.
+// Lines the CLI has already styled are left alone, so their colors aren't cut
+// short by the keywords' resets.
+func colorKeywords(text string) string {
+	colors := keywordColorMap()
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.Contains(line, "\x1b[") {
+			continue
+		}
+		lines[i] = keywordPattern.ReplaceAllStringFunc(line, func(word string) string {
+			color, ok := colors[strings.ToLower(word)]
+			if !ok {
+				return word
+			}
+			return lipgloss.NewStyle().Foreground(color).Render(word)
+		})
+	}
+	return strings.Join(lines, "\n")
+}
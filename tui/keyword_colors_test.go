@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestKeywordColorMap(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if got := keywordColorMap()["done"]; got != keywordColors["done"] {
+		t.Errorf("default done color = %q, want %q", got, keywordColors["done"])
+	}
+
+	if err := SaveSettings(Settings{KeywordColors: map[string]string{"Done": "#00ff00", "low": ""}}); err != nil {
+		t.Fatal(err)
+	}
+	colors := keywordColorMap()
+	if got := colors["done"]; got != lipgloss.Color("#00ff00") {
+		t.Errorf("overridden done color = %q, want #00ff00", got)
+	}
+	if _, ok := colors["low"]; ok {
+		t.Error("an empty override should leave low uncolored")
+	}
+	if got := keywordColors["done"]; got != lipgloss.Color("42") {
+		t.Errorf("overrides changed the defaults: done = %q", got)
+	}
+}
+
+func TestKeywordPattern(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{line: "Status: in-progress  Priority: HIGH", want: []string{"in-progress", "HIGH"}},
+		{line: "Task 3 is done; 2 pending", want: []string{"done", "pending"}},
+		{line: "abandoned lowercase reviewer", want: nil}, // Only whole words
+	}
+	for _, tt := range tests {
+		got := keywordPattern.FindAllString(tt.line, -1)
+		if len(got) != len(tt.want) {
+			t.Errorf("keywords in %q = %q, want %q", tt.line, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("keywords in %q = %q, want %q", tt.line, got, tt.want)
+			}
+		}
+	}
+}
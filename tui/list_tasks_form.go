@@ -2,9 +2,9 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
@@ -13,6 +13,7 @@ import (
 const (
 	listTasksFormKeyFile         = "file"
 	listTasksFormKeyStatusFilter = "status-filter"
+	listTasksFormKeyPriorities   = "priorities"
 	listTasksFormKeyWithSubtasks = "with-subtasks"
 )
 
@@ -34,48 +35,58 @@ type ListTasksModel struct {
 	isProcessing bool
 	statusMsg    string
 	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
+	table        *taskTable          // Tasks read from the file, shown instead of the raw output when available
 
 	// Form values
 	FilePath      string
-	StatusFilter  FilterStatus
+	StatusFilters []FilterStatus // Empty shows every status
+	Priorities    []TaskPriority // Empty shows every priority
 	WithSubtasks  bool
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
 }
 
 // NewListTasksForm creates a new form for the list tasks command.
 func NewListTasksForm() *ListTasksModel {
 	m := &ListTasksModel{
-		StatusFilter: FilterStatusNone, // Default to no filter
-		WithSubtasks: true,             // Default to showing subtasks
+		FilePath:     lastFilePath(), // Pre-populate with the last used tasks file
+		WithSubtasks: true,           // Default to showing subtasks
 	}
 
 	m.form = huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().
-				Key(listTasksFormKeyFile).
-				Title("Tasks File Path").
-				Description("Path to the tasks file (e.g., tasks.md).").
-				Prompt("📄 ").
-				Validate(func(s string) error {
-					if s == "" {
-						return fmt.Errorf("tasks file path cannot be empty")
-					}
-					return nil
-				}).
-				Value(&m.FilePath),
+			newFilePathField(listTasksFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json).", &m.FilePath, tasksFileTypes),
 		),
+		newTasksFileCheckGroup(&m.FilePath),
 		huh.NewGroup(
-			huh.NewSelect[FilterStatus]().
+			huh.NewMultiSelect[FilterStatus]().
 				Key(listTasksFormKeyStatusFilter).
 				Title("Filter by Status").
-				Description("Select a status to filter tasks by, or 'None'.").
+				Description("Select the statuses to show (space to toggle), or none for all.").
 				Options(
-					huh.NewOption("None (Show All)", FilterStatusNone),
 					huh.NewOption("To Do", FilterStatusTodo),
 					huh.NewOption("In Progress", FilterStatusInProgress),
 					huh.NewOption("Review", FilterStatusReview),
 					huh.NewOption("Done", FilterStatusDone),
 				).
-				Value(&m.StatusFilter),
+				Value(&m.StatusFilters),
+
+			huh.NewMultiSelect[TaskPriority]().
+				Key(listTasksFormKeyPriorities).
+				Title("Filter by Priority").
+				Description("Select the priorities to show (space to toggle), or none for all.").
+				Options(
+					huh.NewOption("High", PriorityHigh),
+					huh.NewOption("Medium", PriorityMedium),
+					huh.NewOption("Low", PriorityLow),
+				).
+				Value(&m.Priorities),
 
 			huh.NewConfirm().
 				Key(listTasksFormKeyWithSubtasks).
@@ -85,6 +96,7 @@ func NewListTasksForm() *ListTasksModel {
 				Negative("No").
 				Value(&m.WithSubtasks),
 		),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
 	).WithTheme(huh.ThemeDracula())
 
 	return m
@@ -98,9 +110,49 @@ func (m *ListTasksModel) Init() tea.Cmd {
 }
 
 func (m *ListTasksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		parts := []resizer{&m.result, &m.output}
+		if m.table != nil {
+			parts = append(parts, m.table)
+		}
+		handleResize(msg, &m.width, &m.height, parts...)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case listTasksCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			m.statusMsg = "✅ Success!"
+			m.result.setContent(msg.result.Output)
+			if msg.tasks != nil {
+				table := newTaskTable(msg.tasks, m.StatusFilters, m.Priorities, m.WithSubtasks)
+				table.resize(m.width, m.height)
+				m.table = &table
+			}
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
 	if m.isProcessing {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
 			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
 			case "ctrl+c", "q":
 				return m, tea.Quit
 			}
@@ -108,13 +160,50 @@ func (m *ListTasksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.table != nil && m.table.searching() {
+				m.table.update(keyMsg)
+				return m, nil
+			}
+			if m.table == nil && m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.statusMsg = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+			if m.table != nil {
+				if keyMsg.String() == copyKey {
+					return m, m.result.update(msg) // Copies the CLI's text output
+				}
+				if taskID, ok := m.table.update(keyMsg); ok {
+					return m, func() tea.Msg { return showTaskMsg{filePath: m.FilePath, taskID: taskID} }
+				}
+				return m, nil
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
 	var cmds []tea.Cmd
 	formModel, cmd := m.form.Update(msg)
 	if updatedForm, ok := formModel.(*huh.Form); ok {
 		m.form = updatedForm
 	} else {
 		m.statusMsg = "Error: Form update returned unexpected type."
-		fmt.Fprintf(os.Stderr, "Critical Error: list_tasks_form.go - form update did not return *huh.Form. Got: %T\n", formModel)
+		debugLog.Error("form update did not return *huh.Form", "file", "list_tasks_form.go", "type", fmt.Sprintf("%T", formModel))
 		return m, tea.Quit
 	}
 	cmds = append(cmds, cmd)
@@ -122,7 +211,7 @@ func (m *ListTasksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.form.State == huh.StateCompleted {
 		m.statusMsg = "Executing list-tasks command..."
 		m.isProcessing = true
-		return m, m.executeListTasksCommand()
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeListTasksCommand))
 	}
 
 	if m.form.State == huh.StateAborted {
@@ -131,14 +220,6 @@ func (m *ListTasksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
-	case listTasksCompleteMsg:
-		m.isProcessing = false
-		if msg.result.Success {
-			m.statusMsg = fmt.Sprintf("✅ Success!\n\n%s", msg.result.Output)
-		} else {
-			m.statusMsg = fmt.Sprintf("❌ Error: %s\n\n%s", msg.result.Error, msg.result.Output)
-		}
-		return m, nil
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
@@ -147,8 +228,6 @@ func (m *ListTasksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, func() tea.Msg { return backToMenuMsg{} }
 			}
 		}
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
 	}
 
 	return m, tea.Batch(cmds...)
@@ -164,18 +243,33 @@ func (m *ListTasksModel) View() string {
 
 	if m.statusMsg != "" {
 		viewBuilder.WriteString("\n\n")
-		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-		if strings.HasPrefix(m.statusMsg, "Error:") {
-			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
-		}
-		viewBuilder.WriteString(statusStyle.Render(m.statusMsg))
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
 	}
 
 	helpStyle := lipgloss.NewStyle().Faint(true)
 	if m.isProcessing {
-		viewBuilder.WriteString(helpStyle.Render("\n\nProcessing... Press Ctrl+C to force quit."))
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
+	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") && m.table != nil {
+		viewBuilder.WriteString("\n\n" + m.table.View())
+		if m.table.empty() {
+			viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.withCopyHelp("Command completed! Press Esc to return to main menu.")))
+		} else {
+			help := m.result.withCopyHelp("Use ↑/↓ to select a task, Enter to show it, / to search, Esc to return to main menu.")
+			if status := m.table.search.status(); status != "" {
+				help = status + "\n" + help
+			}
+			viewBuilder.WriteString(helpStyle.Render("\n\n" + help))
+		}
 	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
-		viewBuilder.WriteString(helpStyle.Render("\n\nCommand completed! Press Esc to return to main menu."))
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
 	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
 		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
 	}
@@ -186,6 +280,13 @@ func (m *ListTasksModel) View() string {
 		Render(viewBuilder.String())
 }
 
+func (m *ListTasksModel) keyContext() keyContext {
+	if m.table != nil && m.table.searching() {
+		return contextSearch
+	}
+	return m.result.keyContext(formKeyContext(m.form, m.isProcessing))
+}
+
 // GetFormValues retrieves the structured data after completion.
 func (m *ListTasksModel) GetFormValues() (map[string]interface{}, error) {
 	if m.form.State != huh.StateCompleted {
@@ -193,29 +294,50 @@ func (m *ListTasksModel) GetFormValues() (map[string]interface{}, error) {
 	}
 	return map[string]interface{}{
 		listTasksFormKeyFile:         m.FilePath,
-		listTasksFormKeyStatusFilter: m.StatusFilter,
+		listTasksFormKeyStatusFilter: m.StatusFilters,
+		listTasksFormKeyPriorities:   m.Priorities,
 		listTasksFormKeyWithSubtasks: m.WithSubtasks,
+		advancedFlagsFormKey:         m.AdvancedFlags,
 	}, nil
 }
 
 // listTasksCompleteMsg is sent when the command execution is complete
 type listTasksCompleteMsg struct {
 	result CLIResult
+	tasks  *TasksFile // Parsed tasks file for the table, nil if it couldn't be read
 }
 
 // executeListTasksCommand executes the actual list-tasks CLI command
 func (m *ListTasksModel) executeListTasksCommand() tea.Cmd {
-	return func() tea.Msg {
-		// Convert FilterStatus to string for CLI
-		var statusFilter string
-		if m.StatusFilter != FilterStatusNone {
-			statusFilter = string(m.StatusFilter)
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+
+		// Convert the filters to strings for the CLI
+		var statuses []string
+		for _, status := range m.StatusFilters {
+			if status != FilterStatusNone {
+				statuses = append(statuses, string(status))
+			}
 		}
-		
-		// CLI doesn't support priority filter in this form, so pass empty string
-		result := cliExecutor.ListTasks(m.FilePath, statusFilter, "", m.WithSubtasks)
-		return listTasksCompleteMsg{result: result}
-	}
+		priorities := make([]string, len(m.Priorities))
+		for i, priority := range m.Priorities {
+			priorities[i] = string(priority)
+		}
+
+		result := executor.ListTasks(m.FilePath, statuses, priorities, m.WithSubtasks)
+		msg := listTasksCompleteMsg{result: result}
+		if result.Success {
+			// The CLI only prints text, so build the table from the file itself;
+			// formats other than JSON fall back to the raw output
+			if tf, err := LoadTasksFile(m.FilePath); err == nil {
+				msg.tasks = tf
+			}
+		}
+		return msg
+	})
 }
 
 // Ensure ListTasksModel implements tea.Model.
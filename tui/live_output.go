@@ -0,0 +1,107 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// liveOutputLines is how many of the most recent output lines are shown while a command runs.
+const liveOutputLines = 10
+
+// outputLineMsg carries one line of output from a running command.
+type outputLineMsg struct {
+	stream *outputStream
+	line   string
+}
+
+// outputStream relays a running command's output to the bubbletea loop line by line.
+type outputStream struct {
+	lines chan string
+}
+
+func newOutputStream() *outputStream {
+	return &outputStream{lines: make(chan string, 256)}
+}
+
+// send forwards a line without blocking. Lines are dropped if the UI falls
+// behind; the complete output still arrives with the completion message.
+func (s *outputStream) send(line string) {
+	select {
+	case s.lines <- line:
+	default:
+	}
+}
+
+// close signals that the command has finished writing output.
+func (s *outputStream) close() {
+	close(s.lines)
+}
+
+// wait returns a command that delivers the next line, or nothing once the stream is closed.
+func (s *outputStream) wait() tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-s.lines
+		if !ok {
+			return nil
+		}
+		return outputLineMsg{stream: s, line: line}
+	}
+}
+
+// liveOutput collects the output of the command a form is running so the
+// view can show progress before the command completes.
+type liveOutput struct {
+	stream *outputStream
+	lines  []string
+	width  int
+	height int
+}
+
+// start begins a new stream, discarding output from any previous run.
+func (o *liveOutput) start() *outputStream {
+	o.stream = newOutputStream()
+	o.lines = nil
+	return o.stream
+}
+
+// handle records a streamed line and waits for the next one.
+func (o *liveOutput) handle(msg outputLineMsg) tea.Cmd {
+	if msg.stream == o.stream {
+		o.lines = append(o.lines, msg.line)
+	}
+	return msg.stream.wait()
+}
+
+// resize fits the output to the terminal, once its size is known.
+func (o *liveOutput) resize(width, height int) {
+	o.width, o.height = width, height
+}
+
+// View renders the most recent output lines, fewer on a short terminal and
+// each cut to the terminal's width so a long line doesn't wrap and push the
+// rest of the form off screen.
+func (o *liveOutput) View() string {
+	if len(o.lines) == 0 {
+		return ""
+	}
+	shown := liveOutputLines
+	if o.height > 0 {
+		shown = max(min(shown, o.height-resultViewChrome), 1)
+	}
+	lines := o.lines
+	if len(lines) > shown {
+		lines = lines[len(lines)-shown:]
+	}
+	if o.width > 0 {
+		// Subtract the horizontal padding forms render with
+		cut := make([]string, len(lines))
+		for i, line := range lines {
+			cut[i] = ansi.Truncate(line, max(o.width-4, 1), "…")
+		}
+		lines = cut
+	}
+	return lipgloss.NewStyle().Faint(true).Render(strings.Join(lines, "\n"))
+}
@@ -6,6 +6,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
 )
 
 type view int
@@ -26,33 +27,83 @@ const (
 	nextTaskView
 	showTaskView
 	addDependencyView // New view for Add Dependency form
+	modelsView
+	initView
+	validateDependenciesView
+	fixDependenciesView
+	historyView
+	undoView
+	batchView
+	removeSubtaskView
+	tagsView
+	dependencyGraphView
+	settingsView
+	moveTaskView
+	recentFilesView
+	duplicateTaskView
+	statusMappingView
+	checkpointDoneView
+	taskSearchView
+	reopenTaskView
+	checkFixDependenciesView
+	setPriorityView
+	taskTimelineView
+	reorderTasksView
 	// Add other views as needed
 )
 
 // backToMenuMsg signals the main model to return to the main menu.
 type backToMenuMsg struct{}
 
+// model is the program's root model. It shows the main menu, opens the form for
+// the selected command and returns to the menu when a form sends backToMenuMsg.
+// Only the form for the current view is kept; the others are nil.
 type model struct {
-	currentView            view
-	mainMenuForm           *huh.Form
-	parsePRDModel          tea.Model
-	updateTaskModel        tea.Model
-	updateSingleTaskModel  tea.Model
-	updateSubtaskModel     tea.Model
-	generateFilesModel     tea.Model
-	setStatusModel         tea.Model
-	listTasksModel         tea.Model
-	expandTaskModel        tea.Model
-	analyzeComplexityModel tea.Model
-	clearSubtasksModel     tea.Model
-	addTaskModel           tea.Model
-	nextTaskModel          tea.Model
-	showTaskModel          tea.Model
-	addDependencyModel     tea.Model // Instance of AddDependencyModel
-	width, height          int
+	currentView               view
+	mainMenuForm              *huh.Form
+	parsePRDModel             tea.Model
+	updateTaskModel           tea.Model
+	updateSingleTaskModel     tea.Model
+	updateSubtaskModel        tea.Model
+	generateFilesModel        tea.Model
+	setStatusModel            tea.Model
+	listTasksModel            tea.Model
+	expandTaskModel           tea.Model
+	analyzeComplexityModel    tea.Model
+	clearSubtasksModel        tea.Model
+	addTaskModel              tea.Model
+	nextTaskModel             tea.Model
+	showTaskModel             tea.Model
+	addDependencyModel        tea.Model // Instance of AddDependencyModel
+	modelsModel               tea.Model
+	initModel                 tea.Model
+	validateDependenciesModel tea.Model
+	fixDependenciesModel      tea.Model
+	historyModel              tea.Model
+	undoModel                 tea.Model
+	batchModel                tea.Model
+	removeSubtaskModel        tea.Model
+	tagsModel                 tea.Model
+	dependencyGraphModel      tea.Model
+	settingsModel             tea.Model
+	moveTaskModel             tea.Model
+	recentFilesModel          tea.Model
+	duplicateTaskModel        tea.Model
+	statusMappingModel        tea.Model
+	checkpointDoneModel       tea.Model
+	taskSearchModel           tea.Model
+	reopenTaskModel           tea.Model
+	checkFixDependenciesModel tea.Model
+	setPriorityModel          tea.Model
+	taskTimelineModel         tea.Model
+	reorderTasksModel         tea.Model
+	width, height             int
+	showHelp                  bool   // The help overlay is open
+	activeTag                 string // Shown in the menu header, reloaded on returning to the menu
+	taskSummary               string // Of the last tasks file, shown in the menu header and reloaded with activeTag
 }
 
-// newModel initializes the main application model.
+// newModel initializes the main application model, starting at the main menu.
 func newModel() model {
 	mainMenuSelect := huh.NewSelect[string]().
 		Key("command").
@@ -72,6 +123,29 @@ func newModel() model {
 			huh.NewOption("List Tasks", "listTasks"),
 			huh.NewOption("Expand Task", "expandTask"),
 			huh.NewOption("Analyze Task Complexity", "analyzeComplexity"),
+			huh.NewOption("Configure Models", "models"),
+			huh.NewOption("Initialize Project", "init"),
+			huh.NewOption("Validate Dependencies", "validateDependencies"),
+			huh.NewOption("Fix Dependencies", "fixDependencies"),
+			huh.NewOption("Check & Fix Dependencies", "checkFixDependencies"),
+			huh.NewOption("Command History", "history"),
+			huh.NewOption("Undo Last Change", "undo"),
+			huh.NewOption("Run Batch File", "batch"),
+			huh.NewOption("Remove Subtask", "removeSubtask"),
+			huh.NewOption("Switch Tag", "tags"),
+			huh.NewOption("Dependency Graph", "dependencyGraph"),
+			huh.NewOption("Settings", "settings"),
+			huh.NewOption("Move Task or Subtask", "moveTask"),
+			huh.NewOption("Recent Files", "recentFiles"),
+			huh.NewOption("Duplicate Task", "duplicateTask"),
+			huh.NewOption("Set Statuses From a Mapping", "statusMapping"),
+			huh.NewOption("Mark Checkpoint Done", "checkpointDone"),
+			huh.NewOption("Search Tasks", "searchTasks"),
+			huh.NewOption("Reopen Task", "reopenTask"),
+			huh.NewOption("Set Priority", "setPriority"),
+			huh.NewOption("Task Timeline", "taskTimeline"),
+			huh.NewOption("Reorder Tasks", "reorderTasks"),
+			huh.NewOption("Quit", "quit"),
 		).
 		Value(new(string))
 
@@ -79,9 +153,12 @@ func newModel() model {
 		huh.NewGroup(mainMenuSelect),
 	).WithTheme(huh.ThemeDracula())
 
+	tag := activeTag()
 	return model{
 		mainMenuForm: mainMenuForm,
 		currentView:  mainMenuView,
+		activeTag:    tag,
+		taskSummary:  menuSummary(lastFilePath(), tag),
 	}
 }
 
@@ -117,60 +194,132 @@ func (m model) Init() tea.Cmd {
 		if m.showTaskModel != nil { return m.showTaskModel.Init() }
 	case addDependencyView:
 		if m.addDependencyModel != nil { return m.addDependencyModel.Init() }
+	case modelsView:
+		if m.modelsModel != nil { return m.modelsModel.Init() }
+	case initView:
+		if m.initModel != nil { return m.initModel.Init() }
+	case validateDependenciesView:
+		if m.validateDependenciesModel != nil { return m.validateDependenciesModel.Init() }
+	case fixDependenciesView:
+		if m.fixDependenciesModel != nil { return m.fixDependenciesModel.Init() }
+	case historyView:
+		if m.historyModel != nil { return m.historyModel.Init() }
+	case undoView:
+		if m.undoModel != nil { return m.undoModel.Init() }
+	case batchView:
+		if m.batchModel != nil { return m.batchModel.Init() }
+	case removeSubtaskView:
+		if m.removeSubtaskModel != nil { return m.removeSubtaskModel.Init() }
+	case tagsView:
+		if m.tagsModel != nil { return m.tagsModel.Init() }
+	case dependencyGraphView:
+		if m.dependencyGraphModel != nil { return m.dependencyGraphModel.Init() }
+	case settingsView:
+		if m.settingsModel != nil { return m.settingsModel.Init() }
+	case moveTaskView:
+		if m.moveTaskModel != nil { return m.moveTaskModel.Init() }
+	case recentFilesView:
+		if m.recentFilesModel != nil { return m.recentFilesModel.Init() }
+	case duplicateTaskView:
+		if m.duplicateTaskModel != nil { return m.duplicateTaskModel.Init() }
+	case statusMappingView:
+		if m.statusMappingModel != nil { return m.statusMappingModel.Init() }
+	case checkpointDoneView:
+		if m.checkpointDoneModel != nil { return m.checkpointDoneModel.Init() }
+	case taskSearchView:
+		if m.taskSearchModel != nil { return m.taskSearchModel.Init() }
+	case reopenTaskView:
+		if m.reopenTaskModel != nil { return m.reopenTaskModel.Init() }
+	case checkFixDependenciesView:
+		if m.checkFixDependenciesModel != nil { return m.checkFixDependenciesModel.Init() }
+	case setPriorityView:
+		if m.setPriorityModel != nil { return m.setPriorityModel.Init() }
+	case taskTimelineView:
+		if m.taskTimelineModel != nil { return m.taskTimelineModel.Init() }
+	case reorderTasksView:
+		if m.reorderTasksModel != nil { return m.reorderTasksModel.Init() }
 	}
 	return nil
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	before, fromView := m.keyContext(), m.currentView
+	next, cmd := m.update(msg)
+	if nm, ok := next.(model); ok {
+		if after := nm.keyContext(); after != before || nm.currentView != fromView {
+			debugLog.Debug("screen changed", "from_view", fromView, "to_view", nm.currentView, "from", before, "to", after)
+		}
+	}
+	return next, cmd
+}
+
+// update handles msg for Update, which logs the screen changes it makes.
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	// Handle specific messages first
 	switch msg := msg.(type) {
 	case backToMenuMsg:
 		m.currentView = mainMenuView
+		m.activeTag = activeTag()
+		m.taskSummary = menuSummary(lastFilePath(), m.activeTag)
 		m.parsePRDModel = nil; m.updateTaskModel = nil; m.updateSingleTaskModel = nil
 		m.updateSubtaskModel = nil; m.generateFilesModel = nil; m.setStatusModel = nil
 		m.listTasksModel = nil; m.expandTaskModel = nil; m.analyzeComplexityModel = nil
 		m.clearSubtasksModel = nil; m.addTaskModel = nil; m.nextTaskModel = nil
 		m.showTaskModel = nil; m.addDependencyModel = nil // Clear this model too
+		m.modelsModel = nil
+		m.initModel = nil
+		m.validateDependenciesModel = nil
+		m.fixDependenciesModel = nil
+		m.historyModel = nil
+		m.undoModel = nil
+		m.batchModel = nil
+		m.removeSubtaskModel = nil
+		m.tagsModel = nil
+		m.dependencyGraphModel = nil
+		m.settingsModel = nil
+		m.moveTaskModel = nil
+		m.recentFilesModel = nil
+		m.duplicateTaskModel = nil
+		m.statusMappingModel = nil
+		m.checkpointDoneModel = nil
+		m.taskSearchModel = nil
+		m.reopenTaskModel = nil
+		m.checkFixDependenciesModel = nil
+		m.setPriorityModel = nil
+		m.taskTimelineModel = nil
+		m.reorderTasksModel = nil
 		if m.mainMenuForm != nil {
 			m.mainMenuForm.State = huh.StateNormal
 			return m, m.mainMenuForm.Init()
 		}
 		return m, nil
+	case showTaskMsg:
+		m.listTasksModel = nil
+		m.currentView = showTaskView
+		m.showTaskModel = NewShowTaskFormFor(msg.filePath, msg.taskID)
+		return m, tea.Batch(m.showTaskModel.Init(), m.windowSize())
+	case addDependencyMsg:
+		m.addTaskModel = nil
+		m.currentView = addDependencyView
+		m.addDependencyModel = NewAddDependencyFormFor(msg.filePath, msg.taskID)
+		return m, tea.Batch(m.addDependencyModel.Init(), m.windowSize())
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		// Propagate width to current sub-model
-		switch m.currentView {
-		case parsePRDView:
-			if prdModel, ok := m.parsePRDModel.(*ParsePRDModel); ok { prdModel.width = m.width }
-		case updateTaskView:
-			if utModel, ok := m.updateTaskModel.(*UpdateTaskModel); ok { utModel.width = m.width }
-		case updateSingleTaskView:
-			if ustModel, ok := m.updateSingleTaskModel.(*UpdateSingleTaskModel); ok { ustModel.width = m.width }
-		case updateSubtaskView:
-			if usubModel, ok := m.updateSubtaskModel.(*UpdateSubtaskModel); ok { usubModel.width = m.width }
-		case generateFilesView:
-			if genModel, ok := m.generateFilesModel.(*GenerateFilesModel); ok { genModel.width = m.width }
-		case setStatusView:
-			if statusModel, ok := m.setStatusModel.(*SetStatusModel); ok { statusModel.width = m.width }
-		case listTasksView:
-			if ltModel, ok := m.listTasksModel.(*ListTasksModel); ok { ltModel.width = m.width }
-		case expandTaskView:
-			if etModel, ok := m.expandTaskModel.(*ExpandTaskModel); ok { etModel.width = m.width }
-		case analyzeComplexityView:
-			if acModel, ok := m.analyzeComplexityModel.(*AnalyzeComplexityModel); ok { acModel.width = m.width }
-		case clearSubtasksView:
-			if csModel, ok := m.clearSubtasksModel.(*ClearSubtasksModel); ok { csModel.width = m.width }
-		case addTaskView:
-			if atModel, ok := m.addTaskModel.(*AddTaskModel); ok { atModel.width = m.width }
-		case nextTaskView:
-			if ntModel, ok := m.nextTaskModel.(*NextTaskModel); ok { ntModel.width = m.width }
-		case showTaskView:
-			if stModel, ok := m.showTaskModel.(*ShowTaskModel); ok { stModel.width = m.width }
-		case addDependencyView:
-			if adModel, ok := m.addDependencyModel.(*AddDependencyModel); ok { adModel.width = m.width }
+		// The current view lays itself out to the new size when the message is delegated below
+	}
+
+	// The help overlay takes keys while it's open
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() != "ctrl+c" {
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		if opensHelp(keyMsg, m.keyContext()) {
+			m.showHelp = true
+			return m, nil
 		}
 	}
 
@@ -182,42 +331,89 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if updatedForm, ok := formModel.(*huh.Form); ok {
 			m.mainMenuForm = updatedForm
 		} else {
-			fmt.Fprintf(os.Stderr, "Main menu update returned unexpected model type: %T\n", formModel)
+			debugLog.Error("main menu update returned unexpected model type", "type", fmt.Sprintf("%T", formModel))
 			return m, tea.Quit
 		}
 		cmds = append(cmds, menuCmd)
 
 		if m.mainMenuForm.State == huh.StateCompleted {
 			selectedCommand := m.mainMenuForm.GetString("command")
+			debugLog.Info("menu selection", "command", selectedCommand)
 			switch selectedCommand {
 			case "parsePRD":
-				m.currentView = parsePRDView; m.parsePRDModel = NewParsePRDModel(); return m, m.parsePRDModel.Init()
+				m.currentView = parsePRDView; m.parsePRDModel = NewParsePRDModel(); return m, tea.Batch(m.parsePRDModel.Init(), m.windowSize())
 			case "addTask":
-				m.currentView = addTaskView; m.addTaskModel = NewAddTaskForm(); return m, m.addTaskModel.Init()
+				m.currentView = addTaskView; m.addTaskModel = NewAddTaskForm(); return m, tea.Batch(m.addTaskModel.Init(), m.windowSize())
 			case "nextTask":
-				m.currentView = nextTaskView; m.nextTaskModel = NewNextTaskForm(); return m, m.nextTaskModel.Init()
+				m.currentView = nextTaskView; m.nextTaskModel = NewNextTaskForm(); return m, tea.Batch(m.nextTaskModel.Init(), m.windowSize())
 			case "showTask":
-				m.currentView = showTaskView; m.showTaskModel = NewShowTaskForm(); return m, m.showTaskModel.Init()
+				m.currentView = showTaskView; m.showTaskModel = NewShowTaskForm(); return m, tea.Batch(m.showTaskModel.Init(), m.windowSize())
 			case "addDependency":
-				m.currentView = addDependencyView; m.addDependencyModel = NewAddDependencyForm(); return m, m.addDependencyModel.Init()
+				m.currentView = addDependencyView; m.addDependencyModel = NewAddDependencyForm(); return m, tea.Batch(m.addDependencyModel.Init(), m.windowSize())
 			case "updateTask":
-				m.currentView = updateTaskView; m.updateTaskModel = NewUpdateTaskForm(); return m, m.updateTaskModel.Init()
+				m.currentView = updateTaskView; m.updateTaskModel = NewUpdateTaskForm(); return m, tea.Batch(m.updateTaskModel.Init(), m.windowSize())
 			case "updateSingleTask":
-				m.currentView = updateSingleTaskView; m.updateSingleTaskModel = NewUpdateSingleTaskForm(); return m, m.updateSingleTaskModel.Init()
+				m.currentView = updateSingleTaskView; m.updateSingleTaskModel = NewUpdateSingleTaskForm(); return m, tea.Batch(m.updateSingleTaskModel.Init(), m.windowSize())
 			case "updateSubtask":
-				m.currentView = updateSubtaskView; m.updateSubtaskModel = NewUpdateSubtaskForm(); return m, m.updateSubtaskModel.Init()
+				m.currentView = updateSubtaskView; m.updateSubtaskModel = NewUpdateSubtaskForm(); return m, tea.Batch(m.updateSubtaskModel.Init(), m.windowSize())
 			case "clearSubtasks":
-				m.currentView = clearSubtasksView; m.clearSubtasksModel = NewClearSubtasksForm(); return m, m.clearSubtasksModel.Init()
+				m.currentView = clearSubtasksView; m.clearSubtasksModel = NewClearSubtasksForm(); return m, tea.Batch(m.clearSubtasksModel.Init(), m.windowSize())
 			case "generateFiles":
-				m.currentView = generateFilesView; m.generateFilesModel = NewGenerateFilesForm(); return m, m.generateFilesModel.Init()
+				m.currentView = generateFilesView; m.generateFilesModel = NewGenerateFilesForm(); return m, tea.Batch(m.generateFilesModel.Init(), m.windowSize())
 			case "setStatus":
-				m.currentView = setStatusView; m.setStatusModel = NewSetStatusForm(); return m, m.setStatusModel.Init()
+				m.currentView = setStatusView; m.setStatusModel = NewSetStatusForm(); return m, tea.Batch(m.setStatusModel.Init(), m.windowSize())
 			case "listTasks":
-				m.currentView = listTasksView; m.listTasksModel = NewListTasksForm(); return m, m.listTasksModel.Init()
+				m.currentView = listTasksView; m.listTasksModel = NewListTasksForm(); return m, tea.Batch(m.listTasksModel.Init(), m.windowSize())
 			case "expandTask":
-				m.currentView = expandTaskView; m.expandTaskModel = NewExpandTaskForm(); return m, m.expandTaskModel.Init()
+				m.currentView = expandTaskView; m.expandTaskModel = NewExpandTaskForm(); return m, tea.Batch(m.expandTaskModel.Init(), m.windowSize())
 			case "analyzeComplexity":
-				m.currentView = analyzeComplexityView; m.analyzeComplexityModel = NewAnalyzeComplexityForm(); return m, m.analyzeComplexityModel.Init()
+				m.currentView = analyzeComplexityView; m.analyzeComplexityModel = NewAnalyzeComplexityForm(); return m, tea.Batch(m.analyzeComplexityModel.Init(), m.windowSize())
+			case "models":
+				m.currentView = modelsView; m.modelsModel = NewModelsForm(); return m, tea.Batch(m.modelsModel.Init(), m.windowSize())
+			case "init":
+				m.currentView = initView; m.initModel = NewInitForm(); return m, tea.Batch(m.initModel.Init(), m.windowSize())
+			case "validateDependencies":
+				m.currentView = validateDependenciesView; m.validateDependenciesModel = NewValidateDependenciesForm(); return m, tea.Batch(m.validateDependenciesModel.Init(), m.windowSize())
+			case "fixDependencies":
+				m.currentView = fixDependenciesView; m.fixDependenciesModel = NewFixDependenciesForm(); return m, tea.Batch(m.fixDependenciesModel.Init(), m.windowSize())
+			case "history":
+				m.currentView = historyView; m.historyModel = NewHistoryForm(); return m, tea.Batch(m.historyModel.Init(), m.windowSize())
+			case "undo":
+				m.currentView = undoView; m.undoModel = NewUndoForm(); return m, tea.Batch(m.undoModel.Init(), m.windowSize())
+			case "batch":
+				m.currentView = batchView; m.batchModel = NewBatchForm(); return m, tea.Batch(m.batchModel.Init(), m.windowSize())
+			case "removeSubtask":
+				m.currentView = removeSubtaskView; m.removeSubtaskModel = NewRemoveSubtaskForm(); return m, tea.Batch(m.removeSubtaskModel.Init(), m.windowSize())
+			case "tags":
+				m.currentView = tagsView; m.tagsModel = NewTagsForm(); return m, tea.Batch(m.tagsModel.Init(), m.windowSize())
+			case "dependencyGraph":
+				m.currentView = dependencyGraphView; m.dependencyGraphModel = NewDependencyGraphForm(); return m, tea.Batch(m.dependencyGraphModel.Init(), m.windowSize())
+			case "settings":
+				m.currentView = settingsView; m.settingsModel = NewSettingsForm(); return m, tea.Batch(m.settingsModel.Init(), m.windowSize())
+			case "moveTask":
+				m.currentView = moveTaskView; m.moveTaskModel = NewMoveTaskForm(); return m, tea.Batch(m.moveTaskModel.Init(), m.windowSize())
+			case "recentFiles":
+				m.currentView = recentFilesView; m.recentFilesModel = NewRecentFilesForm(); return m, tea.Batch(m.recentFilesModel.Init(), m.windowSize())
+			case "duplicateTask":
+				m.currentView = duplicateTaskView; m.duplicateTaskModel = NewDuplicateTaskForm(); return m, tea.Batch(m.duplicateTaskModel.Init(), m.windowSize())
+			case "statusMapping":
+				m.currentView = statusMappingView; m.statusMappingModel = NewStatusMappingForm(); return m, tea.Batch(m.statusMappingModel.Init(), m.windowSize())
+			case "checkpointDone":
+				m.currentView = checkpointDoneView; m.checkpointDoneModel = NewCheckpointDoneForm(); return m, tea.Batch(m.checkpointDoneModel.Init(), m.windowSize())
+			case "searchTasks":
+				m.currentView = taskSearchView; m.taskSearchModel = NewTaskSearchForm(); return m, tea.Batch(m.taskSearchModel.Init(), m.windowSize())
+			case "reopenTask":
+				m.currentView = reopenTaskView; m.reopenTaskModel = NewReopenTaskForm(); return m, tea.Batch(m.reopenTaskModel.Init(), m.windowSize())
+			case "checkFixDependencies":
+				m.currentView = checkFixDependenciesView; m.checkFixDependenciesModel = NewCheckFixDependenciesForm(); return m, tea.Batch(m.checkFixDependenciesModel.Init(), m.windowSize())
+			case "setPriority":
+				m.currentView = setPriorityView; m.setPriorityModel = NewSetPriorityForm(); return m, tea.Batch(m.setPriorityModel.Init(), m.windowSize())
+			case "taskTimeline":
+				m.currentView = taskTimelineView; m.taskTimelineModel = NewTaskTimelineForm(); return m, tea.Batch(m.taskTimelineModel.Init(), m.windowSize())
+			case "reorderTasks":
+				m.currentView = reorderTasksView; m.reorderTasksModel = NewReorderTasksForm(); return m, tea.Batch(m.reorderTasksModel.Init(), m.windowSize())
+			case "quit":
+				return m, tea.Quit
 			default:
 				m.mainMenuForm.State = huh.StateNormal; return m, m.mainMenuForm.Init()
 			}
@@ -226,72 +422,182 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case parsePRDView:
 		if m.parsePRDModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
 		updatedSubModel, subCmd := m.parsePRDModel.Update(msg)
-		if prdM, ok := updatedSubModel.(*ParsePRDModel); ok { m.parsePRDModel = prdM } else { return m.Update(updatedSubModel) }
+		if prdM, ok := updatedSubModel.(*ParsePRDModel); ok { m.parsePRDModel = prdM } else { return m.update(updatedSubModel) }
 		cmds = append(cmds, subCmd)
 	case updateTaskView:
 		if m.updateTaskModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
 		updatedSubModel, subCmd := m.updateTaskModel.Update(msg)
-		if utM, ok := updatedSubModel.(*UpdateTaskModel); ok { m.updateTaskModel = utM } else { return m.Update(updatedSubModel) }
+		if utM, ok := updatedSubModel.(*UpdateTaskModel); ok { m.updateTaskModel = utM } else { return m.update(updatedSubModel) }
 		cmds = append(cmds, subCmd)
 	case updateSingleTaskView:
 		if m.updateSingleTaskModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
 		updatedSubModel, subCmd := m.updateSingleTaskModel.Update(msg)
-		if ustM, ok := updatedSubModel.(*UpdateSingleTaskModel); ok { m.updateSingleTaskModel = ustM } else { return m.Update(updatedSubModel) }
+		if ustM, ok := updatedSubModel.(*UpdateSingleTaskModel); ok { m.updateSingleTaskModel = ustM } else { return m.update(updatedSubModel) }
 		cmds = append(cmds, subCmd)
 	case updateSubtaskView:
 		if m.updateSubtaskModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
 		updatedSubModel, subCmd := m.updateSubtaskModel.Update(msg)
-		if usubM, ok := updatedSubModel.(*UpdateSubtaskModel); ok { m.updateSubtaskModel = usubM } else { return m.Update(updatedSubModel) }
+		if usubM, ok := updatedSubModel.(*UpdateSubtaskModel); ok { m.updateSubtaskModel = usubM } else { return m.update(updatedSubModel) }
 		cmds = append(cmds, subCmd)
 	case generateFilesView:
 		if m.generateFilesModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
 		updatedSubModel, subCmd := m.generateFilesModel.Update(msg)
-		if genM, ok := updatedSubModel.(*GenerateFilesModel); ok { m.generateFilesModel = genM } else { return m.Update(updatedSubModel) }
+		if genM, ok := updatedSubModel.(*GenerateFilesModel); ok { m.generateFilesModel = genM } else { return m.update(updatedSubModel) }
 		cmds = append(cmds, subCmd)
 	case setStatusView:
 		if m.setStatusModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
 		updatedSubModel, subCmd := m.setStatusModel.Update(msg)
-		if statusM, ok := updatedSubModel.(*SetStatusModel); ok { m.setStatusModel = statusM } else { return m.Update(updatedSubModel) }
+		if statusM, ok := updatedSubModel.(*SetStatusModel); ok { m.setStatusModel = statusM } else { return m.update(updatedSubModel) }
 		cmds = append(cmds, subCmd)
 	case listTasksView:
 		if m.listTasksModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
 		updatedSubModel, subCmd := m.listTasksModel.Update(msg)
-		if ltM, ok := updatedSubModel.(*ListTasksModel); ok { m.listTasksModel = ltM } else { return m.Update(updatedSubModel) }
+		if ltM, ok := updatedSubModel.(*ListTasksModel); ok { m.listTasksModel = ltM } else { return m.update(updatedSubModel) }
 		cmds = append(cmds, subCmd)
 	case expandTaskView:
 		if m.expandTaskModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
 		updatedSubModel, subCmd := m.expandTaskModel.Update(msg)
-		if etM, ok := updatedSubModel.(*ExpandTaskModel); ok { m.expandTaskModel = etM } else { return m.Update(updatedSubModel) }
+		if etM, ok := updatedSubModel.(*ExpandTaskModel); ok { m.expandTaskModel = etM } else { return m.update(updatedSubModel) }
 		cmds = append(cmds, subCmd)
 	case analyzeComplexityView:
 		if m.analyzeComplexityModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
 		updatedSubModel, subCmd := m.analyzeComplexityModel.Update(msg)
-		if acM, ok := updatedSubModel.(*AnalyzeComplexityModel); ok { m.analyzeComplexityModel = acM } else { return m.Update(updatedSubModel) }
+		if acM, ok := updatedSubModel.(*AnalyzeComplexityModel); ok { m.analyzeComplexityModel = acM } else { return m.update(updatedSubModel) }
 		cmds = append(cmds, subCmd)
 	case clearSubtasksView:
 		if m.clearSubtasksModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
 		updatedSubModel, subCmd := m.clearSubtasksModel.Update(msg)
-		if csM, ok := updatedSubModel.(*ClearSubtasksModel); ok { m.clearSubtasksModel = csM } else { return m.Update(updatedSubModel) }
+		if csM, ok := updatedSubModel.(*ClearSubtasksModel); ok { m.clearSubtasksModel = csM } else { return m.update(updatedSubModel) }
 		cmds = append(cmds, subCmd)
 	case addTaskView:
 		if m.addTaskModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
 		updatedSubModel, subCmd := m.addTaskModel.Update(msg)
-		if atM, ok := updatedSubModel.(*AddTaskModel); ok { m.addTaskModel = atM } else { return m.Update(updatedSubModel) }
+		if atM, ok := updatedSubModel.(*AddTaskModel); ok { m.addTaskModel = atM } else { return m.update(updatedSubModel) }
 		cmds = append(cmds, subCmd)
 	case nextTaskView:
 		if m.nextTaskModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
 		updatedSubModel, subCmd := m.nextTaskModel.Update(msg)
-		if ntM, ok := updatedSubModel.(*NextTaskModel); ok { m.nextTaskModel = ntM } else { return m.Update(updatedSubModel) }
+		if ntM, ok := updatedSubModel.(*NextTaskModel); ok { m.nextTaskModel = ntM } else { return m.update(updatedSubModel) }
 		cmds = append(cmds, subCmd)
 	case showTaskView:
 		if m.showTaskModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
 		updatedSubModel, subCmd := m.showTaskModel.Update(msg)
-		if stM, ok := updatedSubModel.(*ShowTaskModel); ok { m.showTaskModel = stM } else { return m.Update(updatedSubModel) }
+		if stM, ok := updatedSubModel.(*ShowTaskModel); ok { m.showTaskModel = stM } else { return m.update(updatedSubModel) }
 		cmds = append(cmds, subCmd)
 	case addDependencyView:
 		if m.addDependencyModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
 		updatedSubModel, subCmd := m.addDependencyModel.Update(msg)
-		if adM, ok := updatedSubModel.(*AddDependencyModel); ok { m.addDependencyModel = adM } else { return m.Update(updatedSubModel) }
+		if adM, ok := updatedSubModel.(*AddDependencyModel); ok { m.addDependencyModel = adM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case modelsView:
+		if m.modelsModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.modelsModel.Update(msg)
+		if mdM, ok := updatedSubModel.(*ModelsModel); ok { m.modelsModel = mdM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case initView:
+		if m.initModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.initModel.Update(msg)
+		if inM, ok := updatedSubModel.(*InitModel); ok { m.initModel = inM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case validateDependenciesView:
+		if m.validateDependenciesModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.validateDependenciesModel.Update(msg)
+		if vdM, ok := updatedSubModel.(*ValidateDependenciesModel); ok { m.validateDependenciesModel = vdM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case fixDependenciesView:
+		if m.fixDependenciesModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.fixDependenciesModel.Update(msg)
+		if fdM, ok := updatedSubModel.(*FixDependenciesModel); ok { m.fixDependenciesModel = fdM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case historyView:
+		if m.historyModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.historyModel.Update(msg)
+		if hiM, ok := updatedSubModel.(*HistoryModel); ok { m.historyModel = hiM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case undoView:
+		if m.undoModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.undoModel.Update(msg)
+		if udM, ok := updatedSubModel.(*UndoModel); ok { m.undoModel = udM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case batchView:
+		if m.batchModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.batchModel.Update(msg)
+		if btM, ok := updatedSubModel.(*BatchModel); ok { m.batchModel = btM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case removeSubtaskView:
+		if m.removeSubtaskModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.removeSubtaskModel.Update(msg)
+		if rsM, ok := updatedSubModel.(*RemoveSubtaskModel); ok { m.removeSubtaskModel = rsM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case tagsView:
+		if m.tagsModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.tagsModel.Update(msg)
+		if tgM, ok := updatedSubModel.(*TagsModel); ok { m.tagsModel = tgM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case dependencyGraphView:
+		if m.dependencyGraphModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.dependencyGraphModel.Update(msg)
+		if dgM, ok := updatedSubModel.(*DependencyGraphModel); ok { m.dependencyGraphModel = dgM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case settingsView:
+		if m.settingsModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.settingsModel.Update(msg)
+		if stM, ok := updatedSubModel.(*SettingsModel); ok { m.settingsModel = stM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case moveTaskView:
+		if m.moveTaskModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.moveTaskModel.Update(msg)
+		if mtM, ok := updatedSubModel.(*MoveTaskModel); ok { m.moveTaskModel = mtM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case recentFilesView:
+		if m.recentFilesModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.recentFilesModel.Update(msg)
+		if rfM, ok := updatedSubModel.(*RecentFilesModel); ok { m.recentFilesModel = rfM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case duplicateTaskView:
+		if m.duplicateTaskModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.duplicateTaskModel.Update(msg)
+		if dtM, ok := updatedSubModel.(*DuplicateTaskModel); ok { m.duplicateTaskModel = dtM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case statusMappingView:
+		if m.statusMappingModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.statusMappingModel.Update(msg)
+		if smM, ok := updatedSubModel.(*StatusMappingModel); ok { m.statusMappingModel = smM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case checkpointDoneView:
+		if m.checkpointDoneModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.checkpointDoneModel.Update(msg)
+		if cdM, ok := updatedSubModel.(*CheckpointDoneModel); ok { m.checkpointDoneModel = cdM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case taskSearchView:
+		if m.taskSearchModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.taskSearchModel.Update(msg)
+		if tsM, ok := updatedSubModel.(*TaskSearchModel); ok { m.taskSearchModel = tsM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case reopenTaskView:
+		if m.reopenTaskModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.reopenTaskModel.Update(msg)
+		if rtM, ok := updatedSubModel.(*ReopenTaskModel); ok { m.reopenTaskModel = rtM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case checkFixDependenciesView:
+		if m.checkFixDependenciesModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.checkFixDependenciesModel.Update(msg)
+		if cfM, ok := updatedSubModel.(*CheckFixDependenciesModel); ok { m.checkFixDependenciesModel = cfM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case setPriorityView:
+		if m.setPriorityModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.setPriorityModel.Update(msg)
+		if spM, ok := updatedSubModel.(*SetPriorityModel); ok { m.setPriorityModel = spM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case taskTimelineView:
+		if m.taskTimelineModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.taskTimelineModel.Update(msg)
+		if ttM, ok := updatedSubModel.(*TaskTimelineModel); ok { m.taskTimelineModel = ttM } else { return m.update(updatedSubModel) }
+		cmds = append(cmds, subCmd)
+	case reorderTasksView:
+		if m.reorderTasksModel == nil { m.currentView = mainMenuView; return m, m.mainMenuForm.Init() }
+		updatedSubModel, subCmd := m.reorderTasksModel.Update(msg)
+		if rtM, ok := updatedSubModel.(*ReorderTasksModel); ok { m.reorderTasksModel = rtM } else { return m.update(updatedSubModel) }
 		cmds = append(cmds, subCmd)
 		}
 
@@ -307,12 +613,125 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// windowSize re-sends the last known terminal size so a newly opened form can
+// lay itself out before the next resize.
+func (m model) windowSize() tea.Cmd {
+	return func() tea.Msg {
+		return tea.WindowSizeMsg{Width: m.width, Height: m.height}
+	}
+}
+
+// keyContext returns what the current screen is doing, for the help overlay.
+func (m model) keyContext() keyContext {
+	if m.currentView == mainMenuView {
+		return contextMenu
+	}
+	if sub, ok := m.activeModel().(interface{ keyContext() keyContext }); ok {
+		return sub.keyContext()
+	}
+	return contextForm
+}
+
+// activeModel returns the model of the current form view, or nil on the main menu.
+func (m model) activeModel() tea.Model {
+	switch m.currentView {
+	case parsePRDView:
+		return m.parsePRDModel
+	case updateTaskView:
+		return m.updateTaskModel
+	case updateSingleTaskView:
+		return m.updateSingleTaskModel
+	case updateSubtaskView:
+		return m.updateSubtaskModel
+	case generateFilesView:
+		return m.generateFilesModel
+	case setStatusView:
+		return m.setStatusModel
+	case listTasksView:
+		return m.listTasksModel
+	case expandTaskView:
+		return m.expandTaskModel
+	case analyzeComplexityView:
+		return m.analyzeComplexityModel
+	case clearSubtasksView:
+		return m.clearSubtasksModel
+	case addTaskView:
+		return m.addTaskModel
+	case nextTaskView:
+		return m.nextTaskModel
+	case showTaskView:
+		return m.showTaskModel
+	case addDependencyView:
+		return m.addDependencyModel
+	case modelsView:
+		return m.modelsModel
+	case initView:
+		return m.initModel
+	case validateDependenciesView:
+		return m.validateDependenciesModel
+	case fixDependenciesView:
+		return m.fixDependenciesModel
+	case historyView:
+		return m.historyModel
+	case undoView:
+		return m.undoModel
+	case batchView:
+		return m.batchModel
+	case removeSubtaskView:
+		return m.removeSubtaskModel
+	case tagsView:
+		return m.tagsModel
+	case dependencyGraphView:
+		return m.dependencyGraphModel
+	case settingsView:
+		return m.settingsModel
+	case moveTaskView:
+		return m.moveTaskModel
+	case recentFilesView:
+		return m.recentFilesModel
+	case duplicateTaskView:
+		return m.duplicateTaskModel
+	case statusMappingView:
+		return m.statusMappingModel
+	case checkpointDoneView:
+		return m.checkpointDoneModel
+	case taskSearchView:
+		return m.taskSearchModel
+	case reopenTaskView:
+		return m.reopenTaskModel
+	case checkFixDependenciesView:
+		return m.checkFixDependenciesModel
+	case setPriorityView:
+		return m.setPriorityModel
+	case taskTimelineView:
+		return m.taskTimelineModel
+	case reorderTasksView:
+		return m.reorderTasksModel
+	}
+	return nil
+}
+
 func (m model) View() string {
+	if m.showHelp {
+		return renderHelpOverlay(m.keyContext(), m.width)
+	}
 	switch m.currentView {
 	// ... (other cases remain the same)
 	case mainMenuView:
-		if m.mainMenuForm != nil { return m.mainMenuForm.View() }
-		return "Error: Main menu not initialized."
+		if m.mainMenuForm == nil { return "Error: Main menu not initialized." }
+		header := ""
+		if m.activeTag != "" {
+			header = lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Padding(0, 2).Render("🏷️  Tag: "+m.activeTag) + "\n"
+		}
+		if m.taskSummary != "" {
+			header += m.taskSummary + "\n"
+		}
+		if cliExecutor.NodeWarning != "" {
+			// Commands will fail until node is fixed, so say why up front
+			warning := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Padding(0, 2).Render("⚠️ " + cliExecutor.NodeWarning)
+			return header + m.mainMenuForm.View() + "\n" + warning
+		}
+		return header + m.mainMenuForm.View() + "\n" + lipgloss.NewStyle().Faint(true).Padding(0, 2).Render("Press ? for help.")
 	case parsePRDView:
 		if m.parsePRDModel != nil { return m.parsePRDModel.View() }
 		return "Error: Parse PRD form not initialized."
@@ -355,16 +774,94 @@ func (m model) View() string {
 	case addDependencyView:
 		if m.addDependencyModel != nil { return m.addDependencyModel.View() }
 		return "Error: Add Dependency form not initialized."
+	case modelsView:
+		if m.modelsModel != nil { return m.modelsModel.View() }
+		return "Error: Configure Models form not initialized."
+	case initView:
+		if m.initModel != nil { return m.initModel.View() }
+		return "Error: Initialize Project form not initialized."
+	case validateDependenciesView:
+		if m.validateDependenciesModel != nil { return m.validateDependenciesModel.View() }
+		return "Error: Validate Dependencies form not initialized."
+	case fixDependenciesView:
+		if m.fixDependenciesModel != nil { return m.fixDependenciesModel.View() }
+		return "Error: Fix Dependencies form not initialized."
+	case historyView:
+		if m.historyModel != nil { return m.historyModel.View() }
+		return "Error: Command History form not initialized."
+	case undoView:
+		if m.undoModel != nil { return m.undoModel.View() }
+		return "Error: Undo form not initialized."
+	case batchView:
+		if m.batchModel != nil { return m.batchModel.View() }
+		return "Error: Run Batch form not initialized."
+	case removeSubtaskView:
+		if m.removeSubtaskModel != nil { return m.removeSubtaskModel.View() }
+		return "Error: Remove Subtask form not initialized."
+	case tagsView:
+		if m.tagsModel != nil { return m.tagsModel.View() }
+		return "Error: Tags form not initialized."
+	case dependencyGraphView:
+		if m.dependencyGraphModel != nil { return m.dependencyGraphModel.View() }
+		return "Error: Dependency Graph form not initialized."
+	case settingsView:
+		if m.settingsModel != nil { return m.settingsModel.View() }
+		return "Error: Settings form not initialized."
+	case moveTaskView:
+		if m.moveTaskModel != nil { return m.moveTaskModel.View() }
+		return "Error: Move Task form not initialized."
+	case recentFilesView:
+		if m.recentFilesModel != nil { return m.recentFilesModel.View() }
+		return "Error: Recent Files form not initialized."
+	case duplicateTaskView:
+		if m.duplicateTaskModel != nil { return m.duplicateTaskModel.View() }
+		return "Error: Duplicate Task form not initialized."
+	case statusMappingView:
+		if m.statusMappingModel != nil { return m.statusMappingModel.View() }
+		return "Error: Status Mapping form not initialized."
+	case checkpointDoneView:
+		if m.checkpointDoneModel != nil { return m.checkpointDoneModel.View() }
+		return "Error: Mark checkpoint done form not initialized."
+	case taskSearchView:
+		if m.taskSearchModel != nil { return m.taskSearchModel.View() }
+		return "Error: Task search form not initialized."
+	case reopenTaskView:
+		if m.reopenTaskModel != nil { return m.reopenTaskModel.View() }
+		return "Error: Reopen task form not initialized."
+	case checkFixDependenciesView:
+		if m.checkFixDependenciesModel != nil { return m.checkFixDependenciesModel.View() }
+		return "Error: Check & Fix Dependencies form not initialized."
+	case setPriorityView:
+		if m.setPriorityModel != nil { return m.setPriorityModel.View() }
+		return "Error: Set Priority form not initialized."
+	case taskTimelineView:
+		if m.taskTimelineModel != nil { return m.taskTimelineModel.View() }
+		return "Error: Task timeline form not initialized."
+	case reorderTasksView:
+		if m.reorderTasksModel != nil { return m.reorderTasksModel.View() }
+		return "Error: Reorder tasks form not initialized."
 	default:
 		return "Unknown view."
 	}
 }
 
 func main() {
+	// With a command on the command line, run it and print the result as JSON instead of starting the TUI
+	closeLog := openDebugLog()
+	if len(os.Args) > 1 {
+		code := runHeadless(cliExecutor, os.Args[1:], os.Stdout, os.Stderr)
+		closeLog()
+		os.Exit(code)
+	}
+	defer closeLog()
+
+	cliExecutor.CheckNode()
 	initialModel := newModel()
 	p := tea.NewProgram(initialModel, tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {
+		debugLog.Error("program failed", "error", err)
+		closeLog()
 		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// Model roles the CLI can configure.
+const (
+	modelRoleMain     = "main"
+	modelRoleResearch = "research"
+	modelRoleFallback = "fallback"
+)
+
+// supportedModel is an entry of the CLI's supported-models.json.
+type supportedModel struct {
+	ID           string   `json:"id"`
+	AllowedRoles []string `json:"allowed_roles"`
+}
+
+// modelSetting is the model configured for one role in .taskmasterconfig.
+type modelSetting struct {
+	Provider string `json:"provider"`
+	ModelID  string `json:"modelId"`
+}
+
+// modelConfig is the part of .taskmasterconfig the models form displays.
+type modelConfig struct {
+	Models map[string]modelSetting `json:"models"`
+}
+
+// loadSupportedModels reads the models the CLI knows about, keyed by provider.
+func loadSupportedModels() (map[string][]supportedModel, error) {
+	data, err := os.ReadFile(filepath.Join(commandDir(), "scripts", "modules", "supported-models.json"))
+	if err != nil {
+		return nil, err
+	}
+	var models map[string][]supportedModel
+	if err := json.Unmarshal(data, &models); err != nil {
+		return nil, fmt.Errorf("failed to parse supported models: %w", err)
+	}
+	return models, nil
+}
+
+// loadModelConfig reads the project's model configuration.
+func loadModelConfig() (*modelConfig, error) {
+	data, err := os.ReadFile(filepath.Join(commandDir(), ".taskmasterconfig"))
+	if err != nil {
+		return nil, err
+	}
+	var cfg modelConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse .taskmasterconfig: %w", err)
+	}
+	return &cfg, nil
+}
+
+// modelChoice is a model that may be used for a role.
+type modelChoice struct {
+	Provider string
+	ID       string
+}
+
+// modelsForRole lists the models allowed for role, sorted by provider then in
+// the order the CLI lists them.
+func modelsForRole(models map[string][]supportedModel, role string) []modelChoice {
+	providers := make([]string, 0, len(models))
+	for provider := range models {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	var choices []modelChoice
+	for _, provider := range providers {
+		for _, model := range models[provider] {
+			if slices.Contains(model.AllowedRoles, role) {
+				choices = append(choices, modelChoice{Provider: provider, ID: model.ID})
+			}
+		}
+	}
+	return choices
+}
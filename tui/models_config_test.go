@@ -0,0 +1,44 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestModelsForRole(t *testing.T) {
+	models := map[string][]supportedModel{
+		"perplexity": {{ID: "sonar-pro", AllowedRoles: []string{"research"}}},
+		"anthropic": {
+			{ID: "claude-3-7-sonnet", AllowedRoles: []string{"main", "fallback"}},
+			{ID: "claude-3-5-haiku", AllowedRoles: []string{"main"}},
+		},
+		"xai": {{ID: "grok-3", AllowedRoles: []string{"main", "fallback", "research"}}},
+	}
+
+	tests := []struct {
+		role string
+		want []modelChoice
+	}{
+		{role: modelRoleMain, want: []modelChoice{
+			{Provider: "anthropic", ID: "claude-3-7-sonnet"},
+			{Provider: "anthropic", ID: "claude-3-5-haiku"},
+			{Provider: "xai", ID: "grok-3"},
+		}},
+		{role: modelRoleResearch, want: []modelChoice{
+			{Provider: "perplexity", ID: "sonar-pro"},
+			{Provider: "xai", ID: "grok-3"},
+		}},
+		{role: modelRoleFallback, want: []modelChoice{
+			{Provider: "anthropic", ID: "claude-3-7-sonnet"},
+			{Provider: "xai", ID: "grok-3"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			if got := modelsForRole(models, tt.role); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("modelsForRole(%q) = %v, want %v", tt.role, got, tt.want)
+			}
+		})
+	}
+}
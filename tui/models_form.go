@@ -0,0 +1,287 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	modelsFormKeyMain     = "main"
+	modelsFormKeyResearch = "research"
+	modelsFormKeyFallback = "fallback"
+)
+
+// ModelsModel holds the state for the models configuration form.
+type ModelsModel struct {
+	form         *huh.Form
+	aborted      bool
+	isProcessing bool
+	statusMsg    string
+	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
+
+	// Form values; empty keeps the current model for that role
+	MainModel     string
+	ResearchModel string
+	FallbackModel string
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
+}
+
+// NewModelsForm creates a new form for the models command.
+func NewModelsForm() *ModelsModel {
+	m := &ModelsModel{}
+
+	cfg, cfgErr := loadModelConfig()
+	models, modelsErr := loadSupportedModels()
+
+	current := "No .taskmasterconfig found; the CLI defaults are in use."
+	if cfgErr == nil {
+		current = describeModelConfig(cfg)
+	}
+	if modelsErr != nil {
+		current += fmt.Sprintf("\n\nCould not load the supported models (%v). Submit to view the current configuration.", modelsErr)
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().
+				Title("Current Models").
+				Description(current),
+
+			newModelSelect(modelsFormKeyMain, "Main Model", "Used for task generation and updates.", models, modelRoleMain, cfg, &m.MainModel),
+			newModelSelect(modelsFormKeyResearch, "Research Model", "Used for research-backed operations.", models, modelRoleResearch, cfg, &m.ResearchModel),
+			newModelSelect(modelsFormKeyFallback, "Fallback Model", "Used if the main model fails.", models, modelRoleFallback, cfg, &m.FallbackModel),
+		),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
+	).WithTheme(huh.ThemeDracula())
+
+	return m
+}
+
+// newModelSelect builds the select for one model role. The first option keeps
+// the current model.
+func newModelSelect(key, title, description string, models map[string][]supportedModel, role string, cfg *modelConfig, value *string) *huh.Select[string] {
+	keep := "Keep current"
+	if cfg != nil {
+		if setting, ok := cfg.Models[role]; ok && setting.ModelID != "" {
+			keep = fmt.Sprintf("Keep current (%s / %s)", setting.Provider, setting.ModelID)
+		}
+	}
+
+	options := []huh.Option[string]{huh.NewOption(keep, "")}
+	for _, choice := range modelsForRole(models, role) {
+		options = append(options, huh.NewOption(fmt.Sprintf("%s / %s", choice.Provider, choice.ID), choice.ID))
+	}
+
+	return huh.NewSelect[string]().
+		Key(key).
+		Title(title).
+		Description(description + " Press / to filter.").
+		Options(options...).
+		Height(8).
+		Value(value)
+}
+
+// describeModelConfig summarises the configured model for each role.
+func describeModelConfig(cfg *modelConfig) string {
+	var lines []string
+	for _, role := range []string{modelRoleMain, modelRoleResearch, modelRoleFallback} {
+		setting, ok := cfg.Models[role]
+		if !ok || setting.ModelID == "" {
+			lines = append(lines, fmt.Sprintf("%-9s (not set)", role+":"))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%-9s %s / %s", role+":", setting.Provider, setting.ModelID))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *ModelsModel) Init() tea.Cmd {
+	m.isProcessing = false
+	m.statusMsg = ""
+	m.aborted = false
+	return m.form.Init()
+}
+
+func (m *ModelsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case modelsCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			m.statusMsg = "✅ Success!"
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
+	if m.isProcessing {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, nil
+	}
+
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.statusMsg = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
+	var cmds []tea.Cmd
+	formModel, cmd := m.form.Update(msg)
+	if updatedForm, ok := formModel.(*huh.Form); ok {
+		m.form = updatedForm
+	} else {
+		m.statusMsg = "Error: Form update returned unexpected type."
+		debugLog.Error("form update did not return *huh.Form", "file", "models_form.go", "type", fmt.Sprintf("%T", formModel))
+		return m, tea.Quit
+	}
+	cmds = append(cmds, cmd)
+
+	if m.form.State == huh.StateCompleted {
+		m.statusMsg = "Executing models command..."
+		m.isProcessing = true
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeModelsCommand))
+	}
+
+	if m.form.State == huh.StateAborted {
+		m.aborted = true
+		return m, func() tea.Msg { return backToMenuMsg{} }
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if !m.isProcessing {
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			}
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *ModelsModel) View() string {
+	if m.aborted {
+		return "Form aborted. Returning to main menu..."
+	}
+
+	var viewBuilder strings.Builder
+	viewBuilder.WriteString(m.form.View())
+
+	if m.statusMsg != "" {
+		viewBuilder.WriteString("\n\n")
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
+	}
+
+	helpStyle := lipgloss.NewStyle().Faint(true)
+	if m.isProcessing {
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
+	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
+	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Padding(1, 2).
+		Render(viewBuilder.String())
+}
+
+func (m *ModelsModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
+// GetFormValues retrieves the structured data after completion.
+func (m *ModelsModel) GetFormValues() (map[string]interface{}, error) {
+	if m.form.State != huh.StateCompleted {
+		return nil, fmt.Errorf("form is not yet completed")
+	}
+	return map[string]interface{}{
+		modelsFormKeyMain:     m.MainModel,
+		modelsFormKeyResearch: m.ResearchModel,
+		modelsFormKeyFallback: m.FallbackModel,
+		advancedFlagsFormKey:  m.AdvancedFlags,
+	}, nil
+}
+
+// modelsCompleteMsg is sent when the command execution is complete
+type modelsCompleteMsg struct {
+	result CLIResult
+}
+
+// executeModelsCommand executes the actual models CLI command
+func (m *ModelsModel) executeModelsCommand() tea.Cmd {
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+
+		result := executor.Models(m.MainModel, m.ResearchModel, m.FallbackModel)
+		return modelsCompleteMsg{result: result}
+	})
+}
+
+var _ tea.Model = &ModelsModel{}
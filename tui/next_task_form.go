@@ -2,16 +2,19 @@ package main
 
 import (
 	"fmt"
-	"os"
+	"math"
+	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 )
 
 const (
-	nextTaskFormKeyFile = "file"
+	nextTaskFormKeyFile  = "file"
+	nextTaskFormKeyCount = "count"
 )
 
 // NextTaskModel holds the state for the next task form.
@@ -21,30 +24,50 @@ type NextTaskModel struct {
 	isProcessing bool // To simulate action, though 'next' might just display info
 	statusMsg    string
 	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
 
-	// Form value
+	// Form values
 	FilePath string
+	Count    string // How many tasks to list; more than one is ranked in Go, see TasksFile.NextTasks
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
 }
 
 // NewNextTaskForm creates a new form for the next task command.
 func NewNextTaskForm() *NextTaskModel {
-	m := &NextTaskModel{}
+	m := &NextTaskModel{
+		FilePath: lastFilePath(), // Pre-populate with the last used tasks file
+		Count:    "1",
+	}
 
 	m.form = huh.NewForm(
+		huh.NewGroup(
+			newFilePathField(nextTaskFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json) to find the next task from.", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
 		huh.NewGroup(
 			huh.NewInput().
-				Key(nextTaskFormKeyFile).
-				Title("Tasks File Path").
-				Description("Path to the tasks file (e.g., tasks.md) to find the next task from.").
-				Prompt("📄 ").
+				Key(nextTaskFormKeyCount).
+				Title("Number of Tasks").
+				Description("1 asks the CLI for its recommendation; more lists the next tasks ready to work on, ranked.").
+				Prompt("🔢 ").
 				Validate(func(s string) error {
-					if s == "" {
-						return fmt.Errorf("tasks file path cannot be empty")
+					if err := validateCount(s, "number of tasks", math.MaxInt); err != nil {
+						return err
+					}
+					if n, _ := strconv.Atoi(s); n > maxNextTasks {
+						return fmt.Errorf("number of tasks must be at most %d", maxNextTasks)
 					}
 					return nil
 				}).
-				Value(&m.FilePath),
+				Value(&m.Count),
 		),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
 	).WithTheme(huh.ThemeDracula())
 
 	return m
@@ -58,22 +81,81 @@ func (m *NextTaskModel) Init() tea.Cmd {
 }
 
 func (m *NextTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case nextTaskCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			m.statusMsg = "✅ Success!"
+			if m.count() > 1 {
+				m.statusMsg = "✅ " + msg.result.Message + "."
+			}
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
 	if m.isProcessing {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
-			if keyMsg.String() == "ctrl+c" || keyMsg.String() == "q" {
+			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
+			case "ctrl+c", "q":
 				return m, tea.Quit
 			}
 		}
 		return m, nil
 	}
 
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.statusMsg = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
 	var cmds []tea.Cmd
 	formModel, cmd := m.form.Update(msg)
 	if updatedForm, ok := formModel.(*huh.Form); ok {
 		m.form = updatedForm
 	} else {
 		m.statusMsg = "Error: Form update returned unexpected type."
-		fmt.Fprintf(os.Stderr, "Critical Error: next_task_form.go - form update did not return *huh.Form. Got: %T\n", formModel)
+		debugLog.Error("form update did not return *huh.Form", "file", "next_task_form.go", "type", fmt.Sprintf("%T", formModel))
 		return m, tea.Quit
 	}
 	cmds = append(cmds, cmd)
@@ -81,7 +163,7 @@ func (m *NextTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.form.State == huh.StateCompleted {
 		m.statusMsg = "Executing next-task command..."
 		m.isProcessing = true
-		return m, m.executeNextTaskCommand()
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeNextTaskCommand))
 	}
 
 	if m.form.State == huh.StateAborted {
@@ -90,14 +172,6 @@ func (m *NextTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
-	case nextTaskCompleteMsg:
-		m.isProcessing = false
-		if msg.result.Success {
-			m.statusMsg = fmt.Sprintf("✅ Success!\n\n%s", msg.result.Output)
-		} else {
-			m.statusMsg = fmt.Sprintf("❌ Error: %s\n\n%s", msg.result.Error, msg.result.Output)
-		}
-		return m, nil
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
@@ -106,8 +180,6 @@ func (m *NextTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, func() tea.Msg { return backToMenuMsg{} }
 			}
 		}
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
 	}
 
 	return m, tea.Batch(cmds...)
@@ -123,18 +195,22 @@ func (m *NextTaskModel) View() string {
 
 	if m.statusMsg != "" {
 		viewBuilder.WriteString("\n\n")
-		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-		if strings.HasPrefix(m.statusMsg, "Error:") {
-			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
-		}
-		viewBuilder.WriteString(statusStyle.Render(m.statusMsg))
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
 	}
 
 	helpStyle := lipgloss.NewStyle().Faint(true)
 	if m.isProcessing {
-		viewBuilder.WriteString(helpStyle.Render("\n\nProcessing... Press Ctrl+C to force quit."))
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
 	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
-		viewBuilder.WriteString(helpStyle.Render("\n\nCommand completed! Press Esc to return to main menu."))
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
 	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
 		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
 	}
@@ -145,16 +221,26 @@ func (m *NextTaskModel) View() string {
 		Render(viewBuilder.String())
 }
 
+func (m *NextTaskModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
 // GetFormValues retrieves the structured data after completion.
 func (m *NextTaskModel) GetFormValues() (map[string]interface{}, error) {
 	if m.form.State != huh.StateCompleted {
 		return nil, fmt.Errorf("form is not yet completed")
 	}
 	return map[string]interface{}{
-		nextTaskFormKeyFile: m.FilePath,
+		nextTaskFormKeyFile:  m.FilePath,
+		nextTaskFormKeyCount: m.count(),
+		advancedFlagsFormKey: m.AdvancedFlags,
 	}, nil
 }
 
+// count returns the validated number of tasks to list.
+func (m *NextTaskModel) count() int {
+	n, _ := strconv.Atoi(m.Count)
+	return n
+}
+
 // nextTaskCompleteMsg is sent when the command execution is complete
 type nextTaskCompleteMsg struct {
 	result CLIResult
@@ -162,10 +248,15 @@ type nextTaskCompleteMsg struct {
 
 // executeNextTaskCommand executes the actual next-task CLI command
 func (m *NextTaskModel) executeNextTaskCommand() tea.Cmd {
-	return func() tea.Msg {
-		result := cliExecutor.NextTask(m.FilePath)
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+
+		result := executor.NextTasks(m.FilePath, m.count())
 		return nextTaskCompleteMsg{result: result}
-	}
+	})
 }
 
 var _ tea.Model = &NextTaskModel{}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxNextTasks is the most tasks the next-task form lists at once.
+const maxNextTasks = 20
+
+// NextItem is a task or subtask that can be worked on now.
+type NextItem struct {
+	ID           string   `json:"id"` // Task ID, or parent.subtask for a subtask
+	Title        string   `json:"title"`
+	Status       string   `json:"status"`
+	Priority     string   `json:"priority"`
+	Dependencies []string `json:"dependencies"` // Full IDs, all done
+}
+
+// NextTasks ranks the tasks that can be worked on now and returns the first n.
+// The CLI's next command only reports one task, so the ranking follows its
+// rules in Go: pending and in-progress subtasks of in-progress tasks come
+// first, then pending and in-progress tasks, in each case only when all their
+// dependencies are done, and ordered by priority, then fewest dependencies,
+// then ID. The first item is the one next-task recommends.
+func (tf *TasksFile) NextTasks(n int) []NextItem {
+	edges := tf.DependencyEdges()
+	ready := tf.dependenciesDone()
+
+	var subtasks, tasks []NextItem
+	for _, task := range tf.Tasks {
+		taskID := strconv.Itoa(task.ID)
+		if task.Status == "in-progress" {
+			for _, sub := range task.Subtasks {
+				id := taskID + "." + strconv.Itoa(sub.ID)
+				if !isActionableStatus(sub.Status) || !ready(id) {
+					continue
+				}
+				priority := sub.Priority
+				if priority == "" {
+					priority = task.Priority
+				}
+				subtasks = append(subtasks, newNextItem(id, sub, priority, edges[id]))
+			}
+		}
+		if isActionableStatus(task.Status) && ready(taskID) {
+			tasks = append(tasks, newNextItem(taskID, task, task.Priority, edges[taskID]))
+		}
+	}
+	sortNextItems(subtasks)
+	sortNextItems(tasks)
+
+	items := append(subtasks, tasks...)
+	if len(items) > n {
+		items = items[:n]
+	}
+	return items
+}
+
+// dependenciesDone returns a function reporting whether all the dependencies
+// of a task or dotted subtask ID are done.
+func (tf *TasksFile) dependenciesDone() func(id string) bool {
+	done := make(map[string]bool)
+	for _, task := range tf.Tasks {
+		taskID := strconv.Itoa(task.ID)
+		done[taskID] = isDoneStatus(task.Status)
+		for _, sub := range task.Subtasks {
+			done[taskID+"."+strconv.Itoa(sub.ID)] = isDoneStatus(sub.Status)
+		}
+	}
+	edges := tf.DependencyEdges()
+	return func(id string) bool {
+		for _, dep := range edges[id] {
+			if !done[dep] {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// newNextItem describes a task or subtask for NextTasks. Tasks without a
+// status or priority get the CLI's defaults.
+func newNextItem(id string, task Task, priority string, deps []string) NextItem {
+	status := task.Status
+	if status == "" {
+		status = "pending"
+	}
+	if priority == "" {
+		priority = string(PriorityMedium)
+	}
+	return NextItem{ID: id, Title: task.Title, Status: status, Priority: priority, Dependencies: append([]string{}, deps...)}
+}
+
+// isDoneStatus reports whether a status counts as done for dependencies.
+func isDoneStatus(status string) bool {
+	return status == "done" || status == "completed"
+}
+
+// isActionableStatus reports whether a task with status can be worked on.
+func isActionableStatus(status string) bool {
+	status = strings.ToLower(status)
+	return status == "" || status == "pending" || status == "in-progress"
+}
+
+// sortNextItems orders items by priority, then fewest dependencies, then ID.
+func sortNextItems(items []NextItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if pa, pb := nextPriorityValue(a.Priority), nextPriorityValue(b.Priority); pa != pb {
+			return pa > pb
+		}
+		if len(a.Dependencies) != len(b.Dependencies) {
+			return len(a.Dependencies) < len(b.Dependencies)
+		}
+		return compareTaskIDs(a.ID, b.ID) < 0
+	})
+}
+
+// nextPriorityValue ranks priorities as the CLI does; unknown ones count as medium.
+func nextPriorityValue(priority string) int {
+	switch TaskPriority(priority) {
+	case PriorityHigh:
+		return 3
+	case PriorityLow:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// compareTaskIDs compares task or dotted subtask IDs part by part, numerically.
+func compareTaskIDs(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, _ := strconv.Atoi(as[i])
+		y, _ := strconv.Atoi(bs[i])
+		if x != y {
+			return x - y
+		}
+	}
+	return len(as) - len(bs)
+}
+
+// NextTasks lists the next n tasks to work on. One task is the CLI's
+// next-task; more are ranked from the tasks file by TasksFile.NextTasks,
+// since the CLI can't list more than one; that only reads the file, so it
+// also runs in dry-run mode.
+func (e *CLIExecutor) NextTasks(filePath string, n int) CLIResult {
+	if n <= 1 {
+		return e.NextTask(filePath)
+	}
+	tf, err := LoadTasksFile(filePath)
+	if err != nil {
+		return CLIResult{Success: false, Error: err.Error(), Message: fmt.Sprintf("Failed to read tasks: %s", err.Error())}
+	}
+	items := tf.NextTasks(n)
+	result := CLIResult{Success: true, Output: formatNextTasks(items)}
+	switch len(items) {
+	case 0:
+		result.Message = "No tasks are ready to work on"
+	case 1:
+		result.Message = "Found 1 task to work on"
+	default:
+		result.Message = fmt.Sprintf("Found %d tasks to work on", len(items))
+	}
+	return result
+}
+
+// formatNextTasks renders ranked items as a numbered list.
+func formatNextTasks(items []NextItem) string {
+	if len(items) == 0 {
+		return "No pending or in-progress tasks have all their dependencies done."
+	}
+	var b strings.Builder
+	for i, item := range items {
+		fmt.Fprintf(&b, "%2d. [%s] %s (%s, %s priority)\n", i+1, item.ID, item.Title, item.Status, item.Priority)
+		if len(item.Dependencies) > 0 {
+			fmt.Fprintf(&b, "    Depends on: %s\n", strings.Join(item.Dependencies, ", "))
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const rankedTasksJSON = `{"tasks": [
+	{"id": 1, "title": "Setup", "status": "done", "priority": "high"},
+	{"id": 2, "title": "API", "status": "in-progress", "priority": "medium", "dependencies": [1], "subtasks": [
+		{"id": 1, "title": "Routes", "status": "done"},
+		{"id": 2, "title": "Handlers", "status": "pending", "dependencies": [1]},
+		{"id": 3, "title": "Auth", "status": "pending", "dependencies": [2]}
+	]},
+	{"id": 3, "title": "Docs", "status": "pending", "priority": "low"},
+	{"id": 4, "title": "UI", "status": "pending", "priority": "high", "dependencies": [1]},
+	{"id": 5, "title": "Deploy", "status": "pending", "priority": "high", "dependencies": [4]},
+	{"id": 6, "title": "Tests", "status": "pending", "priority": "high"},
+	{"id": 7, "title": "Old", "status": "deferred", "priority": "high"}
+]}`
+
+func TestNextTasksRanking(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	writeTasks(t, path, rankedTasksJSON)
+	tf, err := LoadTasksFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, item := range tf.NextTasks(10) {
+		ids = append(ids, item.ID)
+	}
+	// Subtasks of in-progress tasks first, then by priority, dependency count and ID
+	if want := []string{"2.2", "6", "4", "2", "3"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("NextTasks(10) = %v, want %v", ids, want)
+	}
+
+	if got := tf.NextTasks(2); len(got) != 2 || got[1].ID != "6" {
+		t.Errorf("NextTasks(2) = %+v, want the first two", got)
+	}
+	if got := tf.NextTasks(1)[0]; got.Priority != "medium" || !reflect.DeepEqual(got.Dependencies, []string{"2.1"}) {
+		t.Errorf("subtask item = %+v, want the parent's priority and full dependency IDs", got)
+	}
+}
+
+func TestExecutorNextTasks(t *testing.T) {
+	fake := &fakeRunner{stdout: "Next: task 6\n"}
+	useFakeRunner(t, fake)
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	writeTasks(t, path, rankedTasksJSON)
+
+	// One task is the CLI's recommendation
+	if result := cliExecutor.NextTasks(path, 1); !result.Success || len(fake.calls) != 1 {
+		t.Fatalf("NextTasks(1) = %+v with %d CLI calls, want one CLI call", result, len(fake.calls))
+	}
+
+	// More are ranked from the file without running the CLI
+	result := cliExecutor.NextTasks(path, 3)
+	if !result.Success || len(fake.calls) != 1 {
+		t.Fatalf("NextTasks(3) = %+v with %d CLI calls, want no more CLI calls", result, len(fake.calls))
+	}
+	if result.Message != "Found 3 tasks to work on" || !strings.HasPrefix(result.Output, " 1. [2.2] Handlers") {
+		t.Errorf("NextTasks(3) = %q / %q", result.Message, result.Output)
+	}
+
+	if result := cliExecutor.NextTasks(filepath.Join(t.TempDir(), "missing.json"), 3); result.Success {
+		t.Errorf("NextTasks on a missing file succeeded")
+	}
+}
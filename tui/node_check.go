@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// minNodeMajor is the oldest Node.js major version the CLI supports.
+const minNodeMajor = 18
+
+// nodeNotFoundMessage replaces exec's error when node isn't on the PATH.
+const nodeNotFoundMessage = "Node.js is required to run taskmaster but wasn't found; install Node 18+ and ensure it's on your PATH."
+
+// CheckNode runs `node --version` and records the result on the executor: the
+// version found, and a warning for the user if node is missing or too old.
+func (e *CLIExecutor) CheckNode() {
+	out, err := exec.Command("node", "--version").Output()
+	e.NodeVersion, e.NodeWarning = nodeCheckResult(strings.TrimSpace(string(out)), err)
+}
+
+// nodeCheckResult interprets the output of `node --version`.
+func nodeCheckResult(version string, err error) (string, string) {
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", nodeNotFoundMessage
+	}
+	if err != nil {
+		return "", fmt.Sprintf("Couldn't check the Node.js version: %v", err)
+	}
+	major, err := nodeMajorVersion(version)
+	if err != nil {
+		return version, fmt.Sprintf("Couldn't read the Node.js version %q; Node %d+ is required.", version, minNodeMajor)
+	}
+	if major < minNodeMajor {
+		return version, fmt.Sprintf("Node.js %s is too old; taskmaster requires Node %d+.", version, minNodeMajor)
+	}
+	return version, ""
+}
+
+// nodeMajorVersion parses the major version from node's "v20.11.1" style output.
+func nodeMajorVersion(version string) (int, error) {
+	major, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	return strconv.Atoi(major)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+)
+
+func TestNodeCheckResult(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     string
+		err         error
+		wantVersion string
+		wantWarning bool
+	}{
+		{name: "supported", version: "v20.11.1", wantVersion: "v20.11.1"},
+		{name: "minimum", version: "v18.0.0", wantVersion: "v18.0.0"},
+		{name: "too old", version: "v16.20.2", wantVersion: "v16.20.2", wantWarning: true},
+		{name: "unparseable", version: "unknown", wantVersion: "unknown", wantWarning: true},
+		{name: "not installed", err: &exec.Error{Name: "node", Err: exec.ErrNotFound}, wantWarning: true},
+		{name: "failed", err: errors.New("exit status 1"), wantWarning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, warning := nodeCheckResult(tt.version, tt.err)
+			if version != tt.wantVersion {
+				t.Errorf("version = %q, want %q", version, tt.wantVersion)
+			}
+			if (warning != "") != tt.wantWarning {
+				t.Errorf("warning = %q, want warning: %v", warning, tt.wantWarning)
+			}
+		})
+	}
+
+	if _, warning := nodeCheckResult("", fmt.Errorf("start: %w", exec.ErrNotFound)); warning != nodeNotFoundMessage {
+		t.Errorf("wrapped not-found warning = %q, want %q", warning, nodeNotFoundMessage)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"io"
+	"sync"
+)
+
+// defaultMaxOutputMB is how many megabytes of each of a command's outputs are
+// kept when the settings don't say.
+const defaultMaxOutputMB = 4
+
+// truncatedNotice ends output that was cut short at the size limit.
+const truncatedNotice = "\n(output truncated)"
+
+// limitedWriter passes writes on to w until limit bytes have gone through and
+// discards the rest, so a command printing without end can't fill the TUI's
+// memory. Every write is reported as complete, so the command isn't killed by
+// a failed write and its exit status still says how it went. It is safe for
+// stdout and stderr to write concurrently.
+type limitedWriter struct {
+	mu        sync.Mutex
+	w         io.Writer
+	remaining int64
+	truncated bool
+}
+
+func newLimitedWriter(w io.Writer, limit int64) *limitedWriter {
+	return &limitedWriter{w: w, remaining: limit}
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := len(p)
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+		l.truncated = true
+	}
+	if len(p) > 0 {
+		l.w.Write(p)
+		l.remaining -= int64(len(p))
+	}
+	return n, nil
+}
+
+// wasTruncated reports whether any output was discarded.
+func (l *limitedWriter) wasTruncated() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.truncated
+}
+
+// maxOutputBytes returns how much of each output the executor keeps.
+func (e *CLIExecutor) maxOutputBytes() int64 {
+	if e.MaxOutputMB > 0 {
+		return int64(e.MaxOutputMB) << 20
+	}
+	return defaultMaxOutputMB << 20
+}
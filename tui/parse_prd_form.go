@@ -2,10 +2,10 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
@@ -17,22 +17,33 @@ const (
 	prdFormKeyNumTasks = "num-tasks"
 	prdFormKeyForce    = "force"
 	prdFormKeyAppend   = "append"
+	prdFormKeyTag      = "tag"
 )
 
 // ParsePRDModel holds the state for the parse-prd form.
 type ParsePRDModel struct {
 	form         *huh.Form
 	aborted      bool
-	isProcessing bool   // To simulate command execution
-	status       string // For messages after completion or errors
-	width        int    // Terminal width for layout
+	isProcessing bool                // To simulate command execution
+	status       string              // For messages after completion or errors
+	width        int                 // Terminal width for layout
+	height       int                 // Terminal height for layout
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	progress     progressBar         // Filled from progress lines in the output
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
 
 	// Fields to store form values, bound to the form
 	FilePath   string
 	OutputPath string
-	NumTasks   int  // Will be parsed from string input
+	NumTasks   int // Will be parsed from string input
 	Force      bool
 	Append     bool
+	Tag        string // Tag to add the tasks to; empty for the active tag
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
 }
 
 // NewParsePRDModel creates a new form for the parse-prd command.
@@ -41,35 +52,26 @@ func NewParsePRDModel() *ParsePRDModel {
 	// These defaults will be used to pre-populate form fields where appropriate
 	// or serve as fallback if a field isn't explicitly set.
 	m := &ParsePRDModel{
-		NumTasks: 5, // Default number of tasks
-		Force:    false,
-		Append:   false,
+		OutputPath: lastFilePath(), // Pre-populate with the last used tasks file
+		NumTasks:   5,              // Default number of tasks
+		Force:      false,
+		Append:     false,
 	}
 
 	// Temporary string for NumTasks input, as huh.Input works with *string.
 	// We'll parse this into m.NumTasks upon form completion.
 	numTasksStr := strconv.Itoa(m.NumTasks)
+	maxCount := maxGenerateCount()
+	tagPick, tagTyped := newTagGroups(prdFormKeyTag, &m.OutputPath, &m.Tag)
 
 	m.form = huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().
-				Key(prdFormKeyFile).
-				Title("PRD File Path").
-				Description("Path to the Product Requirements Document.").
-				Prompt("📄 ").
-				Validate(func(s string) error {
-					if s == "" {
-						return fmt.Errorf("file path cannot be empty")
-					}
-					// TODO: Add more robust validation (e.g., check if file exists)
-					return nil
-				}).
-				Value(&m.FilePath), // Direct binding
+			newFilePathField(prdFormKeyFile, "PRD File Path", "Path to the Product Requirements Document.", &m.FilePath, prdFileTypes),
 
 			huh.NewInput().
 				Key(prdFormKeyOutput).
 				Title("Output File Path").
-				Description("Path for the generated tasks file (e.g., tasks.md).").
+				Description("Path for the generated tasks file (e.g., tasks.json).").
 				Prompt("📄 ").
 				Validate(func(s string) error {
 					if s == "" {
@@ -79,26 +81,15 @@ func NewParsePRDModel() *ParsePRDModel {
 				}).
 				Value(&m.OutputPath), // Direct binding
 		),
+		tagPick,
+		tagTyped,
 		huh.NewGroup(
 			huh.NewInput().
 				Key(prdFormKeyNumTasks).
 				Title("Number of Tasks").
-				Description("How many tasks to generate?").
+				Description(fmt.Sprintf("How many tasks to generate? At most %d.", maxCount)).
 				Prompt("🔢 ").
-				Validate(func(s string) error {
-					if s == "" {
-						// Or allow empty to use default, then handle in completion logic
-						return fmt.Errorf("number of tasks cannot be empty")
-					}
-					val, err := strconv.Atoi(s)
-					if err != nil {
-						return fmt.Errorf("must be a valid integer")
-					}
-					if val <= 0 {
-						return fmt.Errorf("must be greater than 0")
-					}
-					return nil
-				}).
+				Validate(func(s string) error { return validateCount(s, "number of tasks", maxCount) }).
 				Value(&numTasksStr), // Use temporary string, parse on completion
 
 			huh.NewConfirm().
@@ -117,6 +108,7 @@ func NewParsePRDModel() *ParsePRDModel {
 				Negative("No").
 				Value(&m.Append), // Direct binding
 		),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
 	).WithTheme(huh.ThemeDracula())
 
 	return m
@@ -130,10 +122,50 @@ func (m *ParsePRDModel) Init() tea.Cmd {
 }
 
 func (m *ParsePRDModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		if msg.stream == m.output.stream {
+			m.progress.observe(msg.line)
+		}
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case parsePRDCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		m.progress.finish(msg.result.Success)
+		if m.cancel.finish() {
+			m.status = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.OutputPath)
+		}
+		if msg.result.Success && msg.appended != "" {
+			m.status = fmt.Sprintf("✅ Success! %s", msg.appended)
+			m.result.setContent(msg.result.Output)
+		} else if msg.result.Success {
+			m.status = "✅ Success!"
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.status = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
 	if m.isProcessing {
-		// If processing, only allow exiting or handling specific processing messages.
+		// If processing, only allow cancelling, exiting, or handling specific processing messages.
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
 			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.status = "Cancelling..."
+				}
 			case "ctrl+c", "q": // Allow quitting during processing
 				return m, tea.Quit
 			}
@@ -141,6 +173,30 @@ func (m *ParsePRDModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.status = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
 	var cmds []tea.Cmd
 
 	// Process the form.
@@ -149,7 +205,7 @@ func (m *ParsePRDModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.form = updatedForm
 	} else {
 		m.status = "Error: Form update returned unexpected type."
-		fmt.Fprintf(os.Stderr, "Critical Error: form update did not return *huh.Form. Got: %T\n", formModel)
+		debugLog.Error("form update did not return *huh.Form", "file", "parse_prd_form.go", "type", fmt.Sprintf("%T", formModel))
 		return m, tea.Quit // Critical error, exit
 	}
 	cmds = append(cmds, cmd)
@@ -172,7 +228,7 @@ func (m *ParsePRDModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.status = "Executing parse-prd command..."
 		m.isProcessing = true
 
-		return m, m.executeParsePRDCommand()
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeParsePRDCommand))
 	}
 
 	if m.form.State == huh.StateAborted {
@@ -181,14 +237,6 @@ func (m *ParsePRDModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
-	case parsePRDCompleteMsg:
-		m.isProcessing = false
-		if msg.result.Success {
-			m.status = fmt.Sprintf("✅ Success!\n\n%s", msg.result.Output)
-		} else {
-			m.status = fmt.Sprintf("❌ Error: %s\n\n%s", msg.result.Error, msg.result.Output)
-		}
-		return m, nil
 	case tea.KeyMsg:
 		switch msg.String() {
 		// Standard Bubble Tea quit behavior, respects form's own ctrl+c handling.
@@ -199,10 +247,6 @@ func (m *ParsePRDModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, func() tea.Msg { return backToMenuMsg{} }
 			}
 		}
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		// If `huh.Form` has a SetWidth or similar, call it here.
-		// For now, this is for the ParsePRDModel's View method.
 	}
 
 	return m, tea.Batch(cmds...)
@@ -219,18 +263,26 @@ func (m *ParsePRDModel) View() string {
 
 	if m.status != "" {
 		viewBuilder.WriteString("\n\n")
-		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205")) // Default status color
-		if strings.HasPrefix(m.status, "Error:") {
-			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196")) // Red for errors
-		}
-		viewBuilder.WriteString(statusStyle.Render(m.status))
+		viewBuilder.WriteString(renderStatus(m.status))
+	}
+
+	if bar := m.progress.View(m.width); bar != "" && (m.isProcessing || m.form.State == huh.StateCompleted) {
+		viewBuilder.WriteString("\n\n" + bar)
 	}
 
 	helpStyle := lipgloss.NewStyle().Faint(true)
 	if m.isProcessing {
-		viewBuilder.WriteString(helpStyle.Render("\n\nProcessing... Press Ctrl+C to force quit."))
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
 	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.status, "✅") {
-		viewBuilder.WriteString(helpStyle.Render("\n\nCommand completed! Press Esc to return to main menu."))
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
 	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
 		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
 	}
@@ -241,6 +293,8 @@ func (m *ParsePRDModel) View() string {
 		Render(viewBuilder.String())
 }
 
+func (m *ParsePRDModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
 // GetFormValues can be called after the form is completed and processing is done (or before processing starts)
 // to get the structured data.
 func (m *ParsePRDModel) GetFormValues() (map[string]interface{}, error) {
@@ -249,11 +303,13 @@ func (m *ParsePRDModel) GetFormValues() (map[string]interface{}, error) {
 	}
 	// Values are already parsed and stored in m.FilePath, m.OutputPath, etc.
 	return map[string]interface{}{
-		prdFormKeyFile:     m.FilePath,
-		prdFormKeyOutput:   m.OutputPath,
-		prdFormKeyNumTasks: m.NumTasks,
-		prdFormKeyForce:    m.Force,
-		prdFormKeyAppend:   m.Append,
+		prdFormKeyFile:       m.FilePath,
+		prdFormKeyOutput:     m.OutputPath,
+		prdFormKeyNumTasks:   m.NumTasks,
+		prdFormKeyForce:      m.Force,
+		prdFormKeyAppend:     m.Append,
+		prdFormKeyTag:        m.Tag,
+		advancedFlagsFormKey: m.AdvancedFlags,
 	}, nil
 }
 
@@ -263,15 +319,76 @@ func (m *ParsePRDModel) GetFormValues() (map[string]interface{}, error) {
 
 // parsePRDCompleteMsg is sent when the command execution is complete
 type parsePRDCompleteMsg struct {
-	result CLIResult
+	result   CLIResult
+	appended string // Summary of the task IDs added in append mode, if known
 }
 
 // executeParsePRDCommand executes the actual parse-prd CLI command
+// In append mode the output file is read before and after so the new ID range can be reported
 func (m *ParsePRDModel) executeParsePRDCommand() tea.Cmd {
-	return func() tea.Msg {
-		result := cliExecutor.ParsePRD(m.FilePath, m.OutputPath, m.NumTasks, m.Force, m.Append)
-		return parsePRDCompleteMsg{result: result}
+	stream := m.output.start()
+	m.progress.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags).WithTag(m.Tag)
+
+		var before *TasksFile
+		if m.Append {
+			before, _ = LoadTasksFile(m.OutputPath) // A missing file just means nothing to append to
+		}
+
+		result := executor.ParsePRD(m.FilePath, m.OutputPath, m.NumTasks, m.Force, m.Append)
+		msg := parsePRDCompleteMsg{result: result}
+
+		if m.Append && result.Success {
+			if after, err := LoadTasksFile(m.OutputPath); err == nil {
+				msg.appended = describeAppendedTasks(before, after)
+			}
+		}
+		return msg
+	})
+}
+
+// appendedIDRange returns the lowest and highest task IDs present in after but not in before.
+// ok is false when no tasks were added.
+func appendedIDRange(before, after *TasksFile) (first, last int, ok bool) {
+	existing := make(map[int]bool)
+	if before != nil {
+		for _, task := range before.Tasks {
+			existing[task.ID] = true
+		}
+	}
+	for _, task := range after.Tasks {
+		if existing[task.ID] {
+			continue
+		}
+		if !ok || task.ID < first {
+			first = task.ID
+		}
+		if !ok || task.ID > last {
+			last = task.ID
+		}
+		ok = true
+	}
+	return first, last, ok
+}
+
+// describeAppendedTasks summarizes an append run, e.g. "Added tasks 11–18 (10 → 18 tasks)".
+func describeAppendedTasks(before, after *TasksFile) string {
+	first, last, ok := appendedIDRange(before, after)
+	if !ok {
+		return "No new tasks were added."
+	}
+	beforeCount := 0
+	if before != nil {
+		beforeCount = len(before.Tasks)
+	}
+	counts := fmt.Sprintf("(%d → %d tasks)", beforeCount, len(after.Tasks))
+	if first == last {
+		return fmt.Sprintf("Added task %d %s", first, counts)
 	}
+	return fmt.Sprintf("Added tasks %d–%d %s", first, last, counts)
 }
 
 // Ensure ParsePRDModel implements tea.Model.
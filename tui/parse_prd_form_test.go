@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func tasksWithIDs(ids ...int) *TasksFile {
+	tf := &TasksFile{}
+	for _, id := range ids {
+		tf.Tasks = append(tf.Tasks, Task{ID: id})
+	}
+	return tf
+}
+
+func TestAppendedIDRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		before    *TasksFile
+		after     *TasksFile
+		wantFirst int
+		wantLast  int
+		wantOK    bool
+	}{
+		{name: "appended range", before: tasksWithIDs(1, 2, 3), after: tasksWithIDs(1, 2, 3, 4, 5, 6), wantFirst: 4, wantLast: 6, wantOK: true},
+		{name: "single task", before: tasksWithIDs(1, 2), after: tasksWithIDs(1, 2, 3), wantFirst: 3, wantLast: 3, wantOK: true},
+		{name: "no previous file", before: nil, after: tasksWithIDs(1, 2), wantFirst: 1, wantLast: 2, wantOK: true},
+		{name: "nothing added", before: tasksWithIDs(1, 2), after: tasksWithIDs(1, 2)},
+		{name: "unordered IDs", before: tasksWithIDs(1), after: tasksWithIDs(1, 9, 7, 8), wantFirst: 7, wantLast: 9, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last, ok := appendedIDRange(tt.before, tt.after)
+			if first != tt.wantFirst || last != tt.wantLast || ok != tt.wantOK {
+				t.Errorf("appendedIDRange() = (%d, %d, %v), want (%d, %d, %v)", first, last, ok, tt.wantFirst, tt.wantLast, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestDescribeAppendedTasks(t *testing.T) {
+	got := describeAppendedTasks(tasksWithIDs(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), tasksWithIDs(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18))
+	want := "Added tasks 11–18 (10 → 18 tasks)"
+	if got != want {
+		t.Errorf("describeAppendedTasks() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/huh"
+)
+
+// taskPriorityOptions are the priorities a task can be given, highest first.
+func taskPriorityOptions() []huh.Option[TaskPriority] {
+	return []huh.Option[TaskPriority]{
+		huh.NewOption("High", PriorityHigh),
+		huh.NewOption("Medium", PriorityMedium),
+		huh.NewOption("Low", PriorityLow),
+	}
+}
+
+// validatePriority checks that s is one of the TaskPriority values.
+func validatePriority(s string) error {
+	switch TaskPriority(s) {
+	case PriorityHigh, PriorityMedium, PriorityLow:
+		return nil
+	}
+	return fmt.Errorf("invalid priority %q: use %s, %s or %s", s, PriorityHigh, PriorityMedium, PriorityLow)
+}
+
+// setPriorityPrompt asks update-task for a priority change and nothing else.
+func setPriorityPrompt(priority string) string {
+	return fmt.Sprintf("Set this task's priority to %s. Change nothing else about the task.", priority)
+}
+
+// SetPriority changes a task's priority. The CLI has no command for it, so
+// update-task is asked to make the change, which needs an AI provider. The
+// file is read again afterwards and the command only succeeds if the
+// priority really changed. Subtasks can't be updated by update-task.
+func (e *CLIExecutor) SetPriority(filePath, taskID, priority string) CLIResult {
+	notRun := func(err error) CLIResult {
+		return CLIResult{
+			Success: false,
+			Error:   err.Error(),
+			Message: fmt.Sprintf("Command not run: %s", err.Error()),
+		}
+	}
+	if err := validatePriority(priority); err != nil {
+		return notRun(err)
+	}
+	if err := validateTopLevelTaskID(filePath, taskID); err != nil {
+		return notRun(err)
+	}
+	before := taskPriority(filePath, taskID)
+	if before == priority {
+		return CLIResult{Success: true, Message: fmt.Sprintf("Task %s is already %s priority", taskID, priority)}
+	}
+
+	result := e.UpdateOneTask(filePath, taskID, setPriorityPrompt(priority), false)
+	if !result.Success || e.DryRun {
+		return result
+	}
+	if after := taskPriority(filePath, taskID); after != priority {
+		result.Success = false
+		result.Error = fmt.Sprintf("task %s's priority is %s, not %s", taskID, after, priority)
+		result.Message = fmt.Sprintf("update-task ran but didn't change the priority: %s", result.Error)
+		return result
+	}
+	result.Message = fmt.Sprintf("Task %s priority: %s → %s", taskID, before, priority)
+	return result
+}
+
+// taskPriority returns the priority of a task in the tasks file, medium if it
+// has none as the CLI assumes, or "" if the task can't be read.
+func taskPriority(filePath, taskID string) string {
+	tf, err := LoadTasksFile(filePath)
+	if err != nil {
+		return ""
+	}
+	task, ok := tf.Find(taskID)
+	if !ok {
+		return ""
+	}
+	if task.Priority == "" {
+		return string(PriorityMedium)
+	}
+	return task.Priority
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// rewritingRunner records commands like fakeRunner and replaces the tasks
+// file, as the CLI would, when one runs.
+type rewritingRunner struct {
+	fakeRunner
+	path, contents string
+}
+
+func (r *rewritingRunner) Run(name string, args ...string) ([]byte, []byte, error, int) {
+	os.WriteFile(r.path, []byte(r.contents), 0o644)
+	return r.fakeRunner.Run(name, args...)
+}
+
+func TestSetPriority(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	const tasks = `{"tasks": [{"id": 1, "title": "Setup", "priority": "low", "subtasks": [{"id": 1, "title": "Repo"}]}, {"id": 2, "title": "Docs"}]}`
+	runner := &rewritingRunner{path: path}
+	useFakeRunner(t, &runner.fakeRunner)
+	cliExecutor = cliExecutor.WithRunner(runner)
+	cliExecutor.WorkDir = t.TempDir() // No model config, so no provider key is needed
+
+	tests := []struct {
+		name, id, priority, rewritten string
+		wantRun, wantSuccess          bool
+		wantMessage                   string
+	}{
+		{name: "invalid priority", id: "1", priority: "urgent", wantMessage: "invalid priority"},
+		{name: "subtask", id: "1.1", priority: "high", wantMessage: "not subtasks"},
+		{name: "several tasks", id: "1,2", priority: "high", wantMessage: "not a list"},
+		{name: "missing task", id: "7", priority: "high", wantMessage: "task 7 not found"},
+		{name: "unchanged default", id: "2", priority: "medium", wantSuccess: true, wantMessage: "already medium"},
+		{
+			name: "changed", id: "1", priority: "high", wantRun: true, wantSuccess: true, wantMessage: "low → high",
+			rewritten: strings.Replace(tasks, `"low"`, `"high"`, 1),
+		},
+		{name: "not changed by the AI", id: "1", priority: "high", rewritten: tasks, wantRun: true, wantMessage: "didn't change the priority"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeTasks(t, path, tasks)
+			runner.contents, runner.calls = tt.rewritten, nil
+
+			result := cliExecutor.SetPriority(path, tt.id, tt.priority)
+			if ran := len(runner.calls) > 0; ran != tt.wantRun {
+				t.Errorf("ran %q, want a run: %v", runner.calls, tt.wantRun)
+			} else if ran && strings.Join(runner.calls[0][2:7], " ") != "update-task "+path+" 1 --prompt "+setPriorityPrompt("high") {
+				t.Errorf("ran %q, want update-task with the priority prompt", runner.calls[0])
+			}
+			if result.Success != tt.wantSuccess || !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("SetPriority() = %+v, want success %v and a message containing %q", result, tt.wantSuccess, tt.wantMessage)
+			}
+		})
+	}
+}
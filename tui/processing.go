@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// elapsedStyle renders the time a command has been running.
+var elapsedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+
+// processingIndicator animates a spinner while a form's command is running,
+// with the time it has been running. The spinner's ticks redraw the view, so
+// the time stays current without a timer of its own.
+type processingIndicator struct {
+	spinner spinner.Model
+	active  bool
+	started time.Time
+}
+
+// start resets the spinner and returns the command that begins ticking it.
+func (p *processingIndicator) start() tea.Cmd {
+	p.spinner = spinner.New(
+		spinner.WithSpinner(spinner.Dot),
+		spinner.WithStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("205"))),
+	)
+	p.active = true
+	p.started = time.Now()
+	return p.spinner.Tick
+}
+
+// stop halts the spinner; pending ticks are dropped.
+func (p *processingIndicator) stop() {
+	p.active = false
+}
+
+// update advances the spinner and schedules the next tick while active.
+func (p *processingIndicator) update(msg spinner.TickMsg) tea.Cmd {
+	if !p.active {
+		return nil
+	}
+	var cmd tea.Cmd
+	p.spinner, cmd = p.spinner.Update(msg)
+	return cmd
+}
+
+// View renders the spinner frame and the elapsed time.
+func (p *processingIndicator) View() string {
+	if !p.active {
+		return ""
+	}
+	return p.spinner.View() + " " + elapsedStyle.Render(formatElapsed(time.Since(p.started)))
+}
+
+// formatElapsed formats a duration as mm:ss, or h:mm:ss from an hour.
+func formatElapsed(d time.Duration) string {
+	seconds := int(d / time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestFormatElapsed(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "00:00"},
+		{5*time.Second + 900*time.Millisecond, "00:05"},
+		{83 * time.Second, "01:23"},
+		{59*time.Minute + 59*time.Second, "59:59"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "1:02:03"},
+	}
+	for _, tt := range tests {
+		if got := formatElapsed(tt.d); got != tt.want {
+			t.Errorf("formatElapsed(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestProcessingIndicatorShowsElapsed(t *testing.T) {
+	var p processingIndicator
+	if got := p.View(); got != "" {
+		t.Errorf("idle indicator View() = %q, want empty", got)
+	}
+	p.start()
+	p.started = time.Now().Add(-90 * time.Second)
+	if got := p.View(); !strings.HasSuffix(ansi.Strip(got), " 01:30") {
+		t.Errorf("View() = %q, want the elapsed time 01:30", got)
+	}
+	p.stop()
+	if got := p.View(); got != "" {
+		t.Errorf("stopped indicator View() = %q, want empty", got)
+	}
+}
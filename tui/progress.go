@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// defaultProgressPattern matches the progress lines the CLI prints while
+// generating, e.g. "Generated 3/10 tasks". Its two groups are the count done
+// and the total.
+const defaultProgressPattern = `(?i)generated\s+(\d+)\s*/\s*(\d+)`
+
+// progressBarWidth is the widest the progress bar is drawn.
+const progressBarWidth = 40
+
+// Styles for the filled and empty parts of the progress bar
+var (
+	progressFilledStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	progressEmptyStyle  = lipgloss.NewStyle().Faint(true)
+)
+
+// progressPattern returns the pattern for progress lines: the one in the
+// settings if it compiles and has the two groups, otherwise the default.
+func progressPattern() *regexp.Regexp {
+	s, err := LoadSettings()
+	if err == nil && s.ProgressPattern != "" {
+		if re, err := regexp.Compile(s.ProgressPattern); err == nil && re.NumSubexp() >= 2 {
+			return re
+		}
+	}
+	return regexp.MustCompile(defaultProgressPattern)
+}
+
+// validateProgressPattern checks that s is empty, meaning the default, or a
+// regular expression progressPattern can use.
+func validateProgressPattern(s string) error {
+	if s == "" {
+		return nil
+	}
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return fmt.Errorf("invalid regular expression: %w", err)
+	}
+	if re.NumSubexp() < 2 {
+		return fmt.Errorf("the pattern needs two groups, the count done and the total")
+	}
+	return nil
+}
+
+// parseProgress returns the fraction done reported by line, if it is a
+// progress line.
+func parseProgress(re *regexp.Regexp, line string) (float64, bool) {
+	match := re.FindStringSubmatch(ansi.Strip(line))
+	if len(match) < 3 {
+		return 0, false
+	}
+	done, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	total, err := strconv.Atoi(match[2])
+	if err != nil || total <= 0 {
+		return 0, false
+	}
+	return min(float64(done)/float64(total), 1), true
+}
+
+// progressBar follows the progress lines in a running command's output. It
+// stays hidden until the first one arrives.
+type progressBar struct {
+	pattern *regexp.Regexp
+	percent float64
+	seen    bool
+}
+
+// start resets the bar for a new run.
+func (p *progressBar) start() {
+	p.pattern = progressPattern()
+	p.percent = 0
+	p.seen = false
+}
+
+// observe updates the bar from a line of output.
+func (p *progressBar) observe(line string) {
+	if p.pattern == nil {
+		return
+	}
+	if percent, ok := parseProgress(p.pattern, line); ok {
+		p.percent = percent
+		p.seen = true
+	}
+}
+
+// finish fills the bar when the command succeeded and hides it otherwise.
+func (p *progressBar) finish(success bool) {
+	if success {
+		p.percent = 1
+	} else {
+		p.seen = false
+	}
+}
+
+// View renders the bar and percentage to fit width, or "" while hidden.
+func (p *progressBar) View(width int) string {
+	if !p.seen {
+		return ""
+	}
+	barWidth := progressBarWidth
+	if width > 0 {
+		barWidth = max(min(barWidth, width-10), 10) // Room for the padding and percentage
+	}
+	filled := int(p.percent * float64(barWidth))
+	return progressFilledStyle.Render(strings.Repeat("█", filled)) +
+		progressEmptyStyle.Render(strings.Repeat("░", barWidth-filled)) +
+		fmt.Sprintf(" %3.0f%%", p.percent*100)
+}
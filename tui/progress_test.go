@@ -0,0 +1,79 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestParseProgress(t *testing.T) {
+	re := regexp.MustCompile(defaultProgressPattern)
+	tests := []struct {
+		line   string
+		want   float64
+		wantOK bool
+	}{
+		{line: "Generated 3/10 tasks", want: 0.3, wantOK: true},
+		{line: "  generated 2 / 4 subtasks for task 5", want: 0.5, wantOK: true},
+		{line: "\x1b[32mGenerated 10/10 tasks\x1b[0m", want: 1, wantOK: true},
+		{line: "Generated 12/10 tasks", want: 1, wantOK: true},
+		{line: "Generated 0/0 tasks"},
+		{line: "Attempt 1/3 calling generateText"},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseProgress(re, tt.line)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("parseProgress(%q) = %v, %v, want %v, %v", tt.line, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestProgressPatternFromSettings(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if err := SaveSettings(Settings{ProgressPattern: `Step (\d+) of (\d+)`}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := parseProgress(progressPattern(), "Step 2 of 8"); !ok {
+		t.Error("configured pattern not used")
+	}
+
+	// A pattern without the two groups falls back to the default
+	if err := SaveSettings(Settings{ProgressPattern: `Step \d+`}); err != nil {
+		t.Fatal(err)
+	}
+	if got := progressPattern().String(); got != defaultProgressPattern {
+		t.Errorf("progressPattern() = %q, want the default", got)
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	var bar progressBar
+	bar.start()
+	bar.observe("Parsing PRD...")
+	if got := bar.View(80); got != "" {
+		t.Errorf("View() before any progress = %q, want hidden", got)
+	}
+
+	bar.observe("Generated 1/4 tasks")
+	if got := ansi.Strip(bar.View(80)); !strings.HasSuffix(got, " 25%") {
+		t.Errorf("View() = %q, want 25%%", got)
+	}
+
+	bar.finish(true)
+	if got := ansi.Strip(bar.View(80)); !strings.HasSuffix(got, "100%") || strings.Contains(got, "░") {
+		t.Errorf("View() after success = %q, want a full bar", got)
+	}
+
+	bar.start()
+	bar.observe("Generated 1/4 tasks")
+	bar.finish(false)
+	if got := bar.View(80); got != "" {
+		t.Errorf("View() after failure = %q, want hidden", got)
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// providerKeyEnv maps each AI provider to the environment variable holding its
+// API key, matching the CLI. Providers without an entry need no key.
+var providerKeyEnv = map[string]string{
+	"anthropic":  "ANTHROPIC_API_KEY",
+	"openai":     "OPENAI_API_KEY",
+	"google":     "GOOGLE_API_KEY",
+	"perplexity": "PERPLEXITY_API_KEY",
+	"mistral":    "MISTRAL_API_KEY",
+	"azure":      "AZURE_OPENAI_API_KEY",
+	"openrouter": "OPENROUTER_API_KEY",
+	"xai":        "XAI_API_KEY",
+}
+
+// forwardedEnvVars are the provider settings passed to the CLI. Any that are
+// missing from the TUI's environment are read from the project's .env file,
+// since launchers don't always source the user's shell profile.
+var forwardedEnvVars = []string{
+	"ANTHROPIC_API_KEY",
+	"OPENAI_API_KEY",
+	"GOOGLE_API_KEY",
+	"PERPLEXITY_API_KEY",
+	"MISTRAL_API_KEY",
+	"AZURE_OPENAI_API_KEY",
+	"OPENROUTER_API_KEY",
+	"XAI_API_KEY",
+	"VERTEX_PROJECT_ID",
+	"VERTEX_LOCATION",
+	"GOOGLE_APPLICATION_CREDENTIALS",
+}
+
+// placeholderKeyPattern matches the dummy values in .env.example, which the CLI
+// also treats as unset.
+var placeholderKeyPattern = regexp.MustCompile(`^YOUR_.*_HERE$`)
+
+// WithEnv returns a copy of the executor that adds the given KEY=VALUE pairs to
+// the environment of every command, overriding inherited values.
+func (e *CLIExecutor) WithEnv(pairs ...string) *CLIExecutor {
+	withEnv := *e
+	withEnv.extraEnv = append(append([]string(nil), e.extraEnv...), pairs...)
+	return &withEnv
+}
+
+// commandEnv builds the environment commands run with: the TUI's own, then
+// provider settings from .env that it lacks, then the executor's extra pairs.
+func (e *CLIExecutor) commandEnv() []string {
+	env := os.Environ()
+	dotEnv := readDotEnv(filepath.Join(commandDir(), ".env"))
+	for _, key := range forwardedEnvVars {
+		if lookupEnv(env, key) != "" {
+			continue
+		}
+		if value := dotEnv[key]; value != "" {
+			env = append(env, key+"="+value)
+		}
+	}
+	return append(env, e.extraEnv...)
+}
+
+// requireProviderKeys checks that the API key for the main model's provider,
+// and the research model's when research is used, is available. If the model
+// configuration can't be read the check is skipped and the CLI has the final say.
+func (e *CLIExecutor) requireProviderKeys(useResearch bool) error {
+	if e.DryRun {
+		return nil // Nothing is sent to a provider
+	}
+	cfg, err := loadModelConfig()
+	if err != nil {
+		return nil
+	}
+	roles := []string{modelRoleMain}
+	if useResearch {
+		roles = append(roles, modelRoleResearch)
+	}
+	return missingProviderKey(e.commandEnv(), cfg, roles...)
+}
+
+// missingProviderKey reports the first role whose provider's API key is unset
+// or still a placeholder in env.
+func missingProviderKey(env []string, cfg *modelConfig, roles ...string) error {
+	for _, role := range roles {
+		provider := cfg.Models[role].Provider
+		key, ok := providerKeyEnv[provider]
+		if !ok {
+			continue
+		}
+		if value := lookupEnv(env, key); value == "" || placeholderKeyPattern.MatchString(value) {
+			return fmt.Errorf("%s is not set; the %s model uses %s. Set it in your environment or the project's .env file", key, role, provider)
+		}
+	}
+	return nil
+}
+
+// missingKeyResult is the result of a command that was not run because an API key is missing.
+func missingKeyResult(err error) CLIResult {
+	return CLIResult{
+		Success: false,
+		Error:   err.Error(),
+		Message: fmt.Sprintf("Missing API key: %s", err.Error()),
+	}
+}
+
+// lookupEnv returns the value of key in env. Later entries win, as they do for exec.
+func lookupEnv(env []string, key string) string {
+	value := ""
+	for _, entry := range env {
+		if k, v, ok := strings.Cut(entry, "="); ok && k == key {
+			value = v
+		}
+	}
+	return value
+}
+
+// readDotEnv reads the KEY=VALUE lines of a .env file, skipping comments and
+// removing surrounding quotes. A missing file yields no values.
+func readDotEnv(path string) map[string]string {
+	values := make(map[string]string)
+	file, err := os.Open(path)
+	if err != nil {
+		return values
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[strings.TrimSpace(key)] = value
+	}
+	return values
+}
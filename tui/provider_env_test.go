@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMissingProviderKey(t *testing.T) {
+	cfg := &modelConfig{Models: map[string]modelSetting{
+		modelRoleMain:     {Provider: "anthropic", ModelID: "claude-3-7-sonnet-20250219"},
+		modelRoleResearch: {Provider: "perplexity", ModelID: "sonar-pro"},
+		modelRoleFallback: {Provider: "ollama", ModelID: "llama3"},
+	}}
+
+	tests := []struct {
+		name    string
+		env     []string
+		roles   []string
+		wantErr bool
+	}{
+		{name: "key set", env: []string{"ANTHROPIC_API_KEY=sk-123"}, roles: []string{modelRoleMain}},
+		{name: "key missing", env: []string{"OPENAI_API_KEY=sk-123"}, roles: []string{modelRoleMain}, wantErr: true},
+		{name: "placeholder", env: []string{"ANTHROPIC_API_KEY=YOUR_ANTHROPIC_KEY_HERE"}, roles: []string{modelRoleMain}, wantErr: true},
+		{name: "later entry wins", env: []string{"ANTHROPIC_API_KEY=sk-123", "ANTHROPIC_API_KEY="}, roles: []string{modelRoleMain}, wantErr: true},
+		{name: "research key missing", env: []string{"ANTHROPIC_API_KEY=sk-123"}, roles: []string{modelRoleMain, modelRoleResearch}, wantErr: true},
+		{name: "provider without key", env: nil, roles: []string{modelRoleFallback}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := missingProviderKey(tt.env, cfg, tt.roles...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("missingProviderKey(%v) error = %v, wantErr %v", tt.env, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadDotEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	content := "# API keys\nANTHROPIC_API_KEY=sk-123\nexport OPENAI_API_KEY=\"sk-456\"\nXAI_API_KEY='sk-789'\n\nnot a pair\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"ANTHROPIC_API_KEY": "sk-123",
+		"OPENAI_API_KEY":    "sk-456",
+		"XAI_API_KEY":       "sk-789",
+	}
+	if got := readDotEnv(path); !reflect.DeepEqual(got, want) {
+		t.Errorf("readDotEnv() = %v, want %v", got, want)
+	}
+	if got := readDotEnv(filepath.Join(t.TempDir(), ".env")); len(got) != 0 {
+		t.Errorf("readDotEnv(missing) = %v, want empty", got)
+	}
+}
+
+func TestWithEnv(t *testing.T) {
+	base := NewCLIExecutor().WithEnv("A=1")
+	extended := base.WithEnv("B=2")
+
+	if got := lookupEnv(extended.commandEnv(), "B"); got != "2" {
+		t.Errorf("commandEnv B = %q, want %q", got, "2")
+	}
+	if got := lookupEnv(base.commandEnv(), "B"); got != "" {
+		t.Errorf("WithEnv modified the original executor: B = %q", got)
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const recentFilesFormKeyFile = "file"
+
+// RecentFilesModel holds the state for picking one of the recent tasks files.
+type RecentFilesModel struct {
+	form      *huh.Form
+	aborted   bool
+	statusMsg string
+	width     int
+	height    int
+
+	// Form values
+	FilePath string
+}
+
+// NewRecentFilesForm creates a new form listing the tasks files used by
+// recent commands, so one can be picked instead of typed.
+func NewRecentFilesForm() *RecentFilesModel {
+	m := &RecentFilesModel{FilePath: lastFilePath()}
+
+	files := recentFiles()
+	if len(files) == 0 {
+		m.statusMsg = "No recent files yet. Files are added when a command on them succeeds."
+	}
+	options := make([]huh.Option[string], 0, len(files))
+	for _, file := range files {
+		options = append(options, huh.NewOption(file, file))
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key(recentFilesFormKeyFile).
+				Title("Recent Files").
+				Description("Forms start with the picked tasks file for the rest of the session.").
+				Options(options...).
+				Value(&m.FilePath),
+		),
+	).WithTheme(huh.ThemeDracula())
+
+	return m
+}
+
+func (m *RecentFilesModel) Init() tea.Cmd {
+	m.aborted = false
+	return m.form.Init()
+}
+
+func (m *RecentFilesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height)
+	}
+
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "esc", "enter":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, nil
+	}
+
+	var cmds []tea.Cmd
+	formModel, cmd := m.form.Update(msg)
+	if updatedForm, ok := formModel.(*huh.Form); ok {
+		m.form = updatedForm
+	} else {
+		m.statusMsg = "Error: Form update returned unexpected type."
+		debugLog.Error("form update did not return *huh.Form", "file", "recent_files_form.go", "type", fmt.Sprintf("%T", formModel))
+		return m, tea.Quit
+	}
+	cmds = append(cmds, cmd)
+
+	if m.form.State == huh.StateCompleted {
+		if m.FilePath == "" {
+			// Nothing to pick from
+			m.aborted = true
+			return m, func() tea.Msg { return backToMenuMsg{} }
+		}
+		useFileForSession(m.FilePath)
+		m.statusMsg = fmt.Sprintf("✅ Forms will start with %s.", m.FilePath)
+		return m, nil
+	}
+
+	if m.form.State == huh.StateAborted {
+		m.aborted = true
+		return m, func() tea.Msg { return backToMenuMsg{} }
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.aborted = true
+			return m, func() tea.Msg { return backToMenuMsg{} }
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *RecentFilesModel) View() string {
+	if m.aborted {
+		return "Form aborted. Returning to main menu..."
+	}
+
+	var viewBuilder strings.Builder
+	viewBuilder.WriteString(m.form.View())
+
+	if m.statusMsg != "" {
+		viewBuilder.WriteString("\n\n")
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
+	}
+
+	helpStyle := lipgloss.NewStyle().Faint(true)
+	if m.form.State == huh.StateCompleted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Enter or Esc to return to main menu."))
+	} else if m.form.State != huh.StateAborted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Padding(1, 2).
+		Render(viewBuilder.String())
+}
+
+func (m *RecentFilesModel) keyContext() keyContext { return formKeyContext(m.form, false) }
+
+// GetFormValues retrieves the structured data after completion.
+func (m *RecentFilesModel) GetFormValues() (map[string]interface{}, error) {
+	if m.form.State != huh.StateCompleted {
+		return nil, fmt.Errorf("form is not yet completed")
+	}
+	return map[string]interface{}{
+		recentFilesFormKeyFile: m.FilePath,
+	}, nil
+}
+
+var _ tea.Model = &RecentFilesModel{}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	reopenTaskFormKeyFile    = "file"
+	reopenTaskFormKeyID      = "id"
+	reopenTaskFormKeyConfirm = "confirm"
+)
+
+// ReopenTaskModel holds the state for the quick action that moves a done task
+// back to in-progress.
+type ReopenTaskModel struct {
+	form         *huh.Form
+	aborted      bool
+	isProcessing bool
+	statusMsg    string
+	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
+
+	// Form values
+	FilePath  string
+	TaskID    string
+	Confirmed bool // Reopening a checkpoint has been confirmed
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
+}
+
+// NewReopenTaskForm creates a new form that sets a task back to in-progress
+// in one step, without the status choice of the set-status form.
+func NewReopenTaskForm() *ReopenTaskModel {
+	m := &ReopenTaskModel{
+		FilePath: lastFilePath(), // Pre-populate with the last used tasks file
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			newFilePathField(reopenTaskFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json).", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		huh.NewGroup(
+			huh.NewInput().
+				Key(reopenTaskFormKeyID).
+				Title("Task ID").
+				DescriptionFunc(func() string { return reopenDescription(m.FilePath, m.TaskID) }, []any{&m.FilePath, &m.TaskID}).
+				Prompt("↩️ ").
+				Validate(func(s string) error {
+					if err := validateTaskID(s); err != nil {
+						return err
+					}
+					return validateTaskExists(m.FilePath, s)
+				}).
+				SuggestionsFunc(taskIDSuggestions(&m.FilePath, true), &m.FilePath).
+				Value(&m.TaskID),
+		),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
+		// Only checkpoints need a second look before reopening
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key(reopenTaskFormKeyConfirm).
+				Title("Reopen Checkpoint").
+				DescriptionFunc(func() string {
+					return fmt.Sprintf("Task %s is a checkpoint. Marking it done again will need its acceptance criteria confirmed again.", m.TaskID)
+				}, &m.TaskID).
+				Affirmative("Reopen").
+				Negative("Cancel").
+				Validate(func(confirmed bool) error {
+					if !confirmed {
+						return fmt.Errorf("choose Reopen to continue, or press Esc to go back")
+					}
+					return nil
+				}).
+				Value(&m.Confirmed),
+		).WithHideFunc(func() bool { return !isCheckpoint(m.FilePath, m.TaskID) }),
+	).WithTheme(huh.ThemeDracula())
+
+	return m
+}
+
+func (m *ReopenTaskModel) Init() tea.Cmd {
+	m.isProcessing = false
+	m.statusMsg = ""
+	m.aborted = false
+	return m.form.Init()
+}
+
+func (m *ReopenTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case reopenTaskCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			m.statusMsg = fmt.Sprintf("✅ Task %s reopened as in-progress.", m.TaskID)
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
+	if m.isProcessing {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, nil
+	}
+
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.statusMsg = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
+	var cmds []tea.Cmd
+	formModel, cmd := m.form.Update(msg)
+	if updatedForm, ok := formModel.(*huh.Form); ok {
+		m.form = updatedForm
+	} else {
+		m.statusMsg = "Error: Form update returned unexpected type."
+		debugLog.Error("form update did not return *huh.Form", "file", "reopen_task_form.go", "type", fmt.Sprintf("%T", formModel))
+		return m, tea.Quit
+	}
+	cmds = append(cmds, cmd)
+
+	if m.form.State == huh.StateCompleted {
+		m.statusMsg = "Executing set-task-status command..."
+		m.isProcessing = true
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeReopenTaskCommand))
+	}
+
+	if m.form.State == huh.StateAborted {
+		m.aborted = true
+		return m, func() tea.Msg { return backToMenuMsg{} }
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if !m.isProcessing {
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			}
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *ReopenTaskModel) View() string {
+	if m.aborted {
+		return "Form aborted. Returning to main menu..."
+	}
+
+	var viewBuilder strings.Builder
+	viewBuilder.WriteString(m.form.View())
+
+	if m.statusMsg != "" {
+		viewBuilder.WriteString("\n\n")
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
+	}
+
+	helpStyle := lipgloss.NewStyle().Faint(true)
+	if m.isProcessing {
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
+	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
+	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Padding(1, 2).
+		Render(viewBuilder.String())
+}
+
+func (m *ReopenTaskModel) keyContext() keyContext {
+	return m.result.keyContext(formKeyContext(m.form, m.isProcessing))
+}
+
+// GetFormValues retrieves the structured data after completion.
+func (m *ReopenTaskModel) GetFormValues() (map[string]interface{}, error) {
+	if m.form.State != huh.StateCompleted {
+		return nil, fmt.Errorf("form is not yet completed")
+	}
+	return map[string]interface{}{
+		reopenTaskFormKeyFile:    m.FilePath,
+		reopenTaskFormKeyID:      m.TaskID,
+		reopenTaskFormKeyConfirm: m.Confirmed,
+		advancedFlagsFormKey:     m.AdvancedFlags,
+	}, nil
+}
+
+// reopenTaskCompleteMsg is sent when the command execution is complete
+type reopenTaskCompleteMsg struct {
+	result CLIResult
+}
+
+// executeReopenTaskCommand sets the task to in-progress
+func (m *ReopenTaskModel) executeReopenTaskCommand() tea.Cmd {
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+		return reopenTaskCompleteMsg{result: executor.SetTaskStatus(m.FilePath, m.TaskID, string(StatusInProgress), false)}
+	})
+}
+
+// reopenDescription describes the task id for the ID field: its title and
+// status, with a note if it isn't done.
+func reopenDescription(filePath, id string) string {
+	const prompt = "ID of the done task or subtask to set back to in-progress."
+	tf, err := LoadTasksFile(filePath)
+	if err != nil {
+		return prompt
+	}
+	task, ok := tf.Find(id)
+	if !ok {
+		return prompt
+	}
+	if task.Status != string(StatusDone) {
+		return fmt.Sprintf("%s: %s\n⚠️  It's %s, not done; reopening sets it to in-progress anyway.", id, task.Title, task.Status)
+	}
+	return fmt.Sprintf("%s: %s", id, task.Title)
+}
+
+// isCheckpoint reports whether id is a checkpoint task in the tasks file. It
+// returns false if the task can't be looked up.
+func isCheckpoint(filePath, id string) bool {
+	tf, err := LoadTasksFile(filePath)
+	if err != nil {
+		return false
+	}
+	task, ok := tf.Find(id)
+	return ok && task.Type == string(TypeCheckpoint)
+}
+
+var _ tea.Model = &ReopenTaskModel{}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestReopenDescription(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	writeTasks(t, path, `{"tasks": [
+		{"id": 1, "title": "Release", "status": "done", "type": "checkpoint"},
+		{"id": 2, "title": "Docs", "status": "pending", "subtasks": [{"id": 1, "title": "Outline", "status": "done"}]}
+	]}`)
+
+	tests := []struct {
+		id, wantDescription string
+		checkpoint          bool
+	}{
+		{id: "1", wantDescription: "1: Release", checkpoint: true},
+		{id: "2", wantDescription: "It's pending, not done"},
+		{id: "2.1", wantDescription: "2.1: Outline"},
+		{id: "9", wantDescription: "ID of the done task"},
+	}
+	for _, tt := range tests {
+		if got := reopenDescription(path, tt.id); !strings.Contains(got, tt.wantDescription) {
+			t.Errorf("reopenDescription(%s) = %q, want it to contain %q", tt.id, got, tt.wantDescription)
+		}
+		if got := isCheckpoint(path, tt.id); got != tt.checkpoint {
+			t.Errorf("isCheckpoint(%s) = %v, want %v", tt.id, got, tt.checkpoint)
+		}
+	}
+}
+
+func TestReopenTaskSetsInProgress(t *testing.T) {
+	fake := &fakeRunner{}
+	useFakeRunner(t, fake)
+
+	m := NewReopenTaskForm()
+	m.FilePath, m.TaskID = "tasks.json", "4"
+	// The batch streams output then runs the command, which sends the result
+	batch := m.executeReopenTaskCommand()().(tea.BatchMsg)
+	m.Update(batch[len(batch)-1]())
+
+	want := []string{"node", "../scripts/dev.js", "set-task-status", "tasks.json", "4", "in-progress"}
+	if len(fake.calls) != 1 || strings.Join(fake.calls[0], " ") != strings.Join(want, " ") {
+		t.Errorf("ran %q, want %q", fake.calls, want)
+	}
+	if m.statusMsg != "✅ Task 4 reopened as in-progress." {
+		t.Errorf("status = %q", m.statusMsg)
+	}
+}
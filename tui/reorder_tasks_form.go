@@ -0,0 +1,411 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	reorderTasksFormKeyFile = "file"
+)
+
+// Keys of the reorder list; j/k and the arrows move the selection as in the task table.
+const (
+	moveTaskUpKey   = "K"
+	moveTaskDownKey = "J"
+	applyOrderKey   = "y" // Confirms applying the new order
+	keepEditingKey  = "n" // Goes back to the list from the confirmation
+)
+
+// ReorderTasksModel holds the state for the reorder tasks view, an
+// interactive list of the tasks in ID order in which tasks are moved up and
+// down. Nothing changes until the new order is confirmed; then the tasks are
+// renumbered to match it with the CLI's move command.
+type ReorderTasksModel struct {
+	form         *huh.Form
+	aborted      bool
+	isProcessing bool
+	statusMsg    string
+	width        int
+	height       int
+	output       liveOutput          // Output streamed while the commands run
+	spinner      processingIndicator // Animated while the commands run
+	result       resultView          // Scrollable output once the order is applied
+	cancel       commandCancel       // Cancels the running commands on Esc
+	list         *taskTable          // Tasks being reordered; nil until the file is read
+	original     []string            // Task IDs in ID order, as the file has them
+	confirming   bool                // Asking whether to apply the new order
+	applied      bool                // The moves have run
+
+	// Form value
+	FilePath string
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
+}
+
+// NewReorderTasksForm creates a new form for reordering a tasks file's tasks.
+func NewReorderTasksForm() *ReorderTasksModel {
+	m := &ReorderTasksModel{
+		FilePath: lastFilePath(), // Pre-populate with the last used tasks file
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			newFilePathField(reorderTasksFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json) to reorder. It is only modified once you confirm the new order.", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
+	).WithTheme(huh.ThemeDracula())
+
+	return m
+}
+
+func (m *ReorderTasksModel) Init() tea.Cmd {
+	m.isProcessing = false
+	m.statusMsg = ""
+	m.aborted = false
+	return m.form.Init()
+}
+
+func (m *ReorderTasksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		parts := []resizer{&m.result, &m.output}
+		if m.list != nil {
+			parts = append(parts, m.list)
+		}
+		handleResize(msg, &m.width, &m.height, parts...)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case reorderTasksCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled. Moves that finished were kept; Undo Last Change reverts them."
+			m.applied = true
+			return m, nil
+		}
+		m.applied = true
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			m.statusMsg = "✅ Success! Tasks reordered."
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
+	if m.isProcessing {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, nil
+	}
+
+	// Once the moves have run, keep showing their result
+	if m.applied {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
+	if m.list != nil {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			return m, m.updateList(keyMsg)
+		}
+		return m, nil
+	}
+
+	var cmds []tea.Cmd
+	formModel, cmd := m.form.Update(msg)
+	if updatedForm, ok := formModel.(*huh.Form); ok {
+		m.form = updatedForm
+	} else {
+		m.statusMsg = "Error: Form update returned unexpected type."
+		debugLog.Error("form update did not return *huh.Form", "file", "reorder_tasks_form.go", "type", fmt.Sprintf("%T", formModel))
+		return m, tea.Quit
+	}
+	cmds = append(cmds, cmd)
+
+	if m.form.State == huh.StateCompleted {
+		// Reading the file is quick, so the list is shown right away
+		tf, err := LoadTasksFile(m.FilePath)
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("Error: failed to read tasks: %v", err)
+			m.form.State = huh.StateNormal // Back to the form to choose another file
+			return m, nil
+		}
+		if len(tf.Tasks) < 2 {
+			m.statusMsg = "Error: the file needs at least two tasks to reorder."
+			m.form.State = huh.StateNormal // Back to the form to choose another file
+			return m, nil
+		}
+		m.statusMsg = ""
+		m.setTasks(tf)
+		return m, nil
+	}
+
+	if m.form.State == huh.StateAborted {
+		m.aborted = true
+		return m, func() tea.Msg { return backToMenuMsg{} }
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.aborted = true
+			return m, func() tea.Msg { return backToMenuMsg{} }
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+// setTasks lists the file's tasks, without their subtasks, in ID order.
+func (m *ReorderTasksModel) setTasks(tf *TasksFile) {
+	sorted := &TasksFile{Tasks: slices.Clone(tf.Tasks)}
+	sort.SliceStable(sorted.Tasks, func(i, j int) bool { return sorted.Tasks[i].ID < sorted.Tasks[j].ID })
+	list := newTaskTable(sorted, nil, nil, false)
+	list.resize(m.width, m.height)
+	m.list = &list
+	m.original = list.rowIDs()
+}
+
+// updateList handles a key while the list is shown: moving the selection or
+// the selected task, and asking for confirmation before applying the order.
+func (m *ReorderTasksModel) updateList(msg tea.KeyMsg) tea.Cmd {
+	if m.confirming {
+		switch msg.String() {
+		case applyOrderKey:
+			m.confirming = false
+			m.statusMsg = "Executing move commands..."
+			m.isProcessing = true
+			return tea.Batch(m.spinner.start(), m.executeReorderTasksCommand())
+		case keepEditingKey, "esc":
+			m.confirming = false
+			m.statusMsg = ""
+		case "ctrl+c":
+			return tea.Quit
+		}
+		return nil
+	}
+
+	// Keys typed into a search are the query's, not commands
+	if m.list.searching() {
+		m.list.update(msg)
+		return nil
+	}
+	switch msg.String() {
+	case "esc":
+		m.aborted = true
+		return func() tea.Msg { return backToMenuMsg{} }
+	case "ctrl+c", "q":
+		return tea.Quit
+	case moveTaskUpKey, "shift+up":
+		m.list.moveRow(-1)
+	case moveTaskDownKey, "shift+down":
+		m.list.moveRow(1)
+	case "enter":
+		moves := reorderMoves(m.original, m.list.rowIDs())
+		if len(moves) == 0 {
+			m.statusMsg = "The order hasn't changed; move a task with J/K first."
+			return nil
+		}
+		m.confirming = true
+		m.statusMsg = fmt.Sprintf("Apply the new order with %d move command(s)? Tasks are renumbered so the first in the list has the lowest ID.", len(moves))
+	default:
+		m.list.update(msg)
+	}
+	return nil
+}
+
+func (m *ReorderTasksModel) View() string {
+	if m.aborted {
+		return "Form aborted. Returning to main menu..."
+	}
+
+	var viewBuilder strings.Builder
+	if m.list == nil {
+		viewBuilder.WriteString(m.form.View())
+	}
+
+	if m.statusMsg != "" {
+		if m.list == nil {
+			viewBuilder.WriteString("\n\n")
+		}
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
+	}
+
+	helpStyle := lipgloss.NewStyle().Faint(true)
+	if m.isProcessing {
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
+	} else if m.applied && strings.HasPrefix(m.statusMsg, "✅") {
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.applied {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu."))
+	} else if m.list != nil {
+		if m.statusMsg != "" {
+			viewBuilder.WriteString("\n\n")
+		}
+		viewBuilder.WriteString(m.list.View())
+		help := "Use ↑/↓ or j/k to select a task, Shift+↑/↓ or J/K to move it, Enter to apply the new order, / to search, Esc to return to main menu without changes."
+		if m.confirming {
+			help = fmt.Sprintf("Press %s to apply the new order, %s or Esc to keep editing.", applyOrderKey, keepEditingKey)
+		} else if status := m.list.search.status(); status != "" {
+			help = status + "\n" + help
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + help))
+	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Padding(1, 2).
+		Render(viewBuilder.String())
+}
+
+func (m *ReorderTasksModel) keyContext() keyContext {
+	switch {
+	case m.list != nil && m.list.searching():
+		return contextSearch
+	case m.isProcessing:
+		return contextProcessing
+	case m.applied:
+		return m.result.keyContext(contextResult)
+	case m.list != nil:
+		return contextResult
+	}
+	return formKeyContext(m.form, false)
+}
+
+// GetFormValues retrieves the structured data after completion.
+func (m *ReorderTasksModel) GetFormValues() (map[string]interface{}, error) {
+	if m.form.State != huh.StateCompleted {
+		return nil, fmt.Errorf("form is not yet completed")
+	}
+	return map[string]interface{}{
+		reorderTasksFormKeyFile: m.FilePath,
+		advancedFlagsFormKey:    m.AdvancedFlags,
+	}, nil
+}
+
+// reorderMoves returns the moves that renumber the tasks with the given IDs,
+// in ascending order, so that the tasks in order take those IDs in turn: the
+// first in order gets the lowest. A task whose ID doesn't change isn't moved.
+//
+// A move can't land on an ID that's taken, so each cycle of renumbering, e.g.
+// 1 → 2 → 3 → 1, parks its first task on a free ID past the last, moves the
+// others along from the end of the cycle and then brings the parked task to
+// its ID.
+func reorderMoves(ids, order []string) []taskMove {
+	target := make(map[string]string, len(order)) // Current ID to new ID
+	free := 0
+	for i, id := range order {
+		target[id] = ids[i]
+		if n, err := strconv.Atoi(id); err == nil {
+			free = max(free, n+1)
+		}
+	}
+	parking := strconv.Itoa(free)
+
+	var moves []taskMove
+	done := make(map[string]bool)
+	for _, start := range ids {
+		if done[start] || target[start] == start {
+			continue
+		}
+		var cycle []string
+		for id := start; !done[id]; id = target[id] {
+			done[id] = true
+			cycle = append(cycle, id)
+		}
+		moves = append(moves, taskMove{from: cycle[0], to: parking})
+		for i := len(cycle) - 1; i > 0; i-- {
+			moves = append(moves, taskMove{from: cycle[i], to: target[cycle[i]]})
+		}
+		moves = append(moves, taskMove{from: parking, to: target[cycle[0]]})
+	}
+	return moves
+}
+
+// reorderTasksCompleteMsg is sent when the move commands are complete
+type reorderTasksCompleteMsg struct {
+	result CLIResult
+}
+
+// executeReorderTasksCommand runs the move commands that apply the new order,
+// one at a time, and summarizes them a line each
+func (m *ReorderTasksModel) executeReorderTasksCommand() tea.Cmd {
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	moves := reorderMoves(m.original, m.list.rowIDs())
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+
+		var results []string
+		var hasError bool
+		var lastError string
+
+		for i, result := range executor.MoveTasks(m.FilePath, moves) {
+			if result.Success {
+				results = append(results, fmt.Sprintf("✅ Moved %s to %s", moves[i].from, moves[i].to))
+			} else {
+				hasError = true
+				if lastError == "" {
+					lastError = result.Error
+				}
+				results = append(results, fmt.Sprintf("❌ Move %s to %s: %s", moves[i].from, moves[i].to, result.Error))
+			}
+		}
+		if hasError {
+			results = append(results, "", "The moves before the failure were kept; Undo Last Change reverts them all.")
+		}
+
+		return reorderTasksCompleteMsg{result: CLIResult{
+			Success: !hasError,
+			Error:   lastError,
+			Output:  strings.Join(results, "\n"),
+		}}
+	})
+}
+
+var _ tea.Model = &ReorderTasksModel{}
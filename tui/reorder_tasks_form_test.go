@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestReorderMoves(t *testing.T) {
+	tests := []struct {
+		order []string
+		want  []taskMove
+	}{
+		{order: []string{"1", "2", "3"}},
+		{order: []string{"2", "1", "3"}, want: []taskMove{{"1", "4"}, {"2", "1"}, {"4", "2"}}},
+		{order: []string{"3", "1", "2"}, want: []taskMove{{"1", "4"}, {"3", "1"}, {"2", "3"}, {"4", "2"}}},
+	}
+	ids := []string{"1", "2", "3"}
+	for _, tt := range tests {
+		moves := reorderMoves(ids, tt.order)
+		if !reflect.DeepEqual(moves, tt.want) {
+			t.Errorf("reorderMoves(%q) = %v, want %v", tt.order, moves, tt.want)
+		}
+
+		// Applying the moves must leave the tasks in order on the IDs, moving none onto a taken ID
+		tasks := map[string]string{"1": "1", "2": "2", "3": "3"}
+		for _, mv := range moves {
+			if _, taken := tasks[mv.to]; taken {
+				t.Fatalf("reorderMoves(%q) moves %s onto taken ID %s", tt.order, mv.from, mv.to)
+			}
+			tasks[mv.to] = tasks[mv.from]
+			delete(tasks, mv.from)
+		}
+		for i, id := range ids {
+			if tasks[id] != tt.order[i] {
+				t.Errorf("after reorderMoves(%q), ID %s holds task %s, want %s", tt.order, id, tasks[id], tt.order[i])
+			}
+		}
+	}
+}
+
+func TestTaskTableMoveRow(t *testing.T) {
+	table := newTaskTable(loadSampleTasks(t), nil, nil, false)
+	table.resize(120, 40)
+
+	if table.moveRow(-1) {
+		t.Error("moved the first row up")
+	}
+	table.moveRow(1)
+	table.moveRow(1)
+	if got, want := table.rowIDs(), []string{"2", "3", "1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+	if table.moveRow(1) {
+		t.Error("moved the last row down")
+	}
+}
+
+func TestMoveTasksStopsAfterFailure(t *testing.T) {
+	fake := &fakeRunner{err: errors.New("boom"), exitCode: 1}
+	useFakeRunner(t, fake)
+
+	results := cliExecutor.MoveTasks(writeSampleTasks(t), []taskMove{{"1", "4"}, {"2", "1"}})
+	if len(fake.calls) != 1 {
+		t.Errorf("ran %q, want only the first move", fake.calls)
+	}
+	if results[0].Success || results[1].Success || !strings.Contains(results[1].Error, "not run") {
+		t.Errorf("results = %+v, want the second move skipped", results)
+	}
+}
+
+func TestMoveTasksRunsTheCLIMoveCommand(t *testing.T) {
+	fake := &fakeRunner{}
+	useFakeRunner(t, fake)
+
+	path := writeSampleTasks(t)
+	cliExecutor.MoveTasks(path, []taskMove{{"1", "4"}, {"2", "1"}})
+	if len(fake.calls) != 2 {
+		t.Fatalf("ran %q, want both moves", fake.calls)
+	}
+	for _, call := range fake.calls {
+		// Each call is node, the CLI script, then the CLI's own arguments
+		checkCLIArgs(t, call[2:])
+	}
+	if want := []string{"move", "--file", path, "--from", "2", "--to", "1"}; !reflect.DeepEqual(fake.calls[1][2:], want) {
+		t.Errorf("second move ran %q, want %q", fake.calls[1][2:], want)
+	}
+}
+
+func TestReorderTasksForm(t *testing.T) {
+	fake := &fakeRunner{}
+	useFakeRunner(t, fake)
+	path := writeSampleTasks(t)
+
+	m := NewReorderTasksForm()
+	m.FilePath = path
+	tf, err := LoadTasksFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.setTasks(tf)
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.confirming {
+		t.Fatal("asked to apply an unchanged order")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("J")})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.confirming || len(fake.calls) != 0 {
+		t.Fatalf("confirming = %v, ran %q; want a confirmation before any move", m.confirming, fake.calls)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	batch := cmd().(tea.BatchMsg)
+	batch = batch[len(batch)-1]().(tea.BatchMsg)
+	m.Update(batch[len(batch)-1]())
+
+	want := [][]string{
+		{"node", "../scripts/dev.js", "move", "--file", path, "--from", "1", "--to", "4"},
+		{"node", "../scripts/dev.js", "move", "--file", path, "--from", "2", "--to", "1"},
+		{"node", "../scripts/dev.js", "move", "--file", path, "--from", "4", "--to", "2"},
+	}
+	if !reflect.DeepEqual(fake.calls, want) {
+		t.Errorf("ran %q, want %q", fake.calls, want)
+	}
+	if !strings.HasPrefix(m.statusMsg, "✅") {
+		t.Errorf("status = %q, want success", m.statusMsg)
+	}
+}
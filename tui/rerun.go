@@ -0,0 +1,40 @@
+package main
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// rerunKey runs a failed command again with the same values.
+const rerunKey = "r"
+
+// rerunHelp is the help line shown under a failed command's error.
+const rerunHelp = "Press r to run the command again, Esc to return to main menu."
+
+// lastRun remembers how a form last started its command, so a run that
+// failed, often for a transient reason such as a flaky AI call, can be
+// repeated without filling in the form again. The form's values can't change
+// once it's completed, so starting the command again runs it as it was.
+type lastRun struct {
+	start  func() tea.Cmd
+	failed bool
+}
+
+// run records how the command is started and starts it.
+func (l *lastRun) run(start func() tea.Cmd) tea.Cmd {
+	l.start = start
+	l.failed = false
+	return start()
+}
+
+// finish records how the run went.
+func (l *lastRun) finish(success bool) {
+	l.failed = !success
+}
+
+// canRerun reports whether the last run failed and can be started again.
+func (l *lastRun) canRerun() bool {
+	return l.failed && l.start != nil
+}
+
+// rerun starts the last command again.
+func (l *lastRun) rerun() tea.Cmd {
+	return l.run(l.start)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+func TestLastRun(t *testing.T) {
+	var l lastRun
+	if l.canRerun() {
+		t.Fatal("canRerun() = true before anything ran")
+	}
+
+	runs := 0
+	l.run(func() tea.Cmd { runs++; return nil })
+	l.finish(true)
+	if l.canRerun() {
+		t.Error("canRerun() = true after a successful run")
+	}
+
+	l.finish(false)
+	if !l.canRerun() {
+		t.Fatal("canRerun() = false after a failed run")
+	}
+	l.rerun()
+	if runs != 2 {
+		t.Errorf("started %d time(s), want 2", runs)
+	}
+	if l.canRerun() {
+		t.Error("canRerun() = true while the rerun is in progress")
+	}
+}
+
+func TestFormRerunsFailedCommand(t *testing.T) {
+	m := NewMoveTaskForm()
+	m.form.State = huh.StateCompleted
+	runs := 0
+	m.last.run(func() tea.Cmd { runs++; return nil })
+	m.isProcessing = true
+
+	m.Update(moveTaskCompleteMsg{result: CLIResult{Success: false, Error: "rate limited"}})
+	if view := m.View(); !strings.Contains(view, rerunHelp) {
+		t.Errorf("View() doesn't offer running the command again:\n%s", view)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(rerunKey)})
+	if runs != 2 || !m.isProcessing {
+		t.Errorf("after %s, started %d time(s), processing = %v; want 2, true", rerunKey, runs, m.isProcessing)
+	}
+
+	m.Update(moveTaskCompleteMsg{result: CLIResult{Success: true, Message: "Moved task 2 to 5"}})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(rerunKey)})
+	if runs != 2 {
+		t.Errorf("%s started a command that succeeded again", rerunKey)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// researchModelFlag selects the research model for a single command, instead
+// of the one configured for the research role.
+const researchModelFlag = "--research-model"
+
+// WithResearchModel returns a copy of the executor whose research commands
+// use model for research. An empty model uses the configured research model.
+func (e *CLIExecutor) WithResearchModel(model string) *CLIExecutor {
+	overridden := *e
+	overridden.researchModel = model
+	return &overridden
+}
+
+// researchModelArgs appends the research model flag to the arguments of a
+// command that does research, when the executor overrides the model. Without
+// research the model isn't used, so the flag is left off.
+func (e *CLIExecutor) researchModelArgs(args []string) []string {
+	if e.researchModel == "" || !slices.Contains(args, "--research") {
+		return args
+	}
+	return append(args[:len(args):len(args)], researchModelFlag, e.researchModel)
+}
+
+// researchModelOptions offers the configured research model, as the empty
+// value, then each model the CLI allows for research.
+func researchModelOptions() []huh.Option[string] {
+	configured := "Configured research model"
+	if cfg, err := loadModelConfig(); err == nil {
+		if setting, ok := cfg.Models[modelRoleResearch]; ok && setting.ModelID != "" {
+			configured = fmt.Sprintf("Configured research model (%s / %s)", setting.Provider, setting.ModelID)
+		}
+	}
+	options := []huh.Option[string]{huh.NewOption(configured, "")}
+
+	models, err := loadSupportedModels()
+	if err != nil {
+		return options // Only the configured model can be offered
+	}
+	for _, model := range modelsForRole(models, modelRoleResearch) {
+		options = append(options, huh.NewOption(fmt.Sprintf("%s / %s", model.Provider, model.ID), model.ID))
+	}
+	return options
+}
+
+// newResearchModelGroup builds the group, placed after a form's research
+// toggle, that picks the research model for this command only. It is hidden
+// while research is off.
+func newResearchModelGroup(key string, useResearch *bool, value *string) *huh.Group {
+	return huh.NewGroup(
+		huh.NewSelect[string]().
+			Key(key).
+			Title("Research Model").
+			Description("Use another research model for this command only; the configuration is unchanged.").
+			Options(researchModelOptions()...).
+			Height(10).
+			Value(value),
+	).WithHideFunc(func() bool { return !*useResearch })
+}
+
+// researchBadgeStyle sets the research badge apart from the status around it.
+var researchBadgeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("141")).Bold(true)
+
+// researchBadge returns a badge for the header of a result from a command
+// that ran with research, so it's clear which runs took the slower, costlier
+// path. It returns "" for a command that ran without.
+func researchBadge(research bool) string {
+	if !research {
+		return ""
+	}
+	return " " + researchBadgeStyle.Render("🔬 research")
+}
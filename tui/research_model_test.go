@@ -0,0 +1,46 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestResearchModelArgs(t *testing.T) {
+	e := &CLIExecutor{}
+	tests := []struct {
+		name string
+		e    *CLIExecutor
+		args []string
+		want []string
+	}{
+		{name: "no model", e: e, args: []string{"expand", "--id=3", "--research"}, want: []string{"expand", "--id=3", "--research"}},
+		{name: "research", e: e.WithResearchModel("sonar-pro"), args: []string{"expand", "--id=3", "--research"}, want: []string{"expand", "--id=3", "--research", "--research-model", "sonar-pro"}},
+		{name: "without research", e: e.WithResearchModel("sonar-pro"), args: []string{"expand", "--id=3"}, want: []string{"expand", "--id=3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.researchModelArgs(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("researchModelArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResearchBadge(t *testing.T) {
+	m := NewUpdateSingleTaskForm()
+	m.Init()
+	m.Research = true
+	m.Update(updateOneTaskCompleteMsg{result: CLIResult{Success: true, Output: "Updated"}})
+	if !strings.Contains(ansi.Strip(m.status), "🔬 research") {
+		t.Errorf("status = %q, want the research badge", m.status)
+	}
+
+	m.Research = false
+	m.Update(updateOneTaskCompleteMsg{result: CLIResult{Success: true, Output: "Updated"}})
+	if m.status != "✅ Success!" {
+		t.Errorf("status without research = %q, want no badge", m.status)
+	}
+}
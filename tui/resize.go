@@ -0,0 +1,19 @@
+package main
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// resizer is a part of a screen that lays itself out to the terminal size.
+type resizer interface {
+	resize(width, height int)
+}
+
+// handleResize records the terminal size from msg in width and height and
+// lays out each part of the screen again to fit it, so scrolling views follow
+// the terminal as it's resized rather than keeping the size they were first
+// drawn at.
+func handleResize(msg tea.WindowSizeMsg, width, height *int, parts ...resizer) {
+	*width, *height = msg.Width, msg.Height
+	for _, part := range parts {
+		part.resize(msg.Width, msg.Height)
+	}
+}
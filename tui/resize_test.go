@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestHandleResize(t *testing.T) {
+	m := NewMoveTaskForm()
+	m.result.setContent(strings.Repeat("line\n", 100))
+	m.Update(tea.WindowSizeMsg{Width: 60, Height: resultViewChrome + 10})
+	if m.width != 60 || m.height != resultViewChrome+10 {
+		t.Errorf("size = %dx%d, want 60x%d", m.width, m.height, resultViewChrome+10)
+	}
+	if m.result.viewport.Height != 10 {
+		t.Errorf("result height = %d, want 10", m.result.viewport.Height)
+	}
+
+	m.Update(tea.WindowSizeMsg{Width: 60, Height: resultViewChrome + 4})
+	if m.result.viewport.Height != 4 {
+		t.Errorf("result height after shrinking = %d, want 4", m.result.viewport.Height)
+	}
+}
+
+func TestLiveOutputFitsTerminal(t *testing.T) {
+	var o liveOutput
+	stream := o.start()
+	for i := 0; i < 20; i++ {
+		o.handle(outputLineMsg{stream: stream, line: fmt.Sprintf("line %d %s", i+1, strings.Repeat("x", 100))})
+	}
+	if got := strings.Count(o.View(), "\n") + 1; got != liveOutputLines {
+		t.Errorf("showed %d lines before the size is known, want %d", got, liveOutputLines)
+	}
+
+	o.resize(40, resultViewChrome+3)
+	lines := strings.Split(ansi.Strip(o.View()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[2], "line 20") {
+		t.Errorf("View() = %q, want the last 3 lines", lines)
+	}
+	for _, line := range lines {
+		if w := ansi.StringWidth(line); w > 36 {
+			t.Errorf("line %q is %d wide, want it cut to 36", line, w)
+		}
+	}
+}
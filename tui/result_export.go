@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// exportKey saves the last command's full result as JSON.
+const exportKey = "x"
+
+// resultRecord is a command's full result as saved by exportKey, with what
+// was run, for attaching to bug reports or reading with other tools.
+type resultRecord struct {
+	Time   time.Time `json:"time"`
+	Args   []string  `json:"args"`
+	Dir    string    `json:"dir"`
+	Result CLIResult `json:"result"`
+}
+
+// lastResult is the result of the command that finished last, nil before any
+// has. Commands of a batch finish concurrently, hence the mutex.
+var (
+	lastResultMu sync.Mutex
+	lastResult   *resultRecord
+)
+
+// rememberResult records the result of a command for exportKey.
+func rememberResult(record resultRecord) {
+	lastResultMu.Lock()
+	defer lastResultMu.Unlock()
+	lastResult = &record
+}
+
+// resultExportedMsg reports the outcome of saving a result.
+type resultExportedMsg struct {
+	path string
+	err  error
+}
+
+// exportLastResult saves the last command's result to a timestamped file in
+// the directory commands run in, reporting the outcome in a resultExportedMsg.
+func exportLastResult() tea.Cmd {
+	lastResultMu.Lock()
+	record := lastResult
+	lastResultMu.Unlock()
+	return func() tea.Msg {
+		if record == nil {
+			return resultExportedMsg{err: fmt.Errorf("no command has run yet")}
+		}
+		path := exportResultPath(commandDir(), time.Now())
+		return resultExportedMsg{path: path, err: writeResultRecord(path, *record)}
+	}
+}
+
+// exportResultPath returns the file a result exported at now is saved to in dir.
+func exportResultPath(dir string, now time.Time) string {
+	return filepath.Join(dir, "taskmaster-result-"+now.Format("20060102-150405")+".json")
+}
+
+// writeResultRecord writes record to path as indented JSON.
+func writeResultRecord(path string, record resultRecord) error {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// exportNotice describes the outcome of saving a result.
+func exportNotice(msg resultExportedMsg) string {
+	if msg.err != nil {
+		return fmt.Sprintf("Couldn't save the result: %v.", msg.err)
+	}
+	return fmt.Sprintf("Saved the result to %s.", msg.path)
+}
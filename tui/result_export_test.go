@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestExportLastResult(t *testing.T) {
+	fake := &fakeRunner{stdout: "Task 3: Write docs\n", stderr: "warning: slow\n", exitCode: 2}
+	useFakeRunner(t, fake)
+	cliExecutor.WorkDir = t.TempDir()
+	cliExecutor.ShowTask("tasks.json", "3")
+
+	msg := exportLastResult()().(resultExportedMsg)
+	if msg.err != nil {
+		t.Fatalf("exportLastResult() error: %v", msg.err)
+	}
+	if dir := filepath.Dir(msg.path); dir != cliExecutor.WorkDir {
+		t.Errorf("saved to %s, want a file in %s", msg.path, cliExecutor.WorkDir)
+	}
+	data, err := os.ReadFile(msg.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record resultRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatalf("saved result isn't JSON: %v", err)
+	}
+	if got := strings.Join(record.Args, " "); got != "node ../scripts/dev.js show-task tasks.json 3" {
+		t.Errorf("saved args = %q", got)
+	}
+	if record.Result.ExitCode != 2 || record.Result.Stderr != "warning: slow\n" || !strings.Contains(record.Result.Output, "Write docs") {
+		t.Errorf("saved result = %+v", record.Result)
+	}
+}
+
+func TestExportResultFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := exportResultPath(filepath.Join(dir, "missing"), time.Date(2026, 10, 15, 13, 4, 5, 0, time.UTC))
+	if want := filepath.Join(dir, "missing", "taskmaster-result-20261015-130405.json"); path != want {
+		t.Errorf("exportResultPath() = %q, want %q", path, want)
+	}
+
+	var r resultView // A failed command shows no output but can still be exported
+	r.update(resultExportedMsg{path: path, err: writeResultRecord(path, resultRecord{})})
+	if !strings.HasPrefix(r.failedHelp(), "Couldn't save the result:") {
+		t.Errorf("failedHelp() = %q, want the failure explained", r.failedHelp())
+	}
+	if cmd := r.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(exportKey)}); cmd == nil {
+		t.Error("export key without output returned no command")
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Styles for status messages and the failure panel
+var (
+	statusStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	errorStatusStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	failureHeaderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
+	failureLabelStyle  = lipgloss.NewStyle().Bold(true)
+	failureStderrStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+	failureStdoutStyle = lipgloss.NewStyle().Faint(true)
+)
+
+// renderResult renders a command's result for a form's status area. A failure
+// becomes a panel: the error and exit code as a header, then what the command
+// wrote to stderr, then its stdout dimmed. A success is its output as is.
+func renderResult(result CLIResult) string {
+	if result.Success {
+		return result.Output
+	}
+
+	header := "❌ Error: " + result.Error
+	if result.ExitCode > 0 {
+		header += fmt.Sprintf(" (exit code %d)", result.ExitCode)
+	}
+	sections := []string{failureHeaderStyle.Render(header)}
+
+	stderr := strings.TrimRight(result.Stderr, "\n")
+	stdout := strings.TrimRight(result.Stdout, "\n")
+	if stderr == "" && stdout == "" {
+		// Not from a single CLI run, e.g. a batch or a command that wasn't run
+		stdout = strings.TrimRight(result.Output, "\n")
+	}
+	if stderr != "" {
+		sections = append(sections, failureLabelStyle.Render("Stderr:")+"\n"+renderLines(failureStderrStyle, stderr))
+	}
+	if stdout != "" {
+		sections = append(sections, failureLabelStyle.Render("Output:")+"\n"+renderLines(failureStdoutStyle, stdout))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// renderLines styles each line of text separately, so lines aren't padded to
+// the width of the longest.
+func renderLines(style lipgloss.Style, text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = style.Render(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderStatus styles a form's status message: errors in red, a rendered
+// failure panel as it is, anything else in the status color.
+func renderStatus(status string) string {
+	switch {
+	case strings.HasPrefix(ansi.Strip(status), "❌"):
+		return status
+	case strings.HasPrefix(status, "Error:"):
+		return errorStatusStyle.Render(status)
+	default:
+		return statusStyle.Render(status)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRenderResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		result CLIResult
+		want   string
+	}{
+		{
+			name:   "success",
+			result: CLIResult{Success: true, Output: "Task 1 done"},
+			want:   "Task 1 done",
+		},
+		{
+			name: "failed run",
+			result: CLIResult{
+				Error:    "exit status 1",
+				ExitCode: 1,
+				Output:   "Loading tasks\nError: Task 9 not found\n",
+				Stdout:   "Loading tasks\n",
+				Stderr:   "Error: Task 9 not found\n",
+			},
+			want: "❌ Error: exit status 1 (exit code 1)\n\nStderr:\nError: Task 9 not found\n\nOutput:\nLoading tasks",
+		},
+		{
+			name:   "not run",
+			result: CLIResult{Error: "another taskmaster-tui instance appears to be editing this file"},
+			want:   "❌ Error: another taskmaster-tui instance appears to be editing this file",
+		},
+		{
+			name:   "batch",
+			result: CLIResult{Error: "exit status 1", Output: "✅ Task 1: ok\n❌ Task 2: exit status 1"},
+			want:   "❌ Error: exit status 1\n\nOutput:\n✅ Task 1: ok\n❌ Task 2: exit status 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ansi.Strip(renderResult(tt.result)); got != tt.want {
+				t.Errorf("renderResult() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderStatusKeepsFailurePanel(t *testing.T) {
+	panel := renderResult(CLIResult{Error: "exit status 1", Stderr: "boom"})
+	if got := renderStatus(panel); got != panel {
+		t.Errorf("renderStatus() restyled the failure panel: %q", got)
+	}
+	if got := renderStatus("Error: bad input"); !strings.Contains(got, "Error: bad input") {
+		t.Errorf("renderStatus() = %q, want the message kept", got)
+	}
+}
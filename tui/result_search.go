@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// searchKey starts typing a search, as in less.
+const searchKey = "/"
+
+// Keys that move to the next and previous match of a search.
+const (
+	nextMatchKey = "n"
+	prevMatchKey = "N"
+)
+
+var (
+	searchMatchStyle   = lipgloss.NewStyle().Background(lipgloss.Color("58"))
+	searchCurrentStyle = lipgloss.NewStyle().Background(lipgloss.Color("214")).Foreground(lipgloss.Color("0"))
+)
+
+// outputSearch is an incremental, case-insensitive search over lines of
+// output. "/" starts typing a query and the matches update with each key;
+// Enter keeps the query so n and N can move between its matches, and Esc
+// drops it.
+type outputSearch struct {
+	typing  bool
+	query   string
+	matches []int // Indexes of the matching lines
+	current int   // Index into matches of the match moved to
+}
+
+// handleKey applies a key to the search and reports whether it used it.
+// While a query is being typed every key is used.
+func (s *outputSearch) handleKey(msg tea.KeyMsg) bool {
+	if !s.typing {
+		switch msg.String() {
+		case searchKey:
+			s.typing, s.query, s.matches, s.current = true, "", nil, 0
+			return true
+		case nextMatchKey, prevMatchKey:
+			if len(s.matches) == 0 {
+				return false
+			}
+			step := 1
+			if msg.String() == prevMatchKey {
+				step = -1
+			}
+			s.current = (s.current + step + len(s.matches)) % len(s.matches)
+			return true
+		}
+		return false
+	}
+
+	switch msg.Type {
+	case tea.KeyEnter:
+		s.typing = false
+	case tea.KeyEsc:
+		s.typing, s.query, s.matches, s.current = false, "", nil, 0
+	case tea.KeyBackspace:
+		if r := []rune(s.query); len(r) > 0 {
+			s.query = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		s.query += " "
+	case tea.KeyRunes:
+		s.query += string(msg.Runes)
+	}
+	return true
+}
+
+// active reports whether there is a query to show matches for.
+func (s *outputSearch) active() bool {
+	return s.query != ""
+}
+
+// find records which of lines match the query, ignoring styling, keeping the
+// current match on the first match at or after from so an incremental search
+// moves forward from where the reader is.
+func (s *outputSearch) find(lines []string, from int) {
+	s.matches = nil
+	if !s.active() {
+		return
+	}
+	query := strings.ToLower(s.query)
+	for i, line := range lines {
+		if strings.Contains(strings.ToLower(ansi.Strip(line)), query) {
+			s.matches = append(s.matches, i)
+		}
+	}
+	s.current = 0
+	for i, line := range s.matches {
+		if line >= from {
+			s.current = i
+			break
+		}
+	}
+}
+
+// currentLine returns the line of the current match.
+func (s *outputSearch) currentLine() (int, bool) {
+	if len(s.matches) == 0 {
+		return 0, false
+	}
+	return s.matches[s.current], true
+}
+
+// highlight marks each occurrence of the query in lines, the current match's
+// line more strongly. Highlighted lines lose their other styling.
+func (s *outputSearch) highlight(lines []string) {
+	current, _ := s.currentLine()
+	for _, i := range s.matches {
+		style := searchMatchStyle
+		if i == current {
+			style = searchCurrentStyle
+		}
+		lines[i] = highlightMatches(ansi.Strip(lines[i]), s.query, style)
+	}
+}
+
+// highlightMatches renders each case-insensitive occurrence of query in line with style.
+func highlightMatches(line, query string, style lipgloss.Style) string {
+	lower, q := strings.ToLower(line), strings.ToLower(query)
+	if len(lower) != len(line) {
+		// Lowercasing changed the byte lengths, so indexes wouldn't line up
+		return style.Render(line)
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, q)
+		if i < 0 {
+			b.WriteString(line)
+			return b.String()
+		}
+		b.WriteString(line[:i])
+		b.WriteString(style.Render(line[i : i+len(q)]))
+		line, lower = line[i+len(q):], lower[i+len(q):]
+	}
+}
+
+// status describes the search for the help line, or "" without one.
+func (s *outputSearch) status() string {
+	switch {
+	case s.typing:
+		return fmt.Sprintf("/%s▏ (Enter to keep, Esc to clear)", s.query)
+	case !s.active():
+		return ""
+	case len(s.matches) == 0:
+		return fmt.Sprintf("/%s: no matches.", s.query)
+	default:
+		return fmt.Sprintf("/%s: match %d of %d, n/N for next/previous.", s.query, s.current+1, len(s.matches))
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// typeSearch types "/", query and Enter as keys.
+func typeSearch(update func(tea.KeyMsg), query string) {
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(searchKey)})
+	for _, r := range query {
+		update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	update(tea.KeyMsg{Type: tea.KeyEnter})
+}
+
+func TestOutputSearch(t *testing.T) {
+	lines := []string{"Setup done", "Core", removedDependencyStyle.Render("DONE: models"), "UI", "done soon"}
+	var s outputSearch
+	typeSearch(func(msg tea.KeyMsg) {
+		s.handleKey(msg)
+		s.find(lines, 1)
+	}, "done")
+
+	if s.typing {
+		t.Error("still typing after Enter")
+	}
+	if fmt.Sprint(s.matches) != "[0 2 4]" {
+		t.Errorf("matches = %v, want [0 2 4], ignoring case and styling", s.matches)
+	}
+	if line, _ := s.currentLine(); line != 2 {
+		t.Errorf("current line = %d, want 2, the first match from line 1", line)
+	}
+
+	next := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(nextMatchKey)}
+	prev := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(prevMatchKey)}
+	s.handleKey(next)
+	s.handleKey(next)
+	if line, _ := s.currentLine(); line != 0 {
+		t.Errorf("current line after n past the last match = %d, want it to wrap to 0", line)
+	}
+	s.handleKey(prev)
+	if line, _ := s.currentLine(); line != 4 {
+		t.Errorf("current line after N = %d, want 4", line)
+	}
+	if got := s.status(); got != "/done: match 3 of 3, n/N for next/previous." {
+		t.Errorf("status() = %q", got)
+	}
+
+	s.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(searchKey)})
+	s.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if s.active() || s.handleKey(next) {
+		t.Error("Esc didn't clear the search")
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+	got := highlightMatches("Done, done", "DONE", searchMatchStyle)
+	if ansi.Strip(got) != "Done, done" {
+		t.Errorf("highlightMatches changed the text: %q", ansi.Strip(got))
+	}
+	if want := searchMatchStyle.Render("done"); !strings.HasSuffix(got, want) {
+		t.Errorf("highlightMatches(...) = %q, want the matches styled", got)
+	}
+}
+
+func TestResultViewSearchScrolls(t *testing.T) {
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	var r resultView
+	r.setContent(strings.Join(lines, "\n"))
+	r.resize(80, resultViewChrome+10)
+
+	typeSearch(func(msg tea.KeyMsg) { r.update(msg) }, "line 80")
+	if !strings.Contains(ansi.Strip(r.View()), "line 80") || strings.Contains(ansi.Strip(r.View()), "line 1\n") {
+		t.Errorf("View() = %q, want it scrolled to the match", ansi.Strip(r.View()))
+	}
+	if !strings.HasPrefix(r.help(), "/line 80: match 1 of 1") {
+		t.Errorf("help() = %q, want the search status", r.help())
+	}
+
+	r.setContent("other output")
+	if r.search.active() {
+		t.Error("new content kept the old search")
+	}
+}
+
+func TestTaskTableSearch(t *testing.T) {
+	table := newTaskTable(loadSampleTasks(t), nil, nil, true)
+	typeSearch(func(msg tea.KeyMsg) { table.update(msg) }, "styl")
+	if id := table.rows[table.cursor][taskColumnID]; id != "3.2" {
+		t.Errorf("cursor on %s after searching, want 3.2", id)
+	}
+
+	typeSearch(func(msg tea.KeyMsg) { table.update(msg) }, "pending")
+	table.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(nextMatchKey)})
+	if id := table.rows[table.cursor][taskColumnID]; id != "2" {
+		t.Errorf("cursor on %s after n from the last row, want it to wrap to 2", id)
+	}
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// resultViewChrome is the number of lines a completed form draws around its
+// result: vertical padding, the status line, blank separators and help text.
+const resultViewChrome = 9
+
+// resultView shows a command's output in a scrollable viewport so results
+// longer than the terminal can be read in full.
+type resultView struct {
+	viewport      viewport.Model
+	content       string
+	width, height int    // Terminal size; zero until the first WindowSizeMsg
+	notice        string // Outcome of the last copy, edit or export, shown until the next key
+	full          bool   // Show all of a long output rather than its first lines
+	search        outputSearch
+}
+
+// copyKey copies the output to the system clipboard.
+const copyKey = "c"
+
+// fullOutputKey toggles between a long output's first lines and all of it.
+const fullOutputKey = "f"
+
+// resultTruncateLines is how many lines of a longer output are shown until
+// the rest is asked for; wrapping and scrolling thousands of lines is slow.
+const resultTruncateLines = 500
+
+// clipboardCopiedMsg reports the outcome of copying the output to the clipboard.
+type clipboardCopiedMsg struct {
+	err error
+}
+
+// writeClipboard is the clipboard used by copyOutput, replaced in tests.
+var writeClipboard = clipboard.WriteAll
+
+// copyOutput copies text to the system clipboard, reporting the outcome in a
+// clipboardCopiedMsg. It runs as a command since it may start a helper program.
+func copyOutput(text string) tea.Cmd {
+	return func() tea.Msg {
+		return clipboardCopiedMsg{err: writeClipboard(text)}
+	}
+}
+
+// setContent replaces the output shown and scrolls back to the top.
+func (r *resultView) setContent(content string) {
+	r.content = content
+	r.notice = ""
+	r.full = false
+	r.search = outputSearch{}
+	r.viewport = viewport.New(0, 0)
+	r.layout()
+}
+
+// resize fits the viewport to a new terminal size.
+func (r *resultView) resize(width, height int) {
+	r.width, r.height = width, height
+	r.layout()
+}
+
+func (r *resultView) layout() {
+	// Subtract the horizontal padding forms render with
+	r.viewport.Width = max(r.width-4, 1)
+	shown := colorKeywords(r.shown())
+	if r.width > 0 {
+		shown = wrapOutput(shown, r.viewport.Width)
+	}
+	if r.search.active() {
+		// Matches are of display lines, so they stay in step with scrolling
+		lines := strings.Split(shown, "\n")
+		from := r.viewport.YOffset
+		if line, ok := r.search.currentLine(); ok {
+			from = line
+		}
+		r.search.find(lines, from)
+		r.search.highlight(lines)
+		shown = strings.Join(lines, "\n")
+	}
+	r.viewport.SetContent(shown)
+	// Shrink to the content so short results are not padded with blank lines
+	r.viewport.Height = max(min(r.height-resultViewChrome, r.viewport.TotalLineCount()), 1)
+}
+
+// scrollToMatch scrolls the current search match to the middle of the viewport.
+func (r *resultView) scrollToMatch() {
+	if line, ok := r.search.currentLine(); ok {
+		r.viewport.SetYOffset(max(line-r.viewport.Height/2, 0))
+	}
+}
+
+// searching reports whether a search query is being typed, when every key
+// belongs to the search rather than the form.
+func (r *resultView) searching() bool {
+	return r.search.typing
+}
+
+// hiddenLines returns how many lines of the output are left out while it is truncated.
+func (r *resultView) hiddenLines() int {
+	if r.full {
+		return 0
+	}
+	return max(strings.Count(r.content, "\n")+1-resultTruncateLines, 0)
+}
+
+// shown returns the output as displayed: all of it, or its first lines and a
+// footer saying how many more there are.
+func (r *resultView) shown() string {
+	hidden := r.hiddenLines()
+	if hidden == 0 {
+		return r.content
+	}
+	lines := strings.SplitN(r.content, "\n", resultTruncateLines+1)
+	return strings.Join(lines[:resultTruncateLines], "\n") +
+		fmt.Sprintf("\n... (%d more lines, press %s for full)", hidden, fullOutputKey)
+}
+
+// truncatable reports whether the output is long enough to be truncated.
+func (r *resultView) truncatable() bool {
+	return strings.Count(r.content, "\n")+1 > resultTruncateLines
+}
+
+// scrollable reports whether the output is taller than the space available.
+func (r *resultView) scrollable() bool {
+	return r.height > 0 && r.viewport.TotalLineCount() > r.viewport.Height
+}
+
+// help describes the keys available while the result is shown, after the
+// outcome of the last copy if there is one.
+func (r *resultView) help() string {
+	help := "Command completed! Press Esc to return to main menu."
+	if r.scrollable() {
+		help = "Command completed! Use ↑/↓ or PgUp/PgDn to scroll, / to search, Esc to return to main menu."
+	}
+	return r.withCopyHelp(help)
+}
+
+// withCopyHelp adds the copy and export keys, the full output toggle for a
+// long output, and the outcome of the last copy, to help.
+func (r *resultView) withCopyHelp(help string) string {
+	if r.content != "" {
+		help += " Press c to copy the output."
+	}
+	help += fmt.Sprintf(" Press %s to save the full result as JSON.", exportKey)
+	if r.truncatable() {
+		if r.full {
+			help += " Press f to show only the first lines."
+		} else {
+			help += " Press f to show the full output."
+		}
+	}
+	if r.notice != "" {
+		help = r.notice + " " + help
+	}
+	if status := r.search.status(); status != "" {
+		help = status + "\n" + help
+	}
+	return help
+}
+
+// failedHelp is the help line shown under a failed command's error, with the
+// export key and the outcome of the last export.
+func (r *resultView) failedHelp() string {
+	help := rerunHelp + fmt.Sprintf(" Press %s to save the full result as JSON.", exportKey)
+	if r.notice != "" {
+		help = r.notice + " " + help
+	}
+	return help
+}
+
+// update handles scrolling keys, copying the output and exporting the result.
+func (r *resultView) update(msg tea.Msg) tea.Cmd {
+	// A failed command has no output to show but its result can be exported
+	switch msg := msg.(type) {
+	case resultExportedMsg:
+		r.notice = exportNotice(msg)
+		return nil
+	case tea.KeyMsg:
+		if msg.String() == exportKey && !r.searching() {
+			r.notice = ""
+			return exportLastResult()
+		}
+	}
+	if r.content == "" {
+		return nil
+	}
+	switch msg := msg.(type) {
+	case clipboardCopiedMsg:
+		if msg.err != nil {
+			// Usual over SSH or without a display, where there is no clipboard
+			r.notice = fmt.Sprintf("Couldn't copy to the clipboard: %v.", msg.err)
+		} else {
+			r.notice = "Copied!"
+		}
+		return nil
+	case tea.KeyMsg:
+		r.notice = ""
+		if r.search.handleKey(msg) {
+			r.layout()
+			r.scrollToMatch()
+			return nil
+		}
+		switch {
+		case msg.String() == copyKey:
+			return copyOutput(ansi.Strip(r.content))
+		case msg.String() == fullOutputKey && r.truncatable():
+			// The whole output is kept, so toggling doesn't run the command again
+			r.full = !r.full
+			r.layout()
+			return nil
+		}
+	}
+	var cmd tea.Cmd
+	r.viewport, cmd = r.viewport.Update(msg)
+	return cmd
+}
+
+// View renders the visible part of the output. Before the terminal size is
+// known the output is shown without scrolling.
+func (r *resultView) View() string {
+	if r.content == "" || r.height <= 0 {
+		return r.shown()
+	}
+	return r.viewport.View()
+}
+
+// resultMarkers are the status prefixes output lines may start with. Wrapped
+// text is indented past them so it lines up with the message.
+var resultMarkers = []string{"✅ ", "❌ "}
+
+// wrapOutput wraps each line of output at word boundaries to fit width,
+// indenting continuation lines to match the line's own indentation and
+// status marker.
+func wrapOutput(output string, width int) string {
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		if ansi.StringWidth(line) <= width {
+			continue
+		}
+		text := strings.TrimLeft(line, " \t")
+		prefix := strings.ReplaceAll(line[:len(line)-len(text)], "\t", "    ")
+		for _, marker := range resultMarkers {
+			if strings.HasPrefix(text, marker) {
+				prefix += marker
+				text = strings.TrimPrefix(text, marker)
+				break
+			}
+		}
+
+		prefixWidth := ansi.StringWidth(prefix)
+		if prefixWidth > width/2 {
+			// Too deep to indent and still leave room for the text
+			lines[i] = ansi.Wrap(line, width, "")
+			continue
+		}
+		hanging := strings.Repeat(" ", prefixWidth)
+		wrapped := strings.Split(ansi.Wrap(text, width-prefixWidth, ""), "\n")
+		for j := range wrapped {
+			if j == 0 {
+				wrapped[j] = prefix + wrapped[j]
+			} else {
+				wrapped[j] = hanging + wrapped[j]
+			}
+		}
+		lines[i] = strings.Join(wrapped, "\n")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// keyContext returns contextSearch while a search is being typed, and
+// otherwise ctx, the context of the form showing the result.
+func (r *resultView) keyContext(ctx keyContext) keyContext {
+	if r.searching() {
+		return contextSearch
+	}
+	return ctx
+}
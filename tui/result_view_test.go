@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestResultViewCopy(t *testing.T) {
+	var copied string
+	saved := writeClipboard
+	writeClipboard = func(text string) error {
+		copied = text
+		return nil
+	}
+	t.Cleanup(func() { writeClipboard = saved })
+
+	var r resultView
+	r.setContent(removedDependencyStyle.Render("- Removing 3") + "\nDone")
+
+	cmd := r.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(copyKey)})
+	if cmd == nil {
+		t.Fatal("copy key returned no command")
+	}
+	r.update(cmd())
+	if copied != "- Removing 3\nDone" {
+		t.Errorf("copied %q, want the output without styling", copied)
+	}
+	if !strings.HasPrefix(r.help(), "Copied!") {
+		t.Errorf("help() = %q, want the copy confirmed", r.help())
+	}
+
+	r.update(clipboardCopiedMsg{err: errors.New("no clipboard utilities available")})
+	if !strings.Contains(r.help(), "Couldn't copy to the clipboard") {
+		t.Errorf("help() = %q, want the copy failure explained", r.help())
+	}
+}
+
+func TestResultViewTruncatesLongOutput(t *testing.T) {
+	lines := make([]string, resultTruncateLines+250)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	var r resultView
+	r.setContent(strings.Join(lines, "\n"))
+
+	got := r.View()
+	if !strings.HasSuffix(got, "line 500\n... (250 more lines, press f for full)") || strings.Contains(got, "line 501") {
+		t.Errorf("View() ends with %q, want the first lines and a footer", got[len(got)-80:])
+	}
+	if !strings.Contains(r.help(), "Press f to show the full output.") {
+		t.Errorf("help() = %q, want the full output key", r.help())
+	}
+
+	fullKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(fullOutputKey)}
+	r.update(fullKey)
+	if got := r.View(); !strings.HasSuffix(got, "line 750") {
+		t.Errorf("full View() ends with %q, want every line", got[len(got)-20:])
+	}
+	r.update(fullKey)
+	if got := r.View(); strings.Contains(got, "line 750") {
+		t.Errorf("View() after toggling back shows every line")
+	}
+
+	// Short output has nothing to toggle
+	r.setContent("Done")
+	r.update(fullKey)
+	if got := r.View(); got != "Done" || strings.Contains(r.help(), "full output") {
+		t.Errorf("short output View() = %q, help() = %q", got, r.help())
+	}
+}
+
+func TestWrapOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		width  int
+		want   string
+	}{
+		{
+			name:   "fits",
+			output: "Task 1: Set up repo",
+			width:  40,
+			want:   "Task 1: Set up repo",
+		},
+		{
+			name:   "word boundaries",
+			output: "Implement the login page with OAuth",
+			width:  16,
+			want:   "Implement the\nlogin page with\nOAuth",
+		},
+		{
+			name:   "keeps indentation",
+			output: "  Details: add a retry loop around the call",
+			width:  20,
+			want:   "  Details: add a\n  retry loop around\n  the call",
+		},
+		{
+			name:   "lines up after marker",
+			output: "✅ Task 2: marked as done successfully",
+			width:  20,
+			want:   "✅ Task 2: marked as\n   done successfully",
+		},
+		{
+			name:   "each line separately",
+			output: "short\n❌ Task 9: not found in tasks file",
+			width:  20,
+			want:   "short\n❌ Task 9: not found\n   in tasks file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapOutput(tt.output, tt.width); got != tt.want {
+				t.Errorf("wrapOutput() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
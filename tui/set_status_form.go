@@ -2,19 +2,24 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 )
 
 const (
-	setStatusFormKeyFile         = "file"
-	setStatusFormKeyIDs          = "ids" // Comma-separated task IDs
-	setStatusFormKeyStatus       = "status"
+	setStatusFormKeyFile        = "file"
+	setStatusFormKeyIDs         = "ids" // Comma-separated task IDs
+	setStatusFormKeySelectedIDs = "selected-ids"
+	setStatusFormKeyStatus      = "status"
 	setStatusFormKeyCriteriaMet = "criteria-met"
+	setStatusFormKeyByStatus    = "by-status"
+	setStatusFormKeyFromStatus  = "from-status"
+	setStatusFormKeyConfirm     = "confirm"
+	setStatusFormKeyCheckpoint  = "checkpoint-criteria"
 )
 
 // TaskStatus represents the possible statuses for a task.
@@ -34,36 +39,80 @@ type SetStatusModel struct {
 	isProcessing bool
 	statusMsg    string // Renamed from 'status' to avoid conflict with form field
 	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
 
 	// Form values
-	FilePath     string
-	TaskIDs      string // Comma-separated string of task IDs
-	NewStatus    TaskStatus
+	FilePath    string
+	TaskIDs     string   // Comma-separated string of task IDs, used when the file can't be parsed
+	SelectedIDs []string // Task IDs picked from the tasks file
+	NewStatus   TaskStatus
 	CriteriaMet bool
+	ByStatus    bool       // Update every task with FromStatus instead of picking tasks
+	FromStatus  TaskStatus // Current status of the tasks to update when ByStatus is set
+	Confirmed   bool       // The tasks found by status have been reviewed
+
+	CheckpointConfirmed bool // Criteria confirmed when asked about checkpoints set to done
+
+	tasks taskChoices // Tasks offered by the multi-select
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
 }
 
 // NewSetStatusForm creates a new form for the set-status command.
 func NewSetStatusForm() *SetStatusModel {
 	m := &SetStatusModel{
-		NewStatus:   StatusTodo, // Default status
-		CriteriaMet: false,      // Default for criteria met
+		FilePath:    lastFilePath(), // Pre-populate with the last used tasks file
+		NewStatus:   StatusTodo,     // Default status
+		CriteriaMet: false,          // Default for criteria met
+		FromStatus:  StatusReview,
+		tasks:       taskChoices{includeSubtasks: true},
 	}
 
 	m.form = huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().
-				Key(setStatusFormKeyFile).
-				Title("Tasks File Path").
-				Description("Path to the tasks file (e.g., tasks.md).").
-				Prompt("📄 ").
-				Validate(func(s string) error {
-					if s == "" {
-						return fmt.Errorf("tasks file path cannot be empty")
+			newFilePathField(setStatusFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json).", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		// Picking tasks by status needs a tasks file that can be parsed
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key(setStatusFormKeyByStatus).
+				Title("Select Tasks By Status").
+				Description("Update every task with a given status, e.g. move all review tasks to done.").
+				Affirmative("By status").
+				Negative("Pick tasks").
+				Value(&m.ByStatus),
+		).WithHideFunc(func() bool { return !m.tasks.available(m.FilePath) }),
+		huh.NewGroup(
+			huh.NewSelect[TaskStatus]().
+				Key(setStatusFormKeyFromStatus).
+				Title("Current Status").
+				Description("Update the tasks and subtasks that have this status.").
+				Options(taskStatusOptions()...).
+				Value(&m.FromStatus),
+		).WithHideFunc(func() bool { return !m.byStatus() }),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Key(setStatusFormKeySelectedIDs).
+				Title("Task(s)").
+				Description("Select the task(s) to update (space to toggle).").
+				OptionsFunc(func() []huh.Option[string] { return m.tasks.load(m.FilePath) }, &m.FilePath).
+				Height(12).
+				Validate(func(ids []string) error {
+					if len(ids) == 0 {
+						return fmt.Errorf("select at least one task")
 					}
 					return nil
 				}).
-				Value(&m.FilePath),
-
+				Value(&m.SelectedIDs),
+		).WithHideFunc(func() bool { return !m.tasks.available(m.FilePath) || m.ByStatus }),
+		// Free-text fallback for files that can't be parsed
+		huh.NewGroup(
 			huh.NewInput().
 				Key(setStatusFormKeyIDs).
 				Title("Task ID(s)").
@@ -77,18 +126,13 @@ func NewSetStatusForm() *SetStatusModel {
 					return nil
 				}).
 				Value(&m.TaskIDs),
-		),
+		).WithHideFunc(func() bool { return m.tasks.available(m.FilePath) }),
 		huh.NewGroup(
 			huh.NewSelect[TaskStatus]().
 				Key(setStatusFormKeyStatus).
 				Title("New Status").
 				Description("Select the new status for the task(s).").
-				Options(
-					huh.NewOption("To Do", StatusTodo),
-					huh.NewOption("In Progress", StatusInProgress),
-					huh.NewOption("Review", StatusReview),
-					huh.NewOption("Done", StatusDone),
-				).
+				Options(taskStatusOptions()...).
 				Value(&m.NewStatus),
 
 			huh.NewConfirm().
@@ -99,6 +143,37 @@ func NewSetStatusForm() *SetStatusModel {
 				Negative("No").
 				Value(&m.CriteriaMet),
 		),
+		// Checkpoints can't be set to done without their criteria met, so ask
+		// again rather than let the CLI refuse
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key(setStatusFormKeyCheckpoint).
+				Title("Checkpoint Criteria").
+				DescriptionFunc(func() string {
+					return fmt.Sprintf("Setting checkpoint(s) %s to done needs their acceptance criteria met. Are they?", strings.Join(m.checkpointIDs(), ", "))
+				}, []any{&m.NewStatus, &m.SelectedIDs, &m.TaskIDs, &m.FromStatus}).
+				Affirmative("Criteria met").
+				Negative("No").
+				Validate(func(confirmed bool) error {
+					if !confirmed {
+						return fmt.Errorf("confirm the criteria are met, or go back (Shift+Tab) and choose another status")
+					}
+					return nil
+				}).
+				Value(&m.CheckpointConfirmed),
+		).WithHideFunc(func() bool { return m.CriteriaMet || m.NewStatus != StatusDone || len(m.checkpointIDs()) == 0 }),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
+		// Tasks found by status are listed before anything runs
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key(setStatusFormKeyConfirm).
+				Title("Update These Tasks?").
+				DescriptionFunc(m.byStatusSummary, []any{&m.FromStatus, &m.NewStatus}).
+				Affirmative("Yes").
+				Negative("No").
+				Validate(m.validateByStatus).
+				Value(&m.Confirmed),
+		).WithHideFunc(func() bool { return !m.byStatus() }),
 	).WithTheme(huh.ThemeDracula())
 
 	return m
@@ -112,9 +187,45 @@ func (m *SetStatusModel) Init() tea.Cmd {
 }
 
 func (m *SetStatusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case setTaskStatusCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		if len(msg.problems) > 0 {
+			m.statusMsg = batchProblemsStatus(msg.problems)
+			m.form.State = huh.StateNormal // Back to the form to fix them all before anything runs
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			m.statusMsg = "✅ Success!"
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
 	if m.isProcessing {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
 			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
 			case "ctrl+c", "q":
 				return m, tea.Quit
 			}
@@ -122,13 +233,37 @@ func (m *SetStatusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.statusMsg = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
 	var cmds []tea.Cmd
 	formModel, cmd := m.form.Update(msg)
 	if updatedForm, ok := formModel.(*huh.Form); ok {
 		m.form = updatedForm
 	} else {
 		m.statusMsg = "Error: Form update returned unexpected type."
-		fmt.Fprintf(os.Stderr, "Critical Error: set_status_form.go - form update did not return *huh.Form. Got: %T\n", formModel)
+		debugLog.Error("form update did not return *huh.Form", "file", "set_status_form.go", "type", fmt.Sprintf("%T", formModel))
 		return m, tea.Quit
 	}
 	cmds = append(cmds, cmd)
@@ -136,7 +271,7 @@ func (m *SetStatusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.form.State == huh.StateCompleted {
 		m.statusMsg = "Executing set-task-status command..."
 		m.isProcessing = true
-		return m, m.executeSetTaskStatusCommand()
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeSetTaskStatusCommand))
 	}
 
 	if m.form.State == huh.StateAborted {
@@ -145,14 +280,6 @@ func (m *SetStatusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
-	case setTaskStatusCompleteMsg:
-		m.isProcessing = false
-		if msg.result.Success {
-			m.statusMsg = fmt.Sprintf("✅ Success!\n\n%s", msg.result.Output)
-		} else {
-			m.statusMsg = fmt.Sprintf("❌ Error: %s\n\n%s", msg.result.Error, msg.result.Output)
-		}
-		return m, nil
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
@@ -161,8 +288,6 @@ func (m *SetStatusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, func() tea.Msg { return backToMenuMsg{} }
 			}
 		}
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
 	}
 
 	return m, tea.Batch(cmds...)
@@ -178,18 +303,22 @@ func (m *SetStatusModel) View() string {
 
 	if m.statusMsg != "" {
 		viewBuilder.WriteString("\n\n")
-		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-		if strings.HasPrefix(m.statusMsg, "Error:") {
-			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
-		}
-		viewBuilder.WriteString(statusStyle.Render(m.statusMsg))
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
 	}
 
 	helpStyle := lipgloss.NewStyle().Faint(true)
 	if m.isProcessing {
-		viewBuilder.WriteString(helpStyle.Render("\n\nProcessing... Press Ctrl+C to force quit."))
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
 	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
-		viewBuilder.WriteString(helpStyle.Render("\n\nCommand completed! Press Esc to return to main menu."))
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
 	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
 		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
 	}
@@ -200,6 +329,8 @@ func (m *SetStatusModel) View() string {
 		Render(viewBuilder.String())
 }
 
+func (m *SetStatusModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
 // GetFormValues retrieves the structured data after completion.
 func (m *SetStatusModel) GetFormValues() (map[string]interface{}, error) {
 	if m.form.State != huh.StateCompleted {
@@ -207,56 +338,140 @@ func (m *SetStatusModel) GetFormValues() (map[string]interface{}, error) {
 	}
 	return map[string]interface{}{
 		setStatusFormKeyFile:        m.FilePath,
-		setStatusFormKeyIDs:         m.TaskIDs,
+		setStatusFormKeyIDs:         strings.Join(m.taskIDs(), ","),
 		setStatusFormKeyStatus:      m.NewStatus,
-		setStatusFormKeyCriteriaMet: m.CriteriaMet,
+		setStatusFormKeyCriteriaMet: m.criteriaMet(),
+		setStatusFormKeyByStatus:    m.byStatus(),
+		setStatusFormKeyFromStatus:  m.FromStatus,
+		advancedFlagsFormKey:        m.AdvancedFlags,
 	}, nil
 }
 
+// taskStatusOptions are the statuses tasks can be set to and selected by.
+func taskStatusOptions() []huh.Option[TaskStatus] {
+	return []huh.Option[TaskStatus]{
+		huh.NewOption("To Do", StatusTodo),
+		huh.NewOption("In Progress", StatusInProgress),
+		huh.NewOption("Review", StatusReview),
+		huh.NewOption("Done", StatusDone),
+	}
+}
+
+// byStatus reports whether the tasks to update are selected by their status.
+func (m *SetStatusModel) byStatus() bool {
+	return m.ByStatus && m.tasks.available(m.FilePath)
+}
+
+// statusIDs returns the IDs of the tasks and subtasks with FromStatus.
+func (m *SetStatusModel) statusIDs() []string {
+	tf, err := LoadTasksFile(m.FilePath)
+	if err != nil {
+		return nil
+	}
+	return tf.IDsWithStatus(m.FromStatus)
+}
+
+// byStatusSummary lists the tasks that selecting by status will update.
+func (m *SetStatusModel) byStatusSummary() string {
+	ids := m.statusIDs()
+	if len(ids) == 0 {
+		return fmt.Sprintf("No tasks are %s.", m.FromStatus)
+	}
+	return fmt.Sprintf("Sets %d task(s) from %s to %s: %s", len(ids), m.FromStatus, m.NewStatus, strings.Join(ids, ", "))
+}
+
+// validateByStatus requires tasks to update and an explicit Yes before running.
+func (m *SetStatusModel) validateByStatus(confirmed bool) error {
+	if m.FromStatus == m.NewStatus {
+		return fmt.Errorf("the new status is the same as the current one")
+	}
+	if len(m.statusIDs()) == 0 {
+		return fmt.Errorf("no tasks are %s; go back and choose another status", m.FromStatus)
+	}
+	if !confirmed {
+		return fmt.Errorf("choose Yes to update these tasks, or press Esc to cancel")
+	}
+	return nil
+}
+
+// taskIDs returns the IDs to update: those with the chosen status, those
+// picked in the multi-select, or the typed list when the tasks file couldn't
+// be parsed.
+func (m *SetStatusModel) taskIDs() []string {
+	if m.byStatus() {
+		return m.statusIDs()
+	}
+	if m.tasks.available(m.FilePath) {
+		return m.SelectedIDs
+	}
+	return splitTaskIDs(m.TaskIDs)
+}
+
+// checkpointIDs returns the IDs to update that are checkpoint tasks, as far
+// as the tasks file can be read.
+func (m *SetStatusModel) checkpointIDs() []string {
+	tf, err := LoadTasksFile(m.FilePath)
+	if err != nil {
+		return nil
+	}
+	var ids []string
+	for _, id := range m.taskIDs() {
+		if task, ok := tf.Find(id); ok && task.Type == string(TypeCheckpoint) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// criteriaMet reports whether the acceptance criteria were confirmed, on the
+// status page or when asked about checkpoints.
+func (m *SetStatusModel) criteriaMet() bool {
+	return m.CriteriaMet || m.CheckpointConfirmed && m.NewStatus == StatusDone && len(m.checkpointIDs()) > 0
+}
+
 // setTaskStatusCompleteMsg is sent when the command execution is complete
 type setTaskStatusCompleteMsg struct {
-	result CLIResult
+	result   CLIResult
+	problems []string // Inputs ValidateBatch rejected, in which case nothing ran
 }
 
 // executeSetTaskStatusCommand executes the actual set-task-status CLI command
-// Handles multiple task IDs by calling the CLI method for each one
+// Handles multiple task IDs by running the CLI for each one in turn
 func (m *SetStatusModel) executeSetTaskStatusCommand() tea.Cmd {
-	return func() tea.Msg {
-		// Parse task IDs from comma-separated string
-		taskIDs := strings.Split(m.TaskIDs, ",")
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+
+		// Check every ID and the status before setting any, so a typo doesn't
+		// stop the batch partway
+		taskIDs := m.taskIDs()
+		if problems := ValidateBatch(m.FilePath, taskIDs, string(m.NewStatus)); len(problems) > 0 {
+			return setTaskStatusCompleteMsg{problems: problems}
+		}
+
+		// Each ID is a separate CLI process; run them in turn and report in input order
 		var results []string
 		var hasError bool
 		var lastError string
-		
-		for _, taskID := range taskIDs {
-			trimmedID := strings.TrimSpace(taskID)
-			if trimmedID == "" {
-				continue
-			}
-			
-			result := cliExecutor.SetTaskStatus(m.FilePath, trimmedID, string(m.NewStatus))
+
+		for i, result := range executor.SetTaskStatuses(m.FilePath, taskIDs, string(m.NewStatus), m.criteriaMet()) {
 			if result.Success {
-				results = append(results, fmt.Sprintf("✅ Task %s: %s", trimmedID, result.Output))
+				results = append(results, fmt.Sprintf("✅ Task %s: %s", taskIDs[i], result.Output))
 			} else {
 				hasError = true
 				lastError = result.Error
-				results = append(results, fmt.Sprintf("❌ Task %s: %s", trimmedID, result.Error))
+				results = append(results, fmt.Sprintf("❌ Task %s: %s", taskIDs[i], result.Error))
 			}
 		}
-		
-		if len(results) == 0 {
-			return setTaskStatusCompleteMsg{result: CLIResult{
-				Success: false,
-				Error:   "No valid task IDs provided",
-			}}
-		}
-		
+
 		return setTaskStatusCompleteMsg{result: CLIResult{
 			Success: !hasError,
 			Error:   lastError,
 			Output:  strings.Join(results, "\n"),
 		}}
-	}
+	})
 }
 
 // Ensure SetStatusModel implements tea.Model.
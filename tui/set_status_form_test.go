@@ -0,0 +1,45 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSetStatusCheckpointCriteria(t *testing.T) {
+	fake := &fakeRunner{}
+	useFakeRunner(t, fake)
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	writeTasks(t, path, `{"tasks": [
+		{"id": 1, "title": "Release", "status": "review", "type": "checkpoint"},
+		{"id": 2, "title": "Docs", "status": "review"}
+	]}`)
+
+	m := NewSetStatusForm()
+	m.FilePath, m.NewStatus = path, StatusDone
+	m.SelectedIDs = []string{"2"}
+	if ids := m.checkpointIDs(); len(ids) != 0 {
+		t.Errorf("checkpointIDs = %q without a checkpoint selected", ids)
+	}
+	m.SelectedIDs = []string{"1", "2"}
+	if ids := m.checkpointIDs(); !reflect.DeepEqual(ids, []string{"1"}) {
+		t.Errorf("checkpointIDs = %q, want [1]", ids)
+	}
+	if m.criteriaMet() {
+		t.Error("criteria met before anything confirmed them")
+	}
+
+	m.CheckpointConfirmed = true
+	batch := m.executeSetTaskStatusCommand()().(tea.BatchMsg)
+	m.Update(batch[len(batch)-1]())
+
+	want := [][]string{
+		{"node", "../scripts/dev.js", "set-task-status", path, "1", "done", "--criteria-met"},
+		{"node", "../scripts/dev.js", "set-task-status", path, "2", "done", "--criteria-met"},
+	}
+	if !reflect.DeepEqual(fake.calls, want) {
+		t.Errorf("ran %q, want %q", fake.calls, want)
+	}
+}
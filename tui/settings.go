@@ -0,0 +1,287 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Settings holds TUI preferences persisted between sessions.
+type Settings struct {
+	LastFilePath string            `json:"lastFilePath,omitempty"` // Tasks file used by the last successful command
+	RecentFiles  []string          `json:"recentFiles,omitempty"`  // Tasks files used by successful commands, most recent first
+	LastPrompts  map[string]string `json:"lastPrompts,omitempty"`  // Last prompt submitted to each AI command, by command name
+
+	// ClearPromptOnSuccess forgets a command's last prompt once it runs
+	// successfully, so only failed prompts are offered again
+	ClearPromptOnSuccess bool `json:"clearPromptOnSuccess,omitempty"`
+
+	// DefaultResearch is the initial value of each form's research toggle
+	DefaultResearch bool `json:"defaultResearch,omitempty"`
+
+	// SkipAddTaskReview runs add-task as soon as its form is filled in,
+	// without the summary asking for confirmation first
+	SkipAddTaskReview bool `json:"skipAddTaskReview,omitempty"`
+
+	// MaxGenerateCount caps how many tasks or subtasks one AI command may be
+	// asked for; zero means defaultMaxGenerateCount
+	MaxGenerateCount int `json:"maxGenerateCount,omitempty"`
+
+	// ProgressPattern matches the CLI's progress lines, with the count done and
+	// the total as its first two groups; empty means defaultProgressPattern
+	ProgressPattern string `json:"progressPattern,omitempty"`
+
+	// KeywordColors overrides the colors of status and priority keywords,
+	// e.g. {"done": "#00ff00"}; an empty color leaves a keyword uncolored
+	KeywordColors map[string]string `json:"keywordColors,omitempty"`
+
+	// ActiveTag is the task context commands work in; empty means the CLI's default
+	ActiveTag string `json:"activeTag,omitempty"`
+
+	// Runner selects how the CLI is invoked, "node" or "npx"; empty means node.
+	// The TASKMASTER_TUI_RUNNER environment variable overrides it
+	Runner Runner `json:"runner,omitempty"`
+
+	// CommandTimeout is how many seconds a command may run before it is
+	// killed; zero means no limit
+	CommandTimeout int `json:"commandTimeout,omitempty"`
+
+	// MaxOutputMB caps how many megabytes of each command's output are kept;
+	// zero means defaultMaxOutputMB
+	MaxOutputMB int `json:"maxOutputMB,omitempty"`
+
+	// WorkDir is the directory commands run in and relative paths are resolved
+	// against; empty means defaultWorkDir. The TASKMASTER_TUI_WORKDIR
+	// environment variable overrides it
+	WorkDir string `json:"workDir,omitempty"`
+
+	// DryRun reports commands instead of running them, as TASKMASTER_TUI_DRY_RUN does
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// ShowAdvancedFlags adds a field for extra CLI flags to each command form,
+	// see newAdvancedFlagsGroup
+	ShowAdvancedFlags bool `json:"showAdvancedFlags,omitempty"`
+}
+
+// maxRecentFiles caps how many tasks files the settings remember.
+const maxRecentFiles = 10
+
+// sessionFilePath is the tasks file picked from the recent files, which forms
+// start with for the rest of the session; "" until one is picked.
+var sessionFilePath string
+
+// defaultMaxGenerateCount is the cap on generated tasks or subtasks unless the settings change it.
+const defaultMaxGenerateCount = 20
+
+// settingsPath returns the location of the settings file under the user's config directory.
+func settingsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "taskmaster-tui", "settings.json"), nil
+}
+
+// LoadSettings reads the persisted settings. A missing settings file yields the defaults.
+func LoadSettings() (Settings, error) {
+	var s Settings
+	path, err := settingsPath()
+	if err != nil {
+		return s, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("failed to parse settings: %w", err)
+	}
+	return s, nil
+}
+
+// SaveSettings writes the settings, creating the config directory if needed.
+func SaveSettings(s Settings) error {
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// lastFilePath returns the tasks file picked for this session, or else the
+// remembered tasks file path, or "" if there is none or the file no longer
+// exists, so the form falls back to an empty field.
+func lastFilePath() string {
+	if sessionFilePath != "" {
+		if _, err := os.Stat(sessionFilePath); err == nil {
+			return sessionFilePath
+		}
+	}
+	s, err := LoadSettings()
+	if err != nil || s.LastFilePath == "" {
+		return ""
+	}
+	if _, err := os.Stat(s.LastFilePath); err != nil {
+		return ""
+	}
+	return s.LastFilePath
+}
+
+// defaultResearch returns the initial value for a form's research toggle,
+// which can still be changed on the form.
+func defaultResearch() bool {
+	s, err := LoadSettings()
+	return err == nil && s.DefaultResearch
+}
+
+// showAdvancedFlags reports whether command forms ask for extra CLI flags.
+func showAdvancedFlags() bool {
+	s, err := LoadSettings()
+	return err == nil && s.ShowAdvancedFlags
+}
+
+// reviewAddTask reports whether add-task asks for confirmation of a summary
+// of its values before running.
+func reviewAddTask() bool {
+	s, err := LoadSettings()
+	return err != nil || !s.SkipAddTaskReview
+}
+
+// maxGenerateCount returns the most tasks or subtasks a form lets one AI command generate.
+func maxGenerateCount() int {
+	s, err := LoadSettings()
+	if err != nil || s.MaxGenerateCount <= 0 {
+		return defaultMaxGenerateCount
+	}
+	return s.MaxGenerateCount
+}
+
+// activeTag returns the task context commands work in, or "" for the CLI's default.
+func activeTag() string {
+	s, err := LoadSettings()
+	if err != nil {
+		return ""
+	}
+	return s.ActiveTag
+}
+
+// saveActiveTag sets the task context commands work in; "" means the CLI's default.
+func saveActiveTag(tag string) error {
+	s, err := LoadSettings()
+	if err != nil {
+		return err
+	}
+	s.ActiveTag = tag
+	return SaveSettings(s)
+}
+
+// rememberFilePath records the tasks file used by a successful command.
+// Failures are ignored; remembering the path is only a convenience.
+func rememberFilePath(path string) {
+	if path == "" {
+		return
+	}
+	s, err := LoadSettings()
+	if err != nil {
+		return
+	}
+	if abs, err := filepath.Abs(resolveTasksPath(path)); err == nil {
+		path = abs
+	}
+	s.LastFilePath = path
+	s.RecentFiles = addRecentFile(s.RecentFiles, path)
+	SaveSettings(s)
+}
+
+// addRecentFile moves path to the front of the recent files, dropping files
+// that no longer exist and the oldest beyond maxRecentFiles.
+func addRecentFile(recent []string, path string) []string {
+	files := []string{path}
+	for _, file := range recent {
+		if len(files) == maxRecentFiles {
+			break
+		}
+		if file == path {
+			continue
+		}
+		if _, err := os.Stat(file); err == nil {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// recentFiles returns the remembered tasks files that still exist, most
+// recent first. Settings from before the list was kept offer the last path.
+func recentFiles() []string {
+	s, err := LoadSettings()
+	if err != nil {
+		return nil
+	}
+	recent := s.RecentFiles
+	if len(recent) == 0 && s.LastFilePath != "" {
+		recent = []string{s.LastFilePath}
+	}
+	var files []string
+	for _, file := range recent {
+		if _, err := os.Stat(file); err == nil {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// useFileForSession makes path the tasks file forms start with for the rest
+// of the session, and the most recent file.
+func useFileForSession(path string) {
+	sessionFilePath = path
+	rememberFilePath(path)
+}
+
+// LoadLastPrompt returns the prompt last submitted to cmd, or "" if there is none.
+func LoadLastPrompt(cmd string) string {
+	s, err := LoadSettings()
+	if err != nil {
+		return ""
+	}
+	return s.LastPrompts[cmd]
+}
+
+// SaveLastPrompt remembers text as the last prompt submitted to cmd; an empty
+// text forgets it. Failures are ignored, as for rememberFilePath.
+func SaveLastPrompt(cmd, text string) {
+	s, err := LoadSettings()
+	if err != nil || s.LastPrompts[cmd] == text {
+		return
+	}
+	if text == "" {
+		delete(s.LastPrompts, cmd)
+	} else {
+		if s.LastPrompts == nil {
+			s.LastPrompts = make(map[string]string)
+		}
+		s.LastPrompts[cmd] = text
+	}
+	SaveSettings(s)
+}
+
+// promptSucceeded is called when cmd has run successfully with its last
+// prompt, which is then forgotten if the user prefers.
+func promptSucceeded(cmd string) {
+	s, err := LoadSettings()
+	if err != nil || !s.ClearPromptOnSuccess {
+		return
+	}
+	SaveLastPrompt(cmd, "")
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	settingsFormKeyRunner          = "runner"
+	settingsFormKeyTimeout         = "timeout"
+	settingsFormKeyMaxOutput       = "max-output"
+	settingsFormKeyWorkDir         = "work-dir"
+	settingsFormKeyDryRun          = "dry-run"
+	settingsFormKeyResearch        = "research"
+	settingsFormKeyClearPrompt     = "clear-prompt"
+	settingsFormKeyAddTaskReview   = "add-task-review"
+	settingsFormKeyMaxGenerate     = "max-generate"
+	settingsFormKeyProgressPattern = "progress-pattern"
+	settingsFormKeyAdvancedFlags   = "advanced-flags"
+)
+
+// SettingsModel holds the state for the settings form.
+type SettingsModel struct {
+	form      *huh.Form
+	aborted   bool
+	statusMsg string
+	width     int
+	height    int
+
+	// Form values, as text where the settings hold numbers so empty can mean the default
+	Runner               Runner
+	Timeout              string // Seconds
+	MaxOutputMB          string
+	WorkDir              string
+	DryRun               bool
+	DefaultResearch      bool
+	ClearPromptOnSuccess bool
+	ReviewAddTask        bool
+	MaxGenerateCount     string
+	ProgressPattern      string
+	ShowAdvancedFlags    bool
+}
+
+// NewSettingsForm creates a new form for editing the persisted settings,
+// starting from their current values.
+func NewSettingsForm() *SettingsModel {
+	s, err := LoadSettings()
+	m := &SettingsModel{
+		Runner:               s.Runner,
+		Timeout:              optionalCount(s.CommandTimeout),
+		MaxOutputMB:          optionalCount(s.MaxOutputMB),
+		WorkDir:              s.WorkDir,
+		DryRun:               s.DryRun,
+		DefaultResearch:      s.DefaultResearch,
+		ClearPromptOnSuccess: s.ClearPromptOnSuccess,
+		ReviewAddTask:        !s.SkipAddTaskReview,
+		MaxGenerateCount:     optionalCount(s.MaxGenerateCount),
+		ProgressPattern:      s.ProgressPattern,
+		ShowAdvancedFlags:    s.ShowAdvancedFlags,
+	}
+	if m.Runner == "" {
+		m.Runner = RunnerNode
+	}
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Error: couldn't read the settings, saving will replace them: %v", err)
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[Runner]().
+				Key(settingsFormKeyRunner).
+				Title("CLI Runner").
+				Description(fmt.Sprintf("How the taskmaster CLI is run. %s overrides this.", runnerEnv)).
+				Options(
+					huh.NewOption("node, from this checkout", RunnerNode),
+					huh.NewOption("npx, the published package", RunnerNpx),
+				).
+				Value(&m.Runner),
+
+			huh.NewInput().
+				Key(settingsFormKeyTimeout).
+				Title("Command Timeout").
+				Description("Seconds a command may run before it is killed; empty for no limit.").
+				Prompt("⏱️ ").
+				Validate(func(s string) error { return validateOptionalCount(s, "timeout") }).
+				Value(&m.Timeout),
+
+			huh.NewInput().
+				Key(settingsFormKeyMaxOutput).
+				Title("Maximum Output").
+				Description(fmt.Sprintf("Megabytes of a command's output to keep, the rest discarded; empty for %d.", defaultMaxOutputMB)).
+				Prompt("📦 ").
+				Validate(func(s string) error { return validateOptionalCount(s, "maximum output") }).
+				Value(&m.MaxOutputMB),
+
+			huh.NewInput().
+				Key(settingsFormKeyWorkDir).
+				Title("Working Directory").
+				Description(fmt.Sprintf("Directory commands run in and tasks files are found from; empty for this checkout's root, or the current directory outside one. %s overrides this.", workDirEnv)).
+				Prompt("📁 ").
+				Validate(validateWorkDir).
+				Value(&m.WorkDir),
+
+			huh.NewConfirm().
+				Key(settingsFormKeyDryRun).
+				Title("Dry Run").
+				Description("Show the commands that would run instead of running them.").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&m.DryRun),
+		),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key(settingsFormKeyResearch).
+				Title("Research By Default").
+				Description("Start each form's research toggle switched on.").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&m.DefaultResearch),
+
+			huh.NewConfirm().
+				Key(settingsFormKeyClearPrompt).
+				Title("Forget Successful Prompts").
+				Description("Only offer a prompt again when the command it was sent to failed.").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&m.ClearPromptOnSuccess),
+
+			huh.NewConfirm().
+				Key(settingsFormKeyAddTaskReview).
+				Title("Review Before Adding Tasks").
+				Description("Show add-task's values and ask for confirmation before it runs.").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&m.ReviewAddTask),
+
+			huh.NewInput().
+				Key(settingsFormKeyMaxGenerate).
+				Title("Most Tasks Per AI Command").
+				Description(fmt.Sprintf("Cap on the tasks or subtasks one command may generate; empty for %d.", defaultMaxGenerateCount)).
+				Prompt("🔢 ").
+				Validate(func(s string) error { return validateOptionalCount(s, "the cap") }).
+				Value(&m.MaxGenerateCount),
+
+			huh.NewInput().
+				Key(settingsFormKeyProgressPattern).
+				Title("Progress Pattern").
+				Description("Regular expression for the CLI's progress lines, with the count done and the total as groups; empty for the default.").
+				Placeholder(defaultProgressPattern).
+				Validate(validateProgressPattern).
+				Value(&m.ProgressPattern),
+
+			huh.NewConfirm().
+				Key(settingsFormKeyAdvancedFlags).
+				Title("Advanced Flags").
+				Description("Add a field to each command form for extra CLI flags the form doesn't offer. They're passed on unchecked.").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&m.ShowAdvancedFlags),
+		),
+	).WithTheme(huh.ThemeDracula())
+
+	return m
+}
+
+// optionalCount shows a setting where zero means the default as an empty field.
+func optionalCount(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func (m *SettingsModel) Init() tea.Cmd {
+	m.aborted = false
+	return m.form.Init()
+}
+
+func (m *SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height)
+	}
+
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "esc", "enter":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, nil
+	}
+
+	var cmds []tea.Cmd
+	formModel, cmd := m.form.Update(msg)
+	if updatedForm, ok := formModel.(*huh.Form); ok {
+		m.form = updatedForm
+	} else {
+		m.statusMsg = "Error: Form update returned unexpected type."
+		debugLog.Error("form update did not return *huh.Form", "file", "settings_form.go", "type", fmt.Sprintf("%T", formModel))
+		return m, tea.Quit
+	}
+	cmds = append(cmds, cmd)
+
+	if m.form.State == huh.StateCompleted {
+		if err := m.save(); err != nil {
+			m.statusMsg = fmt.Sprintf("Error: failed to save the settings: %v", err)
+			m.form.State = huh.StateNormal // Back to the form to try again
+			return m, nil
+		}
+		m.statusMsg = "✅ Settings saved."
+		return m, nil
+	}
+
+	if m.form.State == huh.StateAborted {
+		m.aborted = true
+		return m, func() tea.Msg { return backToMenuMsg{} }
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.aborted = true
+			return m, func() tea.Msg { return backToMenuMsg{} }
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+// save writes the form's values to the settings, keeping the others, and
+// rebuilds the global executor so commands use them.
+func (m *SettingsModel) save() error {
+	s, err := LoadSettings()
+	if err != nil {
+		s = Settings{} // Replace unreadable settings, as the form warned
+	}
+	// Validated by the fields, so the parses can't fail; empty is zero
+	s.Runner = m.Runner
+	s.CommandTimeout, _ = strconv.Atoi(m.Timeout)
+	s.MaxOutputMB, _ = strconv.Atoi(m.MaxOutputMB)
+	s.WorkDir = m.WorkDir
+	s.DryRun = m.DryRun
+	s.DefaultResearch = m.DefaultResearch
+	s.ClearPromptOnSuccess = m.ClearPromptOnSuccess
+	s.SkipAddTaskReview = !m.ReviewAddTask
+	s.MaxGenerateCount, _ = strconv.Atoi(m.MaxGenerateCount)
+	s.ProgressPattern = m.ProgressPattern
+	s.ShowAdvancedFlags = m.ShowAdvancedFlags
+	if err := SaveSettings(s); err != nil {
+		return err
+	}
+
+	cliExecutor = NewCLIExecutor()
+	return nil
+}
+
+func (m *SettingsModel) View() string {
+	if m.aborted {
+		return "Form aborted. Returning to main menu..."
+	}
+
+	var viewBuilder strings.Builder
+	viewBuilder.WriteString(m.form.View())
+
+	if m.statusMsg != "" {
+		viewBuilder.WriteString("\n\n")
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
+	}
+
+	helpStyle := lipgloss.NewStyle().Faint(true)
+	if m.form.State == huh.StateCompleted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Enter or Esc to return to main menu."))
+	} else if m.form.State != huh.StateAborted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu without saving, Ctrl+C to quit application."))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Padding(1, 2).
+		Render(viewBuilder.String())
+}
+
+func (m *SettingsModel) keyContext() keyContext { return formKeyContext(m.form, false) }
+
+// GetFormValues retrieves the structured data after completion.
+func (m *SettingsModel) GetFormValues() (map[string]interface{}, error) {
+	if m.form.State != huh.StateCompleted {
+		return nil, fmt.Errorf("form is not yet completed")
+	}
+	return map[string]interface{}{
+		settingsFormKeyRunner:          m.Runner,
+		settingsFormKeyTimeout:         m.Timeout,
+		settingsFormKeyMaxOutput:       m.MaxOutputMB,
+		settingsFormKeyWorkDir:         m.WorkDir,
+		settingsFormKeyDryRun:          m.DryRun,
+		settingsFormKeyResearch:        m.DefaultResearch,
+		settingsFormKeyClearPrompt:     m.ClearPromptOnSuccess,
+		settingsFormKeyAddTaskReview:   m.ReviewAddTask,
+		settingsFormKeyMaxGenerate:     m.MaxGenerateCount,
+		settingsFormKeyProgressPattern: m.ProgressPattern,
+		settingsFormKeyAdvancedFlags:   m.ShowAdvancedFlags,
+	}, nil
+}
+
+var _ tea.Model = &SettingsModel{}
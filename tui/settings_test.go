@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMain points the user config directory at a temporary one, so tests
+// that reach rememberFilePath or other settings writes never touch the real
+// settings file.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "tui-test-config")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	os.Setenv("HOME", dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestLastPrompt(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	SaveLastPrompt("add-task", "Add a login page")
+	SaveLastPrompt("expand-task", "Cover error cases")
+	if got := LoadLastPrompt("add-task"); got != "Add a login page" {
+		t.Errorf("LoadLastPrompt(add-task) = %q, want the saved prompt", got)
+	}
+
+	// Kept after success unless the user prefers otherwise
+	promptSucceeded("add-task")
+	if got := LoadLastPrompt("add-task"); got != "Add a login page" {
+		t.Errorf("LoadLastPrompt(add-task) after success = %q, want it kept", got)
+	}
+
+	s, _ := LoadSettings()
+	s.ClearPromptOnSuccess = true
+	if err := SaveSettings(s); err != nil {
+		t.Fatal(err)
+	}
+	promptSucceeded("add-task")
+	if got := LoadLastPrompt("add-task"); got != "" {
+		t.Errorf("LoadLastPrompt(add-task) after success = %q, want it cleared", got)
+	}
+	if got := LoadLastPrompt("expand-task"); got != "Cover error cases" {
+		t.Errorf("LoadLastPrompt(expand-task) = %q, want other commands untouched", got)
+	}
+}
+
+func TestRecentFiles(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Cleanup(func() { sessionFilePath = "" })
+	dir := t.TempDir()
+	a, b, gone := filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json"), filepath.Join(dir, "gone.json")
+	for _, path := range []string{a, b, gone} {
+		writeTasks(t, path, `{"tasks": []}`)
+	}
+
+	for _, path := range []string{a, gone, b, a} {
+		rememberFilePath(path)
+	}
+	os.Remove(gone)
+	if got, want := recentFiles(), []string{a, b}; !reflect.DeepEqual(got, want) {
+		t.Errorf("recentFiles() = %q, want %q", got, want)
+	}
+
+	// A picked file is the default for the session, even over later commands
+	useFileForSession(b)
+	rememberFilePath(a)
+	if got := lastFilePath(); got != b {
+		t.Errorf("lastFilePath() = %q, want the picked %q", got, b)
+	}
+
+	var many []string
+	for i := 0; i < maxRecentFiles+5; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("%d.json", i))
+		writeTasks(t, path, `{"tasks": []}`)
+		many = addRecentFile(many, path)
+	}
+	if len(many) != maxRecentFiles || many[0] != filepath.Join(dir, fmt.Sprintf("%d.json", maxRecentFiles+4)) {
+		t.Errorf("addRecentFile() kept %q, want the newest %d", many, maxRecentFiles)
+	}
+}
+
+func TestNewCLIExecutorUsesSettings(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(runnerEnv, "")
+	t.Setenv(dryRunEnv, "")
+
+	if err := SaveSettings(Settings{Runner: RunnerNpx, CommandTimeout: 90, DryRun: true}); err != nil {
+		t.Fatal(err)
+	}
+	e := NewCLIExecutor()
+	if e.Runner != RunnerNpx || e.Timeout != 90*time.Second || !e.DryRun {
+		t.Errorf("NewCLIExecutor() = runner %q, timeout %v, dry run %v; want the settings", e.Runner, e.Timeout, e.DryRun)
+	}
+
+	// The environment overrides the runner setting
+	t.Setenv(runnerEnv, string(RunnerNode))
+	if e := NewCLIExecutor(); e.Runner != RunnerNode {
+		t.Errorf("NewCLIExecutor() runner = %q, want the environment's %q", e.Runner, RunnerNode)
+	}
+}
+
+func TestExecutorTimeout(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep is not available")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	e := &CLIExecutor{Timeout: 50 * time.Millisecond}
+	start := time.Now()
+	result := e.executeCommand("sleep", "5")
+	if result.Success || !strings.Contains(result.Error, "timed out after 50ms") {
+		t.Errorf("executeCommand(sleep 5) = %+v, want a timeout", result)
+	}
+	if elapsed := time.Since(start); elapsed > 4*time.Second {
+		t.Errorf("timed out command ran for %v", elapsed)
+	}
+}
+
+func TestValidateSettingsFields(t *testing.T) {
+	for _, s := range []string{"", "1", "600"} {
+		if err := validateOptionalCount(s, "timeout"); err != nil {
+			t.Errorf("validateOptionalCount(%q) = %v, want nil", s, err)
+		}
+	}
+	for _, s := range []string{"0", "-5", "abc"} {
+		if err := validateOptionalCount(s, "timeout"); err == nil {
+			t.Errorf("validateOptionalCount(%q) = nil, want an error", s)
+		}
+	}
+
+	if err := validateProgressPattern(`(\d+) of (\d+)`); err != nil {
+		t.Errorf("validateProgressPattern with two groups = %v, want nil", err)
+	}
+	for _, s := range []string{`(\d+`, `(\d+) done`} {
+		if err := validateProgressPattern(s); err == nil {
+			t.Errorf("validateProgressPattern(%q) = nil, want an error", s)
+		}
+	}
+}
@@ -2,9 +2,9 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
@@ -16,6 +16,10 @@ const (
 	showTaskFormKeyStatusFilter = "status-filter" // For subtasks
 )
 
+// showDependenciesKey expands the tasks the shown task depends on below it,
+// and collapses them again.
+const showDependenciesKey = "p"
+
 // Using FilterStatus from list_tasks_form.go (assuming it's in the same package `main`)
 // If not, it would need to be redefined or imported if in a different package.
 // For this example, assuming FilterStatus (none, todo, in-progress, review, done) is available.
@@ -27,34 +31,38 @@ type ShowTaskModel struct {
 	isProcessing bool // To simulate action, though 'show' might just display info
 	statusMsg    string
 	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
+	autoRun      bool                // Run immediately with preset values instead of showing the form
+	taskOutput   string              // Output of show-task, without the dependencies
+	dependencies string              // The task's dependencies rendered from the tasks file; "" if none
+	depsShown    bool                // The dependencies are expanded below the output
 
 	// Form values
-	FilePath      string
-	TaskID        string
-	StatusFilter  FilterStatus // For subtask filtering
+	FilePath     string
+	TaskID       string
+	StatusFilter FilterStatus // For subtask filtering
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
 }
 
 // NewShowTaskForm creates a new form for the show task command.
 func NewShowTaskForm() *ShowTaskModel {
 	m := &ShowTaskModel{
+		FilePath:     lastFilePath(),   // Pre-populate with the last used tasks file
 		StatusFilter: FilterStatusNone, // Default to no filter for subtasks
 	}
 
 	m.form = huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().
-				Key(showTaskFormKeyFile).
-				Title("Tasks File Path").
-				Description("Path to the tasks file (e.g., tasks.md).").
-				Prompt("📄 ").
-				Validate(func(s string) error {
-					if s == "" {
-						return fmt.Errorf("tasks file path cannot be empty")
-					}
-					return nil
-				}).
-				Value(&m.FilePath),
-
+			newFilePathField(showTaskFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json).", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		huh.NewGroup(
 			huh.NewInput().
 				Key(showTaskFormKeyID).
 				Title("Task ID").
@@ -66,6 +74,7 @@ func NewShowTaskForm() *ShowTaskModel {
 					}
 					return nil
 				}).
+				SuggestionsFunc(taskIDSuggestions(&m.FilePath, true), &m.FilePath).
 				Value(&m.TaskID),
 		),
 		huh.NewGroup(
@@ -82,35 +91,122 @@ func NewShowTaskForm() *ShowTaskModel {
 				).
 				Value(&m.StatusFilter),
 		),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
 	).WithTheme(huh.ThemeDracula())
 
 	return m
 }
 
+// NewShowTaskFormFor creates a show-task view that runs straight away for the
+// given task, e.g. when a task is picked from the list-tasks table.
+func NewShowTaskFormFor(filePath, taskID string) *ShowTaskModel {
+	m := NewShowTaskForm()
+	m.FilePath = filePath
+	m.TaskID = taskID
+	m.autoRun = true
+	return m
+}
+
 func (m *ShowTaskModel) Init() tea.Cmd {
 	m.isProcessing = false
 	m.statusMsg = ""
 	m.aborted = false
+	if m.autoRun {
+		m.form.State = huh.StateCompleted
+		m.statusMsg = fmt.Sprintf("Executing show-task command for task %s...", m.TaskID)
+		m.isProcessing = true
+		return tea.Batch(m.spinner.start(), m.last.run(m.executeShowTaskCommand))
+	}
 	return m.form.Init()
 }
 
 func (m *ShowTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case showTaskCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			m.autoRun = false              // Show the form even if it was skipped
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			m.statusMsg = "✅ Success!"
+			m.taskOutput = msg.result.Output
+			m.dependencies = renderTaskDependencies(m.FilePath, m.TaskID)
+			m.depsShown = false
+			m.result.setContent(m.taskOutput)
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
 	if m.isProcessing {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
-			if keyMsg.String() == "ctrl+c" || keyMsg.String() == "q" {
+			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
+			case "ctrl+c", "q":
 				return m, tea.Quit
 			}
 		}
 		return m, nil
 	}
 
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.statusMsg = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			case showDependenciesKey:
+				if m.dependencies != "" {
+					m.depsShown = !m.depsShown
+					content := m.taskOutput
+					if m.depsShown {
+						content = strings.TrimRight(content, "\n") + "\n\n" + m.dependencies
+					}
+					m.result.setContent(content)
+					return m, nil
+				}
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
 	var cmds []tea.Cmd
 	formModel, cmd := m.form.Update(msg)
 	if updatedForm, ok := formModel.(*huh.Form); ok {
 		m.form = updatedForm
 	} else {
 		m.statusMsg = "Error: Form update returned unexpected type."
-		fmt.Fprintf(os.Stderr, "Critical Error: show_task_form.go - form update did not return *huh.Form. Got: %T\n", formModel)
+		debugLog.Error("form update did not return *huh.Form", "file", "show_task_form.go", "type", fmt.Sprintf("%T", formModel))
 		return m, tea.Quit
 	}
 	cmds = append(cmds, cmd)
@@ -118,7 +214,7 @@ func (m *ShowTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.form.State == huh.StateCompleted {
 		m.statusMsg = "Executing show-task command..."
 		m.isProcessing = true
-		return m, m.executeShowTaskCommand()
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeShowTaskCommand))
 	}
 
 	if m.form.State == huh.StateAborted {
@@ -127,14 +223,6 @@ func (m *ShowTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
-	case showTaskCompleteMsg:
-		m.isProcessing = false
-		if msg.result.Success {
-			m.statusMsg = fmt.Sprintf("✅ Success!\n\n%s", msg.result.Output)
-		} else {
-			m.statusMsg = fmt.Sprintf("❌ Error: %s\n\n%s", msg.result.Error, msg.result.Output)
-		}
-		return m, nil
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
@@ -143,8 +231,6 @@ func (m *ShowTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, func() tea.Msg { return backToMenuMsg{} }
 			}
 		}
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
 	}
 
 	return m, tea.Batch(cmds...)
@@ -156,22 +242,34 @@ func (m *ShowTaskModel) View() string {
 	}
 
 	var viewBuilder strings.Builder
-	viewBuilder.WriteString(m.form.View())
+	if !m.autoRun {
+		viewBuilder.WriteString(m.form.View())
+	}
 
 	if m.statusMsg != "" {
 		viewBuilder.WriteString("\n\n")
-		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-		if strings.HasPrefix(m.statusMsg, "Error:") {
-			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
-		}
-		viewBuilder.WriteString(statusStyle.Render(m.statusMsg))
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
 	}
 
 	helpStyle := lipgloss.NewStyle().Faint(true)
 	if m.isProcessing {
-		viewBuilder.WriteString(helpStyle.Render("\n\nProcessing... Press Ctrl+C to force quit."))
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
 	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
-		viewBuilder.WriteString(helpStyle.Render("\n\nCommand completed! Press Esc to return to main menu."))
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		help := m.result.help()
+		if m.dependencies != "" && m.depsShown {
+			help += fmt.Sprintf(" Press %s to hide the dependencies.", showDependenciesKey)
+		} else if m.dependencies != "" {
+			help += fmt.Sprintf(" Press %s to show what task %s depends on.", showDependenciesKey, m.TaskID)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + help))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
 	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
 		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
 	}
@@ -182,6 +280,8 @@ func (m *ShowTaskModel) View() string {
 		Render(viewBuilder.String())
 }
 
+func (m *ShowTaskModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
 // GetFormValues retrieves the structured data after completion.
 func (m *ShowTaskModel) GetFormValues() (map[string]interface{}, error) {
 	if m.form.State != huh.StateCompleted {
@@ -191,9 +291,59 @@ func (m *ShowTaskModel) GetFormValues() (map[string]interface{}, error) {
 		showTaskFormKeyFile:         m.FilePath,
 		showTaskFormKeyID:           m.TaskID,
 		showTaskFormKeyStatusFilter: m.StatusFilter,
+		advancedFlagsFormKey:        m.AdvancedFlags,
 	}, nil
 }
 
+// Styles for the dependencies of a shown task, so blockers stand out
+var (
+	doneDependencyStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	blockingDependencyStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	missingDependencyStyle  = lipgloss.NewStyle().Faint(true)
+)
+
+// renderTaskDependencies lists the tasks id depends on, read from the tasks
+// file, with their titles and statuses: done ones in green and the rest, which
+// block it, in red. A dependency on a task that isn't in the file is listed as
+// missing. It returns "" if the task has no dependencies or can't be read.
+func renderTaskDependencies(filePath, id string) string {
+	tf, err := LoadTasksFile(filePath)
+	if err != nil {
+		return ""
+	}
+	deps := tf.DependencyEdges()[id]
+	if len(deps) == 0 {
+		return ""
+	}
+
+	var lines []string
+	done := 0
+	for _, dep := range deps {
+		task, ok := tf.Find(dep)
+		switch {
+		case !ok:
+			lines = append(lines, missingDependencyStyle.Render(fmt.Sprintf("  ? %-6s not found in the tasks file", dep)))
+		case isDoneStatus(task.Status):
+			done++
+			lines = append(lines, doneDependencyStyle.Render(fmt.Sprintf("  ✓ %-6s %s (%s)", dep, task.Title, task.Status)))
+		default:
+			status := task.Status
+			if status == "" {
+				status = "pending"
+			}
+			lines = append(lines, blockingDependencyStyle.Render(fmt.Sprintf("  ✗ %-6s %s (%s)", dep, task.Title, status)))
+		}
+	}
+	header := fixSummaryHeaderStyle.Render(fmt.Sprintf("Depends on (%d of %d done):", done, len(deps)))
+	return header + "\n" + strings.Join(lines, "\n")
+}
+
+// showTaskMsg asks the main model to open the show-task view for a task.
+type showTaskMsg struct {
+	filePath string
+	taskID   string
+}
+
 // showTaskCompleteMsg is sent when the command execution is complete
 type showTaskCompleteMsg struct {
 	result CLIResult
@@ -202,10 +352,15 @@ type showTaskCompleteMsg struct {
 // executeShowTaskCommand executes the actual show-task CLI command
 // Note: The CLI doesn't support status filtering for subtasks, so we ignore the StatusFilter field
 func (m *ShowTaskModel) executeShowTaskCommand() tea.Cmd {
-	return func() tea.Msg {
-		result := cliExecutor.ShowTask(m.FilePath, m.TaskID)
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+
+		result := executor.ShowTask(m.FilePath, m.TaskID)
 		return showTaskCompleteMsg{result: result}
-	}
+	})
 }
 
 var _ tea.Model = &ShowTaskModel{}
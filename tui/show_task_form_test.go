@@ -0,0 +1,79 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRenderTaskDependencies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	writeTasks(t, path, `{"tasks": [
+		{"id": 1, "title": "Setup", "status": "done"},
+		{"id": 2, "title": "Core", "status": "in-progress"},
+		{"id": 3, "title": "UI", "dependencies": [1, 2, 9], "subtasks": [
+			{"id": 1, "title": "Layout", "status": "done", "dependencies": [2]}
+		]}
+	]}`)
+
+	got := renderTaskDependencies(path, "3")
+	want := strings.Join([]string{
+		"Depends on (1 of 3 done):",
+		"  ✓ 1      Setup (done)",
+		"  ✗ 2      Core (in-progress)",
+		"  ? 9      not found in the tasks file",
+	}, "\n")
+	if ansi.Strip(got) != want {
+		t.Errorf("renderTaskDependencies(3) =\n%s\nwant\n%s", ansi.Strip(got), want)
+	}
+
+	// A subtask's numeric dependency is on its sibling, as the CLI reads it
+	if got := ansi.Strip(renderTaskDependencies(path, "3.1")); !strings.Contains(got, "? 3.2") {
+		t.Errorf("renderTaskDependencies(3.1) = %q, want its missing sibling 3.2", got)
+	}
+
+	for _, id := range []string{"1", "7"} {
+		if got := renderTaskDependencies(path, id); got != "" {
+			t.Errorf("renderTaskDependencies(%s) = %q, want nothing", id, got)
+		}
+	}
+	if got := renderTaskDependencies(filepath.Join(t.TempDir(), "missing.json"), "3"); got != "" {
+		t.Errorf("renderTaskDependencies of a missing file = %q, want nothing", got)
+	}
+}
+
+func TestShowTaskExpandsDependencies(t *testing.T) {
+	// A successful run remembers the file path in the settings
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	path := writeSampleTasks(t)
+	m := NewShowTaskFormFor(path, "3")
+	m.form.State = huh.StateCompleted
+	m.Update(showTaskCompleteMsg{result: CLIResult{Success: true, Output: "Task 3: UI\n"}})
+	if !strings.Contains(m.View(), "Press p to show what task 3 depends on.") {
+		t.Errorf("View() doesn't offer the dependencies:\n%s", m.View())
+	}
+
+	key := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(showDependenciesKey)}
+	m.Update(key)
+	if got := ansi.Strip(m.result.content); !strings.HasPrefix(got, "Task 3: UI\n\nDepends on (0 of 1 done):") || !strings.Contains(got, "✗ 2      Core (pending)") {
+		t.Errorf("expanded content = %q, want the output then task 2", got)
+	}
+	m.Update(key)
+	if m.result.content != "Task 3: UI\n" {
+		t.Errorf("collapsed content = %q, want the output alone", m.result.content)
+	}
+
+	// Without dependencies there is nothing to expand
+	m = NewShowTaskFormFor(path, "1")
+	m.form.State = huh.StateCompleted
+	m.Update(showTaskCompleteMsg{result: CLIResult{Success: true, Output: "Task 1: Setup\n"}})
+	m.Update(key)
+	if m.result.content != "Task 1: Setup\n" || strings.Contains(m.View(), "depends on") {
+		t.Errorf("task 1 content = %q, want no dependencies offered", m.result.content)
+	}
+}
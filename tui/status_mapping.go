@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// statusAssignment is one task's new status in a status mapping.
+type statusAssignment struct {
+	ID     string
+	Status TaskStatus
+}
+
+// parseStatusMapping parses a mapping of task or subtask IDs to statuses,
+// such as "1=done, 2.1=in-progress", separated by commas or new lines. Each
+// ID and status is checked before anything runs, and an ID may only appear
+// once.
+func parseStatusMapping(s string) ([]statusAssignment, error) {
+	valid := make(map[TaskStatus]bool)
+	var names []string
+	for _, option := range taskStatusOptions() {
+		valid[option.Value] = true
+		names = append(names, string(option.Value))
+	}
+
+	var assignments []statusAssignment
+	seen := make(map[string]bool)
+	for _, entry := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, status, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not id=status", entry)
+		}
+		id, status = strings.TrimSpace(id), strings.ToLower(strings.TrimSpace(status))
+		if err := validateTaskID(id); err != nil {
+			return nil, err
+		}
+		if !valid[TaskStatus(status)] {
+			return nil, fmt.Errorf("invalid status %q for %s: use %s", status, id, strings.Join(names, ", "))
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("task %s is listed more than once", id)
+		}
+		seen[id] = true
+		assignments = append(assignments, statusAssignment{ID: id, Status: TaskStatus(status)})
+	}
+	if len(assignments) == 0 {
+		return nil, fmt.Errorf("mapping cannot be empty")
+	}
+	return assignments, nil
+}
+
+// SetTaskStatusMapping sets each task in assignments to its own status. Every
+// run rewrites the whole tasks file, so they run one at a time and none is
+// lost. Results are in the order of assignments.
+func (e *CLIExecutor) SetTaskStatusMapping(filePath string, assignments []statusAssignment, criteriaMet bool) []CLIResult {
+	return e.runLockedBatch(filePath, len(assignments), func(i int) CLIResult {
+		a := assignments[i]
+		return e.runCLI(buildSetTaskStatusArgs(filePath, a.ID, string(a.Status), criteriaMet)...)
+	})
+}
+
+// statusMappingResult combines the results of SetTaskStatusMapping into one,
+// with a line per task saying what it was set to and how that went.
+func statusMappingResult(assignments []statusAssignment, results []CLIResult) CLIResult {
+	combined := CLIResult{Success: true}
+	lines := make([]string, len(assignments))
+	for i, a := range assignments {
+		if results[i].Success {
+			lines[i] = fmt.Sprintf("✅ Task %s → %s: %s", a.ID, a.Status, results[i].Output)
+		} else {
+			combined.Success = false
+			combined.Error = results[i].Error
+			lines[i] = fmt.Sprintf("❌ Task %s → %s: %s", a.ID, a.Status, results[i].Error)
+		}
+	}
+	combined.Output = strings.Join(lines, "\n")
+	return combined
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseStatusMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []statusAssignment
+		wantErr string
+	}{
+		{name: "commas", input: "1=done, 2.1=in-progress", want: []statusAssignment{{"1", StatusDone}, {"2.1", StatusInProgress}}},
+		{name: "lines and case", input: "3 = Review\n4=todo\n", want: []statusAssignment{{"3", StatusReview}, {"4", StatusTodo}}},
+		{name: "bad status", input: "1=finished", wantErr: `invalid status "finished" for 1`},
+		{name: "bad ID", input: "one=done", wantErr: "one"},
+		{name: "no equals", input: "1 done", wantErr: "is not id=status"},
+		{name: "repeated ID", input: "1=done,1=review", wantErr: "more than once"},
+		{name: "empty", input: " , ", wantErr: "cannot be empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseStatusMapping(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("parseStatusMapping(%q) error = %v, want one containing %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseStatusMapping(%q) = %v, %v, want %v", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetTaskStatusMapping(t *testing.T) {
+	fake := &fakeRunner{stdout: "ok"}
+	useFakeRunner(t, fake)
+
+	assignments := []statusAssignment{{"1", StatusDone}, {"2.1", StatusInProgress}}
+	results := cliExecutor.SetTaskStatusMapping("tasks.json", assignments, true)
+	result := statusMappingResult(assignments, results)
+	if !result.Success {
+		t.Fatalf("statusMappingResult() failed: %s", result.Error)
+	}
+	if want := "✅ Task 1 → done: ok\n✅ Task 2.1 → in-progress: ok"; result.Output != want {
+		t.Errorf("Output = %q, want %q", result.Output, want)
+	}
+
+	var calls []string
+	for _, call := range fake.calls {
+		calls = append(calls, strings.Join(call[2:], " "))
+	}
+	if want := []string{"set-task-status tasks.json 1 done --criteria-met", "set-task-status tasks.json 2.1 in-progress"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("ran %q, want %q in order", calls, want)
+	}
+}
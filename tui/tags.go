@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/charmbracelet/huh"
+)
+
+// tagFlag selects the task context ("tag") a CLI command works in.
+const tagFlag = "--tag"
+
+// defaultTag is the tag the CLI works in without one, and the one an untagged
+// tasks file holds.
+const defaultTag = "master"
+
+// untaggedCommands are the CLI commands that don't work within a tag.
+var untaggedCommands = map[string]bool{"init": true, "models": true, "add-tag": true}
+
+// WithTag returns a copy of the executor whose commands work in tag. An empty
+// tag uses the active tag from the settings, if any.
+func (e *CLIExecutor) WithTag(tag string) *CLIExecutor {
+	tagged := *e
+	tagged.tag = tag
+	return &tagged
+}
+
+// tagArgs appends the tag flag to the arguments of a command that takes one,
+// for the executor's tag or else the active tag. Without either the CLI uses
+// its default context.
+func (e *CLIExecutor) tagArgs(args []string) []string {
+	if len(args) == 0 || untaggedCommands[args[0]] {
+		return args
+	}
+	tag := e.tag
+	if tag == "" {
+		tag = activeTag()
+	}
+	if tag == "" {
+		return args
+	}
+	return append(args[:len(args):len(args)], tagFlag, tag)
+}
+
+// Tags returns the tags in a tasks file, sorted. A file in the untagged
+// format, with its tasks at the top level, has none: it is a single default
+// context.
+func Tags(filePath string) ([]string, error) {
+	data, err := os.ReadFile(resolveTasksPath(filePath))
+	if err != nil {
+		return nil, err
+	}
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(data, &top); err != nil {
+		return nil, fmt.Errorf("failed to parse tasks file: %w", err)
+	}
+	if _, ok := top["tasks"]; ok {
+		return nil, nil
+	}
+
+	var tags []string
+	for name, raw := range top {
+		var context struct {
+			Tasks json.RawMessage `json:"tasks"`
+		}
+		if json.Unmarshal(raw, &context) == nil && context.Tasks != nil {
+			tags = append(tags, name)
+		}
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// newTagGroups builds the groups that pick the tag a single command works
+// in: a select of the tags in the tasks file at path, or free text when the
+// file has none or can't be read. Only one of the two is shown. An empty tag
+// uses the active tag.
+func newTagGroups(key string, path, tag *string) (pick, typed *huh.Group) {
+	hasTags := func() bool {
+		tags, err := Tags(*path)
+		return err == nil && len(tags) > 0
+	}
+	activeContext := func() string {
+		if current := activeTag(); current != "" {
+			return "the active tag " + current
+		}
+		return "the default context"
+	}
+
+	pick = huh.NewGroup(
+		huh.NewSelect[string]().
+			Key(key).
+			Title("Tag").
+			Description("Task context to work in for this command only.").
+			OptionsFunc(func() []huh.Option[string] {
+				options := []huh.Option[string]{huh.NewOption(fmt.Sprintf("(%s)", activeContext()), "")}
+				tags, _ := Tags(*path)
+				for _, t := range tags {
+					options = append(options, huh.NewOption(t, t))
+				}
+				return options
+			}, path).
+			Value(tag),
+	).WithHideFunc(func() bool { return !hasTags() })
+
+	typed = huh.NewGroup(
+		huh.NewInput().
+			Key(key).
+			Title("Tag (Optional)").
+			DescriptionFunc(func() string {
+				return fmt.Sprintf("Task context to work in for this command only; empty for %s.", activeContext())
+			}, path).
+			Prompt("🏷️ ").
+			Validate(func(s string) error {
+				if s == "" {
+					return nil
+				}
+				return validateTagName(s)
+			}).
+			Value(tag),
+	).WithHideFunc(hasTags)
+
+	return pick, typed
+}
+
+// UseTag makes tag the active task context, first adding it to the tasks
+// file when create is set. The CLI has no active tag of its own: the TUI keeps
+// it in the settings and passes it to each command. An empty tag returns to
+// the CLI's default context.
+func (e *CLIExecutor) UseTag(filePath, tag string, create bool) CLIResult {
+	if tag == "" {
+		if err := saveActiveTag(""); err != nil {
+			return CLIResult{Success: false, Error: err.Error(), Message: fmt.Sprintf("Failed to save the active tag: %s", err.Error())}
+		}
+		return CLIResult{Success: true, Message: "Using the default context"}
+	}
+	if err := validateTagName(tag); err != nil {
+		return CLIResult{Success: false, Error: err.Error(), Message: err.Error()}
+	}
+
+	result := CLIResult{Success: true}
+	if create {
+		result = e.runCLILocked(filePath, buildAddTagArgs(filePath, tag)...)
+		if !result.Success {
+			return result
+		}
+	} else if err := checkTagExists(filePath, tag); err != nil {
+		return CLIResult{Success: false, Error: err.Error(), Message: err.Error()}
+	}
+	if err := saveActiveTag(tag); err != nil {
+		return CLIResult{Success: false, Output: result.Output, Error: err.Error(), Message: fmt.Sprintf("Failed to save the active tag: %s", err.Error())}
+	}
+	result.Message = fmt.Sprintf("Using tag %s", tag)
+	return result
+}
+
+// buildAddTagArgs returns the CLI arguments that add a tag for UseTag
+func buildAddTagArgs(filePath, tag string) []string {
+	return []string{"add-tag", tag, "--file", filePath}
+}
+
+// checkTagExists checks that the tasks file at filePath has tag. An untagged
+// file holds only the default tag.
+func checkTagExists(filePath, tag string) error {
+	tags, err := Tags(filePath)
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		tags = []string{defaultTag}
+	}
+	for _, t := range tags {
+		if t == tag {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s has no tag %q", filePath, tag)
+}
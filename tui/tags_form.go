@@ -0,0 +1,286 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	tagsFormKeyFile = "file"
+	tagsFormKeyTag  = "tag"
+	tagsFormKeyNew  = "new"
+)
+
+// newTagChoice is the tag select's option for creating a tag. It can't be a tag name.
+const newTagChoice = "+new"
+
+// TagsModel holds the state for the tags form.
+type TagsModel struct {
+	form         *huh.Form
+	aborted      bool
+	isProcessing bool
+	statusMsg    string
+	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
+
+	// Form values
+	FilePath string
+	Choice   string // Tag to switch to, "" for the default context or newTagChoice
+	NewTag   string // Name of the tag to create
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
+}
+
+// NewTagsForm creates a new form for listing tags and switching the active one.
+func NewTagsForm() *TagsModel {
+	m := &TagsModel{
+		FilePath: lastFilePath(), // Pre-populate with the last used tasks file
+		Choice:   activeTag(),
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			newFilePathField(tagsFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json).", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key(tagsFormKeyTag).
+				Title("Active Tag").
+				DescriptionFunc(m.tagDescription, &m.FilePath).
+				OptionsFunc(m.tagOptions, &m.FilePath).
+				Value(&m.Choice),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Key(tagsFormKeyNew).
+				Title("New Tag").
+				Description("Name of the tag to create and switch to.").
+				Prompt("🏷️ ").
+				Validate(validateTagName).
+				Value(&m.NewTag),
+		).WithHideFunc(func() bool { return m.Choice != newTagChoice }),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
+	).WithTheme(huh.ThemeDracula())
+
+	return m
+}
+
+// tagOptions offers the default context, the tags in the file and creating a tag.
+func (m *TagsModel) tagOptions() []huh.Option[string] {
+	options := []huh.Option[string]{huh.NewOption("(default context)", "")}
+	tags, _ := Tags(m.FilePath) // An unreadable file still allows the default or a new tag
+	for _, tag := range tags {
+		options = append(options, huh.NewOption(tag, tag))
+	}
+	return append(options, huh.NewOption("+ Create a new tag", newTagChoice))
+}
+
+// tagDescription shows the active tag and notes a file without tags.
+func (m *TagsModel) tagDescription() string {
+	current := activeTag()
+	if current == "" {
+		current = "the default context"
+	}
+	description := fmt.Sprintf("Commands currently use %s.", current)
+
+	tags, err := Tags(m.FilePath)
+	switch {
+	case err != nil:
+		description += fmt.Sprintf(" The file's tags can't be read: %v", err)
+	case len(tags) == 0:
+		description += " This file has no tags yet; its tasks are a single default context."
+	}
+	return description
+}
+
+func (m *TagsModel) Init() tea.Cmd {
+	m.isProcessing = false
+	m.statusMsg = ""
+	m.aborted = false
+	return m.form.Init()
+}
+
+func (m *TagsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case tagsCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.statusMsg = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			m.statusMsg = "✅ " + msg.result.Message + "."
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
+	if m.isProcessing {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.statusMsg = "Cancelling..."
+				}
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, nil
+	}
+
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.statusMsg = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
+	var cmds []tea.Cmd
+	formModel, cmd := m.form.Update(msg)
+	if updatedForm, ok := formModel.(*huh.Form); ok {
+		m.form = updatedForm
+	} else {
+		m.statusMsg = "Error: Form update returned unexpected type."
+		debugLog.Error("form update did not return *huh.Form", "file", "tags_form.go", "type", fmt.Sprintf("%T", formModel))
+		return m, tea.Quit
+	}
+	cmds = append(cmds, cmd)
+
+	if m.form.State == huh.StateCompleted {
+		m.statusMsg = "Switching tags..."
+		m.isProcessing = true
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeUseTagCommand))
+	}
+
+	if m.form.State == huh.StateAborted {
+		m.aborted = true
+		return m, func() tea.Msg { return backToMenuMsg{} }
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if !m.isProcessing {
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			}
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *TagsModel) View() string {
+	if m.aborted {
+		return "Form aborted. Returning to main menu..."
+	}
+
+	var viewBuilder strings.Builder
+	viewBuilder.WriteString(m.form.View())
+
+	if m.statusMsg != "" {
+		viewBuilder.WriteString("\n\n")
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
+	}
+
+	helpStyle := lipgloss.NewStyle().Faint(true)
+	if m.isProcessing {
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
+	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
+	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Padding(1, 2).
+		Render(viewBuilder.String())
+}
+
+func (m *TagsModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
+// GetFormValues retrieves the structured data after completion.
+func (m *TagsModel) GetFormValues() (map[string]interface{}, error) {
+	if m.form.State != huh.StateCompleted {
+		return nil, fmt.Errorf("form is not yet completed")
+	}
+	return map[string]interface{}{
+		tagsFormKeyFile:      m.FilePath,
+		tagsFormKeyTag:       m.Choice,
+		tagsFormKeyNew:       m.NewTag,
+		advancedFlagsFormKey: m.AdvancedFlags,
+	}, nil
+}
+
+// tagsCompleteMsg is sent when the tag has been switched
+type tagsCompleteMsg struct {
+	result CLIResult
+}
+
+// executeUseTagCommand switches to the chosen tag, creating it first if it's new
+func (m *TagsModel) executeUseTagCommand() tea.Cmd {
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+		if m.Choice == newTagChoice {
+			return tagsCompleteMsg{result: executor.UseTag(m.FilePath, m.NewTag, true)}
+		}
+		return tagsCompleteMsg{result: executor.UseTag(m.FilePath, m.Choice, false)}
+	})
+}
+
+var _ tea.Model = &TagsModel{}
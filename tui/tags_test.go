@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestTagArgs(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	e := &CLIExecutor{}
+
+	if got, want := e.tagArgs([]string{"next-task", "tasks.json"}), []string{"next-task", "tasks.json"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("without a tag tagArgs() = %q, want %q", got, want)
+	}
+
+	if err := SaveSettings(Settings{ActiveTag: "feature-x"}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		e    *CLIExecutor
+		args []string
+		want []string
+	}{
+		{name: "active tag", e: e, args: []string{"next-task", "tasks.json"}, want: []string{"next-task", "tasks.json", "--tag", "feature-x"}},
+		{name: "executor tag overrides", e: e.WithTag("hotfix"), args: []string{"next-task", "tasks.json"}, want: []string{"next-task", "tasks.json", "--tag", "hotfix"}},
+		{name: "parse-prd in a tag", e: e.WithTag("hotfix"), args: []string{"parse-prd", "prd.txt"}, want: []string{"parse-prd", "prd.txt", "--tag", "hotfix"}},
+		{name: "add-task in a tag", e: e.WithTag("hotfix"), args: []string{"add-task", "--file=tasks.json"}, want: []string{"add-task", "--file=tasks.json", "--tag", "hotfix"}},
+		{name: "models takes no tag", e: e, args: []string{"models"}, want: []string{"models"}},
+		{name: "init takes no tag", e: e, args: []string{"init", "--yes"}, want: []string{"init", "--yes"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.tagArgs(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tagArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagArgsMatchCLI(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	e := (&CLIExecutor{}).WithTag("feature-x")
+	checkCLIArgs(t, e.tagArgs(buildGenerateTaskFilesArgs("tasks.json", "tasks", nil)))
+	checkCLIArgs(t, e.tagArgs(buildMoveTaskArgs("tasks.json", "1", "4")))
+}
+
+func TestTaggedParsePRDAndAddTaskMatchCLI(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// The parse-prd and add-task forms run their command in the tag field's tag
+	e := (&CLIExecutor{}).WithTag("hotfix")
+	for _, args := range [][]string{
+		e.tagArgs(buildParsePRDArgs("prd.txt", "tasks.json", 5, true, false)),
+		e.tagArgs(buildAddTaskArgs("tasks.json", "Add login", "", "", "", "", "", "", "", "", false)),
+	} {
+		if n := len(args); args[n-2] != tagFlag || args[n-1] != "hotfix" {
+			t.Errorf("args = %q, want them in tag hotfix", args)
+		}
+		checkCLIArgs(t, args)
+	}
+}
+
+func TestWithTagRunsCommandInTag(t *testing.T) {
+	fake := &fakeRunner{}
+	useFakeRunner(t, fake)
+
+	cliExecutor.WithTag("feature-x").ShowTask("tasks.json", "3")
+	want := [][]string{{"node", "../scripts/dev.js", "show-task", "tasks.json", "3", "--tag", "feature-x"}}
+	if !reflect.DeepEqual(fake.calls, want) {
+		t.Errorf("ran %q, want %q", fake.calls, want)
+	}
+}
+
+func TestTags(t *testing.T) {
+	dir := t.TempDir()
+	tagged := filepath.Join(dir, "tagged.json")
+	writeTasks(t, tagged, `{"master": {"tasks": []}, "feature-x": {"tasks": [{"id": 1}]}, "meta": {"version": 1}}`)
+	untagged := filepath.Join(dir, "untagged.json")
+	writeTasks(t, untagged, `{"tasks": [{"id": 1}]}`)
+
+	if got, err := Tags(tagged); err != nil || !reflect.DeepEqual(got, []string{"feature-x", "master"}) {
+		t.Errorf("Tags(tagged) = %q, %v, want [feature-x master]", got, err)
+	}
+	if got, err := Tags(untagged); err != nil || len(got) != 0 {
+		t.Errorf("Tags(untagged) = %q, %v, want none", got, err)
+	}
+	if _, err := Tags(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Tags() of a missing file succeeded, want an error")
+	}
+}
+
+func TestUseTag(t *testing.T) {
+	fake := &fakeRunner{}
+	useFakeRunner(t, fake)
+	tasksPath := filepath.Join(t.TempDir(), "tasks.json")
+
+	if result := cliExecutor.UseTag(tasksPath, "feature-x", true); !result.Success {
+		t.Fatalf("UseTag() failed: %s", result.Error)
+	}
+	want := [][]string{{"node", "../scripts/dev.js", "add-tag", "feature-x", "--file", tasksPath}}
+	if !reflect.DeepEqual(fake.calls, want) {
+		t.Errorf("ran %q, want %q", fake.calls, want)
+	}
+	checkCLIArgs(t, fake.calls[0][2:])
+	if got := activeTag(); got != "feature-x" {
+		t.Errorf("activeTag() = %q, want feature-x", got)
+	}
+
+	// Back to the default context without running the CLI
+	fake.calls = nil
+	if result := cliExecutor.UseTag(tasksPath, "", false); !result.Success || len(fake.calls) != 0 {
+		t.Errorf("UseTag(\"\") = %+v after running %q, want success without running anything", result, fake.calls)
+	}
+	if got := activeTag(); got != "" {
+		t.Errorf("activeTag() = %q, want none", got)
+	}
+}
+
+func TestUseExistingTag(t *testing.T) {
+	fake := &fakeRunner{}
+	useFakeRunner(t, fake)
+	dir := t.TempDir()
+	tagged := filepath.Join(dir, "tagged.json")
+	writeTasks(t, tagged, `{"master": {"tasks": []}, "feature-x": {"tasks": [{"id": 1}]}}`)
+	untagged := filepath.Join(dir, "untagged.json")
+	writeTasks(t, untagged, `{"tasks": [{"id": 1}]}`)
+
+	tests := []struct {
+		path, tag string
+		ok        bool
+	}{
+		{path: tagged, tag: "feature-x", ok: true},
+		{path: tagged, tag: "hotfix"},
+		{path: untagged, tag: "master", ok: true},
+		{path: untagged, tag: "feature-x"},
+	}
+	for _, tt := range tests {
+		if err := saveActiveTag("before"); err != nil {
+			t.Fatal(err)
+		}
+		result := cliExecutor.UseTag(tt.path, tt.tag, false)
+		want := "before"
+		if tt.ok {
+			want = tt.tag
+		}
+		if result.Success != tt.ok || activeTag() != want {
+			t.Errorf("UseTag(%s, %q) = %+v with active tag %q, want success %v and %q", filepath.Base(tt.path), tt.tag, result, activeTag(), tt.ok, want)
+		}
+	}
+	if len(fake.calls) != 0 {
+		t.Errorf("ran %q, want nothing run to switch tags", fake.calls)
+	}
+}
+
+func TestUseTagFailureKeepsActiveTag(t *testing.T) {
+	useFakeRunner(t, &fakeRunner{stderr: "Error: Tag \"feature-x\" already exists\n", err: errors.New("exit status 1"), exitCode: 1})
+	if err := saveActiveTag("master"); err != nil {
+		t.Fatal(err)
+	}
+
+	if result := cliExecutor.UseTag("tasks.json", "feature-x", true); result.Success {
+		t.Fatal("UseTag() succeeded, want the CLI's failure")
+	}
+	if got := activeTag(); got != "master" {
+		t.Errorf("activeTag() = %q, want master kept", got)
+	}
+}
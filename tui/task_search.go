@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// snippetContext is how many columns of a field are kept on each side of a
+// match in its snippet, so wide characters such as CJK count twice.
+const snippetContext = 30
+
+// taskMatch is a task or subtask whose text matches a search.
+type taskMatch struct {
+	ID      string
+	Title   string
+	Field   string // The first field that matched: "title", "description" or "details"
+	Snippet string // The match with some of the field around it, on one line
+}
+
+// newTaskMatcher compiles query into a case-insensitive pattern: a regular
+// expression with useRegex, otherwise the query's literal text.
+func newTaskMatcher(query string, useRegex bool) (*regexp.Regexp, error) {
+	if !useRegex {
+		query = regexp.QuoteMeta(query)
+	}
+	re, err := regexp.Compile("(?i)" + query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %w", err)
+	}
+	return re, nil
+}
+
+// searchTasks returns the tasks and subtasks in tf whose title, description
+// or details match re, in file order.
+func searchTasks(tf *TasksFile, re *regexp.Regexp) []taskMatch {
+	var matches []taskMatch
+	add := func(id string, task Task) {
+		fields := []struct{ name, text string }{
+			{"title", task.Title},
+			{"description", task.Description},
+			{"details", task.Details},
+		}
+		for _, field := range fields {
+			if loc := re.FindStringIndex(field.text); loc != nil {
+				matches = append(matches, taskMatch{ID: id, Title: task.Title, Field: field.name, Snippet: snippet(field.text, loc)})
+				return
+			}
+		}
+	}
+	for _, task := range tf.Tasks {
+		taskID := strconv.Itoa(task.ID)
+		add(taskID, task)
+		for _, sub := range task.Subtasks {
+			add(taskID+"."+strconv.Itoa(sub.ID), sub)
+		}
+	}
+	return matches
+}
+
+// snippet returns the text of s around the match at loc, with runs of
+// whitespace, new lines included, collapsed to single spaces and an ellipsis
+// where it's cut.
+func snippet(s string, loc []int) string {
+	// Whitespace counts as a column each, new lines included, as it will once collapsed
+	spaced := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return ' '
+			}
+			return r
+		}, s)
+	}
+	before, match, after := spaced(s[:loc[0]]), s[loc[0]:loc[1]], spaced(s[loc[1]:])
+	prefix, suffix := "", ""
+	if width := ansi.StringWidth(before); width > snippetContext {
+		before, prefix = ansi.TruncateLeft(before, width-snippetContext, ""), "…"
+		// TruncateLeft keeps a wide character cut in half
+		for ansi.StringWidth(before) > snippetContext {
+			_, size := utf8.DecodeRuneInString(before)
+			before = before[size:]
+		}
+	}
+	if ansi.StringWidth(after) > snippetContext {
+		after, suffix = ansi.Truncate(after, snippetContext, ""), "…"
+	}
+	return prefix + strings.Join(strings.Fields(before+match+after), " ") + suffix
+}
+
+// renderTaskMatches renders matches for the result view, one task a line
+// with the snippet of its match below unless the title matched, and each
+// match of re highlighted.
+func renderTaskMatches(matches []taskMatch, re *regexp.Regexp) string {
+	highlight := func(s string) string {
+		return re.ReplaceAllStringFunc(s, func(m string) string { return searchMatchStyle.Render(m) })
+	}
+	var b strings.Builder
+	for i, match := range matches {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%-6s %s", match.ID, highlight(match.Title))
+		if match.Field != "title" {
+			fmt.Fprintf(&b, "\n       %s: %s", match.Field, highlight(match.Snippet))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	taskSearchFormKeyFile  = "file"
+	taskSearchFormKeyQuery = "query"
+	taskSearchFormKeyRegex = "regex"
+)
+
+// TaskSearchModel holds the state for the task search form.
+type TaskSearchModel struct {
+	form      *huh.Form
+	aborted   bool
+	statusMsg string
+	width     int
+	height    int
+	result    resultView // Scrollable list of the matching tasks
+
+	// Form values
+	FilePath string
+	Query    string
+	UseRegex bool
+}
+
+// NewTaskSearchForm creates a new form for finding tasks by the text of their
+// titles, descriptions and details.
+func NewTaskSearchForm() *TaskSearchModel {
+	m := &TaskSearchModel{
+		FilePath: lastFilePath(), // Pre-populate with the last used tasks file
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			newFilePathField(taskSearchFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json) to search.", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key(taskSearchFormKeyRegex).
+				Title("Regular Expression").
+				Description("Treat the search as a regular expression rather than plain text?").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&m.UseRegex),
+
+			huh.NewInput().
+				Key(taskSearchFormKeyQuery).
+				Title("Search For").
+				Description("Text to find in task and subtask titles, descriptions and details, ignoring case.").
+				Prompt("🔍 ").
+				// Asked after the regular expression choice, so its answer is known here
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("search cannot be empty")
+					}
+					_, err := newTaskMatcher(s, m.UseRegex)
+					return err
+				}).
+				Value(&m.Query),
+		),
+	).WithTheme(huh.ThemeDracula())
+
+	return m
+}
+
+func (m *TaskSearchModel) Init() tea.Cmd {
+	m.statusMsg = ""
+	m.aborted = false
+	return m.form.Init()
+}
+
+func (m *TaskSearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result)
+	}
+
+	// Once the matches are shown, keep showing them rather than letting the
+	// completed form search again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
+	var cmds []tea.Cmd
+	formModel, cmd := m.form.Update(msg)
+	if updatedForm, ok := formModel.(*huh.Form); ok {
+		m.form = updatedForm
+	} else {
+		m.statusMsg = "Error: Form update returned unexpected type."
+		debugLog.Error("form update did not return *huh.Form", "file", "task_search_form.go", "type", fmt.Sprintf("%T", formModel))
+		return m, tea.Quit
+	}
+	cmds = append(cmds, cmd)
+
+	if m.form.State == huh.StateCompleted {
+		// Reading the file is quick, so the search runs right away
+		tf, err := LoadTasksFile(m.FilePath)
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("Error: failed to read tasks: %v", err)
+			m.form.State = huh.StateNormal // Back to the form to choose another file
+			return m, nil
+		}
+		re, err := newTaskMatcher(m.Query, m.UseRegex)
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %v", err)
+			m.form.State = huh.StateNormal
+			return m, nil
+		}
+		rememberFilePath(m.FilePath)
+		matches := searchTasks(tf, re)
+		m.statusMsg = fmt.Sprintf("✅ %d task(s) match %q", len(matches), m.Query)
+		if len(matches) == 0 {
+			m.statusMsg = fmt.Sprintf("✅ No tasks match %q", m.Query)
+		}
+		m.result.setContent(renderTaskMatches(matches, re))
+		return m, nil
+	}
+
+	if m.form.State == huh.StateAborted {
+		m.aborted = true
+		return m, func() tea.Msg { return backToMenuMsg{} }
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.aborted = true
+			return m, func() tea.Msg { return backToMenuMsg{} }
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *TaskSearchModel) View() string {
+	if m.aborted {
+		return "Form aborted. Returning to main menu..."
+	}
+
+	var viewBuilder strings.Builder
+	viewBuilder.WriteString(m.form.View())
+
+	if m.statusMsg != "" {
+		viewBuilder.WriteString("\n\n")
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
+	}
+
+	helpStyle := lipgloss.NewStyle().Faint(true)
+	if m.form.State == huh.StateCompleted {
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State != huh.StateAborted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Padding(1, 2).
+		Render(viewBuilder.String())
+}
+
+func (m *TaskSearchModel) keyContext() keyContext {
+	return m.result.keyContext(formKeyContext(m.form, false))
+}
+
+// GetFormValues retrieves the structured data after completion.
+func (m *TaskSearchModel) GetFormValues() (map[string]interface{}, error) {
+	if m.form.State != huh.StateCompleted {
+		return nil, fmt.Errorf("form is not yet completed")
+	}
+	return map[string]interface{}{
+		taskSearchFormKeyFile:  m.FilePath,
+		taskSearchFormKeyQuery: m.Query,
+		taskSearchFormKeyRegex: m.UseRegex,
+	}, nil
+}
+
+var _ tea.Model = &TaskSearchModel{}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+const searchTasksJSON = `{
+  "tasks": [
+    {"id": 1, "title": "Setup", "description": "Create the repo", "details": "Add CI and the OAuth client config", "status": "done"},
+    {"id": 2, "title": "Auth flow", "description": "Log users in", "status": "pending", "subtasks": [
+      {"id": 1, "title": "Tokens", "description": "Refresh oauth tokens before they expire", "status": "pending"}
+    ]},
+    {"id": 3, "title": "UI", "status": "pending"}
+  ]
+}`
+
+func TestSearchTasks(t *testing.T) {
+	var tf TasksFile
+	if err := json.Unmarshal([]byte(searchTasksJSON), &tf); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query    string
+		useRegex bool
+		want     []string // ID and field of each match
+	}{
+		{query: "oauth", want: []string{"1 details", "2.1 description"}},
+		{query: "AUTH", want: []string{"1 details", "2 title", "2.1 description"}},
+		{query: "o.auth", want: nil},
+		{query: `^(ui|setup)$`, useRegex: true, want: []string{"1 title", "3 title"}},
+	}
+	for _, tt := range tests {
+		re, err := newTaskMatcher(tt.query, tt.useRegex)
+		if err != nil {
+			t.Fatalf("newTaskMatcher(%q) error: %v", tt.query, err)
+		}
+		var got []string
+		for _, match := range searchTasks(&tf, re) {
+			got = append(got, match.ID+" "+match.Field)
+		}
+		if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+			t.Errorf("searchTasks(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+
+	if _, err := newTaskMatcher("(", true); err == nil {
+		t.Error("newTaskMatcher accepted an invalid regular expression")
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	text := strings.Repeat("a", 40) + "\n  needle  " + strings.Repeat("b", 40)
+	loc := []int{strings.Index(text, "needle"), strings.Index(text, "needle") + len("needle")}
+	got := snippet(text, loc)
+	want := "…" + strings.Repeat("a", 27) + " needle " + strings.Repeat("b", 28) + "…"
+	if got != want {
+		t.Errorf("snippet() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderTaskMatches(t *testing.T) {
+	re, _ := newTaskMatcher("token", false)
+	matches := []taskMatch{
+		{ID: "2.1", Title: "Tokens", Field: "title", Snippet: "Tokens"},
+		{ID: "4", Title: "Login", Field: "details", Snippet: "Store the token"},
+	}
+	got := renderTaskMatches(matches, re)
+	if want := "2.1    Tokens\n4      Login\n       details: Store the token"; ansi.Strip(got) != want {
+		t.Errorf("renderTaskMatches() = %q, want %q", ansi.Strip(got), want)
+	}
+	if !strings.Contains(got, searchMatchStyle.Render("token")) {
+		t.Errorf("renderTaskMatches() = %q, want the match highlighted", got)
+	}
+}
+
+func TestSnippetWideCharacters(t *testing.T) {
+	// Each CJK character takes two columns, so half as many fit
+	text := strings.Repeat("项", 40) + " needle " + strings.Repeat("目", 40)
+	loc := []int{strings.Index(text, "needle"), strings.Index(text, "needle") + len("needle")}
+	got := snippet(text, loc)
+	want := "…" + strings.Repeat("项", 14) + " needle " + strings.Repeat("目", 14) + "…"
+	if got != want {
+		t.Errorf("snippet() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// taskSummaryStyle renders the project summary in the menu header.
+var taskSummaryStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("111")).Padding(0, 2)
+
+// taskSummary counts the top-level tasks of a tasks file by status.
+type taskSummary struct {
+	Total, Todo, InProgress, Review, Done int
+	Ready                                 int // To do with all dependencies done
+}
+
+// summarizeTasks counts the tasks in tf. Statuses outside the four the TUI
+// sets only count towards the total.
+func summarizeTasks(tf *TasksFile) taskSummary {
+	ready := tf.dependenciesDone()
+	s := taskSummary{Total: len(tf.Tasks)}
+	for _, task := range tf.Tasks {
+		switch status := strings.ToLower(task.Status); {
+		case status == "" || matchesStatusFilter(status, FilterStatusTodo):
+			s.Todo++
+			if ready(fmt.Sprint(task.ID)) {
+				s.Ready++
+			}
+		case status == string(StatusInProgress):
+			s.InProgress++
+		case status == string(StatusReview):
+			s.Review++
+		case isDoneStatus(status):
+			s.Done++
+		}
+	}
+	return s
+}
+
+// String renders the summary on one line.
+func (s taskSummary) String() string {
+	return fmt.Sprintf("📋 %d tasks · %d to do · %d in progress · %d review · %d done · %d ready to start",
+		s.Total, s.Todo, s.InProgress, s.Review, s.Done, s.Ready)
+}
+
+// menuSummary returns the menu header line summarizing the tasks in the tasks
+// file at path, in tag if the file is tagged, or "" if there is no file to
+// summarize.
+func menuSummary(path, tag string) string {
+	if path == "" {
+		return ""
+	}
+	tf, err := loadTagTasks(path, tag)
+	if err != nil || len(tf.Tasks) == 0 {
+		return ""
+	}
+	return taskSummaryStyle.Render(summarizeTasks(tf).String())
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSummarizeTasks(t *testing.T) {
+	tf := loadSampleTasks(t)
+	tf.Tasks = append(tf.Tasks,
+		Task{ID: 4, Title: "Review", Status: "review"},
+		Task{ID: 5, Title: "Docs", Status: "in-progress"},
+		Task{ID: 6, Title: "Later", Status: "deferred"},
+	)
+
+	want := taskSummary{Total: 6, Todo: 2, InProgress: 1, Review: 1, Done: 1, Ready: 1}
+	if got := summarizeTasks(tf); got != want {
+		t.Errorf("summarizeTasks() = %+v, want %+v", got, want)
+	}
+	if got, want := want.String(), "📋 6 tasks · 2 to do · 1 in progress · 1 review · 1 done · 1 ready to start"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestLoadTagTasks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	writeTasks(t, path, `{
+		"master": {"tasks": [{"id": 1, "title": "Setup", "status": "done"}]},
+		"feature": {"tasks": [{"id": 1, "title": "Login", "status": "pending"}, {"id": 2, "title": "Logout", "status": "pending"}]}
+	}`)
+
+	for tag, want := range map[string]int{"feature": 2, "master": 1, "": 1} {
+		tf, err := loadTagTasks(path, tag)
+		if err != nil {
+			t.Fatalf("loadTagTasks(%q) error: %v", tag, err)
+		}
+		if len(tf.Tasks) != want {
+			t.Errorf("loadTagTasks(%q) has %d tasks, want %d", tag, len(tf.Tasks), want)
+		}
+	}
+	if _, err := loadTagTasks(path, "missing"); err == nil {
+		t.Error("loadTagTasks(missing tag) succeeded, want an error")
+	}
+
+	if got := menuSummary(filepath.Join(t.TempDir(), "missing.json"), ""); got != "" {
+		t.Errorf("menuSummary(missing file) = %q, want nothing", got)
+	}
+}
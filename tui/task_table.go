@@ -0,0 +1,268 @@
+package main
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+// taskTableChrome is the number of lines the table's borders and header take up.
+const taskTableChrome = 4
+
+// Columns of the task table.
+const (
+	taskColumnID = iota
+	taskColumnTitle
+	taskColumnStatus
+	taskColumnPriority
+	taskColumnDependencies
+)
+
+// taskTable renders tasks as a navigable table with one row per task and,
+// optionally, per subtask.
+type taskTable struct {
+	rows          [][]string
+	cursor        int
+	offset        int                       // Index of the first visible row
+	width, height int                       // Terminal size; zero until known
+	colors        map[string]lipgloss.Color // Status and priority colors, read once
+	search        outputSearch              // Search over the rows, moving the selection
+}
+
+// newTaskTable builds the table for the tasks matching any of statuses and any
+// of priorities; an empty list matches everything. With priorities the tasks are
+// ordered by their position in that list, then by ID. Each task is listed once,
+// with its subtasks following it.
+func newTaskTable(tf *TasksFile, statuses []FilterStatus, priorities []TaskPriority, withSubtasks bool) taskTable {
+	var tasks []Task
+	for _, task := range tf.Tasks {
+		if matchesAnyStatus(task.Status, statuses) && priorityRank(task.Priority, priorities) >= 0 {
+			tasks = append(tasks, task)
+		}
+	}
+	if len(priorities) > 0 {
+		sort.SliceStable(tasks, func(i, j int) bool {
+			ri, rj := priorityRank(tasks[i].Priority, priorities), priorityRank(tasks[j].Priority, priorities)
+			if ri != rj {
+				return ri < rj
+			}
+			return tasks[i].ID < tasks[j].ID
+		})
+	}
+
+	var rows [][]string
+	for _, task := range tasks {
+		taskID := strconv.Itoa(task.ID)
+		rows = append(rows, []string{taskID, task.Title, task.Status, task.Priority, strings.Join(task.Dependencies, ", ")})
+		if !withSubtasks {
+			continue
+		}
+		for _, sub := range task.Subtasks {
+			rows = append(rows, []string{
+				taskID + "." + strconv.Itoa(sub.ID),
+				"└ " + sub.Title,
+				sub.Status,
+				sub.Priority,
+				strings.Join(sub.Dependencies, ", "),
+			})
+		}
+	}
+	return taskTable{rows: rows, colors: keywordColorMap()}
+}
+
+// priorityRank returns the position of a task's priority in priorities, or -1
+// if it isn't listed. Every priority ranks 0 when the list is empty. Tasks
+// without a priority count as medium, the CLI's default.
+func priorityRank(priority string, priorities []TaskPriority) int {
+	if len(priorities) == 0 {
+		return 0
+	}
+	if priority == "" {
+		priority = string(PriorityMedium)
+	}
+	return slices.Index(priorities, TaskPriority(priority))
+}
+
+// matchesAnyStatus reports whether a task status passes any of the filters.
+// No filters match every status.
+func matchesAnyStatus(status string, filters []FilterStatus) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, filter := range filters {
+		if matchesStatusFilter(status, filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesStatusFilter reports whether a task status passes the list filter.
+// The CLI stores to-do tasks as "pending".
+func matchesStatusFilter(status string, filter FilterStatus) bool {
+	switch filter {
+	case FilterStatusNone:
+		return true
+	case FilterStatusTodo:
+		return status == "pending" || status == string(FilterStatusTodo)
+	default:
+		return status == string(filter)
+	}
+}
+
+// resize fits the table to a new terminal size.
+func (t *taskTable) resize(width, height int) {
+	t.width, t.height = width, height
+	t.scrollToCursor()
+}
+
+// visibleRows is how many rows fit on screen.
+func (t *taskTable) visibleRows() int {
+	if t.height <= 0 {
+		return len(t.rows)
+	}
+	return max(t.height-resultViewChrome-taskTableChrome, 1)
+}
+
+func (t *taskTable) scrollToCursor() {
+	visible := t.visibleRows()
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	} else if t.cursor >= t.offset+visible {
+		t.offset = t.cursor - visible + 1
+	}
+	t.offset = max(min(t.offset, len(t.rows)-visible), 0)
+}
+
+// update moves the selection. It returns the selected task ID when enter is pressed.
+func (t *taskTable) update(msg tea.KeyMsg) (selectedID string, selected bool) {
+	if len(t.rows) == 0 {
+		return "", false
+	}
+	query := t.search.query
+	if t.search.handleKey(msg) {
+		if t.search.query != query {
+			t.search.find(t.rowTexts(), t.cursor)
+		}
+		if row, ok := t.search.currentLine(); ok {
+			t.cursor = row
+			t.scrollToCursor()
+		}
+		return "", false
+	}
+	switch msg.String() {
+	case "up", "k":
+		t.cursor--
+	case "down", "j":
+		t.cursor++
+	case "pgup":
+		t.cursor -= t.visibleRows()
+	case "pgdown":
+		t.cursor += t.visibleRows()
+	case "home", "g":
+		t.cursor = 0
+	case "end", "G":
+		t.cursor = len(t.rows) - 1
+	case "enter":
+		return t.rows[t.cursor][taskColumnID], true
+	}
+	t.cursor = max(min(t.cursor, len(t.rows)-1), 0)
+	t.scrollToCursor()
+	return "", false
+}
+
+// moveRow moves the selected row up (-1) or down (+1) past its neighbour,
+// keeping it selected. It reports whether the row moved.
+func (t *taskTable) moveRow(delta int) bool {
+	to := t.cursor + delta
+	if to < 0 || to >= len(t.rows) {
+		return false
+	}
+	t.rows[t.cursor], t.rows[to] = t.rows[to], t.rows[t.cursor]
+	t.cursor = to
+	t.scrollToCursor()
+	return true
+}
+
+// rowIDs returns the task ID of each row, in order.
+func (t *taskTable) rowIDs() []string {
+	ids := make([]string, len(t.rows))
+	for i, row := range t.rows {
+		ids[i] = row[taskColumnID]
+	}
+	return ids
+}
+
+// rowTexts returns each row's cells as one line, for searching.
+func (t *taskTable) rowTexts() []string {
+	texts := make([]string, len(t.rows))
+	for i, row := range t.rows {
+		texts[i] = strings.Join(row, "  ")
+	}
+	return texts
+}
+
+// searching reports whether a search query is being typed.
+func (t *taskTable) searching() bool {
+	return t.search.typing
+}
+
+// empty reports whether no tasks matched.
+func (t *taskTable) empty() bool {
+	return len(t.rows) == 0
+}
+
+// View renders the visible rows.
+func (t *taskTable) View() string {
+	if len(t.rows) == 0 {
+		return lipgloss.NewStyle().Faint(true).Render("No tasks match the selected filter.")
+	}
+
+	end := min(t.offset+t.visibleRows(), len(t.rows))
+	visible := t.rows[t.offset:end]
+	matched := make(map[int]bool, len(t.search.matches))
+	for _, row := range t.search.matches {
+		matched[row] = true
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Padding(0, 1)
+	cellStyle := lipgloss.NewStyle().Padding(0, 1)
+	selectedBg := lipgloss.Color("237")
+
+	tbl := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderColumn(false).
+		Wrap(false). // Keep one line per row so scrolling stays in step
+		BorderStyle(lipgloss.NewStyle().Faint(true)).
+		Headers("ID", "Title", "Status", "Priority", "Dependencies").
+		Rows(visible...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return headerStyle
+			}
+			style := cellStyle
+			if col == taskColumnStatus || col == taskColumnPriority {
+				if color, ok := t.colors[visible[row][col]]; ok {
+					style = style.Foreground(color)
+				}
+			}
+			if t.offset+row == t.cursor {
+				style = style.Background(selectedBg).Bold(true)
+			} else if matched[t.offset+row] {
+				style = style.Background(searchMatchStyle.GetBackground())
+			}
+			return style
+		})
+	// Only shrink the table (leaving room for the form's padding); widening it
+	// would just spread the columns apart
+	out := tbl.Render()
+	if available := t.width - 4; t.width > 0 && lipgloss.Width(out) > available {
+		out = tbl.Width(available).Render()
+	}
+	return out
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func tableIDs(t taskTable) []string {
+	var ids []string
+	for _, row := range t.rows {
+		ids = append(ids, row[taskColumnID])
+	}
+	return ids
+}
+
+func TestNewTaskTable(t *testing.T) {
+	tf := loadSampleTasks(t)
+
+	tests := []struct {
+		name         string
+		statuses     []FilterStatus
+		withSubtasks bool
+		want         []string
+	}{
+		{name: "all tasks", want: []string{"1", "2", "3"}},
+		{name: "none filter", statuses: []FilterStatus{FilterStatusNone}, want: []string{"1", "2", "3"}},
+		{name: "with subtasks", withSubtasks: true, want: []string{"1", "2", "2.1", "3", "3.1", "3.2"}},
+		{name: "done only", statuses: []FilterStatus{FilterStatusDone}, want: []string{"1"}},
+		{name: "to do matches pending", statuses: []FilterStatus{FilterStatusTodo}, want: []string{"2", "3"}},
+		{name: "no matches", statuses: []FilterStatus{FilterStatusReview}},
+		{name: "several statuses", statuses: []FilterStatus{FilterStatusDone, FilterStatusTodo}, want: []string{"1", "2", "3"}},
+		{name: "overlapping statuses list subtasks once", statuses: []FilterStatus{FilterStatusTodo, FilterStatusTodo}, withSubtasks: true, want: []string{"2", "2.1", "3", "3.1", "3.2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tableIDs(newTaskTable(tf, tt.statuses, nil, tt.withSubtasks))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rows = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTaskTablePriorities(t *testing.T) {
+	var tf TasksFile
+	data := `{"tasks": [
+		{"id": 1, "title": "A", "status": "pending", "priority": "low"},
+		{"id": 2, "title": "B", "status": "pending", "priority": "high", "subtasks": [{"id": 1, "title": "B1", "status": "pending"}]},
+		{"id": 3, "title": "C", "status": "done"},
+		{"id": 4, "title": "D", "status": "pending", "priority": "high"}
+	]}`
+	if err := json.Unmarshal([]byte(data), &tf); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		priorities []TaskPriority
+		want       []string
+	}{
+		{name: "no priority filter keeps file order", want: []string{"1", "2", "2.1", "3", "4"}},
+		{name: "single priority", priorities: []TaskPriority{PriorityHigh}, want: []string{"2", "2.1", "4"}},
+		{name: "ordered by priority then ID", priorities: []TaskPriority{PriorityMedium, PriorityHigh}, want: []string{"3", "2", "2.1", "4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tableIDs(newTaskTable(&tf, nil, tt.priorities, true))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rows = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskTableDependenciesColumn(t *testing.T) {
+	table := newTaskTable(loadSampleTasks(t), nil, nil, true)
+	last := table.rows[len(table.rows)-1]
+	if got, want := last[taskColumnDependencies], "1, 2.1"; got != want {
+		t.Errorf("dependencies of 3.2 = %q, want %q", got, want)
+	}
+}
+
+func TestTaskTableSelection(t *testing.T) {
+	table := newTaskTable(loadSampleTasks(t), nil, nil, true)
+	key := func(s string) tea.KeyMsg {
+		switch s {
+		case "up":
+			return tea.KeyMsg{Type: tea.KeyUp}
+		case "down":
+			return tea.KeyMsg{Type: tea.KeyDown}
+		case "enter":
+			return tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	table.update(key("up"))
+	if table.cursor != 0 {
+		t.Errorf("cursor moved above the first row: %d", table.cursor)
+	}
+
+	table.update(key("down"))
+	table.update(key("down"))
+	if id, ok := table.update(key("enter")); !ok || id != "2.1" {
+		t.Errorf("enter selected %q, %v, want 2.1", id, ok)
+	}
+
+	table.update(key("G"))
+	table.update(key("down"))
+	if id, _ := table.update(key("enter")); id != "3.2" {
+		t.Errorf("enter after moving past the end selected %q, want 3.2", id)
+	}
+}
+
+func TestTaskTableScrolling(t *testing.T) {
+	table := newTaskTable(loadSampleTasks(t), nil, nil, true)
+	// Leaves room for two rows
+	table.resize(80, resultViewChrome+taskTableChrome+2)
+
+	for i := 0; i < 4; i++ {
+		table.update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	if table.cursor != 4 || table.offset != 3 {
+		t.Errorf("cursor, offset = %d, %d, want 4, 3", table.cursor, table.offset)
+	}
+
+	table.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if table.cursor != 0 || table.offset != 0 {
+		t.Errorf("after home: cursor, offset = %d, %d, want 0, 0", table.cursor, table.offset)
+	}
+}
+
+func TestTaskTableWideCharacters(t *testing.T) {
+	tf := &TasksFile{Tasks: []Task{
+		{ID: 1, Title: "Set up 项目结构 🚀 deploy ⏱️", Status: "pending", Priority: "high"},
+		{ID: 2, Title: "Docs", Status: "done"},
+		{ID: 3, Title: "🏷️ タグ and 🇯🇵 flags", Status: "pending"},
+	}}
+
+	for _, width := range []int{120, 40} {
+		table := newTaskTable(tf, nil, nil, false)
+		table.resize(width, 30)
+		view := table.View()
+		lines := strings.Split(view, "\n")
+		first := ansi.StringWidth(lines[0])
+		if first > width {
+			t.Errorf("at width %d the table is %d columns wide", width, first)
+		}
+		// A glyph measured as one column but drawn as two would push its
+		// row's border out of line with the others
+		for _, line := range lines {
+			if got := ansi.StringWidth(line); got != first {
+				t.Errorf("at width %d line %q is %d columns wide, want %d", width, ansi.Strip(line), got, first)
+			}
+		}
+		if width == 120 && !strings.Contains(ansi.Strip(view), "Set up 项目结构 🚀 deploy ⏱️") {
+			t.Errorf("at width %d the title was cut:\n%s", width, ansi.Strip(view))
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// taskTime is a timestamp in a tasks file. The CLI doesn't record any, but
+// files kept by other tools may: an RFC 3339 or date-only string, or a Unix
+// time in milliseconds. Anything else reads as unset rather than failing the
+// whole file.
+type taskTime struct {
+	time.Time
+}
+
+// UnmarshalJSON accepts the timestamp forms of taskTime.
+func (t *taskTime) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil
+	}
+	switch v := v.(type) {
+	case float64:
+		t.Time = time.UnixMilli(int64(v))
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+			if parsed, err := time.Parse(layout, v); err == nil {
+				t.Time = parsed
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// infoAddedPattern matches the marker update-subtask puts around the notes it
+// appends to details, e.g. "<info added on 2025-05-01T21:59:10.551Z>".
+var infoAddedPattern = regexp.MustCompile(`<info added on ([^>]+)>`)
+
+// defaultStaleDays is how many days a task may stay in progress before the
+// timeline warns that it's stale, unless the form changes it.
+const defaultStaleDays = 7
+
+// timelineEntry is a task or subtask in the timeline.
+type timelineEntry struct {
+	ID       string
+	Title    string
+	Status   string
+	Created  time.Time // Zero if unknown
+	Updated  time.Time // Zero if unknown
+	InStatus time.Time // When it entered its status; zero if unknown
+}
+
+// timelineEntries returns the tasks in file order, followed in each case by
+// their subtasks when withSubtasks is set, with the timestamps the file
+// records. A task without an updatedAt is taken to be updated when
+// update-subtask last added notes to its details. ok is false if no task has
+// any timestamp.
+func timelineEntries(tf *TasksFile, withSubtasks bool) (entries []timelineEntry, ok bool) {
+	add := func(id string, task Task) {
+		entry := timelineEntry{
+			ID:       id,
+			Title:    task.Title,
+			Status:   task.Status,
+			Created:  task.CreatedAt.Time,
+			Updated:  task.UpdatedAt.Time,
+			InStatus: task.StatusChangedAt.Time,
+		}
+		if entry.Status == "" {
+			entry.Status = "pending"
+		}
+		if entry.Updated.IsZero() {
+			entry.Updated = lastInfoAdded(task.Details)
+		}
+		ok = ok || !entry.Created.IsZero() || !entry.Updated.IsZero() || !entry.InStatus.IsZero()
+		entries = append(entries, entry)
+	}
+	for _, task := range tf.Tasks {
+		taskID := strconv.Itoa(task.ID)
+		add(taskID, task)
+		if !withSubtasks {
+			continue
+		}
+		for _, sub := range task.Subtasks {
+			add(taskID+"."+strconv.Itoa(sub.ID), sub)
+		}
+	}
+	return entries, ok
+}
+
+// lastInfoAdded returns the latest time update-subtask added notes to details,
+// or the zero time if it never did.
+func lastInfoAdded(details string) time.Time {
+	var last time.Time
+	for _, match := range infoAddedPattern.FindAllStringSubmatch(details, -1) {
+		if t, err := time.Parse(time.RFC3339Nano, match[1]); err == nil && t.After(last) {
+			last = t
+		}
+	}
+	return last
+}
+
+// age returns how long the entry has been in its status, or failing that
+// since it was last updated or created, and which of those it measured from.
+// It returns "" if none is known.
+func (e timelineEntry) age(now time.Time) (time.Duration, string) {
+	switch {
+	case !e.InStatus.IsZero():
+		return now.Sub(e.InStatus), "in status"
+	case !e.Updated.IsZero():
+		return now.Sub(e.Updated), "since updated"
+	case !e.Created.IsZero():
+		return now.Sub(e.Created), "since created"
+	}
+	return 0, ""
+}
+
+// isStale reports whether the entry is in progress and has been, as far as
+// its timestamps tell, for longer than staleAfter.
+func (e timelineEntry) isStale(now time.Time, staleAfter time.Duration) bool {
+	age, from := e.age(now)
+	return e.Status == string(StatusInProgress) && from != "" && age > staleAfter
+}
+
+// formatAge renders a duration as whole minutes, hours or days, whichever is
+// the largest that fits, e.g. "3d".
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// formatDate renders t as a date, or "-" if it's unknown.
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02")
+}
+
+// staleTaskStyle marks in-progress tasks that have been so for too long.
+var staleTaskStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+
+// noTimestampsMessage explains an empty timeline.
+const noTimestampsMessage = "This tasks file records no timestamps, so task ages are unavailable.\n" +
+	"The CLI doesn't write them; a task's createdAt, updatedAt and statusChangedAt fields are shown if another tool sets them, " +
+	"as are the dates update-subtask adds to details."
+
+// renderTaskTimeline renders each entry on a line with its status, how long
+// it has been that way and its created and updated dates, in-progress tasks
+// older than staleAfter in the warning color.
+func renderTaskTimeline(entries []timelineEntry, now time.Time, staleAfter time.Duration) string {
+	lines := []string{fixSummaryHeaderStyle.Render(fmt.Sprintf("%-6s %-12s %-20s %-10s %-10s %s", "ID", "Status", "Age", "Created", "Updated", "Title"))}
+	stale := 0
+	for _, e := range entries {
+		age := "-"
+		if d, from := e.age(now); from != "" {
+			age = formatAge(d) + " " + from
+		}
+		line := fmt.Sprintf("%-6s %-12s %-20s %-10s %-10s %s", e.ID, e.Status, age, formatDate(e.Created), formatDate(e.Updated), e.Title)
+		if e.isStale(now, staleAfter) {
+			stale++
+			line = staleTaskStyle.Render(line + " ⚠️ stale")
+		}
+		lines = append(lines, line)
+	}
+	if stale > 0 {
+		lines = append(lines, "", staleTaskStyle.Render(fmt.Sprintf("%d task(s) in progress for more than %s.", stale, formatAge(staleAfter))))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	taskTimelineFormKeyFile     = "file"
+	taskTimelineFormKeySubtasks = "subtasks"
+	taskTimelineFormKeyStale    = "stale-days"
+)
+
+// TaskTimelineModel holds the state for the task timeline view, which shows
+// how long each task has been in its status from the tasks file's timestamps.
+type TaskTimelineModel struct {
+	form      *huh.Form
+	aborted   bool
+	statusMsg string
+	width     int
+	height    int
+	result    resultView // Scrollable rendering of the timeline
+
+	// Form values
+	FilePath     string
+	WithSubtasks bool
+	StaleDays    string // Days in progress before a task is stale; empty for defaultStaleDays
+}
+
+// NewTaskTimelineForm creates a new form for viewing a tasks file's timeline.
+func NewTaskTimelineForm() *TaskTimelineModel {
+	m := &TaskTimelineModel{
+		FilePath: lastFilePath(), // Pre-populate with the last used tasks file
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			newFilePathField(taskTimelineFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json) to show the timeline of.", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key(taskTimelineFormKeySubtasks).
+				Title("Include Subtasks").
+				Description("Show subtasks and their ages too.").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&m.WithSubtasks),
+
+			huh.NewInput().
+				Key(taskTimelineFormKeyStale).
+				Title("Stale After (Days)").
+				Description(fmt.Sprintf("Warn about tasks in progress for longer than this; empty for %d.", defaultStaleDays)).
+				Prompt("⏳ ").
+				Validate(func(s string) error { return validateOptionalCount(s, "days") }).
+				Value(&m.StaleDays),
+		),
+	).WithTheme(huh.ThemeDracula())
+
+	return m
+}
+
+func (m *TaskTimelineModel) Init() tea.Cmd {
+	m.statusMsg = ""
+	m.aborted = false
+	return m.form.Init()
+}
+
+func (m *TaskTimelineModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result)
+	}
+
+	// Once the timeline is shown, keep showing it rather than letting the
+	// completed form render it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
+	var cmds []tea.Cmd
+	formModel, cmd := m.form.Update(msg)
+	if updatedForm, ok := formModel.(*huh.Form); ok {
+		m.form = updatedForm
+	} else {
+		m.statusMsg = "Error: Form update returned unexpected type."
+		debugLog.Error("form update did not return *huh.Form", "file", "task_timeline_form.go", "type", fmt.Sprintf("%T", formModel))
+		return m, tea.Quit
+	}
+	cmds = append(cmds, cmd)
+
+	if m.form.State == huh.StateCompleted {
+		// Reading the file is quick, so the timeline is rendered right away
+		tf, err := LoadTasksFile(m.FilePath)
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("Error: failed to read tasks: %v", err)
+			m.form.State = huh.StateNormal // Back to the form to choose another file
+			return m, nil
+		}
+		rememberFilePath(m.FilePath)
+		entries, ok := timelineEntries(tf, m.WithSubtasks)
+		if !ok {
+			m.statusMsg = "✅ Task timeline: no timestamps"
+			m.result.setContent(noTimestampsMessage)
+			return m, nil
+		}
+		m.statusMsg = "✅ Task timeline"
+		m.result.setContent(renderTaskTimeline(entries, time.Now(), m.staleAfter()))
+		return m, nil
+	}
+
+	if m.form.State == huh.StateAborted {
+		m.aborted = true
+		return m, func() tea.Msg { return backToMenuMsg{} }
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.aborted = true
+			return m, func() tea.Msg { return backToMenuMsg{} }
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *TaskTimelineModel) View() string {
+	if m.aborted {
+		return "Form aborted. Returning to main menu..."
+	}
+
+	var viewBuilder strings.Builder
+	viewBuilder.WriteString(m.form.View())
+
+	if m.statusMsg != "" {
+		viewBuilder.WriteString("\n\n")
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
+	}
+
+	helpStyle := lipgloss.NewStyle().Faint(true)
+	if m.form.State == huh.StateCompleted {
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State != huh.StateAborted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Padding(1, 2).
+		Render(viewBuilder.String())
+}
+
+// staleAfter returns how long a task may be in progress before it's stale.
+func (m *TaskTimelineModel) staleAfter() time.Duration {
+	days, err := strconv.Atoi(m.StaleDays)
+	if err != nil { // Empty, as the field allows nothing else
+		days = defaultStaleDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func (m *TaskTimelineModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, false)) }
+
+// GetFormValues retrieves the structured data after completion.
+func (m *TaskTimelineModel) GetFormValues() (map[string]interface{}, error) {
+	if m.form.State != huh.StateCompleted {
+		return nil, fmt.Errorf("form is not yet completed")
+	}
+	return map[string]interface{}{
+		taskTimelineFormKeyFile:     m.FilePath,
+		taskTimelineFormKeySubtasks: m.WithSubtasks,
+		taskTimelineFormKeyStale:    m.StaleDays,
+	}, nil
+}
+
+var _ tea.Model = &TaskTimelineModel{}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestTaskTimeParsesTimestamps(t *testing.T) {
+	var tasks []Task
+	data := `[
+		{"id": 1, "createdAt": "2025-05-01T10:00:00Z", "updatedAt": "2025-05-03", "statusChangedAt": 1746439200000},
+		{"id": 2, "createdAt": "yesterday", "updatedAt": null, "statusChangedAt": {"at": 1}}
+	]`
+	if err := json.Unmarshal([]byte(data), &tasks); err != nil {
+		t.Fatalf("unreadable timestamps failed the file: %v", err)
+	}
+
+	if want := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC); !tasks[0].CreatedAt.Equal(want) {
+		t.Errorf("createdAt = %v, want %v", tasks[0].CreatedAt, want)
+	}
+	if want := time.Date(2025, 5, 3, 0, 0, 0, 0, time.UTC); !tasks[0].UpdatedAt.Equal(want) {
+		t.Errorf("updatedAt = %v, want %v", tasks[0].UpdatedAt, want)
+	}
+	if want := time.Date(2025, 5, 5, 10, 0, 0, 0, time.UTC); !tasks[0].StatusChangedAt.Equal(want) {
+		t.Errorf("statusChangedAt = %v, want %v", tasks[0].StatusChangedAt, want)
+	}
+	if !tasks[1].CreatedAt.IsZero() || !tasks[1].UpdatedAt.IsZero() || !tasks[1].StatusChangedAt.IsZero() {
+		t.Errorf("unreadable timestamps = %+v, want them unset", tasks[1])
+	}
+}
+
+func TestTimelineEntries(t *testing.T) {
+	if _, ok := timelineEntries(loadSampleTasks(t), true); ok {
+		t.Error("timelineEntries found timestamps in a file without any")
+	}
+
+	var tf TasksFile
+	data := `{"tasks": [
+		{"id": 1, "title": "Setup", "status": "done"},
+		{"id": 2, "title": "Core", "subtasks": [
+			{"id": 1, "title": "Models", "details": "<info added on 2025-05-01T21:59:10.551Z>\nA\n</info added on 2025-05-01T21:59:10.551Z>\n<info added on 2025-05-02T08:00:00Z>\nB\n</info added on 2025-05-02T08:00:00Z>"}
+		]}
+	]}`
+	if err := json.Unmarshal([]byte(data), &tf); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := timelineEntries(&tf, false); ok {
+		t.Error("timelineEntries found timestamps without the subtasks that have them")
+	}
+	entries, ok := timelineEntries(&tf, true)
+	if !ok || len(entries) != 3 {
+		t.Fatalf("timelineEntries = %+v, %v, want 3 entries with timestamps", entries, ok)
+	}
+	if want := time.Date(2025, 5, 2, 8, 0, 0, 0, time.UTC); entries[2].ID != "2.1" || !entries[2].Updated.Equal(want) {
+		t.Errorf("subtask entry = %+v, want 2.1 updated at its last note, %v", entries[2], want)
+	}
+	if entries[1].Status != "pending" {
+		t.Errorf("status of a task without one = %q, want pending", entries[1].Status)
+	}
+}
+
+func TestRenderTaskTimeline(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	entries := []timelineEntry{
+		{ID: "1", Title: "Setup", Status: "done", Created: now.Add(-30 * 24 * time.Hour)},
+		{ID: "2", Title: "Core", Status: "in-progress", InStatus: now.Add(-10 * 24 * time.Hour), Updated: now.Add(-2 * time.Hour)},
+		{ID: "3", Title: "UI", Status: "in-progress", Updated: now.Add(-3 * time.Hour)},
+		{ID: "4", Title: "Docs", Status: "pending"},
+	}
+	got := ansi.Strip(renderTaskTimeline(entries, now, 7*24*time.Hour))
+	lines := strings.Split(got, "\n")
+
+	for i, want := range []string{
+		"30d since created",
+		"10d in status",
+		"3h since updated",
+	} {
+		if !strings.Contains(lines[i+1], want) {
+			t.Errorf("line for task %s = %q, want %q", entries[i].ID, lines[i+1], want)
+		}
+	}
+	if !strings.HasSuffix(lines[2], "⚠️ stale") || strings.Contains(lines[3], "stale") || strings.Contains(lines[1], "stale") {
+		t.Errorf("timeline =\n%s\nwant only task 2 marked stale", got)
+	}
+	if !strings.HasPrefix(lines[4], "4      pending      -  ") {
+		t.Errorf("line for a task without timestamps = %q", lines[4])
+	}
+	if !strings.HasSuffix(got, "1 task(s) in progress for more than 7d.") {
+		t.Errorf("timeline =\n%s\nwant the stale count at the end", got)
+	}
+}
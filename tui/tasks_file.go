@@ -0,0 +1,268 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Task mirrors the fields of a tasks.json entry that the TUI inspects directly.
+type Task struct {
+	ID                 int        `json:"id"`
+	Title              string     `json:"title"`
+	Description        string     `json:"description"`
+	Details            string     `json:"details"`
+	Status             string     `json:"status"`
+	Priority           string     `json:"priority"`
+	Type               string     `json:"type"`               // "standard" or "checkpoint"; empty is standard
+	AcceptanceCriteria string     `json:"acceptanceCriteria"` // Set for checkpoints
+	Dependencies       taskIDList `json:"dependencies"`
+	Subtasks           []Task     `json:"subtasks"`
+
+	// Timestamps, when the file records them; the CLI itself doesn't, see taskTime
+	CreatedAt       taskTime `json:"createdAt"`
+	UpdatedAt       taskTime `json:"updatedAt"`
+	StatusChangedAt taskTime `json:"statusChangedAt"` // When the task entered its current status
+}
+
+// TasksFile is the parsed contents of a tasks.json file.
+type TasksFile struct {
+	Tasks []Task `json:"tasks"`
+}
+
+// taskIDList holds dependency IDs, which the CLI writes as either numbers or dotted strings.
+type taskIDList []string
+
+// UnmarshalJSON accepts a mix of numeric and string IDs.
+func (l *taskIDList) UnmarshalJSON(data []byte) error {
+	var raw []interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	ids := make(taskIDList, 0, len(raw))
+	for _, v := range raw {
+		switch id := v.(type) {
+		case float64:
+			ids = append(ids, strconv.Itoa(int(id)))
+		case string:
+			ids = append(ids, id)
+		default:
+			return fmt.Errorf("unexpected dependency ID type %T", v)
+		}
+	}
+	*l = ids
+	return nil
+}
+
+// LoadTasksFile reads and parses a tasks file: tasks.json, or a markdown file
+// in the format of the CLI's generated task files, see parseTasksMarkdown.
+// Relative paths are resolved the same way the CLI resolves them. The tasks
+// are those of the active tag, see loadTagTasks.
+func LoadTasksFile(path string) (*TasksFile, error) {
+	return loadTagTasks(path, activeTag())
+}
+
+// loadTagTasks loads the tasks file at path in tag, "" meaning the default
+// tag. Like the CLI, it reads a tasks.json in the tagged layout, which keeps
+// each tag's tasks under its name, in tag, and it is an error for the file not
+// to have that tag.
+func loadTagTasks(path, tag string) (*TasksFile, error) {
+	data, err := os.ReadFile(resolveTasksPath(path))
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".md") {
+		return parseTasksMarkdown(string(data))
+	}
+	if data, err = tagTasks(data, tag); err != nil {
+		return nil, err
+	}
+	var tf TasksFile
+	if err := json.Unmarshal(data, &tf); err != nil {
+		return nil, fmt.Errorf("failed to parse tasks file: %w", err)
+	}
+	return &tf, nil
+}
+
+// tagTasks returns the part of a tasks.json that holds tag's tasks: the whole
+// file when it is untagged, with its tasks at the top level.
+func tagTasks(data []byte, tag string) ([]byte, error) {
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(data, &top); err != nil {
+		return nil, fmt.Errorf("failed to parse tasks file: %w", err)
+	}
+	if tag == "" {
+		tag = defaultTag
+	}
+	if _, ok := top["tasks"]; ok {
+		if tag != defaultTag {
+			return nil, fmt.Errorf("tasks file has no tag %q", tag)
+		}
+		return data, nil
+	}
+	var context struct {
+		Tasks json.RawMessage `json:"tasks"`
+	}
+	if raw, ok := top[tag]; !ok || json.Unmarshal(raw, &context) != nil || context.Tasks == nil {
+		return nil, fmt.Errorf("tasks file has no tag %q", tag)
+	}
+	return top[tag], nil
+}
+
+// TaskIDs returns the IDs of all tasks in file order, followed in each case by
+// the task's subtask IDs ("3.1") when includeSubtasks is set.
+func (tf *TasksFile) TaskIDs(includeSubtasks bool) []string {
+	var ids []string
+	for _, task := range tf.Tasks {
+		taskID := strconv.Itoa(task.ID)
+		ids = append(ids, taskID)
+		if !includeSubtasks {
+			continue
+		}
+		for _, sub := range task.Subtasks {
+			ids = append(ids, taskID+"."+strconv.Itoa(sub.ID))
+		}
+	}
+	return ids
+}
+
+// HasTask reports whether the file contains the task or dotted subtask ID.
+func (tf *TasksFile) HasTask(id string) bool {
+	_, ok := tf.Find(id)
+	return ok
+}
+
+// Find returns the task or dotted subtask with id.
+func (tf *TasksFile) Find(id string) (Task, bool) {
+	tasks := tf.Tasks
+	var found *Task
+	for _, part := range strings.Split(id, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return Task{}, false
+		}
+		found = nil
+		for i := range tasks {
+			if tasks[i].ID == n {
+				found = &tasks[i]
+				break
+			}
+		}
+		if found == nil {
+			return Task{}, false
+		}
+		tasks = found.Subtasks
+	}
+	return *found, true
+}
+
+// IDsUpdatedFrom returns the IDs of the tasks an update from fromID sends to
+// the AI: those from that ID on that aren't done, as the CLI picks them.
+func (tf *TasksFile) IDsUpdatedFrom(fromID int) []string {
+	var ids []string
+	for _, task := range tf.Tasks {
+		if task.ID >= fromID && task.Status != "done" {
+			ids = append(ids, strconv.Itoa(task.ID))
+		}
+	}
+	return ids
+}
+
+// IDsWithStatus returns the IDs of the tasks and subtasks with status, in file
+// order. The CLI stores to-do tasks as "pending", and subtasks without a status
+// are pending.
+func (tf *TasksFile) IDsWithStatus(status TaskStatus) []string {
+	var ids []string
+	for _, task := range tf.Tasks {
+		taskID := strconv.Itoa(task.ID)
+		if matchesStatusFilter(task.Status, FilterStatus(status)) {
+			ids = append(ids, taskID)
+		}
+		for _, sub := range task.Subtasks {
+			subStatus := sub.Status
+			if subStatus == "" {
+				subStatus = "pending"
+			}
+			if matchesStatusFilter(subStatus, FilterStatus(status)) {
+				ids = append(ids, taskID+"."+strconv.Itoa(sub.ID))
+			}
+		}
+	}
+	return ids
+}
+
+// resolveTasksPath makes a relative path relative to the directory commands run in.
+func resolveTasksPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(commandDir(), path)
+}
+
+// DependencyEdges returns each task and subtask ID mapped to the full IDs it depends on.
+// Numeric subtask dependencies refer to sibling subtasks, matching the CLI.
+func (tf *TasksFile) DependencyEdges() map[string][]string {
+	edges := make(map[string][]string)
+	for id, dep := range tf.Dependencies() {
+		edges[id] = append(edges[id], dep)
+	}
+	return edges
+}
+
+// Dependencies yields each dependency in the file, in file order, as the task
+// or subtask ID and the full ID it depends on. Numeric subtask dependencies
+// refer to sibling subtasks, matching the CLI.
+func (tf *TasksFile) Dependencies() iter.Seq2[string, string] {
+	return func(yield func(id, dep string) bool) {
+		for _, task := range tf.Tasks {
+			taskID := strconv.Itoa(task.ID)
+			for _, dep := range task.Dependencies {
+				if !yield(taskID, dep) {
+					return
+				}
+			}
+			for _, sub := range task.Subtasks {
+				subID := fmt.Sprintf("%s.%d", taskID, sub.ID)
+				for _, dep := range sub.Dependencies {
+					if !strings.Contains(dep, ".") {
+						dep = fmt.Sprintf("%s.%s", taskID, dep)
+					}
+					if !yield(subID, dep) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// findDependencyCycle reports the cycle that adding taskID -> dependsOn would
+// create, as a path starting and ending with taskID. It returns nil if none.
+func findDependencyCycle(edges map[string][]string, taskID, dependsOn string) []string {
+	visited := make(map[string]bool)
+	var walk func(id string) []string
+	walk = func(id string) []string {
+		if id == taskID {
+			return []string{id}
+		}
+		if visited[id] {
+			return nil
+		}
+		visited[id] = true
+		for _, dep := range edges[id] {
+			if path := walk(dep); path != nil {
+				return append([]string{id}, path...)
+			}
+		}
+		return nil
+	}
+
+	if path := walk(dependsOn); path != nil {
+		return append([]string{taskID}, path...)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+)
+
+// checkTasksFile looks at a tasks file's contents and reports, as a warning,
+// why the CLI may fail to read it: the CLI reads every tasks file as JSON, so
+// a markdown file or malformed JSON fails with a confusing parse error from
+// node. When the file doesn't look right, sibling is the first of the
+// alternatives next to it that does: the same name with the other extension,
+// then tasks.json. A file that can't be read gets no warning; the path field
+// reports that.
+func checkTasksFile(path string) (warning, sibling string) {
+	warning = tasksFileProblem(path)
+	if warning == "" {
+		return "", ""
+	}
+
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	candidates := []string{base + ".json", base + ".md", filepath.Join(filepath.Dir(path), "tasks.json")}
+	for _, candidate := range candidates {
+		if candidate == path {
+			continue
+		}
+		if _, err := os.Stat(resolveTasksPath(candidate)); err == nil && tasksFileProblem(candidate) == "" {
+			return warning, candidate
+		}
+	}
+	return warning, ""
+}
+
+// tasksFileProblem describes why path doesn't look like a tasks file the CLI
+// can read, or returns "" if it does or can't be read.
+func tasksFileProblem(path string) string {
+	data, err := os.ReadFile(resolveTasksPath(path))
+	if err != nil {
+		return ""
+	}
+	name := filepath.Base(path)
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return fmt.Sprintf("%s is empty.", name)
+	}
+	if trimmed[0] != '{' {
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			return fmt.Sprintf("%s doesn't contain a JSON object.", name)
+		}
+		return fmt.Sprintf("%s looks like text or markdown, but the CLI reads tasks files as JSON.", name)
+	}
+
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &top); err != nil {
+		return fmt.Sprintf("%s isn't valid JSON: %v", name, err)
+	}
+	if _, ok := top["tasks"]; ok {
+		return ""
+	}
+	if tags, err := Tags(path); err == nil && len(tags) > 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s has no tasks list.", name)
+}
+
+// tasksFileCheck warns on a form, without blocking it, when the chosen tasks
+// file doesn't look like one, and offers a sibling file that does.
+type tasksFileCheck struct {
+	path    *string // The form's tasks file value, switched by the select
+	checked string  // The path the warning is about
+	warning string
+	sibling string
+}
+
+// hide re-checks the file when the path has changed other than by picking
+// one of the offered files, and hides the warning when there's nothing to say.
+func (c *tasksFileCheck) hide() bool {
+	if *c.path != c.checked && (c.sibling == "" || *c.path != c.sibling) {
+		c.checked = *c.path
+		c.warning, c.sibling = checkTasksFile(c.checked)
+	}
+	return c.warning == ""
+}
+
+// options offers keeping the checked file and, if there is one, the sibling.
+func (c *tasksFileCheck) options() []huh.Option[string] {
+	options := []huh.Option[string]{huh.NewOption(fmt.Sprintf("Continue with %s", c.checked), c.checked)}
+	if c.sibling != "" {
+		options = append(options, huh.NewOption(fmt.Sprintf("Use %s instead", c.sibling), c.sibling))
+	}
+	return options
+}
+
+// newTasksFileCheckGroup builds the group, placed after the one with the
+// tasks file field, that warns about a file that doesn't look like a tasks
+// file. It is hidden for files that do.
+func newTasksFileCheckGroup(path *string) *huh.Group {
+	c := &tasksFileCheck{path: path}
+	return huh.NewGroup(
+		huh.NewSelect[string]().
+			TitleFunc(func() string { return "⚠️  " + c.warning }, &c.checked).
+			Description("The command will probably fail to parse it.").
+			OptionsFunc(c.options, &c.checked).
+			Value(path),
+	).WithHideFunc(c.hide)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckTasksFile(t *testing.T) {
+	dir := t.TempDir()
+	tasksJSON := filepath.Join(dir, "tasks.json")
+	tasksMD := filepath.Join(dir, "tasks.md")
+	tagged := filepath.Join(dir, "tagged.json")
+	broken := filepath.Join(dir, "broken.json")
+	other := filepath.Join(t.TempDir(), "notes.md")
+	writeTasks(t, tasksJSON, `{"tasks": []}`)
+	writeTasks(t, tasksMD, "# Tasks\n\n- [ ] Write docs\n")
+	writeTasks(t, tagged, `{"master": {"tasks": []}}`)
+	writeTasks(t, broken, `{"tasks": [`)
+	writeTasks(t, other, "# Notes\n")
+
+	tests := []struct {
+		name        string
+		path        string
+		wantWarning string
+		wantSibling string
+	}{
+		{name: "tasks file", path: tasksJSON},
+		{name: "tagged tasks file", path: tagged},
+		{name: "missing file", path: filepath.Join(dir, "missing.json")},
+		{name: "markdown with a JSON sibling", path: tasksMD, wantWarning: "looks like text or markdown", wantSibling: tasksJSON},
+		{name: "broken JSON falls back to tasks.json", path: broken, wantWarning: "isn't valid JSON", wantSibling: tasksJSON},
+		{name: "markdown without a sibling", path: other, wantWarning: "looks like text or markdown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning, sibling := checkTasksFile(tt.path)
+			if tt.wantWarning == "" && warning != "" || !strings.Contains(warning, tt.wantWarning) {
+				t.Errorf("checkTasksFile(%q) warning = %q, want %q", tt.path, warning, tt.wantWarning)
+			}
+			if sibling != tt.wantSibling {
+				t.Errorf("checkTasksFile(%q) sibling = %q, want %q", tt.path, sibling, tt.wantSibling)
+			}
+		})
+	}
+}
+
+func TestTasksFileCheckSwitchesToSibling(t *testing.T) {
+	dir := t.TempDir()
+	tasksJSON := filepath.Join(dir, "tasks.json")
+	tasksMD := filepath.Join(dir, "tasks.md")
+	writeTasks(t, tasksJSON, `{"tasks": []}`)
+	writeTasks(t, tasksMD, "# Tasks\n")
+
+	path := tasksMD
+	c := &tasksFileCheck{path: &path}
+	if c.hide() {
+		t.Fatal("check hidden for a markdown tasks file")
+	}
+	if options := c.options(); len(options) != 2 || options[1].Value != tasksJSON {
+		t.Fatalf("options = %+v, want to keep the file or use %s", options, tasksJSON)
+	}
+
+	// Picking the sibling keeps the warning about the original file
+	path = tasksJSON
+	if c.hide() || c.checked != tasksMD {
+		t.Errorf("after switching, hide() = %v and checked = %q; want the warning kept", c.hide(), c.checked)
+	}
+
+	// Choosing another file re-checks it
+	path = filepath.Join(dir, "other.json")
+	writeTasks(t, path, `{"tasks": []}`)
+	if !c.hide() {
+		t.Errorf("check shown for a valid tasks file")
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const sampleTasksJSON = `{
+  "tasks": [
+    {"id": 1, "title": "Setup", "status": "done", "dependencies": []},
+    {"id": 2, "title": "Core", "status": "pending", "dependencies": [1], "subtasks": [
+      {"id": 1, "title": "Models", "status": "pending", "dependencies": []}
+    ]},
+    {"id": 3, "title": "UI", "status": "pending", "dependencies": [2], "subtasks": [
+      {"id": 1, "title": "Layout", "status": "pending", "dependencies": []},
+      {"id": 2, "title": "Styling", "status": "pending", "dependencies": [1, "2.1"]}
+    ]}
+  ]
+}`
+
+func loadSampleTasks(t *testing.T) *TasksFile {
+	t.Helper()
+	var tf TasksFile
+	if err := json.Unmarshal([]byte(sampleTasksJSON), &tf); err != nil {
+		t.Fatalf("failed to parse sample tasks: %v", err)
+	}
+	return &tf
+}
+
+func TestDependencyEdges(t *testing.T) {
+	edges := loadSampleTasks(t).DependencyEdges()
+
+	want := map[string][]string{
+		"2":   {"1"},
+		"3":   {"2"},
+		"3.2": {"3.1", "2.1"},
+	}
+	for id, deps := range want {
+		if !reflect.DeepEqual(edges[id], deps) {
+			t.Errorf("edges[%q] = %v, want %v", id, edges[id], deps)
+		}
+	}
+}
+
+func TestFindDependencyCycle(t *testing.T) {
+	edges := loadSampleTasks(t).DependencyEdges()
+
+	tests := []struct {
+		name      string
+		taskID    string
+		dependsOn string
+		want      []string
+	}{
+		{name: "no cycle", taskID: "3", dependsOn: "1"},
+		{name: "direct cycle", taskID: "1", dependsOn: "2", want: []string{"1", "2", "1"}},
+		{name: "transitive cycle", taskID: "1", dependsOn: "3", want: []string{"1", "3", "2", "1"}},
+		{name: "through subtask", taskID: "2.1", dependsOn: "3.2", want: []string{"2.1", "3.2", "2.1"}},
+		{name: "unknown task", taskID: "9", dependsOn: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findDependencyCycle(edges, tt.taskID, tt.dependsOn)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findDependencyCycle(%q, %q) = %v, want %v", tt.taskID, tt.dependsOn, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskIDs(t *testing.T) {
+	tf := loadSampleTasks(t)
+
+	if got, want := tf.TaskIDs(false), []string{"1", "2", "3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TaskIDs(false) = %v, want %v", got, want)
+	}
+	if got, want := tf.TaskIDs(true), []string{"1", "2", "2.1", "3", "3.1", "3.2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TaskIDs(true) = %v, want %v", got, want)
+	}
+}
+
+func TestHasTask(t *testing.T) {
+	tf := loadSampleTasks(t)
+
+	for _, id := range []string{"1", "3", "2.1", "3.2"} {
+		if !tf.HasTask(id) {
+			t.Errorf("HasTask(%q) = false, want true", id)
+		}
+	}
+	for _, id := range []string{"4", "1.1", "3.3", ""} {
+		if tf.HasTask(id) {
+			t.Errorf("HasTask(%q) = true, want false", id)
+		}
+	}
+}
+
+func TestIDsUpdatedFrom(t *testing.T) {
+	tf := loadSampleTasks(t)
+
+	// Task 1 is done, so it isn't updated even from 1
+	if got, want := tf.IDsUpdatedFrom(1), []string{"2", "3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IDsUpdatedFrom(1) = %v, want %v", got, want)
+	}
+	if got := tf.IDsUpdatedFrom(4); got != nil {
+		t.Errorf("IDsUpdatedFrom(4) = %v, want none", got)
+	}
+}
+
+func TestIDsWithStatus(t *testing.T) {
+	tf := loadSampleTasks(t)
+
+	if got, want := tf.IDsWithStatus(StatusDone), []string{"1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IDsWithStatus(done) = %v, want %v", got, want)
+	}
+	// To do matches the CLI's pending
+	if got, want := tf.IDsWithStatus(StatusTodo), []string{"2", "2.1", "3", "3.1", "3.2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IDsWithStatus(todo) = %v, want %v", got, want)
+	}
+	if got := tf.IDsWithStatus(StatusReview); got != nil {
+		t.Errorf("IDsWithStatus(review) = %v, want none", got)
+	}
+}
+
+func TestFind(t *testing.T) {
+	tf := loadSampleTasks(t)
+
+	tests := []struct {
+		id    string
+		title string
+		ok    bool
+	}{
+		{id: "2", title: "Core", ok: true},
+		{id: "3.2", title: "Styling", ok: true},
+		{id: "4"},
+		{id: "2.2"},
+		{id: "2.1.1"},
+		{id: "x"},
+	}
+	for _, tt := range tests {
+		task, ok := tf.Find(tt.id)
+		if ok != tt.ok || task.Title != tt.title {
+			t.Errorf("Find(%q) = %q, %v, want %q, %v", tt.id, task.Title, ok, tt.title, tt.ok)
+		}
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	tf := loadSampleTasks(t)
+
+	var got [][2]string
+	for id, dep := range tf.Dependencies() {
+		got = append(got, [2]string{id, dep})
+	}
+	want := [][2]string{{"2", "1"}, {"3", "2"}, {"3.2", "3.1"}, {"3.2", "2.1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependencies() = %v, want %v", got, want)
+	}
+
+	// Stopping early must not panic or keep yielding
+	n := 0
+	for range tf.Dependencies() {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("Dependencies() yielded %d edges after break, want 1", n)
+	}
+}
+
+func TestLoadTasksFile(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "tasks.json")
+	mdPath := filepath.Join(dir, "tasks.md")
+	writeTasks(t, jsonPath, sampleTasksJSON)
+	writeTasks(t, mdPath, sampleTasksMarkdown)
+
+	want := loadSampleTasks(t)
+	for _, path := range []string{jsonPath, mdPath} {
+		tf, err := LoadTasksFile(path)
+		if err != nil {
+			t.Fatalf("LoadTasksFile(%s) error: %v", filepath.Base(path), err)
+		}
+		if got, want := tf.TaskIDs(true), want.TaskIDs(true); !reflect.DeepEqual(got, want) {
+			t.Errorf("LoadTasksFile(%s) IDs = %v, want %v", filepath.Base(path), got, want)
+		}
+		if got, want := tf.DependencyEdges(), want.DependencyEdges(); !reflect.DeepEqual(got, want) {
+			t.Errorf("LoadTasksFile(%s) edges = %v, want %v", filepath.Base(path), got, want)
+		}
+	}
+
+	if _, err := LoadTasksFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("LoadTasksFile of a missing file succeeded")
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// markdownSubtaskPattern matches a subtask heading: "## 1. Title [pending]".
+	markdownSubtaskPattern = regexp.MustCompile(`^##\s+(\d+)\.\s+(.*?)(?:\s+\[([^\]]*)\])?\s*$`)
+	// markdownDependencyPattern matches the IDs in a dependency list, which
+	// the CLI writes with each dependency's status after it.
+	markdownDependencyPattern = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+)
+
+// parseTasksMarkdown parses tasks in the format of the CLI's generated task
+// files: each task starts with "# Task ID: N" and its fields follow as
+// "# Field: value" lines, with subtasks under "## N. Title [status]" headings
+// and their "### Description:" and "### Dependencies:" lines. Fields the TUI
+// doesn't read, such as details and test strategies, are skipped.
+func parseTasksMarkdown(data string) (*TasksFile, error) {
+	var tf TasksFile
+	var task, sub *Task
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if m := markdownSubtaskPattern.FindStringSubmatch(line); m != nil {
+			if task == nil {
+				return nil, fmt.Errorf("line %d: subtask before any task", i+1)
+			}
+			id, _ := strconv.Atoi(m[1])
+			task.Subtasks = append(task.Subtasks, Task{ID: id, Title: m[2], Status: m[3]})
+			sub = &task.Subtasks[len(task.Subtasks)-1]
+			continue
+		}
+
+		level, rest := 0, line
+		for strings.HasPrefix(rest, "#") {
+			level++
+			rest = rest[1:]
+		}
+		field, value, ok := strings.Cut(rest, ":")
+		if level == 0 || !ok {
+			continue
+		}
+		field, value = strings.ToLower(strings.TrimSpace(field)), strings.TrimSpace(value)
+
+		if level == 3 {
+			if sub == nil {
+				continue
+			}
+			switch field {
+			case "dependencies":
+				sub.Dependencies = markdownDependencies(value)
+			case "description":
+				sub.Description = value
+			}
+			continue
+		}
+		if level != 1 {
+			continue
+		}
+		if field == "task id" {
+			id, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid task ID %q", i+1, value)
+			}
+			tf.Tasks = append(tf.Tasks, Task{ID: id})
+			task, sub = &tf.Tasks[len(tf.Tasks)-1], nil
+			continue
+		}
+		if task == nil {
+			continue
+		}
+		switch field {
+		case "title":
+			task.Title = value
+		case "description":
+			task.Description = value
+		case "status":
+			task.Status = value
+		case "priority":
+			task.Priority = value
+		case "type":
+			task.Type = value
+		case "acceptance criteria":
+			task.AcceptanceCriteria = value
+		case "dependencies":
+			task.Dependencies = markdownDependencies(value)
+		}
+	}
+	if len(tf.Tasks) == 0 {
+		return nil, fmt.Errorf("failed to parse tasks file: no \"# Task ID:\" lines found")
+	}
+	return &tf, nil
+}
+
+// markdownDependencies returns the IDs in a dependency list, or nil for "None".
+func markdownDependencies(value string) taskIDList {
+	ids := markdownDependencyPattern.FindAllString(value, -1)
+	if len(ids) == 0 {
+		return nil
+	}
+	return taskIDList(ids)
+}
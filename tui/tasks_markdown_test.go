@@ -0,0 +1,104 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// sampleTasksMarkdown is sampleTasksJSON as the CLI's generate command writes
+// it, with a checkpoint's type and acceptance criteria added to task 3.
+const sampleTasksMarkdown = `# Task ID: 1
+# Title: Setup
+# Status: done
+# Dependencies: None
+# Priority: high
+# Description: Set up the project
+# Details:
+Run npm init.
+
+# Test Strategy:
+None
+
+# Task ID: 2
+# Title: Core
+# Status: pending
+# Dependencies: ✅ 1 (done)
+# Priority: medium
+# Description: Core models
+# Details:
+# Not a field: headings in details are skipped
+
+# Test Strategy:
+
+# Subtasks:
+## 1. Models [pending]
+### Dependencies: None
+### Description: Data models
+### Details:
+
+
+# Task ID: 3
+# Title: UI
+# Status: pending
+# Dependencies: ⏱️ 2 (pending)
+# Priority: low
+# Type: checkpoint
+# Acceptance Criteria: The layout renders at 80 columns
+# Description: The interface
+# Details:
+
+# Test Strategy:
+
+# Subtasks:
+## 1. Layout [pending]
+### Dependencies: None
+### Description: Layout
+### Details:
+
+## 2. Styling [in-progress]
+### Dependencies: 3.1, 2.1
+### Description: Styling
+### Details:
+
+`
+
+func TestParseTasksMarkdown(t *testing.T) {
+	tf, err := parseTasksMarkdown(sampleTasksMarkdown)
+	if err != nil {
+		t.Fatalf("parseTasksMarkdown error: %v", err)
+	}
+
+	want := []Task{
+		{ID: 1, Title: "Setup", Description: "Set up the project", Status: "done", Priority: "high"},
+		{ID: 2, Title: "Core", Description: "Core models", Status: "pending", Priority: "medium", Dependencies: taskIDList{"1"}, Subtasks: []Task{
+			{ID: 1, Title: "Models", Description: "Data models", Status: "pending"},
+		}},
+		{ID: 3, Title: "UI", Description: "The interface", Status: "pending", Priority: "low", Type: "checkpoint",
+			AcceptanceCriteria: "The layout renders at 80 columns", Dependencies: taskIDList{"2"}, Subtasks: []Task{
+				{ID: 1, Title: "Layout", Description: "Layout", Status: "pending"},
+				{ID: 2, Title: "Styling", Description: "Styling", Status: "in-progress", Dependencies: taskIDList{"3.1", "2.1"}},
+			}},
+	}
+	if !reflect.DeepEqual(tf.Tasks, want) {
+		t.Errorf("parseTasksMarkdown tasks =\n%+v\nwant\n%+v", tf.Tasks, want)
+	}
+}
+
+func TestParseTasksMarkdownErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "empty", data: ""},
+		{name: "no tasks", data: "# Title: Setup\n# Status: done\n"},
+		{name: "invalid ID", data: "# Task ID: one\n"},
+		{name: "subtask before task", data: "## 1. Models [pending]\n# Task ID: 1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseTasksMarkdown(tt.data); err == nil {
+				t.Errorf("parseTasksMarkdown(%q) succeeded, want an error", tt.data)
+			}
+		})
+	}
+}
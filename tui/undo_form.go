@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	undoFormKeyFile    = "file"
+	undoFormKeyConfirm = "confirm"
+)
+
+// UndoModel holds the state for the undo last change form.
+type UndoModel struct {
+	form         *huh.Form
+	aborted      bool
+	isProcessing bool
+	statusMsg    string
+	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+
+	// Form values
+	FilePath  string
+	Confirmed bool // Must be set before the file is restored
+}
+
+// NewUndoForm creates a new form for restoring a tasks file from its latest backup.
+func NewUndoForm() *UndoModel {
+	m := &UndoModel{
+		FilePath: lastFilePath(), // Pre-populate with the last used tasks file
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			newFilePathField(undoFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json) to restore.", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key(undoFormKeyConfirm).
+				Title("Undo Last Change").
+				DescriptionFunc(m.confirmDescription, &m.FilePath).
+				Affirmative("Yes").
+				Negative("No").
+				Value(&m.Confirmed), // Defaults to No
+		),
+	).WithTheme(huh.ThemeDracula())
+
+	return m
+}
+
+// confirmDescription describes the backup that will be restored.
+func (m *UndoModel) confirmDescription() string {
+	backups, err := listBackups(m.FilePath)
+	if err != nil {
+		return fmt.Sprintf("Backups can't be read: %v", err)
+	}
+	if len(backups) == 0 {
+		return "There are no backups of this file to restore."
+	}
+	return fmt.Sprintf("Restore %s to its state at %s? Changes made since then are lost. %d backup(s) available.",
+		m.FilePath, backupTime(backups[0]).Format("2006-01-02 15:04:05"), len(backups))
+}
+
+func (m *UndoModel) Init() tea.Cmd {
+	m.isProcessing = false
+	m.statusMsg = ""
+	m.aborted = false
+	return m.form.Init()
+}
+
+func (m *UndoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case undoCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			m.statusMsg = "✅ Success!"
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.statusMsg = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
+	if m.isProcessing {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			if keyMsg.String() == "ctrl+c" || keyMsg.String() == "q" {
+				return m, tea.Quit
+			}
+		}
+		return m, nil
+	}
+
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
+	var cmds []tea.Cmd
+	formModel, cmd := m.form.Update(msg)
+	if updatedForm, ok := formModel.(*huh.Form); ok {
+		m.form = updatedForm
+	} else {
+		m.statusMsg = "Error: Form update returned unexpected type."
+		debugLog.Error("form update did not return *huh.Form", "file", "undo_form.go", "type", fmt.Sprintf("%T", formModel))
+		return m, tea.Quit
+	}
+	cmds = append(cmds, cmd)
+
+	if m.form.State == huh.StateCompleted {
+		if !m.Confirmed {
+			// Declining the confirm leaves the file untouched
+			m.aborted = true
+			return m, func() tea.Msg { return backToMenuMsg{} }
+		}
+
+		m.statusMsg = "Restoring the latest backup..."
+		m.isProcessing = true
+		return m, tea.Batch(m.spinner.start(), m.executeUndoCommand())
+	}
+
+	if m.form.State == huh.StateAborted {
+		m.aborted = true
+		return m, func() tea.Msg { return backToMenuMsg{} }
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if !m.isProcessing {
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			}
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *UndoModel) View() string {
+	if m.aborted {
+		return "Form aborted. Returning to main menu..."
+	}
+
+	var viewBuilder strings.Builder
+	viewBuilder.WriteString(m.form.View())
+
+	if m.statusMsg != "" {
+		viewBuilder.WriteString("\n\n")
+		viewBuilder.WriteString(renderStatus(m.statusMsg))
+	}
+
+	helpStyle := lipgloss.NewStyle().Faint(true)
+	if m.isProcessing {
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Ctrl+C to force quit."))
+	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.statusMsg, "✅") {
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
+		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Padding(1, 2).
+		Render(viewBuilder.String())
+}
+
+func (m *UndoModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
+// GetFormValues retrieves the structured data after completion.
+func (m *UndoModel) GetFormValues() (map[string]interface{}, error) {
+	if m.form.State != huh.StateCompleted {
+		return nil, fmt.Errorf("form is not yet completed")
+	}
+	return map[string]interface{}{
+		undoFormKeyFile:    m.FilePath,
+		undoFormKeyConfirm: m.Confirmed,
+	}, nil
+}
+
+// undoCompleteMsg is sent when the backup has been restored
+type undoCompleteMsg struct {
+	result CLIResult
+}
+
+// executeUndoCommand restores the tasks file from its latest backup
+func (m *UndoModel) executeUndoCommand() tea.Cmd {
+	return func() tea.Msg {
+		return undoCompleteMsg{result: cliExecutor.UndoLastChange(m.FilePath)}
+	}
+}
+
+var _ tea.Model = &UndoModel{}
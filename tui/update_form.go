@@ -2,20 +2,21 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 )
 
 const (
-	updateFormKeyFile     = "file"
-	updateFormKeyFrom     = "from"
-	updateFormKeyPrompt   = "prompt"
-	updateFormKeyResearch = "research"
+	updateFormKeyFile          = "file"
+	updateFormKeyFrom          = "from"
+	updateFormKeyPrompt        = "prompt"
+	updateFormKeyResearch      = "research"
+	updateFormKeyResearchModel = "research-model"
 )
 
 // UpdateTaskModel holds the state for the update tasks form.
@@ -25,19 +26,30 @@ type UpdateTaskModel struct {
 	isProcessing bool
 	status       string
 	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
 
 	// Form values
-	FilePath string
-	FromTask int // Task ID to start updating from
-	Prompt   string
-	Research bool
+	FilePath      string
+	FromTask      int // Task ID to start updating from
+	Prompt        string
+	Research      bool
+	ResearchModel string // Research model for this command only; empty for the configured one
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
 }
 
 // NewUpdateTaskForm creates a new form for the update command.
 func NewUpdateTaskForm() *UpdateTaskModel {
 	m := &UpdateTaskModel{
-		FromTask: 1, // Default to start from task 1
-		Research: false,
+		FilePath: lastFilePath(),                 // Pre-populate with the last used tasks file
+		Prompt:   LoadLastPrompt("update-tasks"), // Offer the last prompt again, e.g. after a failure
+		FromTask: 1,                              // Default to start from task 1
+		Research: defaultResearch(),
 	}
 
 	// Temporary string for FromTask input
@@ -45,24 +57,14 @@ func NewUpdateTaskForm() *UpdateTaskModel {
 
 	m.form = huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().
-				Key(updateFormKeyFile).
-				Title("Tasks File Path").
-				Description("Path to the tasks file (e.g., tasks.md).").
-				Prompt("📄 ").
-				Validate(func(s string) error {
-					if s == "" {
-						return fmt.Errorf("file path cannot be empty")
-					}
-					// Consider adding file existence check if appropriate
-					return nil
-				}).
-				Value(&m.FilePath),
-
+			newFilePathField(updateFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json).", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		huh.NewGroup(
 			huh.NewInput().
 				Key(updateFormKeyFrom).
 				Title("From Task ID").
-				Description("Task ID to start updating from.").
+				DescriptionFunc(func() string { return updateFromDescription(m.FilePath, fromTaskStr) }, []any{&m.FilePath, &fromTaskStr}).
 				Prompt("🔢 ").
 				Validate(func(s string) error {
 					if s == "" {
@@ -75,8 +77,9 @@ func NewUpdateTaskForm() *UpdateTaskModel {
 					if val <= 0 {
 						return fmt.Errorf("task ID must be greater than 0")
 					}
-					return nil
+					return validateUpdateFrom(m.FilePath, val)
 				}).
+				SuggestionsFunc(taskIDSuggestions(&m.FilePath, false), &m.FilePath).
 				Value(&fromTaskStr), // Use temporary string, parse on completion
 
 			huh.NewText(). // For potentially longer prompt text
@@ -101,11 +104,31 @@ func NewUpdateTaskForm() *UpdateTaskModel {
 				Negative("No").
 				Value(&m.Research),
 		),
+		newResearchModelGroup(updateFormKeyResearchModel, &m.Research, &m.ResearchModel),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
 	).WithTheme(huh.ThemeDracula())
 
 	return m
 }
 
+// updateFromDescription says which tasks an update from the typed ID affects.
+func updateFromDescription(filePath, from string) string {
+	const base = "Task ID to start updating from; tasks that are done are skipped."
+	fromID, err := strconv.Atoi(from)
+	if err != nil || fromID <= 0 {
+		return base
+	}
+	tasksFile, err := LoadTasksFile(filePath)
+	if err != nil {
+		return base
+	}
+	ids := tasksFile.IDsUpdatedFrom(fromID)
+	if len(ids) == 0 {
+		return base
+	}
+	return fmt.Sprintf("%s Updates %d task(s): %s", base, len(ids), strings.Join(ids, ", "))
+}
+
 func (m *UpdateTaskModel) Init() tea.Cmd {
 	m.isProcessing = false
 	m.status = ""
@@ -114,9 +137,50 @@ func (m *UpdateTaskModel) Init() tea.Cmd {
 }
 
 func (m *UpdateTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case updateTasksCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.status = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		summary, breakdown := updateTasksReport(msg.before, msg.after, m.FromTask, msg.result.Output)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			promptSucceeded("update-tasks")
+			m.status = strings.TrimSpace("✅ Success! "+summary) + researchBadge(m.Research)
+			output := msg.result.Output
+			if breakdown != "" {
+				output = breakdown + "\n\n" + output
+			}
+			m.result.setContent(output)
+		} else {
+			m.status = renderResult(msg.result)
+			if breakdown != "" && msg.before.changed(msg.after) {
+				// The file changed anyway, so say which tasks did
+				m.status += "\n\n" + summary + "\n" + breakdown
+			}
+		}
+		return m, nil
+	}
+
 	if m.isProcessing {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
 			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.status = "Cancelling..."
+				}
 			case "ctrl+c", "q":
 				return m, tea.Quit
 			}
@@ -124,13 +188,37 @@ func (m *UpdateTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.status = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
 	var cmds []tea.Cmd
 	formModel, cmd := m.form.Update(msg)
 	if updatedForm, ok := formModel.(*huh.Form); ok {
 		m.form = updatedForm
 	} else {
 		m.status = "Error: Form update returned unexpected type."
-		fmt.Fprintf(os.Stderr, "Critical Error: update_form.go - form update did not return *huh.Form. Got: %T\n", formModel)
+		debugLog.Error("form update did not return *huh.Form", "file", "update_form.go", "type", fmt.Sprintf("%T", formModel))
 		return m, tea.Quit
 	}
 	cmds = append(cmds, cmd)
@@ -148,9 +236,10 @@ func (m *UpdateTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.FromTask = parsedFromTask
 
+		SaveLastPrompt("update-tasks", m.Prompt)
 		m.status = "Executing update-tasks command..."
 		m.isProcessing = true
-		return m, m.executeUpdateTasksCommand()
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeUpdateTasksCommand))
 	}
 
 	if m.form.State == huh.StateAborted {
@@ -159,14 +248,6 @@ func (m *UpdateTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
-	case updateTasksCompleteMsg:
-		m.isProcessing = false
-		if msg.result.Success {
-			m.status = fmt.Sprintf("✅ Success!\n\n%s", msg.result.Output)
-		} else {
-			m.status = fmt.Sprintf("❌ Error: %s\n\n%s", msg.result.Error, msg.result.Output)
-		}
-		return m, nil
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
@@ -175,8 +256,6 @@ func (m *UpdateTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, func() tea.Msg { return backToMenuMsg{} }
 			}
 		}
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
 	}
 
 	return m, tea.Batch(cmds...)
@@ -192,18 +271,22 @@ func (m *UpdateTaskModel) View() string {
 
 	if m.status != "" {
 		viewBuilder.WriteString("\n\n")
-		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-		if strings.HasPrefix(m.status, "Error:") {
-			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
-		}
-		viewBuilder.WriteString(statusStyle.Render(m.status))
+		viewBuilder.WriteString(renderStatus(m.status))
 	}
 
 	helpStyle := lipgloss.NewStyle().Faint(true)
 	if m.isProcessing {
-		viewBuilder.WriteString(helpStyle.Render("\n\nProcessing... Press Ctrl+C to force quit."))
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
 	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.status, "✅") {
-		viewBuilder.WriteString(helpStyle.Render("\n\nCommand completed! Press Esc to return to main menu."))
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
 	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
 		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
 	}
@@ -214,34 +297,45 @@ func (m *UpdateTaskModel) View() string {
 		Render(viewBuilder.String())
 }
 
+func (m *UpdateTaskModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
 // GetFormValues can be used to retrieve the structured data after completion.
 func (m *UpdateTaskModel) GetFormValues() (map[string]interface{}, error) {
 	if m.form.State != huh.StateCompleted {
 		return nil, fmt.Errorf("form is not yet completed")
 	}
 	return map[string]interface{}{
-		updateFormKeyFile:     m.FilePath,
-		updateFormKeyFrom:     m.FromTask,
-		updateFormKeyPrompt:   m.Prompt,
-		updateFormKeyResearch: m.Research,
+		updateFormKeyFile:          m.FilePath,
+		updateFormKeyFrom:          m.FromTask,
+		updateFormKeyPrompt:        m.Prompt,
+		updateFormKeyResearch:      m.Research,
+		updateFormKeyResearchModel: m.ResearchModel,
+		advancedFlagsFormKey:       m.AdvancedFlags,
 	}, nil
 }
 
 // updateTasksCompleteMsg is sent when the command execution is complete
 type updateTasksCompleteMsg struct {
-	result CLIResult
+	result        CLIResult
+	before, after *taskSnapshot // The tasks around the run, or nil if unreadable
 }
 
 // executeUpdateTasksCommand executes the actual update-tasks CLI command
 // Note: The CLI expects a slice of task IDs, but this form collects a "from" task ID
 // We'll pass an empty slice to update all tasks, as the CLI supports this
 func (m *UpdateTaskModel) executeUpdateTasksCommand() tea.Cmd {
-	return func() tea.Msg {
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags).WithResearchModel(m.ResearchModel)
+
 		// Pass empty taskIDs slice to update all tasks (CLI supports this)
 		var taskIDs []string
-		result := cliExecutor.UpdateTasks(m.FilePath, m.Prompt, taskIDs, m.Research)
-		return updateTasksCompleteMsg{result: result}
-	}
+		before := snapshotTasks(m.FilePath)
+		result := executor.UpdateTasks(m.FilePath, m.Prompt, taskIDs, m.Research)
+		return updateTasksCompleteMsg{result: result, before: before, after: snapshotTasks(m.FilePath)}
+	})
 }
 
 // Ensure UpdateTaskModel implements tea.Model.
@@ -2,10 +2,10 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	// "strconv" // Not strictly needed if ID is treated as string, but good for validation if numeric
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
@@ -25,35 +25,36 @@ type UpdateSingleTaskModel struct {
 	isProcessing bool
 	status       string
 	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
 
 	// Form values
 	FilePath string
 	TaskID   string // Task ID can be string to accommodate various ID formats (e.g., alphanumeric)
 	Prompt   string
 	Research bool
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
 }
 
 // NewUpdateSingleTaskForm creates a new form for the update-task command.
 func NewUpdateSingleTaskForm() *UpdateSingleTaskModel {
 	m := &UpdateSingleTaskModel{
-		Research: false, // Default for research
+		FilePath: lastFilePath(),                // Pre-populate with the last used tasks file
+		Prompt:   LoadLastPrompt("update-task"), // Offer the last prompt again, e.g. after a failure
+		Research: defaultResearch(),
 	}
 
 	m.form = huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().
-				Key(updateOneTaskFormKeyFile).
-				Title("Tasks File Path").
-				Description("Path to the tasks file (e.g., tasks.md).").
-				Prompt("📄 ").
-				Validate(func(s string) error {
-					if s == "" {
-						return fmt.Errorf("file path cannot be empty")
-					}
-					return nil
-				}).
-				Value(&m.FilePath),
-
+			newFilePathField(updateOneTaskFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json).", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		huh.NewGroup(
 			huh.NewInput().
 				Key(updateOneTaskFormKeyID).
 				Title("Task ID").
@@ -66,6 +67,7 @@ func NewUpdateSingleTaskForm() *UpdateSingleTaskModel {
 					// Add more specific ID validation if needed (e.g., numeric, specific format)
 					return nil
 				}).
+				SuggestionsFunc(taskIDSuggestions(&m.FilePath, false), &m.FilePath).
 				Value(&m.TaskID),
 
 			huh.NewText().
@@ -90,6 +92,7 @@ func NewUpdateSingleTaskForm() *UpdateSingleTaskModel {
 				Negative("No").
 				Value(&m.Research),
 		),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
 	).WithTheme(huh.ThemeDracula())
 
 	return m
@@ -103,9 +106,41 @@ func (m *UpdateSingleTaskModel) Init() tea.Cmd {
 }
 
 func (m *UpdateSingleTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case updateOneTaskCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.status = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			promptSucceeded("update-task")
+			m.status = "✅ Success!" + researchBadge(m.Research)
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.status = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
 	if m.isProcessing {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
 			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.status = "Cancelling..."
+				}
 			case "ctrl+c", "q":
 				return m, tea.Quit
 			}
@@ -113,22 +148,47 @@ func (m *UpdateSingleTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.status = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
 	var cmds []tea.Cmd
 	formModel, cmd := m.form.Update(msg)
 	if updatedForm, ok := formModel.(*huh.Form); ok {
 		m.form = updatedForm
 	} else {
 		m.status = "Error: Form update returned unexpected type."
-		fmt.Fprintf(os.Stderr, "Critical Error: update_one_task_form.go - form update did not return *huh.Form. Got: %T\n", formModel)
+		debugLog.Error("form update did not return *huh.Form", "file", "update_one_task_form.go", "type", fmt.Sprintf("%T", formModel))
 		return m, tea.Quit
 	}
 	cmds = append(cmds, cmd)
 
 	if m.form.State == huh.StateCompleted {
 		// Values are already bound to m.FilePath, m.TaskID, m.Prompt, m.Research.
+		SaveLastPrompt("update-task", m.Prompt)
 		m.status = "Executing update-task command..."
 		m.isProcessing = true
-		return m, m.executeUpdateOneTaskCommand()
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeUpdateOneTaskCommand))
 	}
 
 	if m.form.State == huh.StateAborted {
@@ -137,14 +197,6 @@ func (m *UpdateSingleTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
-	case updateOneTaskCompleteMsg:
-		m.isProcessing = false
-		if msg.result.Success {
-			m.status = fmt.Sprintf("✅ Success!\n\n%s", msg.result.Output)
-		} else {
-			m.status = fmt.Sprintf("❌ Error: %s\n\n%s", msg.result.Error, msg.result.Output)
-		}
-		return m, nil
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
@@ -153,8 +205,6 @@ func (m *UpdateSingleTaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, func() tea.Msg { return backToMenuMsg{} }
 			}
 		}
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
 	}
 
 	return m, tea.Batch(cmds...)
@@ -170,18 +220,22 @@ func (m *UpdateSingleTaskModel) View() string {
 
 	if m.status != "" {
 		viewBuilder.WriteString("\n\n")
-		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-		if strings.HasPrefix(m.status, "Error:") {
-			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
-		}
-		viewBuilder.WriteString(statusStyle.Render(m.status))
+		viewBuilder.WriteString(renderStatus(m.status))
 	}
 
 	helpStyle := lipgloss.NewStyle().Faint(true)
 	if m.isProcessing {
-		viewBuilder.WriteString(helpStyle.Render("\n\nProcessing... Press Ctrl+C to force quit."))
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
 	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.status, "✅") {
-		viewBuilder.WriteString(helpStyle.Render("\n\nCommand completed! Press Esc to return to main menu."))
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
 	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
 		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
 	}
@@ -192,6 +246,8 @@ func (m *UpdateSingleTaskModel) View() string {
 		Render(viewBuilder.String())
 }
 
+func (m *UpdateSingleTaskModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
 // GetFormValues can be used to retrieve the structured data after completion.
 func (m *UpdateSingleTaskModel) GetFormValues() (map[string]interface{}, error) {
 	if m.form.State != huh.StateCompleted {
@@ -202,6 +258,7 @@ func (m *UpdateSingleTaskModel) GetFormValues() (map[string]interface{}, error)
 		updateOneTaskFormKeyID:       m.TaskID,
 		updateOneTaskFormKeyPrompt:   m.Prompt,
 		updateOneTaskFormKeyResearch: m.Research,
+		advancedFlagsFormKey:         m.AdvancedFlags,
 	}, nil
 }
 
@@ -212,10 +269,15 @@ type updateOneTaskCompleteMsg struct {
 
 // executeUpdateOneTaskCommand executes the actual update-task CLI command
 func (m *UpdateSingleTaskModel) executeUpdateOneTaskCommand() tea.Cmd {
-	return func() tea.Msg {
-		result := cliExecutor.UpdateOneTask(m.FilePath, m.TaskID, m.Prompt, m.Research)
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags)
+
+		result := executor.UpdateOneTask(m.FilePath, m.TaskID, m.Prompt, m.Research)
 		return updateOneTaskCompleteMsg{result: result}
-	}
+	})
 }
 
 // Ensure UpdateSingleTaskModel implements tea.Model.
@@ -2,20 +2,21 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	// "regexp" // For more complex ID validation if needed
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 )
 
 const (
-	updateSubtaskFormKeyFile     = "file"
-	updateSubtaskFormKeyID       = "id" // Subtask ID, e.g., "1.2"
-	updateSubtaskFormKeyPrompt   = "prompt"
-	updateSubtaskFormKeyResearch = "research"
+	updateSubtaskFormKeyFile          = "file"
+	updateSubtaskFormKeyID            = "id" // Subtask ID, e.g., "1.2"
+	updateSubtaskFormKeyPrompt        = "prompt"
+	updateSubtaskFormKeyResearch      = "research"
+	updateSubtaskFormKeyResearchModel = "research-model"
 )
 
 // UpdateSubtaskModel holds the state for the update-subtask form.
@@ -25,18 +26,29 @@ type UpdateSubtaskModel struct {
 	isProcessing bool
 	status       string
 	width        int
+	height       int
+	output       liveOutput          // Output streamed while the command runs
+	spinner      processingIndicator // Animated while the command runs
+	result       resultView          // Scrollable output of a successful command
+	cancel       commandCancel       // Cancels the running command on Esc
+	last         lastRun             // Starts a failed command again on r
 
 	// Form values
-	FilePath   string
-	SubtaskID  string // e.g., "1.2"
-	Prompt     string
-	Research   bool
+	FilePath      string
+	SubtaskID     string // e.g., "1.2"
+	Prompt        string
+	Research      bool
+	ResearchModel string // Research model for this command only; empty for the configured one
+
+	AdvancedFlags string // Extra CLI flags, see newAdvancedFlagsGroup
 }
 
 // NewUpdateSubtaskForm creates a new form for the update-subtask command.
 func NewUpdateSubtaskForm() *UpdateSubtaskModel {
 	m := &UpdateSubtaskModel{
-		Research: false, // Default for research
+		FilePath: lastFilePath(),                   // Pre-populate with the last used tasks file
+		Prompt:   LoadLastPrompt("update-subtask"), // Offer the last prompt again, e.g. after a failure
+		Research: defaultResearch(),
 	}
 
 	// Example validation for subtask ID format (e.g., "1.2", "10.3.1")
@@ -44,19 +56,10 @@ func NewUpdateSubtaskForm() *UpdateSubtaskModel {
 
 	m.form = huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().
-				Key(updateSubtaskFormKeyFile).
-				Title("Tasks File Path").
-				Description("Path to the tasks file (e.g., tasks.md).").
-				Prompt("📄 ").
-				Validate(func(s string) error {
-					if s == "" {
-						return fmt.Errorf("file path cannot be empty")
-					}
-					return nil
-				}).
-				Value(&m.FilePath),
-
+			newFilePathField(updateSubtaskFormKeyFile, "Tasks File Path", "Path to the tasks file (e.g., tasks.json).", &m.FilePath, tasksFileTypes),
+		),
+		newTasksFileCheckGroup(&m.FilePath),
+		huh.NewGroup(
 			huh.NewInput().
 				Key(updateSubtaskFormKeyID).
 				Title("Subtask ID").
@@ -71,6 +74,7 @@ func NewUpdateSubtaskForm() *UpdateSubtaskModel {
 					// }
 					return nil
 				}).
+				SuggestionsFunc(taskIDSuggestions(&m.FilePath, true), &m.FilePath).
 				Value(&m.SubtaskID),
 
 			huh.NewText().
@@ -95,6 +99,8 @@ func NewUpdateSubtaskForm() *UpdateSubtaskModel {
 				Negative("No").
 				Value(&m.Research),
 		),
+		newResearchModelGroup(updateSubtaskFormKeyResearchModel, &m.Research, &m.ResearchModel),
+		newAdvancedFlagsGroup(&m.AdvancedFlags),
 	).WithTheme(huh.ThemeDracula())
 
 	return m
@@ -108,9 +114,41 @@ func (m *UpdateSubtaskModel) Init() tea.Cmd {
 }
 
 func (m *UpdateSubtaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		handleResize(msg, &m.width, &m.height, &m.result, &m.output)
+	case outputLineMsg:
+		return m, m.output.handle(msg)
+	case spinner.TickMsg:
+		return m, m.spinner.update(msg)
+	case updateSubtaskCompleteMsg:
+		m.isProcessing = false
+		m.spinner.stop()
+		if m.cancel.finish() {
+			m.status = "Command cancelled."
+			m.form.State = huh.StateNormal // Back to the form to adjust and resubmit
+			return m, nil
+		}
+		m.last.finish(msg.result.Success)
+		if msg.result.Success {
+			rememberFilePath(m.FilePath)
+			promptSucceeded("update-subtask")
+			m.status = "✅ Success!" + researchBadge(m.Research)
+			m.result.setContent(msg.result.Output)
+		} else {
+			m.status = renderResult(msg.result)
+		}
+		return m, nil
+	}
+
 	if m.isProcessing {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
 			switch keyMsg.String() {
+			case "esc":
+				// Kill the command but stay in the TUI
+				if m.cancel.request() {
+					m.status = "Cancelling..."
+				}
 			case "ctrl+c", "q":
 				return m, tea.Quit
 			}
@@ -118,21 +156,46 @@ func (m *UpdateSubtaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Once the command has run, keep showing its result rather than letting the
+	// completed form execute it again
+	if m.form.State == huh.StateCompleted {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			// Keys typed into a search are the query's, not commands
+			if m.result.searching() {
+				return m, m.result.update(msg)
+			}
+			if keyMsg.String() == rerunKey && m.last.canRerun() {
+				m.status = "Running the command again..."
+				m.isProcessing = true
+				return m, tea.Batch(m.spinner.start(), m.last.rerun())
+			}
+			switch keyMsg.String() {
+			case "esc":
+				m.aborted = true
+				return m, func() tea.Msg { return backToMenuMsg{} }
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+		}
+		return m, m.result.update(msg)
+	}
+
 	var cmds []tea.Cmd
 	formModel, cmd := m.form.Update(msg)
 	if updatedForm, ok := formModel.(*huh.Form); ok {
 		m.form = updatedForm
 	} else {
 		m.status = "Error: Form update returned unexpected type."
-		fmt.Fprintf(os.Stderr, "Critical Error: update_subtask_form.go - form update did not return *huh.Form. Got: %T\n", formModel)
+		debugLog.Error("form update did not return *huh.Form", "file", "update_subtask_form.go", "type", fmt.Sprintf("%T", formModel))
 		return m, tea.Quit
 	}
 	cmds = append(cmds, cmd)
 
 	if m.form.State == huh.StateCompleted {
+		SaveLastPrompt("update-subtask", m.Prompt)
 		m.status = "Executing update-subtask command..."
 		m.isProcessing = true
-		return m, m.executeUpdateSubtaskCommand()
+		return m, tea.Batch(m.spinner.start(), m.last.run(m.executeUpdateSubtaskCommand))
 	}
 
 	if m.form.State == huh.StateAborted {
@@ -141,14 +204,6 @@ func (m *UpdateSubtaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
-	case updateSubtaskCompleteMsg:
-		m.isProcessing = false
-		if msg.result.Success {
-			m.status = fmt.Sprintf("✅ Success!\n\n%s", msg.result.Output)
-		} else {
-			m.status = fmt.Sprintf("❌ Error: %s\n\n%s", msg.result.Error, msg.result.Output)
-		}
-		return m, nil
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
@@ -157,8 +212,6 @@ func (m *UpdateSubtaskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, func() tea.Msg { return backToMenuMsg{} }
 			}
 		}
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
 	}
 
 	return m, tea.Batch(cmds...)
@@ -174,18 +227,22 @@ func (m *UpdateSubtaskModel) View() string {
 
 	if m.status != "" {
 		viewBuilder.WriteString("\n\n")
-		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-		if strings.HasPrefix(m.status, "Error:") {
-			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
-		}
-		viewBuilder.WriteString(statusStyle.Render(m.status))
+		viewBuilder.WriteString(renderStatus(m.status))
 	}
 
 	helpStyle := lipgloss.NewStyle().Faint(true)
 	if m.isProcessing {
-		viewBuilder.WriteString(helpStyle.Render("\n\nProcessing... Press Ctrl+C to force quit."))
+		if live := m.output.View(); live != "" {
+			viewBuilder.WriteString("\n\n" + live)
+		}
+		viewBuilder.WriteString("\n\n" + m.spinner.View() + helpStyle.Render(" Processing... Press Esc to cancel, Ctrl+C to force quit."))
 	} else if m.form.State == huh.StateCompleted && strings.HasPrefix(m.status, "✅") {
-		viewBuilder.WriteString(helpStyle.Render("\n\nCommand completed! Press Esc to return to main menu."))
+		if result := m.result.View(); result != "" {
+			viewBuilder.WriteString("\n\n" + result)
+		}
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.help()))
+	} else if m.form.State == huh.StateCompleted && m.last.canRerun() {
+		viewBuilder.WriteString(helpStyle.Render("\n\n" + m.result.failedHelp()))
 	} else if m.form.State != huh.StateCompleted && m.form.State != huh.StateAborted {
 		viewBuilder.WriteString(helpStyle.Render("\n\nPress Esc to return to main menu, Ctrl+C to quit application."))
 	}
@@ -196,16 +253,20 @@ func (m *UpdateSubtaskModel) View() string {
 		Render(viewBuilder.String())
 }
 
+func (m *UpdateSubtaskModel) keyContext() keyContext { return m.result.keyContext(formKeyContext(m.form, m.isProcessing)) }
+
 // GetFormValues retrieves the structured data after completion.
 func (m *UpdateSubtaskModel) GetFormValues() (map[string]interface{}, error) {
 	if m.form.State != huh.StateCompleted {
 		return nil, fmt.Errorf("form is not yet completed")
 	}
 	return map[string]interface{}{
-		updateSubtaskFormKeyFile:     m.FilePath,
-		updateSubtaskFormKeyID:       m.SubtaskID,
-		updateSubtaskFormKeyPrompt:   m.Prompt,
-		updateSubtaskFormKeyResearch: m.Research,
+		updateSubtaskFormKeyFile:          m.FilePath,
+		updateSubtaskFormKeyID:            m.SubtaskID,
+		updateSubtaskFormKeyPrompt:        m.Prompt,
+		updateSubtaskFormKeyResearch:      m.Research,
+		updateSubtaskFormKeyResearchModel: m.ResearchModel,
+		advancedFlagsFormKey:              m.AdvancedFlags,
 	}, nil
 }
 
@@ -217,7 +278,12 @@ type updateSubtaskCompleteMsg struct {
 // executeUpdateSubtaskCommand executes the actual update-subtask CLI command
 // Parses SubtaskID (e.g., "1.2") into taskID and subtaskID
 func (m *UpdateSubtaskModel) executeUpdateSubtaskCommand() tea.Cmd {
-	return func() tea.Msg {
+	stream := m.output.start()
+	ctx := m.cancel.start()
+	return tea.Batch(stream.wait(), func() tea.Msg {
+		defer stream.close()
+		executor := cliExecutor.WithOutput(stream.send).WithContext(ctx).WithAdvancedFlags(m.AdvancedFlags).WithResearchModel(m.ResearchModel)
+
 		// Parse subtask ID like "1.2" into taskID="1" and subtaskID="2"
 		parts := strings.Split(m.SubtaskID, ".")
 		if len(parts) < 2 {
@@ -229,9 +295,9 @@ func (m *UpdateSubtaskModel) executeUpdateSubtaskCommand() tea.Cmd {
 		taskID := parts[0]
 		subtaskID := strings.Join(parts[1:], ".") // Handle nested subtasks like "1.2.3"
 		
-		result := cliExecutor.UpdateSubtask(m.FilePath, taskID, subtaskID, m.Prompt, m.Research)
+		result := executor.UpdateSubtask(m.FilePath, taskID, subtaskID, m.Prompt, m.Research)
 		return updateSubtaskCompleteMsg{result: result}
-	}
+	})
 }
 
 // Ensure UpdateSubtaskModel implements tea.Model.
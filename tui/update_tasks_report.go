@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// taskSnapshot holds each task of a tasks file as compacted JSON, so any
+// change to a task shows, not only to the fields Task parses.
+type taskSnapshot struct {
+	order  []int          // Task IDs in file order
+	raw    map[int]string // Compacted JSON of each task
+	status map[int]string
+}
+
+// snapshotTasks reads the tasks of a tasks file, in the active tag, for
+// comparing before and after a command. It returns nil if the file can't be
+// read or parsed.
+func snapshotTasks(path string) *taskSnapshot {
+	data, err := os.ReadFile(resolveTasksPath(path))
+	if err != nil {
+		return nil
+	}
+	if data, err = tagTasks(data, activeTag()); err != nil {
+		return nil
+	}
+	var file struct {
+		Tasks []json.RawMessage `json:"tasks"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil
+	}
+
+	s := &taskSnapshot{raw: make(map[int]string), status: make(map[int]string)}
+	for _, raw := range file.Tasks {
+		var task Task
+		var compact bytes.Buffer
+		if err := json.Unmarshal(raw, &task); err != nil {
+			return nil
+		}
+		if err := json.Compact(&compact, raw); err != nil {
+			return nil
+		}
+		s.order = append(s.order, task.ID)
+		s.raw[task.ID] = compact.String()
+		s.status[task.ID] = task.Status
+	}
+	return s
+}
+
+// changed reports whether any task differs between s and after.
+func (s *taskSnapshot) changed(after *taskSnapshot) bool {
+	if s == nil || after == nil {
+		return false
+	}
+	return !maps.Equal(s.raw, after.raw)
+}
+
+// updatedCountPattern matches the CLI's summary of an update run.
+var updatedCountPattern = regexp.MustCompile(`Successfully updated (\d+) tasks`)
+
+// updateTasksReport describes what an update run did to each task: those
+// from fromID on that weren't done, which the CLI sends to the AI, and any
+// other task that changed. The AI may leave tasks out of its reply, and the
+// CLI then keeps them as they were, so a run can succeed with only some of
+// the tasks updated. Without snapshots of the file it falls back to the count
+// the CLI prints. summary is "" when neither is available.
+func updateTasksReport(before, after *taskSnapshot, fromID int, output string) (summary, breakdown string) {
+	if before == nil || after == nil {
+		if m := updatedCountPattern.FindStringSubmatch(output); m != nil {
+			return fmt.Sprintf("Updated %s task(s).", m[1]), ""
+		}
+		return "", ""
+	}
+
+	var lines []string
+	updated := 0
+	for _, id := range before.order {
+		scoped := id >= fromID && before.status[id] != "done" // As the CLI picks them
+		raw, ok := after.raw[id]
+		switch {
+		case !ok:
+			lines = append(lines, fmt.Sprintf("❌ Task %d: missing after the update", id))
+		case raw != before.raw[id]:
+			updated++
+			lines = append(lines, fmt.Sprintf("✅ Task %d: updated", id))
+		case scoped:
+			lines = append(lines, fmt.Sprintf("⚠️  Task %d: unchanged", id))
+		}
+	}
+	if len(lines) == 0 {
+		return "No tasks were updated.", ""
+	}
+	summary = fmt.Sprintf("Updated %d of %d task(s).", updated, len(lines))
+	if missed := len(lines) - updated; missed > 0 {
+		summary += fmt.Sprintf(" %d weren't updated.", missed)
+	}
+	return summary, strings.Join(lines, "\n")
+}
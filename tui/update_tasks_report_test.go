@@ -0,0 +1,40 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestUpdateTasksReport(t *testing.T) {
+	tasksPath := filepath.Join(t.TempDir(), "tasks.json")
+	writeTasks(t, tasksPath, `{"tasks": [
+		{"id": 1, "title": "Setup", "status": "done"},
+		{"id": 2, "title": "API", "status": "pending"},
+		{"id": 3, "title": "UI", "status": "pending"}
+	]}`)
+	before := snapshotTasks(tasksPath)
+	// The AI left task 3 out of its reply, so only task 2 changed
+	writeTasks(t, tasksPath, `{"tasks": [
+		{"id": 1, "title": "Setup", "status": "done"},
+		{"id": 2, "title": "API", "status": "pending", "details": "Use REST"},
+		{"id": 3, "title": "UI", "status": "pending"}
+	]}`)
+	after := snapshotTasks(tasksPath)
+
+	summary, breakdown := updateTasksReport(before, after, 1, "")
+	if want := "Updated 1 of 2 task(s). 1 weren't updated."; summary != want {
+		t.Errorf("summary = %q, want %q", summary, want)
+	}
+	if want := "✅ Task 2: updated\n⚠️  Task 3: unchanged"; breakdown != want {
+		t.Errorf("breakdown = %q, want %q", breakdown, want)
+	}
+	if !before.changed(after) {
+		t.Error("changed() = false, want true")
+	}
+
+	// Without snapshots, the CLI's own count is all there is
+	summary, breakdown = updateTasksReport(nil, nil, 1, "Successfully updated 4 tasks")
+	if summary != "Updated 4 task(s)." || breakdown != "" {
+		t.Errorf("from output = %q, %q", summary, breakdown)
+	}
+}
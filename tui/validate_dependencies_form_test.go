@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestHighlightDependencyIssues(t *testing.T) {
+	report := "[INFO] Checking for invalid dependencies in task files...\n" +
+		"[ERROR]   [CIRCULAR] Task 3: Circular dependency detected\n" +
+		"[ERROR]   [MISSING] Task 4: Dependency 9 does not exist (Dependency: 9)\n" +
+		"[ERROR]   [SELF] Task 5: Task depends on itself"
+
+	want := "[INFO] Checking for invalid dependencies in task files...\n" +
+		dependencyIssueStyle.Render("[ERROR]   [CIRCULAR] Task 3: Circular dependency detected") + "\n" +
+		dependencyIssueStyle.Render("[ERROR]   [MISSING] Task 4: Dependency 9 does not exist (Dependency: 9)") + "\n" +
+		"[ERROR]   [SELF] Task 5: Task depends on itself"
+
+	if got := highlightDependencyIssues(report); got != want {
+		t.Errorf("highlightDependencyIssues() =\n%q\nwant\n%q", got, want)
+	}
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// taskIDPattern matches top-level task IDs ("2") and dotted subtask IDs ("3.1").
+var taskIDPattern = regexp.MustCompile(`^\d+(\.\d+)?$`)
+
+// tagNamePattern matches the names the CLI accepts for tags.
+var tagNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// subtaskIDPattern matches dotted subtask IDs ("3.1") only.
+var subtaskIDPattern = regexp.MustCompile(`^\d+\.\d+$`)
+
+// validateTaskID checks that s looks like a task or subtask ID the CLI understands.
+func validateTaskID(s string) error {
+	if !taskIDPattern.MatchString(s) {
+		return fmt.Errorf("invalid task ID %q: use a number like \"2\" or a subtask ID like \"3.1\"", s)
+	}
+	return nil
+}
+
+// validateSubtaskID checks that s is a dotted subtask ID, parent then subtask.
+func validateSubtaskID(s string) error {
+	if !subtaskIDPattern.MatchString(s) {
+		return fmt.Errorf("invalid subtask ID %q: use the parent and subtask IDs like \"3.1\"", s)
+	}
+	return nil
+}
+
+// validateTagName checks that s can name a tag: letters, digits, hyphens and underscores.
+func validateTagName(s string) error {
+	if !tagNamePattern.MatchString(s) {
+		return fmt.Errorf("invalid tag name %q: use letters, digits, hyphens and underscores", s)
+	}
+	return nil
+}
+
+// validateDependencyPair checks that a task is not being made to depend on itself.
+// The CLI accepts dependencies between a parent and its own subtasks, so only
+// identical IDs are rejected here.
+func validateDependencyPair(taskID, dependsOn string) error {
+	if taskID != "" && taskID == dependsOn {
+		return fmt.Errorf("task ID and 'Depends On' ID cannot be the same")
+	}
+	return nil
+}
+
+// validateMovePair checks the source and destination of a move: each a task
+// or dotted subtask ID, different, and not a task moving under itself.
+func validateMovePair(fromID, toID string) error {
+	if err := validateTaskID(fromID); err != nil {
+		return err
+	}
+	if err := validateTaskID(toID); err != nil {
+		return err
+	}
+	if fromID == toID {
+		return fmt.Errorf("the source and destination are the same")
+	}
+	if strings.HasPrefix(toID, fromID+".") {
+		return fmt.Errorf("task %s can't be moved into its own subtasks", fromID)
+	}
+	return nil
+}
+
+// validateTaskExists checks that id names a task or subtask in the tasks file.
+// If the file can't be read the check is skipped and the CLI has the final say.
+func validateTaskExists(filePath, id string) error {
+	tasksFile, err := LoadTasksFile(filePath)
+	if err != nil {
+		return nil
+	}
+	if !tasksFile.HasTask(id) {
+		return fmt.Errorf("task %s not found", id)
+	}
+	return nil
+}
+
+// validateExpandTaskID checks the ID of a task to expand and that it's in the
+// tasks file. Subtasks can't have subtasks of their own, and the CLI would read
+// "2.3" as task 2 and expand the parent, so a subtask ID is refused with the
+// way to expand it instead.
+func validateExpandTaskID(filePath, s string) error {
+	if err := validateTaskID(s); err != nil {
+		return err
+	}
+	if subtaskIDPattern.MatchString(s) {
+		return fmt.Errorf("%s is a subtask, which can't have subtasks: convert it to a task with Remove Subtask, then expand that", s)
+	}
+	return validateTaskExists(filePath, s)
+}
+
+// normalizeDependencies checks a comma-separated list of dependency IDs,
+// which may be empty, and returns it as the CLI expects: without spaces and
+// with each ID once, so "1, 2 ,1" becomes "1,2". An empty entry, as in "1,,2",
+// or one that isn't a task or subtask ID is an error.
+func normalizeDependencies(s string) (string, error) {
+	if strings.TrimSpace(s) == "" {
+		return "", nil
+	}
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range strings.Split(s, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return "", fmt.Errorf("empty dependency in %q: separate IDs with single commas", s)
+		}
+		if err := validateTaskID(id); err != nil {
+			return "", err
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return strings.Join(ids, ","), nil
+}
+
+// validateTopLevelTaskIDs checks a comma-separated list of top-level task IDs,
+// which may be empty, and that each is in the tasks file. If the file can't be
+// read only the IDs' form is checked.
+func validateTopLevelTaskIDs(filePath, s string) error {
+	tasksFile, _ := LoadTasksFile(filePath)
+	for _, id := range splitTaskIDs(s) {
+		if _, err := strconv.Atoi(id); err != nil {
+			return fmt.Errorf("invalid task ID %q: use task numbers like \"2\", not subtasks", id)
+		}
+		if tasksFile != nil && !tasksFile.HasTask(id) {
+			return fmt.Errorf("task %s not found", id)
+		}
+	}
+	return nil
+}
+
+// validateTopLevelTaskID checks that s is a single top-level task ID in the
+// tasks file, for commands that take exactly one. If the file can't be read
+// only the ID's form is checked.
+func validateTopLevelTaskID(filePath, s string) error {
+	if strings.Contains(s, ",") {
+		return fmt.Errorf("enter a single task ID, not a list")
+	}
+	if _, err := strconv.Atoi(s); err != nil {
+		return fmt.Errorf("invalid task ID %q: use task numbers like \"2\", not subtasks", s)
+	}
+	return validateTaskExists(filePath, s)
+}
+
+// ValidateBatch checks every input of a multi-ID operation before any of it
+// runs, so a bad ID doesn't leave the batch half done: each ID's form, that
+// it's in the tasks file, and the new status unless it's "". It returns every
+// problem found, in the order of ids, so they can all be fixed in one edit,
+// or nil if the batch can run. If the file can't be read only the IDs' form
+// is checked.
+func ValidateBatch(filePath string, ids []string, status string) []string {
+	var problems []string
+	if len(ids) == 0 {
+		problems = append(problems, "no task IDs given")
+	}
+	if status != "" {
+		var names []string
+		valid := false
+		for _, option := range taskStatusOptions() {
+			names = append(names, string(option.Value))
+			valid = valid || string(option.Value) == status
+		}
+		if !valid {
+			problems = append(problems, fmt.Sprintf("invalid status %q: use %s", status, strings.Join(names, ", ")))
+		}
+	}
+
+	tasksFile, _ := LoadTasksFile(filePath)
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if err := validateTaskID(id); err != nil {
+			problems = append(problems, err.Error())
+		} else if tasksFile != nil && !tasksFile.HasTask(id) {
+			problems = append(problems, fmt.Sprintf("task %s not found", id))
+		} else if seen[id] {
+			problems = append(problems, fmt.Sprintf("task %s is listed more than once", id))
+		}
+		seen[id] = true
+	}
+	return problems
+}
+
+// batchProblemsStatus describes the problems ValidateBatch found, for a form's status line.
+func batchProblemsStatus(problems []string) string {
+	return fmt.Sprintf("Error: nothing was run. Fix these first:\n- %s", strings.Join(problems, "\n- "))
+}
+
+// validateUpdateFrom checks that the tasks file has the task to update from
+// and that it leaves something to update; otherwise the CLI updates nothing.
+// If the file can't be read the check is skipped and the CLI has the final say.
+func validateUpdateFrom(filePath string, fromID int) error {
+	tasksFile, err := LoadTasksFile(filePath)
+	if err != nil {
+		return nil
+	}
+	if !tasksFile.HasTask(strconv.Itoa(fromID)) {
+		return fmt.Errorf("task %d not found", fromID)
+	}
+	if len(tasksFile.IDsUpdatedFrom(fromID)) == 0 {
+		return fmt.Errorf("every task from %d on is done, so there is nothing to update", fromID)
+	}
+	return nil
+}
+
+// validateCount checks that s is a whole number from 1 to max. what names the
+// count in messages, e.g. "number of subtasks".
+func validateCount(s, what string, max int) error {
+	if s == "" {
+		return fmt.Errorf("%s cannot be empty", what)
+	}
+	val, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("must be a valid integer")
+	}
+	if val <= 0 {
+		return fmt.Errorf("must be greater than 0")
+	}
+	if val > max {
+		return fmt.Errorf("%s must be at most %d; raise maxGenerateCount in the settings to allow more", what, max)
+	}
+	return nil
+}
+
+// validateExistingFile checks that an input file path is set and points at an existing file.
+// Output paths that the CLI creates should not use this.
+func validateExistingFile(s string) error {
+	if s == "" {
+		return fmt.Errorf("file path cannot be empty")
+	}
+	info, err := os.Stat(resolveTasksPath(s))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("file does not exist")
+	}
+	if err != nil {
+		return fmt.Errorf("cannot access file: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("path is a directory, not a file")
+	}
+	return nil
+}
+
+// validateOutputDir checks that an output directory path is set and that
+// files can be written there: the directory is writable, or it doesn't exist
+// yet and the nearest existing parent is writable, so it can be created.
+func validateOutputDir(s string) error {
+	if s == "" {
+		return fmt.Errorf("output directory cannot be empty")
+	}
+	dir := resolveTasksPath(s)
+	for existing := dir; ; existing = filepath.Dir(existing) {
+		info, err := os.Stat(existing)
+		if err != nil {
+			// Missing, or under a file; either way the parent decides
+			if filepath.Dir(existing) == existing {
+				return fmt.Errorf("no part of %s exists", dir)
+			}
+			continue
+		}
+
+		if !info.IsDir() {
+			return fmt.Errorf("%s is a file, not a directory", existing)
+		}
+		if err := checkWritableDir(existing); err != nil {
+			if existing == dir {
+				return fmt.Errorf("output directory is not writable: %w", err)
+			}
+			return fmt.Errorf("output directory can't be created: %w", err)
+		}
+		return nil
+	}
+}
+
+// checkWritableDir checks that files can be created in dir by creating and removing one.
+func checkWritableDir(dir string) error {
+	f, err := os.CreateTemp(dir, ".taskmaster-write-check-*")
+	if err != nil {
+		return fmt.Errorf("%s is not writable", dir)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// dirExists reports whether path, resolved like the CLI resolves it, is an existing directory.
+func dirExists(path string) bool {
+	info, err := os.Stat(resolveTasksPath(path))
+	return err == nil && info.IsDir()
+}
+
+// validateOptionalCount checks that s is empty, meaning the default, or a
+// whole number greater than 0.
+func validateOptionalCount(s, what string) error {
+	if s == "" {
+		return nil
+	}
+	val, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("%s must be a whole number, or empty for the default", what)
+	}
+	if val <= 0 {
+		return fmt.Errorf("%s must be greater than 0, or empty for the default", what)
+	}
+	return nil
+}
+
+// validateWorkDir checks that s is empty, meaning the default, or an existing
+// directory. A relative path is relative to the directory the TUI started in.
+func validateWorkDir(s string) error {
+	if s == "" {
+		return nil
+	}
+	info, err := os.Stat(s)
+	if err != nil {
+		return fmt.Errorf("working directory %s not found", s)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", s)
+	}
+	return nil
+}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateTaskID(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "top-level ID", input: "2"},
+		{name: "multi-digit ID", input: "12"},
+		{name: "subtask ID", input: "3.1"},
+		{name: "word", input: "one", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+		{name: "trailing dot", input: "3.", wantErr: true},
+		{name: "leading dot", input: ".1", wantErr: true},
+		{name: "nested subtask", input: "1.2.3", wantErr: true},
+		{name: "negative", input: "-1", wantErr: true},
+		{name: "whitespace", input: " 2", wantErr: true},
+		{name: "comma list", input: "1,2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTaskID(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateTaskID(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDependencyPair(t *testing.T) {
+	tests := []struct {
+		name      string
+		taskID    string
+		dependsOn string
+		wantErr   bool
+	}{
+		{name: "different tasks", taskID: "2", dependsOn: "1"},
+		{name: "same task", taskID: "2", dependsOn: "2", wantErr: true},
+		{name: "same subtask", taskID: "3.1", dependsOn: "3.1", wantErr: true},
+		{name: "sibling subtasks", taskID: "3.1", dependsOn: "3.2"},
+		{name: "subtask on parent", taskID: "3.1", dependsOn: "3"},
+		{name: "parent on subtask", taskID: "3", dependsOn: "3.1"},
+		{name: "empty task ID", taskID: "", dependsOn: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDependencyPair(tt.taskID, tt.dependsOn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateDependencyPair(%q, %q) error = %v, wantErr %v", tt.taskID, tt.dependsOn, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "tasks.json")
+	if err := os.WriteFile(file, []byte(`{"tasks": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "existing file", input: file},
+		{name: "empty", input: "", wantErr: "file path cannot be empty"},
+		{name: "missing file", input: filepath.Join(dir, "missing.json"), wantErr: "file does not exist"},
+		{name: "directory", input: dir, wantErr: "path is a directory, not a file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateExistingFile(tt.input)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateExistingFile(%q) unexpected error: %v", tt.input, err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("validateExistingFile(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTaskExists(t *testing.T) {
+	path := writeSampleTasks(t)
+
+	if err := validateTaskExists(path, "2.1"); err != nil {
+		t.Errorf("validateTaskExists(2.1) unexpected error: %v", err)
+	}
+	if err := validateTaskExists(path, "7"); err == nil || err.Error() != "task 7 not found" {
+		t.Errorf("validateTaskExists(7) error = %v, want %q", err, "task 7 not found")
+	}
+	// An unreadable file skips the check
+	if err := validateTaskExists(filepath.Join(t.TempDir(), "missing.json"), "7"); err != nil {
+		t.Errorf("validateTaskExists on missing file unexpected error: %v", err)
+	}
+}
+
+func TestValidateTaskExistsInTag(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	writeTasks(t, path, `{"master": {"tasks": [{"id": 1}]}, "feature-x": {"tasks": [{"id": 1}, {"id": 2}]}}`)
+
+	if err := validateTaskExists(path, "1"); err != nil {
+		t.Errorf("validateTaskExists(1) in master unexpected error: %v", err)
+	}
+	if err := validateTaskExists(path, "2"); err == nil {
+		t.Error("validateTaskExists(2) in master succeeded, want it not found")
+	}
+	if err := saveActiveTag("feature-x"); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateTaskExists(path, "2"); err != nil {
+		t.Errorf("validateTaskExists(2) in feature-x unexpected error: %v", err)
+	}
+	// A tag the file doesn't have skips the check
+	if err := saveActiveTag("hotfix"); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateTaskExists(path, "2"); err != nil {
+		t.Errorf("validateTaskExists(2) in a missing tag unexpected error: %v", err)
+	}
+}
+
+func TestValidateTopLevelTaskIDs(t *testing.T) {
+	path := writeSampleTasks(t)
+
+	tests := []struct {
+		ids     string
+		wantErr string
+	}{
+		{ids: ""},
+		{ids: "1, 2"},
+		{ids: "2.1", wantErr: `invalid task ID "2.1": use task numbers like "2", not subtasks`},
+		{ids: "1,x", wantErr: `invalid task ID "x": use task numbers like "2", not subtasks`},
+		{ids: "1,7", wantErr: "task 7 not found"},
+	}
+	for _, tt := range tests {
+		err := validateTopLevelTaskIDs(path, tt.ids)
+		if (err == nil) != (tt.wantErr == "") || (err != nil && err.Error() != tt.wantErr) {
+			t.Errorf("validateTopLevelTaskIDs(%q) error = %v, want %q", tt.ids, err, tt.wantErr)
+		}
+	}
+	// An unreadable file only checks the IDs' form
+	if err := validateTopLevelTaskIDs(filepath.Join(t.TempDir(), "missing.json"), "7"); err != nil {
+		t.Errorf("validateTopLevelTaskIDs on missing file unexpected error: %v", err)
+	}
+}
+
+func TestValidateTopLevelTaskID(t *testing.T) {
+	path := writeSampleTasks(t)
+
+	tests := []struct {
+		id      string
+		wantErr string
+	}{
+		{id: "2"},
+		{id: "", wantErr: `invalid task ID "": use task numbers like "2", not subtasks`},
+		{id: "1,2", wantErr: "enter a single task ID, not a list"},
+		{id: "2.1", wantErr: `invalid task ID "2.1": use task numbers like "2", not subtasks`},
+		{id: "7", wantErr: "task 7 not found"},
+	}
+	for _, tt := range tests {
+		err := validateTopLevelTaskID(path, tt.id)
+		if (err == nil) != (tt.wantErr == "") || (err != nil && err.Error() != tt.wantErr) {
+			t.Errorf("validateTopLevelTaskID(%q) error = %v, want %q", tt.id, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateBatch(t *testing.T) {
+	path := writeSampleTasks(t)
+
+	if problems := ValidateBatch(path, []string{"1", "2.1", "3.2"}, "done"); problems != nil {
+		t.Errorf("ValidateBatch of valid inputs = %q, want none", problems)
+	}
+
+	got := ValidateBatch(path, []string{"1", "x", "7", "9.9", "1"}, "finished")
+	want := []string{
+		`invalid status "finished": use todo, in-progress, review, done`,
+		`invalid task ID "x": use a number like "2" or a subtask ID like "3.1"`,
+		"task 7 not found",
+		"task 9.9 not found",
+		"task 1 is listed more than once",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateBatch = %q, want every problem %q", got, want)
+	}
+
+	if got := ValidateBatch(path, nil, ""); !reflect.DeepEqual(got, []string{"no task IDs given"}) {
+		t.Errorf("ValidateBatch with no IDs = %q", got)
+	}
+	// An unreadable file only checks the IDs' form
+	if problems := ValidateBatch(filepath.Join(t.TempDir(), "missing.json"), []string{"7"}, ""); problems != nil {
+		t.Errorf("ValidateBatch on missing file = %q, want none", problems)
+	}
+}
+
+func TestValidateExpandTaskID(t *testing.T) {
+	path := writeSampleTasks(t)
+
+	if err := validateExpandTaskID(path, "2"); err != nil {
+		t.Errorf("validateExpandTaskID(2) unexpected error: %v", err)
+	}
+	err := validateExpandTaskID(path, "2.3")
+	if err == nil || !strings.Contains(err.Error(), "convert it to a task with Remove Subtask") {
+		t.Errorf("validateExpandTaskID(2.3) error = %v, want the subtask refused with a way forward", err)
+	}
+	if err := validateExpandTaskID(path, "7"); err == nil {
+		t.Error("validateExpandTaskID(7) = nil, want task not found")
+	}
+}
+
+func TestNormalizeDependencies(t *testing.T) {
+	tests := []struct {
+		in, want, wantErr string
+	}{
+		{in: "", want: ""},
+		{in: "  ", want: ""},
+		{in: "1, 2 ,3", want: "1,2,3"},
+		{in: "2.1,1,2.1", want: "2.1,1"},
+		{in: "1,,2", wantErr: `empty dependency in "1,,2": separate IDs with single commas`},
+		{in: "1,2,", wantErr: `empty dependency in "1,2,": separate IDs with single commas`},
+		{in: "abc", wantErr: `invalid task ID "abc": use a number like "2" or a subtask ID like "3.1"`},
+	}
+	for _, tt := range tests {
+		got, err := normalizeDependencies(tt.in)
+		if (err == nil) != (tt.wantErr == "") || (err != nil && err.Error() != tt.wantErr) {
+			t.Errorf("normalizeDependencies(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("normalizeDependencies(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestValidateUpdateFrom(t *testing.T) {
+	path := writeSampleTasks(t)
+
+	if err := validateUpdateFrom(path, 2); err != nil {
+		t.Errorf("validateUpdateFrom(2) unexpected error: %v", err)
+	}
+	if err := validateUpdateFrom(path, 7); err == nil || err.Error() != "task 7 not found" {
+		t.Errorf("validateUpdateFrom(7) error = %v, want %q", err, "task 7 not found")
+	}
+	writeTasks(t, path, `{"tasks": [{"id": 1, "status": "pending"}, {"id": 2, "status": "done"}]}`)
+	if err := validateUpdateFrom(path, 2); err == nil {
+		t.Error("validateUpdateFrom(2) with only done tasks left, want an error")
+	}
+	// An unreadable file skips the check
+	if err := validateUpdateFrom(filepath.Join(t.TempDir(), "missing.json"), 7); err != nil {
+		t.Errorf("validateUpdateFrom on missing file unexpected error: %v", err)
+	}
+}
+
+func TestValidateCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "one", input: "1"},
+		{name: "at max", input: "20"},
+		{name: "over max", input: "21", wantErr: true},
+		{name: "fat-fingered", input: "500", wantErr: true},
+		{name: "zero", input: "0", wantErr: true},
+		{name: "negative", input: "-3", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+		{name: "word", input: "five", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCount(tt.input, "number of subtasks", 20)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateCount(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMaxGenerateCount(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if got := maxGenerateCount(); got != defaultMaxGenerateCount {
+		t.Errorf("maxGenerateCount() without settings = %d, want %d", got, defaultMaxGenerateCount)
+	}
+
+	if err := SaveSettings(Settings{MaxGenerateCount: 50}); err != nil {
+		t.Fatal(err)
+	}
+	if got := maxGenerateCount(); got != 50 {
+		t.Errorf("maxGenerateCount() = %d, want the configured 50", got)
+	}
+}
+
+func TestValidateSubtaskID(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr bool
+	}{
+		{input: "3.1"},
+		{input: "12.10"},
+		{input: "3", wantErr: true},
+		{input: "3.", wantErr: true},
+		{input: "1.2.3", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := validateSubtaskID(tt.input); (err != nil) != tt.wantErr {
+			t.Errorf("validateSubtaskID(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateMovePair(t *testing.T) {
+	tests := []struct {
+		from, to string
+		wantErr  string
+	}{
+		{from: "1.2", to: "3.1"},
+		{from: "1.2", to: "4"},
+		{from: "5", to: "2.3"},
+		{from: "2", to: "7"},
+		{from: "1.2", to: "1.2", wantErr: "the source and destination are the same"},
+		{from: "3", to: "3.1", wantErr: "task 3 can't be moved into its own subtasks"},
+		{from: "1.x", to: "3.1", wantErr: `invalid task ID "1.x": use a number like "2" or a subtask ID like "3.1"`},
+		{from: "1.2", to: "", wantErr: `invalid task ID "": use a number like "2" or a subtask ID like "3.1"`},
+	}
+
+	for _, tt := range tests {
+		err := validateMovePair(tt.from, tt.to)
+		if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+			t.Errorf("validateMovePair(%q, %q) error = %v, want %q", tt.from, tt.to, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateOutputDir(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "tasks.json")
+	if err := os.WriteFile(file, []byte(`{"tasks": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "existing directory", input: dir},
+		{name: "missing nested directory", input: filepath.Join(dir, "out", "tasks")},
+		{name: "empty", input: "", wantErr: "output directory cannot be empty"},
+		{name: "under a file", input: filepath.Join(file, "out"), wantErr: file + " is a file, not a directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateOutputDir(tt.input)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateOutputDir(%q) unexpected error: %v", tt.input, err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("validateOutputDir(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(dir, "out")); !os.IsNotExist(err) {
+		t.Errorf("validateOutputDir created the missing directory")
+	}
+}
+
+func TestValidateOutputDirReadOnly(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permissions are not enforced for root")
+	}
+	dir := filepath.Join(t.TempDir(), "readonly")
+	if err := os.Mkdir(dir, 0o555); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "output directory is not writable: " + dir + " is not writable"
+	if err := validateOutputDir(dir); err == nil || err.Error() != want {
+		t.Errorf("validateOutputDir(%q) error = %v, want %q", dir, err, want)
+	}
+	want = "output directory can't be created: " + dir + " is not writable"
+	if err := validateOutputDir(filepath.Join(dir, "out")); err == nil || err.Error() != want {
+		t.Errorf("validateOutputDir under a read-only directory error = %v, want %q", err, want)
+	}
+}